@@ -1,7 +1,9 @@
 package cel
 
 import (
+	"bytes"
 	"crypto"
+	"crypto/sha256"
 	"fmt"
 	"regexp"
 	"strings"
@@ -28,6 +30,10 @@ const (
 	CosCCELMRIndex = 4
 )
 
+var workloadEventNameRegexp = regexp.MustCompile("^[a-zA-Z][a-zA-Z0-9_.-]*$")
+
+var secretNameRegexp = regexp.MustCompile("^[a-zA-Z0-9][a-zA-Z0-9_.-]*$")
+
 // CosType represent a COS content type in a CEL record content.
 type CosType uint8
 
@@ -45,6 +51,15 @@ const (
 	LaunchSeparatorType
 	MemoryMonitorType
 	GpuCCModeType
+	// EventContent is an event measured by the workload after launch, formatted
+	// by FormatWorkloadEvent.
+	WorkloadEventType
+	// EventContent is empty. No events follow it, as the workload has locked
+	// its measurements.
+	WorkloadSeparatorType
+	// EventContent is a secret imported for the workload, formatted by
+	// FormatSecret.
+	SecretType
 )
 
 // CosTlv is a specific event type created for the COS (Google Container-Optimized OS),
@@ -138,3 +153,51 @@ func ParseEnvVar(envvar string) (string, string, error) {
 
 	return e[0], e[1], nil
 }
+
+// FormatWorkloadEvent takes in the name and content of a workload event, checks the name,
+// and concats them by '='. Unlike environment variables, the content may be any bytes.
+func FormatWorkloadEvent(name string, content []byte) ([]byte, error) {
+	if !workloadEventNameRegexp.MatchString(name) {
+		return nil, fmt.Errorf("malformed workload event name [%s], name must start with an alpha character, followed by a string of alphanumeric characters, '_', '-' or '.' (%s)", name, workloadEventNameRegexp)
+	}
+	return append([]byte(name+"="), content...), nil
+}
+
+// ParseWorkloadEvent takes in a workload event (name=content), parses it and returns its
+// name and content, or an error if it fails the validation check.
+func ParseWorkloadEvent(event []byte) (string, []byte, error) {
+	name, content, ok := bytes.Cut(event, []byte("="))
+	if !ok {
+		return "", nil, fmt.Errorf("malformed workload event, doesn't contain '=': [%q]", event)
+	}
+	if _, err := FormatWorkloadEvent(string(name), content); err != nil {
+		return "", nil, err
+	}
+	return string(name), content, nil
+}
+
+// FormatSecret takes in the name of a secret and the SHA-256 digest of its
+// ImportBlob, checks them, and concats them by '='.
+func FormatSecret(name string, blobDigest []byte) ([]byte, error) {
+	if !secretNameRegexp.MatchString(name) {
+		return nil, fmt.Errorf("malformed secret name [%s], name must start with an alphanumeric character, followed by a string of alphanumeric characters, '_', '-' or '.' (%s)", name, secretNameRegexp)
+	}
+	if len(blobDigest) != sha256.Size {
+		return nil, fmt.Errorf("malformed secret digest, got %d bytes, want %d", len(blobDigest), sha256.Size)
+	}
+	return append([]byte(name+"="), blobDigest...), nil
+}
+
+// ParseSecret takes in a secret event (name=digest), parses it and returns the
+// secret's name and ImportBlob digest, or an error if it fails the validation
+// check.
+func ParseSecret(event []byte) (string, []byte, error) {
+	name, blobDigest, ok := bytes.Cut(event, []byte("="))
+	if !ok {
+		return "", nil, fmt.Errorf("malformed secret event, doesn't contain '=': [%q]", event)
+	}
+	if _, err := FormatSecret(string(name), blobDigest); err != nil {
+		return "", nil, err
+	}
+	return string(name), blobDigest, nil
+}
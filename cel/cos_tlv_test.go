@@ -2,6 +2,7 @@ package cel
 
 import (
 	"bytes"
+	"crypto/sha256"
 	"strings"
 	"testing"
 
@@ -126,3 +127,91 @@ func TestParseEnvVar(t *testing.T) {
 		})
 	}
 }
+
+func TestParseWorkloadEvent(t *testing.T) {
+	tests := []struct {
+		testName             string
+		event                []byte
+		name                 string
+		content              []byte
+		expectedErrSubstring string
+	}{
+		{"normal case", []byte("model=sha256:abcd"), "model", []byte("sha256:abcd"), ""},
+		{"dotted name", []byte("config.v2-digest=1"), "config.v2-digest", []byte("1"), ""},
+		{"binary content", []byte{'m', '=', 0xC0, 0, '='}, "m", []byte{0xC0, 0, '='}, ""},
+		{"empty content", []byte("model="), "model", []byte{}, ""},
+		{"no =", []byte("model"), "", nil, "malformed workload event, doesn't contain '='"},
+		{"empty name", []byte("=foo"), "", nil, "name must start with an alpha character"},
+		{"bad name", []byte("_model=foo"), "", nil, "name must start with an alpha character"},
+		{"bad name space", []byte("my model=foo"), "", nil, "name must start with an alpha character"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			n, c, err := ParseWorkloadEvent(test.event)
+			if test.expectedErrSubstring != "" {
+				if err == nil {
+					t.Errorf("expected error substring [%s], but got no error", test.expectedErrSubstring)
+				} else if !strings.Contains(err.Error(), test.expectedErrSubstring) {
+					t.Errorf("expected error substring [%s], but got [%v]", test.expectedErrSubstring, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error, but got [%s]", err)
+			}
+			if n != test.name {
+				t.Errorf("name mismatch, want [%s], got [%s]", test.name, n)
+			}
+			if !bytes.Equal(c, test.content) {
+				t.Errorf("content mismatch, want [%q], got [%q]", test.content, c)
+			}
+			formatted, err := FormatWorkloadEvent(n, c)
+			if err != nil {
+				t.Errorf("expected no error, but got [%s]", err)
+			} else if !bytes.Equal(formatted, test.event) {
+				t.Errorf("formatted event mismatch, want [%q], got [%q]", test.event, formatted)
+			}
+		})
+	}
+}
+
+func TestParseSecret(t *testing.T) {
+	digest := sha256.Sum256([]byte("blob"))
+	tests := []struct {
+		testName             string
+		event                []byte
+		name                 string
+		expectedErrSubstring string
+	}{
+		{"normal case", append([]byte("api-key="), digest[:]...), "api-key", ""},
+		{"leading digit", append([]byte("0.key="), digest[:]...), "0.key", ""},
+		{"no =", []byte("api-key"), "", "malformed secret event, doesn't contain '='"},
+		{"empty name", append([]byte("="), digest[:]...), "", "name must start with an alphanumeric character"},
+		{"bad name", append([]byte("../key="), digest[:]...), "", "name must start with an alphanumeric character"},
+		{"short digest", append([]byte("api-key="), digest[:31]...), "", "malformed secret digest"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			n, d, err := ParseSecret(test.event)
+			if test.expectedErrSubstring != "" {
+				if err == nil {
+					t.Errorf("expected error substring [%s], but got no error", test.expectedErrSubstring)
+				} else if !strings.Contains(err.Error(), test.expectedErrSubstring) {
+					t.Errorf("expected error substring [%s], but got [%v]", test.expectedErrSubstring, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error, but got [%s]", err)
+			}
+			if n != test.name {
+				t.Errorf("name mismatch, want [%s], got [%s]", test.name, n)
+			}
+			if !bytes.Equal(d, digest[:]) {
+				t.Errorf("digest mismatch, want [%x], got [%x]", digest, d)
+			}
+		})
+	}
+}
@@ -45,6 +45,15 @@ type AttestOpts struct {
 	// logs overlap, server-side verification using this library may fail.
 	// Deprecated: Manually populate the pb.Attestation instead.
 	CanonicalEventLog []byte
+	// Linux IMA runtime measurement list to add to the attestation, in either
+	// the binary or ASCII format.
+	// If not specified and CollectIMAMeasurementList is set, then it takes the
+	// list by calling GetIMAMeasurementList().
+	IMAMeasurementList []byte
+	// Setting this adds the machine's IMA runtime measurement list to the
+	// attestation. The list is read after the PCRs are quoted, so it may
+	// contain measurements that are newer than the quotes.
+	CollectIMAMeasurementList bool
 	// If non-nil, will be used to fetch the AK certificate chain for validation.
 	// Key.Attest() will construct the certificate chain by making GET requests to
 	// the contents of Key.cert.IssuingCertificateURL using this client.
@@ -309,6 +318,13 @@ func (k *Key) Attest(opts AttestOpts) (*pb.Attestation, error) {
 	if len(opts.CanonicalEventLog) != 0 {
 		attestation.CanonicalEventLog = opts.CanonicalEventLog
 	}
+	if opts.IMAMeasurementList != nil {
+		attestation.ImaMeasurementList = opts.IMAMeasurementList
+	} else if opts.CollectIMAMeasurementList {
+		if attestation.ImaMeasurementList, err = GetIMAMeasurementList(); err != nil {
+			return nil, fmt.Errorf("failed to retrieve IMA measurement list: %w", err)
+		}
+	}
 
 	// Attempt to construct certificate chain. fetchIssuingCertificate checks if
 	// AK cert is present and contains intermediate cert URLs.
@@ -0,0 +1,86 @@
+package client
+
+import (
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/google/go-tpm-tools/internal"
+	"google.golang.org/protobuf/proto"
+)
+
+// defaultAttestedTLSValidity is the validity period of attested TLS
+// certificates whose template does not set one.
+const defaultAttestedTLSValidity = time.Hour
+
+// AttestedTLSCertificate returns a tls.Certificate for the loaded TPM Key with
+// a self-issued certificate embedding a fresh attestation by ak, for attested
+// TLS. The attestation's nonce binds it to the Key's public key and to
+// opts.Nonce, which may be empty, and must be the VerifyOpts.Nonce of the
+// peer's server.VerifyAttestedTLS. The other opts are passed to ak.Attest.
+//
+// The certificate is created from template, which may be nil. Its validity
+// period defaults to one hour from now, and its serial number to a random one.
+// The Key must meet the requirements of TLSCertificate, apart from having a
+// certificate. As Go limits the size of TLS certificate messages to 256 KiB,
+// the attestation's event logs must be smaller than that.
+func (k *Key) AttestedTLSCertificate(ak *Key, template *x509.Certificate, opts AttestOpts) (tls.Certificate, error) {
+	signer, err := k.GetSigner()
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	var tmpl x509.Certificate
+	if template != nil {
+		tmpl = *template
+	}
+	if tmpl.SerialNumber == nil {
+		if tmpl.SerialNumber, err = rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128)); err != nil {
+			return tls.Certificate{}, fmt.Errorf("failed to generate serial number: %w", err)
+		}
+	}
+	if tmpl.NotBefore.IsZero() {
+		tmpl.NotBefore = time.Now()
+	}
+	if tmpl.NotAfter.IsZero() {
+		tmpl.NotAfter = tmpl.NotBefore.Add(defaultAttestedTLSValidity)
+	}
+	if tmpl.KeyUsage == 0 {
+		tmpl.KeyUsage = x509.KeyUsageDigitalSignature
+	}
+	if len(tmpl.ExtKeyUsage) == 0 {
+		tmpl.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth}
+	}
+	if tmpl.SignatureAlgorithm, err = k.x509SignatureAlgorithm(); err != nil {
+		return tls.Certificate{}, err
+	}
+
+	spki, err := x509.MarshalPKIXPublicKey(k.pubKey)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("failed to encode public key: %w", err)
+	}
+	opts.Nonce = internal.AttestedTLSNonce(spki, opts.Nonce)
+	attestation, err := ak.Attest(opts)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("failed to attest: %w", err)
+	}
+	value, err := proto.Marshal(attestation)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("failed to marshal attestation: %w", err)
+	}
+	tmpl.ExtraExtensions = append(tmpl.ExtraExtensions[:len(tmpl.ExtraExtensions):len(tmpl.ExtraExtensions)],
+		pkix.Extension{Id: internal.AttestedTLSExtensionOID, Value: value})
+
+	der, err := x509.CreateCertificate(rand.Reader, &tmpl, &tmpl, k.pubKey, signer)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("failed to create certificate: %w", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("failed to parse certificate: %w", err)
+	}
+	return k.tlsCertificate([][]byte{der}, cert)
+}
@@ -0,0 +1,42 @@
+package client
+
+import (
+	"fmt"
+
+	"github.com/google/go-tpm-tools/internal"
+	tpb "github.com/google/go-tpm-tools/proto/tpm"
+	"github.com/google/go-tpm/legacy/tpm2"
+)
+
+// Certify uses the loaded signing Key (usually an AK) to certify that the
+// object Key is loaded in the same TPM, with TPM2_Certify. The returned
+// CertifiedBlob contains the object's public area, and the certification
+// (including extraData) and its signature, which can be checked with
+// server.VerifyCertifiedKey if the object is an unrestricted signing key. Both
+// keys must be usable with an empty password, and the object must not require
+// a policy for administration.
+func (k *Key) Certify(object *Key, extraData []byte) (*tpb.CertifiedBlob, error) {
+	if _, err := internal.GetSigningHashAlg(k.pubArea); err != nil {
+		return nil, err
+	}
+	pubArea, err := object.pubArea.Encode()
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode public area: %w", err)
+	}
+
+	certifyInfo, rawSig, err := tpm2.CertifyEx(k.rw, "", "", object.Handle(), k.Handle(), extraData, tpm2.SigScheme{Alg: tpm2.AlgNull})
+	if err != nil {
+		return nil, fmt.Errorf("failed to certify: %w", err)
+	}
+	blob := &tpb.CertifiedBlob{
+		PubArea:     pubArea,
+		CertifyInfo: certifyInfo,
+		RawSig:      rawSig,
+	}
+	// Verify the certification client-side to make sure we didn't mess things
+	// up, as with Quote.
+	if _, err := internal.VerifyCertification(blob, k.PublicKey(), extraData); err != nil {
+		return nil, fmt.Errorf("failed to verify certification: %w", err)
+	}
+	return blob, nil
+}
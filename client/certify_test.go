@@ -0,0 +1,54 @@
+package client_test
+
+import (
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/google/go-tpm/legacy/tpm2"
+
+	"github.com/google/go-tpm-tools/client"
+	"github.com/google/go-tpm-tools/internal"
+	"github.com/google/go-tpm-tools/internal/test"
+)
+
+func TestCertify(t *testing.T) {
+	rwc := test.GetTPM(t)
+	defer client.CheckedClose(t, rwc)
+
+	object, err := client.NewKey(rwc, tpm2.HandleOwner, templateECC(tpm2.AlgSHA256))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer object.Close()
+
+	extraData := []byte("certification nonce")
+	for _, akFunc := range []func(io.ReadWriter) (*client.Key, error){
+		client.AttestationKeyRSA,
+		client.AttestationKeyECC,
+	} {
+		ak, err := akFunc(rwc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		blob, err := ak.Certify(object, extraData)
+		if err != nil {
+			ak.Close()
+			t.Fatalf("Certify() failed: %v", err)
+		}
+
+		pubArea, err := internal.VerifyCertification(blob, ak.PublicKey(), extraData)
+		if err != nil {
+			t.Errorf("VerifyCertification() failed: %v", err)
+		} else if !reflect.DeepEqual(pubArea, object.PublicArea()) {
+			t.Errorf("VerifyCertification() returned %v, want %v", pubArea, object.PublicArea())
+		}
+		if _, err := internal.VerifyCertification(blob, ak.PublicKey(), []byte("other nonce")); err == nil {
+			t.Error("VerifyCertification() succeeded with the wrong extraData")
+		}
+		if _, err := internal.VerifyCertification(blob, object.PublicKey(), extraData); err == nil {
+			t.Error("VerifyCertification() succeeded with the wrong key")
+		}
+		ak.Close()
+	}
+}
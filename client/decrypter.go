@@ -0,0 +1,164 @@
+package client
+
+import (
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/google/go-tpm/legacy/tpm2"
+	directtpm2 "github.com/google/go-tpm/tpm2"
+	"github.com/google/go-tpm/tpm2/transport"
+)
+
+type tpmDecrypter struct {
+	Key *Key
+}
+
+// Public returns the tpmDecrypter's public key.
+func (decrypter *tpmDecrypter) Public() crypto.PublicKey {
+	return decrypter.Key.PublicKey()
+}
+
+// Decrypt uses the TPM key to decrypt the ciphertext with RSA_Decrypt.
+// The opts must be nil or *rsa.PKCS1v15DecryptOptions for PKCS #1 v1.5
+// padding, or *rsa.OAEPOptions for OAEP padding. The TPM requires a non-empty
+// OAEP label to end with a zero byte (which is part of the label).
+// Concurrent use of Decrypt is thread safe, but it is not safe to access the
+// TPM from other sources while Decrypt is executing.
+func (decrypter *tpmDecrypter) Decrypt(_ io.Reader, ciphertext []byte, opts crypto.DecrypterOpts) ([]byte, error) {
+	cmd := directtpm2.RSADecrypt{
+		CipherText: directtpm2.TPM2BPublicKeyRSA{Buffer: ciphertext},
+	}
+	switch opts := opts.(type) {
+	case nil:
+		cmd.InScheme.Scheme = directtpm2.TPMAlgRSAES
+	case *rsa.PKCS1v15DecryptOptions:
+		if opts.SessionKeyLen != 0 {
+			return nil, errors.New("invalid options: SessionKeyLen is not supported")
+		}
+		cmd.InScheme.Scheme = directtpm2.TPMAlgRSAES
+	case *rsa.OAEPOptions:
+		if opts.MGFHash != 0 && opts.MGFHash != opts.Hash {
+			return nil, errors.New("invalid options: MGFHash must be the same as Hash")
+		}
+		if len(opts.Label) != 0 && opts.Label[len(opts.Label)-1] != 0 {
+			return nil, errors.New("invalid options: Label must end with a zero byte")
+		}
+		hashAlg, err := tpm2.HashToAlgorithm(opts.Hash)
+		if err != nil {
+			return nil, fmt.Errorf("invalid options: %w", err)
+		}
+		cmd.InScheme = directtpm2.TPMTRSADecrypt{
+			Scheme: directtpm2.TPMAlgOAEP,
+			Details: directtpm2.NewTPMUAsymScheme(directtpm2.TPMAlgOAEP, &directtpm2.TPMSEncSchemeOAEP{
+				HashAlg: directtpm2.TPMIAlgHash(hashAlg),
+			}),
+		}
+		cmd.Label = directtpm2.TPM2BData{Buffer: opts.Label}
+	default:
+		return nil, fmt.Errorf("unsupported options type: %T", opts)
+	}
+
+	signerMutex.Lock()
+	defer signerMutex.Unlock()
+
+	var err error
+	if cmd.KeyHandle, err = decrypter.Key.secretOutputHandle(); err != nil {
+		return nil, err
+	}
+	rsp, err := cmd.Execute(transport.FromReadWriter(decrypter.Key.rw))
+	if err != nil {
+		return nil, err
+	}
+	return rsp.Message.Buffer, nil
+}
+
+// GetDecrypter returns a crypto.Decrypter wrapping the loaded TPM Key, which
+// must be an unrestricted RSA decryption key (see DecryptionKeyTemplateRSA).
+// If the key uses encrypted sessions (see SetSessionEncryption), the decrypted
+// data is encrypted on the TPM bus.
+// Concurrent use of one or more Decrypters is thread safe, but it is not safe
+// to access the TPM from other sources while using a Decrypter.
+// The returned Decrypter lasts the lifetime of the Key, and will no longer
+// work once the Key has been closed.
+func (k *Key) GetDecrypter() (crypto.Decrypter, error) {
+	if k.pubArea.Type != tpm2.AlgRSA {
+		return nil, fmt.Errorf("unsupported key type: %v", k.pubArea.Type)
+	}
+	if err := k.checkDecryptionKey(); err != nil {
+		return nil, err
+	}
+	return &tpmDecrypter{k}, nil
+}
+
+// ECDH performs an ECDH exchange with TPM2_ECDH_ZGen, and returns the shared
+// secret, like ecdh.PrivateKey.ECDH. The Key must be an unrestricted ECC
+// decryption key (see DecryptionKeyTemplateECC) on the same curve as remote.
+// The Key's public key for the exchange can be obtained with
+// k.PublicKey().(*ecdsa.PublicKey).ECDH().
+// If the key uses encrypted sessions (see SetSessionEncryption), the shared
+// secret is encrypted on the TPM bus. It is thread safe to use ECDH
+// concurrently with the Signers and Decrypters of this package.
+func (k *Key) ECDH(remote *ecdh.PublicKey) ([]byte, error) {
+	pub, ok := k.pubKey.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("unsupported key type: %v", k.pubArea.Type)
+	}
+	if err := k.checkDecryptionKey(); err != nil {
+		return nil, err
+	}
+	ecdhPub, err := pub.ECDH()
+	if err != nil {
+		return nil, err
+	}
+	if remote.Curve() != ecdhPub.Curve() {
+		return nil, errors.New("remote public key is on a different curve")
+	}
+	// An uncompressed point is 0x04 || X || Y.
+	point := remote.Bytes()[1:]
+	size := len(point) / 2
+
+	signerMutex.Lock()
+	defer signerMutex.Unlock()
+
+	handle, err := k.secretOutputHandle()
+	if err != nil {
+		return nil, err
+	}
+	rsp, err := directtpm2.ECDHZGen{
+		KeyHandle: handle,
+		InPoint: directtpm2.New2B(directtpm2.TPMSECCPoint{
+			X: directtpm2.TPM2BECCParameter{Buffer: point[:size]},
+			Y: directtpm2.TPM2BECCParameter{Buffer: point[size:]},
+		}),
+	}.Execute(transport.FromReadWriter(k.rw))
+	if err != nil {
+		return nil, err
+	}
+	z, err := rsp.OutPoint.Contents()
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode shared point: %w", err)
+	}
+	// The shared secret is the X coordinate, padded to the field size.
+	if len(z.X.Buffer) > size {
+		return nil, errors.New("invalid shared point")
+	}
+	secret := make([]byte, size)
+	copy(secret[size-len(z.X.Buffer):], z.X.Buffer)
+	return secret, nil
+}
+
+// checkDecryptionKey checks that the key is an unrestricted decryption key.
+func (k *Key) checkDecryptionKey() error {
+	if k.hasAttribute(tpm2.FlagRestricted) {
+		return errors.New("restricted keys are not supported")
+	}
+	if !k.hasAttribute(tpm2.FlagDecrypt) {
+		return errors.New("key must be a decryption key")
+	}
+	return nil
+}
@@ -0,0 +1,235 @@
+package client_test
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+
+	"github.com/google/go-tpm/legacy/tpm2"
+
+	"github.com/google/go-tpm-tools/client"
+	"github.com/google/go-tpm-tools/internal/test"
+)
+
+func TestDecrypter(t *testing.T) {
+	rwc := test.GetTPM(t)
+	defer client.CheckedClose(t, rwc)
+	bus := &busRecorder{rw: rwc}
+
+	key, err := client.NewKey(bus, tpm2.HandleOwner, client.DecryptionKeyTemplateRSA())
+	if err != nil {
+		t.Fatalf("failed to create key: %v", err)
+	}
+	defer key.Close()
+	decrypter, err := key.GetDecrypter()
+	if err != nil {
+		t.Fatalf("GetDecrypter() failed: %v", err)
+	}
+	pub := decrypter.Public().(*rsa.PublicKey)
+
+	secret := []byte("decrypted secret")
+	encryptOAEP := func(hash crypto.Hash, label []byte) func() ([]byte, error) {
+		return func() ([]byte, error) {
+			return rsa.EncryptOAEP(hash.New(), rand.Reader, pub, secret, label)
+		}
+	}
+	tests := []struct {
+		name    string
+		encrypt func() ([]byte, error)
+		opts    crypto.DecrypterOpts
+	}{
+		{"PKCS1v15", func() ([]byte, error) { return rsa.EncryptPKCS1v15(rand.Reader, pub, secret) }, &rsa.PKCS1v15DecryptOptions{}},
+		{"PKCS1v15NilOpts", func() ([]byte, error) { return rsa.EncryptPKCS1v15(rand.Reader, pub, secret) }, nil},
+		{"OAEPSHA256", encryptOAEP(crypto.SHA256, nil), &rsa.OAEPOptions{Hash: crypto.SHA256}},
+		{"OAEPSHA1", encryptOAEP(crypto.SHA1, nil), &rsa.OAEPOptions{Hash: crypto.SHA1}},
+		{"OAEPLabel", encryptOAEP(crypto.SHA256, []byte("label\x00")), &rsa.OAEPOptions{Hash: crypto.SHA256, Label: []byte("label\x00")}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ciphertext, err := tc.encrypt()
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := decrypter.Decrypt(nil, ciphertext, tc.opts)
+			if err != nil {
+				t.Fatalf("Decrypt() failed: %v", err)
+			}
+			if !bytes.Equal(got, secret) {
+				t.Errorf("Decrypt() = %q, want %q", got, secret)
+			}
+		})
+	}
+
+	ciphertext, err := rsa.EncryptOAEP(crypto.SHA256.New(), rand.Reader, pub, secret, []byte("label\x00"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	failOpts := []struct {
+		name string
+		opts crypto.DecrypterOpts
+	}{
+		{"WrongLabel", &rsa.OAEPOptions{Hash: crypto.SHA256, Label: []byte("other\x00")}},
+		{"UnterminatedLabel", &rsa.OAEPOptions{Hash: crypto.SHA256, Label: []byte("label")}},
+		{"WrongHash", &rsa.OAEPOptions{Hash: crypto.SHA384, Label: []byte("label\x00")}},
+		{"MGFHash", &rsa.OAEPOptions{Hash: crypto.SHA256, MGFHash: crypto.SHA1, Label: []byte("label\x00")}},
+		{"PKCS1v15", &rsa.PKCS1v15DecryptOptions{}},
+		{"SessionKeyLen", &rsa.PKCS1v15DecryptOptions{SessionKeyLen: 16}},
+		{"PSSOptions", &rsa.PSSOptions{}},
+	}
+	for _, o := range failOpts {
+		t.Run(o.name, func(t *testing.T) {
+			if _, err := decrypter.Decrypt(nil, ciphertext, o.opts); err == nil {
+				t.Error("Decrypt() succeeded, expected failure")
+			}
+		})
+	}
+
+	t.Run("SessionEncryption", func(t *testing.T) {
+		test.SkipForRealTPM(t)
+		ek, err := client.EndorsementKeyECC(bus)
+		if err != nil {
+			t.Fatalf("failed to create EK: %v", err)
+		}
+		defer ek.Close()
+		if err := key.SetSessionEncryption(ek); err != nil {
+			t.Fatalf("SetSessionEncryption() failed: %v", err)
+		}
+		defer key.SetSessionEncryption(nil)
+
+		ciphertext, err := rsa.EncryptPKCS1v15(rand.Reader, pub, secret)
+		if err != nil {
+			t.Fatal(err)
+		}
+		bus.traffic.Reset()
+		got, err := decrypter.Decrypt(nil, ciphertext, nil)
+		if err != nil {
+			t.Fatalf("Decrypt() failed: %v", err)
+		}
+		if !bytes.Equal(got, secret) {
+			t.Errorf("Decrypt() = %q, want %q", got, secret)
+		}
+		if bytes.Contains(bus.traffic.Bytes(), secret) {
+			t.Error("decrypted secret was sent over the TPM bus in the clear")
+		}
+	})
+}
+
+func TestECDH(t *testing.T) {
+	rwc := test.GetTPM(t)
+	defer client.CheckedClose(t, rwc)
+	bus := &busRecorder{rw: rwc}
+
+	key, err := client.NewKey(bus, tpm2.HandleOwner, client.DecryptionKeyTemplateECC())
+	if err != nil {
+		t.Fatalf("failed to create key: %v", err)
+	}
+	defer key.Close()
+	pub, err := key.PublicKey().(*ecdsa.PublicKey).ECDH()
+	if err != nil {
+		t.Fatalf("failed to convert public key: %v", err)
+	}
+
+	for i := 0; i < 5; i++ {
+		remote, err := ecdh.P256().GenerateKey(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, err := remote.ECDH(pub)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := key.ECDH(remote.PublicKey())
+		if err != nil {
+			t.Fatalf("ECDH() failed: %v", err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("ECDH() = %x, want %x", got, want)
+		}
+	}
+
+	remote, err := ecdh.P384().GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := key.ECDH(remote.PublicKey()); err == nil {
+		t.Error("ECDH() with a P-384 public key succeeded, expected failure")
+	}
+
+	t.Run("SessionEncryption", func(t *testing.T) {
+		test.SkipForRealTPM(t)
+		ek, err := client.EndorsementKeyRSA(bus)
+		if err != nil {
+			t.Fatalf("failed to create EK: %v", err)
+		}
+		defer ek.Close()
+		if err := key.SetSessionEncryption(ek); err != nil {
+			t.Fatalf("SetSessionEncryption() failed: %v", err)
+		}
+		defer key.SetSessionEncryption(nil)
+
+		remote, err := ecdh.P256().GenerateKey(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, err := remote.ECDH(pub)
+		if err != nil {
+			t.Fatal(err)
+		}
+		bus.traffic.Reset()
+		got, err := key.ECDH(remote.PublicKey())
+		if err != nil {
+			t.Fatalf("ECDH() failed: %v", err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("ECDH() = %x, want %x", got, want)
+		}
+		if bytes.Contains(bus.traffic.Bytes(), want) {
+			t.Error("shared secret was sent over the TPM bus in the clear")
+		}
+	})
+}
+
+func TestDecryptionKeyFail(t *testing.T) {
+	rwc := test.GetTPM(t)
+	defer client.CheckedClose(t, rwc)
+
+	keys := []struct {
+		name   string
+		newKey func() (*client.Key, error)
+	}{
+		{"AK", func() (*client.Key, error) { return client.AttestationKeyRSA(rwc) }},
+		{"SRK", func() (*client.Key, error) { return client.StorageRootKeyRSA(rwc) }},
+		{"ECCKey", func() (*client.Key, error) {
+			return client.NewKey(rwc, tpm2.HandleOwner, client.DecryptionKeyTemplateECC())
+		}},
+	}
+	for _, k := range keys {
+		t.Run(k.name, func(t *testing.T) {
+			key, err := k.newKey()
+			if err != nil {
+				t.Fatalf("failed to create key: %v", err)
+			}
+			defer key.Close()
+			if _, err := key.GetDecrypter(); err == nil {
+				t.Error("GetDecrypter() succeeded, expected failure")
+			}
+		})
+	}
+
+	rsaKey, err := client.NewKey(rwc, tpm2.HandleOwner, client.DecryptionKeyTemplateRSA())
+	if err != nil {
+		t.Fatalf("failed to create key: %v", err)
+	}
+	defer rsaKey.Close()
+	remote, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rsaKey.ECDH(remote.PublicKey()); err == nil {
+		t.Error("ECDH() with an RSA key succeeded, expected failure")
+	}
+}
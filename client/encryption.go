@@ -0,0 +1,185 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/go-tpm/legacy/tpm2"
+	directtpm2 "github.com/google/go-tpm/tpm2"
+	"github.com/google/go-tpm/tpm2/transport"
+	"github.com/google/go-tpm/tpmutil"
+)
+
+// sessionKeyBits is the AES key size used for parameter encryption.
+const sessionKeyBits = 128
+
+// SetSessionEncryption makes the key's Seal, Unseal, Import, Quote (and so
+// Attest) and ECDH methods, and its Decrypter, use sessions salted with
+// saltKey. These sessions encrypt the secrets sent to and from the TPM (such
+// as sealed data and passwords) with AES-CFB, and verify the HMAC of the TPM's
+// responses, so an interposer on the TPM bus or a malicious TPM transport
+// cannot read or modify them.
+//
+// The saltKey is usually the EK, and must be an RSA or ECC decryption key that
+// stays loaded while the key is used. An interposer could substitute its own
+// salt key, so it should be verified (for example, with its certificate)
+// before relying on the encryption. Passing nil disables encrypted sessions.
+func (k *Key) SetSessionEncryption(saltKey *Key) error {
+	if saltKey == nil {
+		k.salt = nil
+		return nil
+	}
+	pub := saltKey.PublicArea()
+	if pub.Type != tpm2.AlgRSA && pub.Type != tpm2.AlgECC {
+		return fmt.Errorf("unsupported salt key type: %v", pub.Type)
+	}
+	if pub.Attributes&tpm2.FlagDecrypt == 0 {
+		return errors.New("salt key must be a decryption key")
+	}
+	encoded, err := pub.Encode()
+	if err != nil {
+		return fmt.Errorf("failed to encode salt key: %w", err)
+	}
+	saltPub, err := directtpm2.Unmarshal[directtpm2.TPMTPublic](encoded)
+	if err != nil {
+		return fmt.Errorf("failed to decode salt key: %w", err)
+	}
+	k.salt = directtpm2.Salted(directtpm2.TPMHandle(saltKey.Handle()), *saltPub)
+	return nil
+}
+
+// saltedSession returns a single-use HMAC session salted with the key's salt
+// key. The key must be usable with an empty password.
+func (k *Key) saltedSession(opts ...directtpm2.AuthOption) directtpm2.Session {
+	opts = append([]directtpm2.AuthOption{k.salt}, opts...)
+	return directtpm2.HMAC(directtpm2.TPMIAlgHash(SessionHashAlgTpm), SessionHashAlg.Size(), opts...)
+}
+
+// authHandle returns the key's handle, authorized with a salted session.
+func (k *Key) authHandle(opts ...directtpm2.AuthOption) (directtpm2.AuthHandle, error) {
+	if _, ok := k.session.(NullSession); !ok {
+		return directtpm2.AuthHandle{}, errors.New("encrypted sessions require a key usable with an empty password")
+	}
+	handle, err := k.passwordHandle()
+	if err != nil {
+		return directtpm2.AuthHandle{}, err
+	}
+	handle.Auth = k.saltedSession(opts...)
+	return handle, nil
+}
+
+// secretOutputHandle returns the key's handle, authorized for a command that
+// returns a secret: with a salted session encrypting the response if the key
+// uses encrypted sessions, or with an empty password otherwise.
+func (k *Key) secretOutputHandle() (directtpm2.AuthHandle, error) {
+	if k.salt == nil {
+		return k.passwordHandle()
+	}
+	return k.authHandle(directtpm2.AESEncryption(sessionKeyBits, directtpm2.EncryptOut))
+}
+
+// createEncrypted creates a sealed object under the key, like
+// tpm2.CreateKeyWithSensitive, encrypting the sensitive data and password.
+func (k *Key) createEncrypted(inPublic tpm2.Public, password string, sensitive []byte, certifyPCRsSel tpm2.PCRSelection) (priv, pub, creationData, ticket []byte, err error) {
+	parent, err := k.authHandle(directtpm2.AESEncryption(sessionKeyBits, directtpm2.EncryptIn))
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	encodedPublic, err := inPublic.Encode()
+	if err != nil {
+		return nil, nil, nil, nil, fmt.Errorf("failed to encode public template: %w", err)
+	}
+	rsp, err := directtpm2.Create{
+		ParentHandle: parent,
+		InSensitive: directtpm2.TPM2BSensitiveCreate{
+			Sensitive: &directtpm2.TPMSSensitiveCreate{
+				UserAuth: directtpm2.TPM2BAuth{Buffer: []byte(password)},
+				Data:     directtpm2.NewTPMUSensitiveCreate(&directtpm2.TPM2BSensitiveData{Buffer: sensitive}),
+			},
+		},
+		InPublic:    directtpm2.BytesAs2B[directtpm2.TPMTPublic](encodedPublic),
+		CreationPCR: directPCRSelection(certifyPCRsSel),
+	}.Execute(transport.FromReadWriter(k.rw))
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	// The legacy encoding of a ticket is the same as a TPMT_TK_CREATION.
+	return rsp.OutPrivate.Buffer, rsp.OutPublic.Bytes(), rsp.CreationData.Bytes(), directtpm2.Marshal(rsp.CreationTicket), nil
+}
+
+// unsealEncrypted unseals a loaded object, like unsealWithPolicy, encrypting
+// the unsealed data. The policy session proves knowledge of the password with
+// an HMAC.
+func (k *Key) unsealEncrypted(object tpmutil.Handle, policy policyRunner, password string) ([]byte, error) {
+	tpm := transport.FromReadWriter(k.rw)
+	pub, err := directtpm2.ReadPublic{ObjectHandle: directtpm2.TPMHandle(object)}.Execute(tpm)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sealed object: %w", err)
+	}
+	opts := []directtpm2.AuthOption{
+		k.salt,
+		directtpm2.AESEncryption(sessionKeyBits, directtpm2.EncryptOut),
+		directtpm2.Auth([]byte(password)),
+	}
+
+	var session directtpm2.Session
+	if policy == nil {
+		session = directtpm2.HMAC(directtpm2.TPMIAlgHash(SessionHashAlgTpm), SessionHashAlg.Size(), opts...)
+	} else {
+		var closer func() error
+		session, closer, err = directtpm2.PolicySession(tpm, directtpm2.TPMIAlgHash(SessionHashAlgTpm), SessionHashAlg.Size(), opts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create session: %w", err)
+		}
+		defer closer()
+		if err := policy.runPolicy(tpmutil.Handle(session.Handle()), true); err != nil {
+			return nil, err
+		}
+	}
+	rsp, err := directtpm2.Unseal{
+		ItemHandle: directtpm2.AuthHandle{
+			Handle: directtpm2.TPMHandle(object),
+			Name:   pub.Name,
+			Auth:   session,
+		},
+	}.Execute(tpm)
+	if err != nil {
+		return nil, err
+	}
+	return rsp.OutData.Buffer, nil
+}
+
+// quoteEncrypted is tpm2.QuoteRaw, verifying the HMAC of the response.
+func (k *Key) quoteEncrypted(selpcr tpm2.PCRSelection, extraData []byte) (quoted, rawSig []byte, err error) {
+	signer, err := k.authHandle(directtpm2.AESEncryption(sessionKeyBits, directtpm2.EncryptInOut))
+	if err != nil {
+		return nil, nil, err
+	}
+	rsp, err := directtpm2.Quote{
+		SignHandle:     signer,
+		QualifyingData: directtpm2.TPM2BData{Buffer: extraData},
+		InScheme:       directtpm2.TPMTSigScheme{Scheme: directtpm2.TPMAlgNull},
+		PCRSelect:      directPCRSelection(selpcr),
+	}.Execute(transport.FromReadWriter(k.rw))
+	if err != nil {
+		return nil, nil, err
+	}
+	return rsp.Quoted.Bytes(), directtpm2.Marshal(rsp.Signature), nil
+}
+
+// directPCRSelection converts a legacy PCR selection.
+func directPCRSelection(sel tpm2.PCRSelection) directtpm2.TPMLPCRSelection {
+	if len(sel.PCRs) == 0 {
+		return directtpm2.TPMLPCRSelection{}
+	}
+	pcrs := make([]uint, len(sel.PCRs))
+	for i, pcr := range sel.PCRs {
+		pcrs[i] = uint(pcr)
+	}
+	return directtpm2.TPMLPCRSelection{
+		PCRSelections: []directtpm2.TPMSPCRSelection{{
+			Hash:      directtpm2.TPMIAlgHash(sel.Hash),
+			PCRSelect: directtpm2.PCClientCompatible.PCRs(pcrs...),
+		}},
+	}
+}
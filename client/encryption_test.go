@@ -0,0 +1,183 @@
+package client_test
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"testing"
+
+	"github.com/google/go-tpm-tools/client"
+	"github.com/google/go-tpm-tools/internal/test"
+	"github.com/google/go-tpm/legacy/tpm2"
+)
+
+// busRecorder records all the traffic to and from a TPM.
+type busRecorder struct {
+	rw      io.ReadWriter
+	traffic bytes.Buffer
+}
+
+func (b *busRecorder) Read(p []byte) (int, error) {
+	n, err := b.rw.Read(p)
+	b.traffic.Write(p[:n])
+	return n, err
+}
+
+func (b *busRecorder) Write(p []byte) (int, error) {
+	b.traffic.Write(p)
+	return b.rw.Write(p)
+}
+
+func TestSealWithSessionEncryption(t *testing.T) {
+	test.SkipForRealTPM(t)
+	rwc := test.GetTPM(t)
+	defer client.CheckedClose(t, rwc)
+	bus := &busRecorder{rw: rwc}
+
+	ek, err := client.EndorsementKeyRSA(bus)
+	if err != nil {
+		t.Fatalf("failed to create EK: %v", err)
+	}
+	defer ek.Close()
+	srk, err := client.StorageRootKeyECC(bus)
+	if err != nil {
+		t.Fatalf("failed to create SRK: %v", err)
+	}
+	defer srk.Close()
+
+	pcrSel := tpm2.PCRSelection{Hash: tpm2.AlgSHA256, PCRs: []int{7, test.DebugPCR}}
+	pcrs, err := client.ReadPCRs(rwc, pcrSel)
+	if err != nil {
+		t.Fatalf("failed to read PCRs: %v", err)
+	}
+	policy := client.NewPolicy().PolicyPCR(pcrs).PolicyAuthValue().PolicyClockBefore(math.MaxUint64)
+	tests := []struct {
+		name   string
+		sOpts  client.SealOpts
+		uOpts  client.UnsealOpts
+		secret string
+	}{
+		{"NoPCRs", client.SealOpts{}, client.UnsealOpts{}, "no PCRs secret"},
+		{"Password", client.SealOpts{Password: "password1"}, client.UnsealOpts{Password: "password1"}, "password secret"},
+		{"PCRs", client.SealOpts{Current: pcrSel}, client.UnsealOpts{CertifyCurrent: pcrSel}, "PCR secret"},
+		{"Policy", client.SealOpts{Policy: policy, Password: "password2"}, client.UnsealOpts{Policy: policy, Password: "password2"}, "policy secret"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			secret := []byte(tc.secret)
+			if err := srk.SetSessionEncryption(ek); err != nil {
+				t.Fatalf("SetSessionEncryption() failed: %v", err)
+			}
+			bus.traffic.Reset()
+			sealed, err := srk.Seal(secret, tc.sOpts)
+			if err != nil {
+				t.Fatalf("failed to seal: %v", err)
+			}
+			unsealed, err := srk.Unseal(sealed, tc.uOpts)
+			if err != nil {
+				t.Fatalf("failed to unseal: %v", err)
+			}
+			if !bytes.Equal(unsealed, secret) {
+				t.Errorf("unsealed (%v) not equal to secret (%v)", unsealed, secret)
+			}
+			if bytes.Contains(bus.traffic.Bytes(), secret) {
+				t.Error("secret was sent over the TPM bus in the clear")
+			}
+			if tc.uOpts.Password != "" && bytes.Contains(bus.traffic.Bytes(), []byte(tc.uOpts.Password)) {
+				t.Error("password was sent over the TPM bus in the clear")
+			}
+
+			// Encrypted sessions do not change the sealed data.
+			if err := srk.SetSessionEncryption(nil); err != nil {
+				t.Fatalf("SetSessionEncryption(nil) failed: %v", err)
+			}
+			bus.traffic.Reset()
+			if unsealed, err = srk.Unseal(sealed, tc.uOpts); err != nil {
+				t.Fatalf("failed to unseal without encrypted sessions: %v", err)
+			}
+			if !bytes.Equal(unsealed, secret) {
+				t.Errorf("unsealed (%v) not equal to secret (%v)", unsealed, secret)
+			}
+			if !bytes.Contains(bus.traffic.Bytes(), secret) {
+				t.Error("secret was not sent in the clear without encrypted sessions")
+			}
+		})
+	}
+
+	if err := srk.SetSessionEncryption(ek); err != nil {
+		t.Fatalf("SetSessionEncryption() failed: %v", err)
+	}
+	sealed, err := srk.Seal([]byte("test"), client.SealOpts{Password: "password"})
+	if err != nil {
+		t.Fatalf("failed to seal: %v", err)
+	}
+	if _, err := srk.Unseal(sealed, client.UnsealOpts{Password: "wrong"}); err == nil {
+		t.Error("unsealing with the wrong password succeeded, expected failure")
+	}
+}
+
+func TestQuoteWithSessionEncryption(t *testing.T) {
+	test.SkipForRealTPM(t)
+	rwc := test.GetTPM(t)
+	defer client.CheckedClose(t, rwc)
+	bus := &busRecorder{rw: rwc}
+
+	ek, err := client.EndorsementKeyECC(bus)
+	if err != nil {
+		t.Fatalf("failed to create EK: %v", err)
+	}
+	defer ek.Close()
+	for _, newAK := range []func(io.ReadWriter) (*client.Key, error){client.AttestationKeyRSA, client.AttestationKeyECC} {
+		ak, err := newAK(bus)
+		if err != nil {
+			t.Fatalf("failed to create AK: %v", err)
+		}
+		defer ak.Close()
+		if err := ak.SetSessionEncryption(ek); err != nil {
+			t.Fatalf("SetSessionEncryption() failed: %v", err)
+		}
+
+		nonce := []byte("super secret nonce")
+		bus.traffic.Reset()
+		sel := tpm2.PCRSelection{Hash: tpm2.AlgSHA256, PCRs: []int{0, 7, test.DebugPCR}}
+		// Quote verifies the quote before returning it.
+		if _, err := ak.Quote(sel, nonce); err != nil {
+			t.Fatalf("failed to quote: %v", err)
+		}
+		if bytes.Contains(bus.traffic.Bytes(), nonce) {
+			t.Error("nonce was sent over the TPM bus in the clear")
+		}
+	}
+}
+
+func TestSetSessionEncryptionFail(t *testing.T) {
+	rwc := test.GetTPM(t)
+	defer client.CheckedClose(t, rwc)
+
+	ak, err := client.AttestationKeyECC(rwc)
+	if err != nil {
+		t.Fatalf("failed to create AK: %v", err)
+	}
+	defer ak.Close()
+	srk, err := client.StorageRootKeyRSA(rwc)
+	if err != nil {
+		t.Fatalf("failed to create SRK: %v", err)
+	}
+	defer srk.Close()
+	if err := srk.SetSessionEncryption(ak); err == nil {
+		t.Error("using a signing key as the salt key succeeded, expected failure")
+	}
+
+	ek, err := client.EndorsementKeyRSA(rwc)
+	if err != nil {
+		t.Fatalf("failed to create EK: %v", err)
+	}
+	defer ek.Close()
+	// The EK is authorized with a policy session.
+	if err := ek.SetSessionEncryption(srk); err != nil {
+		t.Fatalf("SetSessionEncryption() failed: %v", err)
+	}
+	if _, err := ek.Seal([]byte("test"), client.SealOpts{}); err == nil {
+		t.Error("sealing to the EK with encrypted sessions succeeded, expected failure")
+	}
+}
@@ -0,0 +1,92 @@
+package client
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	pb "github.com/google/go-tpm-tools/proto/tpm"
+)
+
+const (
+	// envelopeVersion is the format version of EnvelopeSealedBytes.
+	envelopeVersion = 1
+	// envelopeKeySize is the size of the AES-256-GCM data key.
+	envelopeKeySize = 32
+)
+
+// SealEnvelope seals a payload of any size to the key, which must be an SRK.
+// Seal is limited to small secrets (the TPM's MAX_SYM_DATA, usually 128 bytes),
+// so SealEnvelope encrypts the payload with a random AES-256-GCM data key,
+// and seals the data key with Seal using opts.
+func (k *Key) SealEnvelope(payload []byte, opts SealOpts) (*pb.EnvelopeSealedBytes, error) {
+	dataKey := make([]byte, envelopeKeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, fmt.Errorf("failed to generate data key: %w", err)
+	}
+	aead, err := envelopeAEAD(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	sealedKey, err := k.Seal(dataKey, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to seal data key: %w", err)
+	}
+	return &pb.EnvelopeSealedBytes{
+		Version:    envelopeVersion,
+		SealedKey:  sealedKey,
+		Nonce:      nonce,
+		Ciphertext: aead.Seal(nil, nonce, payload, envelopeAdditionalData(envelopeVersion)),
+	}, nil
+}
+
+// UnsealEnvelope reverses SealEnvelope, unsealing the data key with Unseal
+// using opts, and decrypting the payload.
+func (k *Key) UnsealEnvelope(in *pb.EnvelopeSealedBytes, opts UnsealOpts) ([]byte, error) {
+	if in.GetVersion() != envelopeVersion {
+		return nil, fmt.Errorf("unsupported envelope version %d, expected %d", in.GetVersion(), envelopeVersion)
+	}
+	if in.GetSealedKey() == nil {
+		return nil, errors.New("envelope has no sealed data key")
+	}
+	dataKey, err := k.Unseal(in.GetSealedKey(), opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unseal data key: %w", err)
+	}
+	aead, err := envelopeAEAD(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(in.GetNonce()) != aead.NonceSize() {
+		return nil, fmt.Errorf("invalid nonce size %d, expected %d", len(in.GetNonce()), aead.NonceSize())
+	}
+	payload, err := aead.Open(nil, in.GetNonce(), in.GetCiphertext(), envelopeAdditionalData(in.GetVersion()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt payload: %w", err)
+	}
+	return payload, nil
+}
+
+func envelopeAEAD(dataKey []byte) (cipher.AEAD, error) {
+	if len(dataKey) != envelopeKeySize {
+		return nil, fmt.Errorf("invalid data key size %d, expected %d", len(dataKey), envelopeKeySize)
+	}
+	block, err := aes.NewCipher(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// envelopeAdditionalData binds the ciphertext to the envelope's version.
+func envelopeAdditionalData(version uint32) []byte {
+	return binary.BigEndian.AppendUint32(nil, version)
+}
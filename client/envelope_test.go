@@ -0,0 +1,80 @@
+package client_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/google/go-tpm/legacy/tpm2"
+	"github.com/google/go-tpm/tpmutil"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/google/go-tpm-tools/client"
+	"github.com/google/go-tpm-tools/internal/test"
+	pb "github.com/google/go-tpm-tools/proto/tpm"
+)
+
+func TestSealEnvelope(t *testing.T) {
+	rwc := test.GetTPM(t)
+	defer client.CheckedClose(t, rwc)
+
+	srk, err := client.StorageRootKeyECC(rwc)
+	if err != nil {
+		t.Fatalf("failed to create SRK: %v", err)
+	}
+	defer srk.Close()
+
+	// Much larger than the TPM's MAX_SYM_DATA.
+	payload := make([]byte, 1<<20)
+	if _, err := rand.Read(payload); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := srk.Seal(payload, client.SealOpts{}); err == nil {
+		t.Fatal("sealing a large payload without an envelope succeeded, expected failure")
+	}
+
+	pcrToChange := test.DebugPCR
+	sel := tpm2.PCRSelection{Hash: tpm2.AlgSHA256, PCRs: []int{7, pcrToChange}}
+	sealed, err := srk.SealEnvelope(payload, client.SealOpts{Current: sel})
+	if err != nil {
+		t.Fatalf("failed to seal: %v", err)
+	}
+	opts := client.UnsealOpts{CertifyCurrent: tpm2.PCRSelection{Hash: tpm2.AlgSHA256, PCRs: []int{7}}}
+	unsealed, err := srk.UnsealEnvelope(sealed, opts)
+	if err != nil {
+		t.Fatalf("failed to unseal: %v", err)
+	}
+	if !bytes.Equal(unsealed, payload) {
+		t.Fatal("unsealed payload not equal to the sealed payload")
+	}
+
+	modifications := []struct {
+		name   string
+		modify func(*pb.EnvelopeSealedBytes)
+	}{
+		{"Version", func(e *pb.EnvelopeSealedBytes) { e.Version++ }},
+		{"Nonce", func(e *pb.EnvelopeSealedBytes) { e.Nonce[0] ^= 1 }},
+		{"ShortNonce", func(e *pb.EnvelopeSealedBytes) { e.Nonce = e.Nonce[1:] }},
+		{"Ciphertext", func(e *pb.EnvelopeSealedBytes) { e.Ciphertext[0] ^= 1 }},
+		{"TruncatedCiphertext", func(e *pb.EnvelopeSealedBytes) { e.Ciphertext = e.Ciphertext[:len(e.Ciphertext)-1] }},
+		{"NoSealedKey", func(e *pb.EnvelopeSealedBytes) { e.SealedKey = nil }},
+	}
+	for _, m := range modifications {
+		t.Run(m.name, func(t *testing.T) {
+			modified := proto.Clone(sealed).(*pb.EnvelopeSealedBytes)
+			m.modify(modified)
+			if _, err := srk.UnsealEnvelope(modified, opts); err == nil {
+				t.Error("unsealing a modified envelope succeeded, expected failure")
+			}
+		})
+	}
+
+	extension := bytes.Repeat([]byte{0xAA}, sha256.Size)
+	if err = tpm2.PCRExtend(rwc, tpmutil.Handle(pcrToChange), tpm2.AlgSHA256, extension, ""); err != nil {
+		t.Fatalf("failed to extend pcr: %v", err)
+	}
+	if _, err = srk.UnsealEnvelope(sealed, opts); err == nil {
+		t.Error("unsealing after the PCRs changed succeeded, expected failure")
+	}
+}
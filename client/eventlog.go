@@ -17,3 +17,9 @@ func GetEventLog(rw io.ReadWriter) ([]byte, error) {
 type EventLogGetter interface {
 	EventLog() ([]byte, error)
 }
+
+// GetIMAMeasurementList grabs the Linux IMA runtime measurement list for the
+// system, in the binary format.
+func GetIMAMeasurementList() ([]byte, error) {
+	return getRealIMAMeasurementList()
+}
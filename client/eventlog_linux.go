@@ -5,3 +5,7 @@ import "os"
 func getRealEventLog() ([]byte, error) {
 	return os.ReadFile("/sys/kernel/security/tpm0/binary_bios_measurements")
 }
+
+func getRealIMAMeasurementList() ([]byte, error) {
+	return os.ReadFile("/sys/kernel/security/ima/binary_runtime_measurements")
+}
@@ -8,3 +8,7 @@ import "errors"
 func getRealEventLog() ([]byte, error) {
 	return nil, errors.New("failed to get event log: only Linux supported")
 }
+
+func getRealIMAMeasurementList() ([]byte, error) {
+	return nil, errors.New("failed to get IMA measurement list: only Linux supported")
+}
@@ -34,23 +34,31 @@ func loadHandle(k *Key, blob *pb.ImportBlob) (tpmutil.Handle, error) {
 // The key used must be an encryption key (signing keys cannot be used).
 // The req parameter should come from server.CreateImportBlob.
 func (k *Key) Import(blob *pb.ImportBlob) ([]byte, error) {
-	handle, err := loadHandle(k, blob)
-	if err != nil {
-		return nil, err
+	var policy policyRunner
+	if sel := internal.PCRSelection(blob.Pcrs); len(sel.PCRs) != 0 {
+		policy = PCRSession{rw: k.rw, sel: sel}
 	}
-	defer tpm2.FlushContext(k.rw, handle)
+	return importHelper(k, blob, policy)
+}
 
-	unsealSession, err := NewPCRSession(k.rw, internal.PCRSelection(blob.Pcrs))
-	if err != nil {
+// ImportWithPolicy decrypts the secret contained in an encoded import request
+// whose authPolicy is the digest of the given Policy. The req parameter should
+// come from server.CreateImportBlobWithPolicy.
+func (k *Key) ImportWithPolicy(blob *pb.ImportBlob, policy *Policy) ([]byte, error) {
+	if err := checkPolicyDigest(blob.GetPublicArea(), policy); err != nil {
 		return nil, err
 	}
-	defer unsealSession.Close()
+	return importHelper(k, blob, PolicySession{rw: k.rw, policy: policy})
+}
 
-	auth, err := unsealSession.Auth()
+func importHelper(k *Key, blob *pb.ImportBlob, policy policyRunner) ([]byte, error) {
+	handle, err := loadHandle(k, blob)
 	if err != nil {
 		return nil, err
 	}
-	out, err := tpm2.UnsealWithSession(k.rw, auth.Session, handle, "")
+	defer tpm2.FlushContext(k.rw, handle)
+
+	out, err := k.unsealWithPolicy(handle, policy, "")
 	if err != nil {
 		return nil, fmt.Errorf("unseal failed: %w", err)
 	}
@@ -9,10 +9,12 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"slices"
 
 	"github.com/google/go-tpm-tools/internal"
 	pb "github.com/google/go-tpm-tools/proto/tpm"
 	"github.com/google/go-tpm/legacy/tpm2"
+	directtpm2 "github.com/google/go-tpm/tpm2"
 	"github.com/google/go-tpm/tpmutil"
 )
 
@@ -29,6 +31,8 @@ type Key struct {
 	name    tpm2.Name
 	session Session
 	cert    *x509.Certificate
+	// salt is set to salt the sessions of key operations that send secrets.
+	salt directtpm2.AuthOption
 }
 
 // EndorsementKeyRSA generates and loads a key from DefaultEKTemplateRSA.
@@ -184,18 +188,20 @@ func NewCachedKey(rw io.ReadWriter, parent tpmutil.Handle, template tpm2.Public,
 //   - If parent is tpm2.Handle{Owner|Endorsement|Platform|Null} a primary key
 //     is created in the specified hierarchy (using CreatePrimary).
 //   - If parent is a valid key handle, a normal key object is created under
-//     that parent (using Create and Load). NOTE: Not yet supported.
+//     that parent (using Create and Load). The parent (such as an SRK) must be
+//     usable with an empty password.
 //
 // This function also assumes that the desired key:
 //   - Does not have its usage locked to specific PCR values
 //   - Usable with empty authorization sessions (i.e. doesn't need a password)
 func NewKey(rw io.ReadWriter, parent tpmutil.Handle, template tpm2.Public) (k *Key, err error) {
-	if !isHierarchy(parent) {
-		// TODO add support for normal objects with Create() and Load()
-		return nil, fmt.Errorf("unsupported parent handle: %x", parent)
+	var handle tpmutil.Handle
+	var pubArea []byte
+	if isHierarchy(parent) {
+		handle, pubArea, _, _, _, _, err = tpm2.CreatePrimaryEx(rw, parent, tpm2.PCRSelection{}, "", "", template)
+	} else {
+		handle, pubArea, err = createAndLoad(rw, parent, template)
 	}
-
-	handle, pubArea, _, _, _, _, err := tpm2.CreatePrimaryEx(rw, parent, tpm2.PCRSelection{}, "", "", template)
 	if err != nil {
 		return nil, err
 	}
@@ -212,10 +218,59 @@ func NewKey(rw io.ReadWriter, parent tpmutil.Handle, template tpm2.Public) (k *K
 	return k, k.finish()
 }
 
+// createAndLoad creates a key from the template under the parent key, and
+// loads it.
+func createAndLoad(rw io.ReadWriter, parent tpmutil.Handle, template tpm2.Public) (tpmutil.Handle, []byte, error) {
+	private, public, _, _, _, err := tpm2.CreateKey(rw, parent, tpm2.PCRSelection{}, "", "", template)
+	if err != nil {
+		return tpmutil.Handle(0), nil, err
+	}
+	handle, _, err := tpm2.Load(rw, parent, "", public, private)
+	if err != nil {
+		return tpmutil.Handle(0), nil, err
+	}
+	return handle, public, nil
+}
+
+// CreateChildKey creates a key from the template under the loaded Key (such
+// as an SRK), without loading it, and returns its public area and its private
+// area wrapped by the Key. Unlike NewKey under the Key, the returned blobs can
+// be stored and loaded with LoadChildKey when needed, so the child key does
+// not use a TPM object slot in between. The Key must be usable with an empty
+// password.
+func (k *Key) CreateChildKey(template tpm2.Public) (public, private []byte, err error) {
+	private, public, _, _, _, err = tpm2.CreateKey(k.rw, k.Handle(), tpm2.PCRSelection{}, "", "", template)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create child key: %w", err)
+	}
+	return public, private, nil
+}
+
+// LoadChildKey loads a key created with CreateChildKey under the same parent
+// Key. The returned Key must be closed when no longer needed.
+func (k *Key) LoadChildKey(public, private []byte) (*Key, error) {
+	handle, _, err := tpm2.Load(k.rw, k.Handle(), "", public, private)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load child key: %w", err)
+	}
+	child := &Key{rw: k.rw, handle: handle}
+	if child.pubArea, err = tpm2.DecodePublic(public); err == nil {
+		err = child.finish()
+	}
+	if err != nil {
+		tpm2.FlushContext(k.rw, handle)
+		return nil, err
+	}
+	return child, nil
+}
+
 func (k *Key) finish() error {
 	var err error
-	if k.pubKey, err = k.pubArea.Key(); err != nil {
-		return err
+	// Symmetric keys have no public key.
+	if k.pubArea.Type == tpm2.AlgRSA || k.pubArea.Type == tpm2.AlgECC {
+		if k.pubKey, err = k.pubArea.Key(); err != nil {
+			return err
+		}
 	}
 	if k.name, err = k.pubArea.Name(); err != nil {
 		return err
@@ -253,7 +308,8 @@ func (k *Key) PublicArea() tpm2.Public {
 	return k.pubArea
 }
 
-// PublicKey provides a go interface to the loaded key's public area.
+// PublicKey provides a go interface to the loaded key's public area. It is nil
+// for symmetric (AES and HMAC) keys.
 func (k *Key) PublicKey() crypto.PublicKey {
 	return k.pubKey
 }
@@ -272,22 +328,61 @@ func (k *Key) Close() {
 // be modified to provide sealed-to PCRs. In this case, the sensitive data can
 // only be unsealed if the seal-time PCRs are in the SealOpts-specified state.
 // There must not be overlap in PCRs between SealOpts' Current and Target.
+// Alternatively, SealOpts.PolicySigner can be set to seal the data to any PCR
+// values approved by the signer, or SealOpts.Policy to seal the data to a
+// compound Policy. SealOpts.RollbackCounter additionally binds the data to the
+// current value of an NV counter, so that stale data cannot be unsealed.
 // During the sealing process, certification data will be created allowing
 // Unseal() to validate the state of the TPM during the sealing process.
 func (k *Key) Seal(sensitive []byte, opts SealOpts) (*pb.SealedBytes, error) {
 	var pcrs *pb.PCRs
 	var err error
 	var auth []byte
+	var policySigner []byte
 
-	pcrs, err = mergePCRSelAndProto(k.rw, opts.Current, opts.Target)
-	if err != nil {
-		return nil, fmt.Errorf("invalid SealOpts: %v", err)
+	switch {
+	case opts.Policy != nil:
+		if len(opts.Current.PCRs) != 0 || opts.Target != nil || opts.PolicySigner != nil {
+			return nil, errors.New("invalid SealOpts: Policy cannot be used with Current, Target or PolicySigner")
+		}
+		if auth, err = opts.Policy.Digest(); err != nil {
+			return nil, fmt.Errorf("invalid SealOpts: %v", err)
+		}
+	case opts.PolicySigner != nil:
+		if len(opts.Current.PCRs) != 0 || opts.Target != nil {
+			return nil, errors.New("invalid SealOpts: PolicySigner cannot be used with Current or Target")
+		}
+		policySigner, auth, err = policyAuthorizeAuth(opts.PolicySigner)
+		if err != nil {
+			return nil, fmt.Errorf("invalid SealOpts: %v", err)
+		}
+	default:
+		pcrs, err = mergePCRSelAndProto(k.rw, opts.Current, opts.Target)
+		if err != nil {
+			return nil, fmt.Errorf("invalid SealOpts: %v", err)
+		}
+		if len(pcrs.GetPcrs()) > 0 {
+			auth = internal.PCRSessionAuth(pcrs, SessionHashAlg)
+		}
 	}
-	if len(pcrs.GetPcrs()) > 0 {
-		auth = internal.PCRSessionAuth(pcrs, SessionHashAlg)
+	var counterValue uint64
+	if opts.RollbackCounter != 0 {
+		if counterValue, err = RollbackCounterValue(k.rw, opts.RollbackCounter); err != nil {
+			return nil, err
+		}
+		counter, err := rollbackCounterStep(k.rw, opts.RollbackCounter, counterValue)
+		if err != nil {
+			return nil, err
+		}
+		if auth == nil {
+			auth = updatePolicy(make([]byte, SessionHashAlg.Size()), cmdPolicyAuthValue)
+		}
+		if auth, err = counter.update(auth); err != nil {
+			return nil, err
+		}
 	}
 	certifySel := FullPcrSel(CertifyHashAlgTpm)
-	sb, err := sealHelper(k.rw, k.Handle(), auth, sensitive, certifySel)
+	sb, err := sealHelper(k, auth, opts.Password, sensitive, certifySel)
 	if err != nil {
 		return nil, err
 	}
@@ -297,10 +392,35 @@ func (k *Key) Seal(sensitive []byte, opts SealOpts) (*pb.SealedBytes, error) {
 	}
 	sb.Hash = pcrs.GetHash()
 	sb.Srk = pb.ObjectType(k.pubArea.Type)
+	sb.PolicySigner = policySigner
+	sb.RollbackCounter = uint32(opts.RollbackCounter)
+	sb.RollbackCounterValue = counterValue
 	return sb, nil
 }
 
-func sealHelper(rw io.ReadWriter, parentHandle tpmutil.Handle, auth []byte, sensitive []byte, certifyPCRsSel tpm2.PCRSelection) (*pb.SealedBytes, error) {
+// policyAuthorizeAuth returns the encoded public area of a policy signer, and
+// the authorization value for its TPM2_PolicyAuthorize policy.
+func policyAuthorizeAuth(signer crypto.PublicKey) ([]byte, []byte, error) {
+	pub, err := policySignerTemplate(signer)
+	if err != nil {
+		return nil, nil, err
+	}
+	encoded, err := pub.Encode()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode policy signer: %w", err)
+	}
+	name, err := pub.Name()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to compute policy signer name: %w", err)
+	}
+	nameBytes, err := name.Digest.Encode()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode policy signer name: %w", err)
+	}
+	return encoded, internal.PolicyAuthorizeAuth(nameBytes, SessionHashAlg), nil
+}
+
+func sealHelper(parent *Key, auth []byte, password string, sensitive []byte, certifyPCRsSel tpm2.PCRSelection) (*pb.SealedBytes, error) {
 	inPublic := tpm2.Public{
 		Type:       tpm2.AlgKeyedHash,
 		NameAlg:    SessionHashAlgTpm,
@@ -313,11 +433,21 @@ func sealHelper(rw io.ReadWriter, parentHandle tpmutil.Handle, auth []byte, sens
 		inPublic.Attributes |= tpm2.FlagAdminWithPolicy
 	}
 
-	priv, pub, creationData, _, ticket, err := tpm2.CreateKeyWithSensitive(rw, parentHandle, certifyPCRsSel, "", "", inPublic, sensitive)
+	var priv, pub, creationData, ticket []byte
+	var err error
+	if parent.salt != nil {
+		priv, pub, creationData, ticket, err = parent.createEncrypted(inPublic, password, sensitive, certifyPCRsSel)
+	} else {
+		var legacyTicket tpm2.Ticket
+		priv, pub, creationData, _, legacyTicket, err = tpm2.CreateKeyWithSensitive(parent.rw, parent.Handle(), certifyPCRsSel, "", password, inPublic, sensitive)
+		if err == nil {
+			ticket, err = tpmutil.Pack(legacyTicket)
+		}
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to create key: %w", err)
 	}
-	certifiedPcr, err := ReadPCRs(rw, certifyPCRsSel)
+	certifiedPcr, err := ReadPCRs(parent.rw, certifyPCRsSel)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read PCRs: %w", err)
 	}
@@ -338,16 +468,16 @@ func sealHelper(rw io.ReadWriter, parentHandle tpmutil.Handle, auth []byte, sens
 	sb.Priv = priv
 	sb.Pub = pub
 	sb.CreationData = creationData
-	if sb.Ticket, err = tpmutil.Pack(ticket); err != nil {
-		return nil, err
-	}
+	sb.Ticket = ticket
 	return sb, nil
 }
 
 // Unseal attempts to reverse the process of Seal(), using the PCRs, public, and
 // private data in proto.SealedBytes. Optionally, the UnsealOpts parameter can
 // be used to verify the state of the TPM when the data was sealed. The
-// zero-value UnsealOpts can be passed to skip certification.
+// zero-value UnsealOpts can be passed to skip certification. Data sealed with
+// SealOpts.PolicySigner also requires UnsealOpts.SignedPolicy, and data sealed
+// with SealOpts.Policy requires the same UnsealOpts.Policy.
 func (k *Key) Unseal(in *pb.SealedBytes, opts UnsealOpts) ([]byte, error) {
 	if in.Srk != pb.ObjectType(k.pubArea.Type) {
 		return nil, fmt.Errorf("expected key of type %v, got %v", in.Srk, k.pubArea.Type)
@@ -419,22 +549,88 @@ func (k *Key) Unseal(in *pb.SealedBytes, opts UnsealOpts) ([]byte, error) {
 		}
 	}
 
-	sel := tpm2.PCRSelection{Hash: tpm2.Algorithm(in.GetHash())}
-	for _, pcr := range in.GetPcrs() {
-		sel.PCRs = append(sel.PCRs, int(pcr))
+	var counter *nvStep
+	if in.GetRollbackCounter() != 0 {
+		if counter, err = rollbackCounterStep(k.rw, tpmutil.Handle(in.GetRollbackCounter()), in.GetRollbackCounterValue()); err != nil {
+			return nil, err
+		}
 	}
 
-	session, err := NewPCRSession(k.rw, sel)
+	var policy policyRunner
+	switch {
+	case len(in.GetPolicySigner()) != 0:
+		if opts.SignedPolicy == nil {
+			return nil, errors.New("invalid UnsealOpts: SignedPolicy is required to unseal data sealed with a PolicySigner")
+		}
+		if len(opts.SignedPolicy.GetPcrs().GetPcrs()) == 0 {
+			return nil, errors.New("invalid UnsealOpts: signed PCR policy has no PCRs")
+		}
+		policy = AuthorizedPCRSession{rw: k.rw, policySigner: in.GetPolicySigner(), policy: opts.SignedPolicy}
+	case opts.SignedPolicy != nil:
+		return nil, errors.New("invalid UnsealOpts: SignedPolicy can only be used with data sealed with a PolicySigner")
+	case opts.Policy != nil:
+		checked := opts.Policy
+		if counter != nil {
+			checked = &Policy{append(slices.Clip(opts.Policy.steps), *counter)}
+		}
+		if err := checkPolicyDigest(in.GetPub(), checked); err != nil {
+			return nil, fmt.Errorf("invalid UnsealOpts: %w", err)
+		}
+		policy = PolicySession{rw: k.rw, policy: opts.Policy}
+	default:
+		sel := tpm2.PCRSelection{Hash: tpm2.Algorithm(in.GetHash())}
+		for _, pcr := range in.GetPcrs() {
+			sel.PCRs = append(sel.PCRs, int(pcr))
+		}
+		if len(sel.PCRs) != 0 {
+			policy = PCRSession{rw: k.rw, sel: sel}
+		}
+	}
+	if counter != nil {
+		if policy == nil {
+			policy = PolicySession{rw: k.rw, policy: NewPolicy().PolicyAuthValue()}
+		}
+		policy = rollbackSession{rw: k.rw, base: policy, counter: counter}
+	}
+	return k.unsealWithPolicy(sealed, policy, opts.Password)
+}
+
+// unsealWithPolicy unseals a loaded object, authorizing it with a policy
+// session that satisfies policy or, if policy is nil, with the password.
+func (k *Key) unsealWithPolicy(object tpmutil.Handle, policy policyRunner, password string) ([]byte, error) {
+	if k.salt != nil {
+		return k.unsealEncrypted(object, policy, password)
+	}
+	if policy == nil {
+		return tpm2.UnsealWithSession(k.rw, tpm2.HandlePasswordSession, object, password)
+	}
+	session, err := startAuthSession(k.rw)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create session: %w", err)
 	}
-	defer session.Close()
+	defer tpm2.FlushContext(k.rw, session)
 
-	auth, err := session.Auth()
-	if err != nil {
+	if err := policy.runPolicy(session, false); err != nil {
 		return nil, err
 	}
-	return tpm2.UnsealWithSession(k.rw, auth.Session, sealed, "")
+	return tpm2.UnsealWithSession(k.rw, session, object, password)
+}
+
+// checkPolicyDigest checks that the policy's digest matches the authPolicy of
+// an encoded public area.
+func checkPolicyDigest(encodedPub []byte, policy *Policy) error {
+	pub, err := tpm2.DecodePublic(encodedPub)
+	if err != nil {
+		return fmt.Errorf("failed to decode public area: %w", err)
+	}
+	digest, err := policy.Digest()
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(digest, pub.AuthPolicy) {
+		return errors.New("policy digest does not match the object's authPolicy")
+	}
+	return nil
 }
 
 // Quote will tell TPM to compute a hash of a set of given PCR selection, together with
@@ -452,7 +648,11 @@ func (k *Key) Quote(selpcr tpm2.PCRSelection, extraData []byte) (*pb.Quote, erro
 	}
 
 	quote := &pb.Quote{}
-	quote.Quote, quote.RawSig, err = tpm2.QuoteRaw(k.rw, k.Handle(), "", "", extraData, selpcr, tpm2.AlgNull)
+	if k.salt != nil {
+		quote.Quote, quote.RawSig, err = k.quoteEncrypted(selpcr, extraData)
+	} else {
+		quote.Quote, quote.RawSig, err = tpm2.QuoteRaw(k.rw, k.Handle(), "", "", extraData, selpcr, tpm2.AlgNull)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to quote: %w", err)
 	}
@@ -2,8 +2,10 @@ package client_test
 
 import (
 	"crypto"
+	"crypto/ecdsa"
 	"crypto/rand"
 	"crypto/rsa"
+	"crypto/sha256"
 	"crypto/x509"
 	"io"
 	"math/big"
@@ -300,3 +302,70 @@ func TestLoadCachedKey(t *testing.T) {
 		})
 	}
 }
+
+func TestNewKeyUnderSRK(t *testing.T) {
+	rwc := test.GetTPM(t)
+	defer client.CheckedClose(t, rwc)
+
+	srk, err := client.StorageRootKeyECC(rwc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := client.NewKey(rwc, srk.Handle(), templateECC(tpm2.AlgSHA256))
+	srk.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer key.Close()
+
+	verifySignData(t, key)
+}
+
+func TestLoadChildKey(t *testing.T) {
+	rwc := test.GetTPM(t)
+	defer client.CheckedClose(t, rwc)
+
+	srk, err := client.StorageRootKeyECC(rwc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer srk.Close()
+	public, private, err := srk.CreateChildKey(templateECC(tpm2.AlgSHA256))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The child key is the same each time it is loaded.
+	var pubKey crypto.PublicKey
+	for i := 0; i < 2; i++ {
+		key, err := srk.LoadChildKey(public, private)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pubKey != nil && !reflect.DeepEqual(key.PublicKey(), pubKey) {
+			t.Error("LoadChildKey() returned a different key")
+		}
+		pubKey = key.PublicKey()
+		verifySignData(t, key)
+		key.Close()
+	}
+
+	private[len(private)-1] ^= 1
+	if key, err := srk.LoadChildKey(public, private); err == nil {
+		key.Close()
+		t.Error("LoadChildKey() succeeded with a corrupted private area")
+	}
+}
+
+func verifySignData(t *testing.T, key *client.Key) {
+	t.Helper()
+	data := []byte("signed data")
+	sig, err := key.SignData(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest := sha256.Sum256(data)
+	if !ecdsa.VerifyASN1(key.PublicKey().(*ecdsa.PublicKey), digest[:], sig) {
+		t.Error("signature verification failed")
+	}
+}
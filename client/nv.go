@@ -0,0 +1,269 @@
+package client
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/google/go-tpm/legacy/tpm2"
+	directtpm2 "github.com/google/go-tpm/tpm2"
+	"github.com/google/go-tpm/tpmutil"
+)
+
+// NV commands that legacy tpm2 does not define.
+const (
+	cmdNVSetBits tpmutil.Command = 0x00000135
+	cmdNVExtend  tpmutil.Command = 0x00000136
+)
+
+// nvTypeShift is the offset of the TPM_NT field in TPMA_NV.
+const nvTypeShift = 4
+
+// nvAuthAttributes are the attributes that allow reading or writing an index.
+const nvAuthAttributes = tpm2.AttrPPWrite | tpm2.AttrOwnerWrite | tpm2.AttrAuthWrite | tpm2.AttrPolicyWrite |
+	tpm2.AttrPPRead | tpm2.AttrOwnerRead | tpm2.AttrAuthRead | tpm2.AttrPolicyRead
+
+// NVDefineOpts specifies the NV index created by NVDefine.
+type NVDefineOpts struct {
+	// Type of the index: directtpm2.TPMNTOrdinary (the default),
+	// directtpm2.TPMNTCounter, directtpm2.TPMNTBits or directtpm2.TPMNTExtend.
+	Type directtpm2.TPMNT
+	// Size of an ordinary index in bytes. Counter and bit field indices are
+	// always 8 bytes, and extend indices are the size of a HashAlg digest.
+	Size uint16
+	// HashAlg is the name algorithm of the index, which is also the hash
+	// algorithm of an extend index. Defaults to SHA256.
+	HashAlg tpm2.Algorithm
+	// Password is the index's authorization value.
+	Password string
+	// Policy, if set, is the index's authPolicy.
+	Policy *Policy
+	// Attributes are added to the index's attributes (for example, to allow
+	// locking the index). If none of the read or write attributes are set,
+	// the index can be read and written with its Password, and with its
+	// Policy if it is set.
+	Attributes tpm2.NVAttr
+	// OwnerPassword is the password of the owner hierarchy, which authorizes
+	// defining the index.
+	OwnerPassword string
+}
+
+// NVAuth authorizes a command on an NV index.
+type NVAuth struct {
+	// Hierarchy, if set to tpm2.HandleOwner or tpm2.HandlePlatform, authorizes
+	// the command with the hierarchy instead of the index. The index must
+	// have the corresponding owner or platform read or write attribute.
+	Hierarchy tpmutil.Handle
+	// Password is the authorization value of the index (or of Hierarchy).
+	Password string
+	// Policy, if set, authorizes the command with a policy session satisfying
+	// the index's authPolicy. The Password is also needed if the policy
+	// contains PolicyAuthValue.
+	Policy *Policy
+}
+
+// NVDefine defines an NV index in the owner hierarchy.
+func NVDefine(rw io.ReadWriter, index tpmutil.Handle, opts NVDefineOpts) error {
+	hashAlg := opts.HashAlg
+	if hashAlg == tpm2.AlgUnknown {
+		hashAlg = tpm2.AlgSHA256
+	}
+	pub := tpm2.NVPublic{
+		NVIndex:    index,
+		NameAlg:    hashAlg,
+		Attributes: opts.Attributes | tpm2.NVAttr(opts.Type)<<nvTypeShift,
+		DataSize:   opts.Size,
+	}
+
+	switch opts.Type {
+	case directtpm2.TPMNTOrdinary:
+		if opts.Size == 0 {
+			return errors.New("ordinary NV index must have a size")
+		}
+	case directtpm2.TPMNTCounter, directtpm2.TPMNTBits:
+		pub.DataSize = 8
+	case directtpm2.TPMNTExtend:
+		hash, err := hashAlg.Hash()
+		if err != nil {
+			return err
+		}
+		pub.DataSize = uint16(hash.Size())
+	default:
+		return fmt.Errorf("unsupported NV index type: %v", opts.Type)
+	}
+
+	if opts.Policy != nil {
+		var err error
+		if pub.AuthPolicy, err = opts.Policy.Digest(); err != nil {
+			return fmt.Errorf("invalid NVDefineOpts: %v", err)
+		}
+		if hashAlg != SessionHashAlgTpm {
+			return fmt.Errorf("invalid NVDefineOpts: an index with a Policy must use %v", SessionHashAlgTpm)
+		}
+	}
+	if pub.Attributes&nvAuthAttributes == 0 {
+		pub.Attributes |= tpm2.AttrAuthRead | tpm2.AttrAuthWrite
+		if opts.Policy != nil {
+			pub.Attributes |= tpm2.AttrPolicyRead | tpm2.AttrPolicyWrite
+		}
+	}
+
+	ownerAuth := tpm2.AuthCommand{Session: tpm2.HandlePasswordSession, Attributes: tpm2.AttrContinueSession, Auth: []byte(opts.OwnerPassword)}
+	if err := tpm2.NVDefineSpaceEx(rw, tpm2.HandleOwner, opts.Password, pub, ownerAuth); err != nil {
+		return fmt.Errorf("failed to define NV index: %w", err)
+	}
+	return nil
+}
+
+// NVUndefine deletes an NV index defined in the owner hierarchy.
+func NVUndefine(rw io.ReadWriter, index tpmutil.Handle, ownerPassword string) error {
+	return tpm2.NVUndefineSpace(rw, ownerPassword, tpm2.HandleOwner, index)
+}
+
+// NVIndices returns the handles of all the defined NV indices.
+func NVIndices(rw io.ReadWriter) ([]tpmutil.Handle, error) {
+	return Handles(rw, tpm2.HandleTypeNVIndex)
+}
+
+// NVRead reads all the data of an NV index. Counter and bit field indices
+// contain a big-endian uint64.
+func NVRead(rw io.ReadWriter, index tpmutil.Handle, auth NVAuth) ([]byte, error) {
+	pub, err := tpm2.NVReadPublic(rw, index)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read NV index public area: %w", err)
+	}
+	blockSize, err := nvBufferMax(rw)
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]byte, 0, pub.DataSize)
+	for len(data) < int(pub.DataSize) {
+		size := min(int(pub.DataSize)-len(data), blockSize)
+		resp, err := runNVCommand(rw, tpm2.CmdReadNV, index, auth, uint16(size), uint16(len(data)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read NV index at offset %d: %w", len(data), err)
+		}
+		var block tpmutil.U16Bytes
+		if _, err := tpmutil.Unpack(resp, &block); err != nil {
+			return nil, fmt.Errorf("failed to decode NV data: %w", err)
+		}
+		data = append(data, block...)
+	}
+	return data, nil
+}
+
+// NVWrite writes data to an ordinary NV index, starting at offset.
+func NVWrite(rw io.ReadWriter, index tpmutil.Handle, data []byte, offset uint16, auth NVAuth) error {
+	blockSize, err := nvBufferMax(rw)
+	if err != nil {
+		return err
+	}
+	for written := 0; written < len(data); {
+		block := data[written:min(len(data), written+blockSize)]
+		if _, err := runNVCommand(rw, tpm2.CmdWriteNV, index, auth, tpmutil.U16Bytes(block), offset+uint16(written)); err != nil {
+			return fmt.Errorf("failed to write NV index at offset %d: %w", int(offset)+written, err)
+		}
+		written += len(block)
+	}
+	return nil
+}
+
+// NVExtend extends data into an extend NV index, like a PCR.
+func NVExtend(rw io.ReadWriter, index tpmutil.Handle, data []byte, auth NVAuth) error {
+	_, err := runNVCommand(rw, cmdNVExtend, index, auth, tpmutil.U16Bytes(data))
+	return err
+}
+
+// NVIncrement increments a counter NV index.
+func NVIncrement(rw io.ReadWriter, index tpmutil.Handle, auth NVAuth) error {
+	_, err := runNVCommand(rw, tpm2.CmdIncrementNVCounter, index, auth)
+	return err
+}
+
+// NVSetBits sets the given bits in a bit field NV index.
+func NVSetBits(rw io.ReadWriter, index tpmutil.Handle, bits uint64, auth NVAuth) error {
+	_, err := runNVCommand(rw, cmdNVSetBits, index, auth, bits)
+	return err
+}
+
+// NVReadLock prevents reading an NV index with the AttrReadSTClear attribute
+// until the next TPM restart.
+func NVReadLock(rw io.ReadWriter, index tpmutil.Handle, auth NVAuth) error {
+	_, err := runNVCommand(rw, tpm2.CmdReadLockNV, index, auth)
+	return err
+}
+
+// NVWriteLock prevents writing an NV index with the AttrWriteSTClear
+// attribute (until the next TPM restart) or the AttrWriteDefine attribute
+// (permanently).
+func NVWriteLock(rw io.ReadWriter, index tpmutil.Handle, auth NVAuth) error {
+	_, err := runNVCommand(rw, tpm2.CmdWriteLockNV, index, auth)
+	return err
+}
+
+// NVCounterValue decodes the value of a counter or bit field NV index read
+// with NVRead.
+func NVCounterValue(data []byte) (uint64, error) {
+	if len(data) != 8 {
+		return 0, fmt.Errorf("got %d bytes of NV data, expected 8", len(data))
+	}
+	return binary.BigEndian.Uint64(data), nil
+}
+
+// runNVCommand runs a command on an NV index, authorized by auth, and returns
+// the response parameters.
+func runNVCommand(rw io.ReadWriter, cc tpmutil.Command, index tpmutil.Handle, auth NVAuth, params ...interface{}) ([]byte, error) {
+	authHandle := index
+	if auth.Hierarchy != 0 {
+		authHandle = auth.Hierarchy
+	}
+	authCmd := tpm2.AuthCommand{Session: tpm2.HandlePasswordSession, Attributes: tpm2.AttrContinueSession, Auth: []byte(auth.Password)}
+	if auth.Policy != nil {
+		session, err := startAuthSession(rw)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create session: %w", err)
+		}
+		defer tpm2.FlushContext(rw, session)
+		if err := (PolicySession{rw: rw, policy: auth.Policy}).runPolicy(session, false); err != nil {
+			return nil, err
+		}
+		authCmd.Session = session
+	}
+	authArea, err := tpmutil.Pack(authCmd)
+	if err != nil {
+		return nil, err
+	}
+
+	args := append([]interface{}{authHandle, index, tpmutil.U32Bytes(authArea)}, params...)
+	resp, code, err := tpmutil.RunCommand(rw, tpm2.TagSessions, cc, args...)
+	if err != nil {
+		return nil, err
+	}
+	if code != tpmutil.RCSuccess {
+		return nil, directtpm2.TPMRC(code)
+	}
+	// The response parameters are preceded by their size.
+	var respParams tpmutil.U32Bytes
+	if _, err := tpmutil.Unpack(resp, &respParams); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return respParams, nil
+}
+
+// nvBufferMax returns the maximum size of the data in an NV read or write.
+func nvBufferMax(rw io.ReadWriter) (int, error) {
+	vals, _, err := tpm2.GetCapability(rw, tpm2.CapabilityTPMProperties, 1, uint32(tpm2.NVMaxBufferSize))
+	if err != nil {
+		return 0, fmt.Errorf("failed to get TPM_PT_NV_BUFFER_MAX: %w", err)
+	}
+	if len(vals) != 1 {
+		return 0, errors.New("could not determine TPM_PT_NV_BUFFER_MAX")
+	}
+	prop, ok := vals[0].(tpm2.TaggedProperty)
+	if !ok || prop.Tag != tpm2.NVMaxBufferSize {
+		return 0, fmt.Errorf("GetCapability returned unexpected value %v, expected TPM_PT_NV_BUFFER_MAX", vals[0])
+	}
+	return int(prop.Value), nil
+}
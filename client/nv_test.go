@@ -0,0 +1,256 @@
+package client_test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"slices"
+	"testing"
+
+	"github.com/google/go-tpm/legacy/tpm2"
+	directtpm2 "github.com/google/go-tpm/tpm2"
+	"github.com/google/go-tpm/tpmutil"
+
+	"github.com/google/go-tpm-tools/client"
+	"github.com/google/go-tpm-tools/internal/test"
+)
+
+const testNVIndex = tpmutil.Handle(0x01500200)
+
+func TestNVOrdinary(t *testing.T) {
+	rwc := test.GetTPM(t)
+	defer client.CheckedClose(t, rwc)
+
+	// Larger than the TPM's maximum NV buffer size.
+	data := bytes.Repeat([]byte("0123456789abcdef"), 128)
+	if err := client.NVDefine(rwc, testNVIndex, client.NVDefineOpts{Size: uint16(len(data)), Password: "password"}); err != nil {
+		t.Fatalf("NVDefine() failed: %v", err)
+	}
+	defer client.NVUndefine(rwc, testNVIndex, "")
+
+	indices, err := client.NVIndices(rwc)
+	if err != nil {
+		t.Fatalf("NVIndices() failed: %v", err)
+	}
+	if !slices.Contains(indices, testNVIndex) {
+		t.Errorf("NVIndices() = %v, missing %v", indices, testNVIndex)
+	}
+
+	auth := client.NVAuth{Password: "password"}
+	if err := client.NVWrite(rwc, testNVIndex, data, 0, auth); err != nil {
+		t.Fatalf("NVWrite() failed: %v", err)
+	}
+	if err := client.NVWrite(rwc, testNVIndex, []byte("XYZ"), 16, auth); err != nil {
+		t.Fatalf("NVWrite() at an offset failed: %v", err)
+	}
+	copy(data[16:], "XYZ")
+	got, err := client.NVRead(rwc, testNVIndex, auth)
+	if err != nil {
+		t.Fatalf("NVRead() failed: %v", err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Errorf("NVRead() = %q, want %q", got, data)
+	}
+
+	wrongAuth := client.NVAuth{Password: "wrong"}
+	if _, err := client.NVRead(rwc, testNVIndex, wrongAuth); err == nil {
+		t.Error("NVRead() with the wrong password succeeded, expected failure")
+	}
+	if err := client.NVWrite(rwc, testNVIndex, data, 0, wrongAuth); err == nil {
+		t.Error("NVWrite() with the wrong password succeeded, expected failure")
+	}
+	if err := client.NVIncrement(rwc, testNVIndex, auth); err == nil {
+		t.Error("NVIncrement() on an ordinary index succeeded, expected failure")
+	}
+	if err := client.NVWrite(rwc, testNVIndex, data, 1, auth); err == nil {
+		t.Error("NVWrite() past the end of the index succeeded, expected failure")
+	}
+
+	if err := client.NVUndefine(rwc, testNVIndex, ""); err != nil {
+		t.Fatalf("NVUndefine() failed: %v", err)
+	}
+	if _, err := client.NVRead(rwc, testNVIndex, auth); err == nil {
+		t.Error("NVRead() after NVUndefine() succeeded, expected failure")
+	}
+}
+
+func TestNVCounterAndBits(t *testing.T) {
+	rwc := test.GetTPM(t)
+	defer client.CheckedClose(t, rwc)
+
+	types := []struct {
+		name   string
+		nvType directtpm2.TPMNT
+		update func(uint64) error
+	}{
+		{"Counter", directtpm2.TPMNTCounter, func(uint64) error { return client.NVIncrement(rwc, testNVIndex, client.NVAuth{}) }},
+		{"Bits", directtpm2.TPMNTBits, func(bits uint64) error { return client.NVSetBits(rwc, testNVIndex, bits, client.NVAuth{}) }},
+	}
+	for _, typ := range types {
+		t.Run(typ.name, func(t *testing.T) {
+			if err := client.NVDefine(rwc, testNVIndex, client.NVDefineOpts{Type: typ.nvType}); err != nil {
+				t.Fatalf("NVDefine() failed: %v", err)
+			}
+			defer client.NVUndefine(rwc, testNVIndex, "")
+
+			// Counters and bit fields cannot be read before they are written.
+			if _, err := client.NVRead(rwc, testNVIndex, client.NVAuth{}); err == nil {
+				t.Error("NVRead() before the first update succeeded, expected failure")
+			}
+			var values []uint64
+			for _, bits := range []uint64{0x1, 0x10, 0x1} {
+				if err := typ.update(bits); err != nil {
+					t.Fatalf("failed to update index: %v", err)
+				}
+				data, err := client.NVRead(rwc, testNVIndex, client.NVAuth{})
+				if err != nil {
+					t.Fatalf("NVRead() failed: %v", err)
+				}
+				value, err := client.NVCounterValue(data)
+				if err != nil {
+					t.Fatalf("NVCounterValue() failed: %v", err)
+				}
+				values = append(values, value)
+			}
+			for i := 1; i < len(values); i++ {
+				if typ.nvType == directtpm2.TPMNTCounter && values[i] != values[i-1]+1 {
+					t.Errorf("counter values %v do not increase by 1", values)
+				}
+			}
+			if typ.nvType == directtpm2.TPMNTBits && !slices.Equal(values, []uint64{0x1, 0x11, 0x11}) {
+				t.Errorf("got bit field values %#x, want [0x1 0x11 0x11]", values)
+			}
+		})
+	}
+}
+
+func TestNVExtend(t *testing.T) {
+	rwc := test.GetTPM(t)
+	defer client.CheckedClose(t, rwc)
+
+	if err := client.NVDefine(rwc, testNVIndex, client.NVDefineOpts{Type: directtpm2.TPMNTExtend}); err != nil {
+		t.Fatalf("NVDefine() failed: %v", err)
+	}
+	defer client.NVUndefine(rwc, testNVIndex, "")
+
+	want := make([]byte, sha256.Size)
+	for _, event := range []string{"event 1", "event 2"} {
+		if err := client.NVExtend(rwc, testNVIndex, []byte(event), client.NVAuth{}); err != nil {
+			t.Fatalf("NVExtend() failed: %v", err)
+		}
+		want = extendSHA256(want, []byte(event))
+	}
+	got, err := client.NVRead(rwc, testNVIndex, client.NVAuth{})
+	if err != nil {
+		t.Fatalf("NVRead() failed: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("NVRead() = %x, want %x", got, want)
+	}
+}
+
+func TestNVPolicy(t *testing.T) {
+	test.SkipForRealTPM(t)
+	rwc := test.GetTPM(t)
+	defer client.CheckedClose(t, rwc)
+
+	sel := tpm2.PCRSelection{Hash: tpm2.AlgSHA256, PCRs: []int{test.DebugPCR}}
+	pcrs, err := client.ReadPCRs(rwc, sel)
+	if err != nil {
+		t.Fatalf("failed to read PCRs: %v", err)
+	}
+	policy := client.NewPolicy().PolicyPCR(pcrs).PolicyAuthValue()
+	opts := client.NVDefineOpts{
+		Size:       4,
+		Password:   "password",
+		Policy:     policy,
+		Attributes: tpm2.AttrPolicyRead | tpm2.AttrPolicyWrite | tpm2.AttrOwnerRead | tpm2.AttrWriteSTClear,
+	}
+	if err := client.NVDefine(rwc, testNVIndex, opts); err != nil {
+		t.Fatalf("NVDefine() failed: %v", err)
+	}
+	defer client.NVUndefine(rwc, testNVIndex, "")
+
+	auth := client.NVAuth{Policy: policy, Password: "password"}
+	if err := client.NVWrite(rwc, testNVIndex, []byte("test"), 0, auth); err != nil {
+		t.Fatalf("NVWrite() failed: %v", err)
+	}
+	if err := client.NVWrite(rwc, testNVIndex, []byte("test"), 0, client.NVAuth{Password: "password"}); err == nil {
+		t.Error("NVWrite() without the policy succeeded, expected failure")
+	}
+	if err := client.NVWrite(rwc, testNVIndex, []byte("test"), 0, client.NVAuth{Policy: policy, Password: "wrong"}); err == nil {
+		t.Error("NVWrite() with the wrong password succeeded, expected failure")
+	}
+	// The owner can read, but not write, the index.
+	ownerAuth := client.NVAuth{Hierarchy: tpm2.HandleOwner}
+	got, err := client.NVRead(rwc, testNVIndex, ownerAuth)
+	if err != nil {
+		t.Fatalf("NVRead() with owner authorization failed: %v", err)
+	}
+	if !bytes.Equal(got, []byte("test")) {
+		t.Errorf("NVRead() = %q, want %q", got, "test")
+	}
+	if err := client.NVWrite(rwc, testNVIndex, []byte("test"), 0, ownerAuth); err == nil {
+		t.Error("NVWrite() with owner authorization succeeded, expected failure")
+	}
+
+	if err := client.NVWriteLock(rwc, testNVIndex, auth); err != nil {
+		t.Fatalf("NVWriteLock() failed: %v", err)
+	}
+	if err := client.NVWrite(rwc, testNVIndex, []byte("next"), 0, auth); err == nil {
+		t.Error("NVWrite() after NVWriteLock() succeeded, expected failure")
+	}
+
+	if err := tpm2.PCRExtend(rwc, tpmutil.Handle(test.DebugPCR), tpm2.AlgSHA256, make([]byte, sha256.Size), ""); err != nil {
+		t.Fatalf("failed to extend PCR: %v", err)
+	}
+	if _, err := client.NVRead(rwc, testNVIndex, auth); err == nil {
+		t.Error("NVRead() after the PCRs changed succeeded, expected failure")
+	}
+}
+
+func TestNVReadLock(t *testing.T) {
+	rwc := test.GetTPM(t)
+	defer client.CheckedClose(t, rwc)
+
+	opts := client.NVDefineOpts{Size: 4, Attributes: tpm2.AttrAuthRead | tpm2.AttrAuthWrite | tpm2.AttrReadSTClear}
+	if err := client.NVDefine(rwc, testNVIndex, opts); err != nil {
+		t.Fatalf("NVDefine() failed: %v", err)
+	}
+	defer client.NVUndefine(rwc, testNVIndex, "")
+
+	if err := client.NVWrite(rwc, testNVIndex, []byte("test"), 0, client.NVAuth{}); err != nil {
+		t.Fatalf("NVWrite() failed: %v", err)
+	}
+	if _, err := client.NVRead(rwc, testNVIndex, client.NVAuth{}); err != nil {
+		t.Fatalf("NVRead() failed: %v", err)
+	}
+	if err := client.NVReadLock(rwc, testNVIndex, client.NVAuth{}); err != nil {
+		t.Fatalf("NVReadLock() failed: %v", err)
+	}
+	if _, err := client.NVRead(rwc, testNVIndex, client.NVAuth{}); err == nil {
+		t.Error("NVRead() after NVReadLock() succeeded, expected failure")
+	}
+}
+
+func TestNVDefineFail(t *testing.T) {
+	rwc := test.GetTPM(t)
+	defer client.CheckedClose(t, rwc)
+
+	opts := []struct {
+		name string
+		opts client.NVDefineOpts
+	}{
+		{"NoSize", client.NVDefineOpts{}},
+		{"PinFail", client.NVDefineOpts{Type: directtpm2.TPMNTPinFail}},
+		{"PolicyWithSHA1", client.NVDefineOpts{Size: 4, HashAlg: tpm2.AlgSHA1, Policy: client.NewPolicy().PolicyAuthValue()}},
+		{"InvalidPolicy", client.NVDefineOpts{Size: 4, Policy: client.NewPolicy()}},
+	}
+	for _, o := range opts {
+		t.Run(o.name, func(t *testing.T) {
+			if err := client.NVDefine(rwc, testNVIndex, o.opts); err == nil {
+				client.NVUndefine(rwc, testNVIndex, "")
+				t.Error("NVDefine() succeeded, expected failure")
+			}
+		})
+	}
+}
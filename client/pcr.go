@@ -8,6 +8,7 @@ import (
 
 	pb "github.com/google/go-tpm-tools/proto/tpm"
 	"github.com/google/go-tpm/legacy/tpm2"
+	"github.com/google/go-tpm/tpmutil"
 )
 
 // NumPCRs is set to the spec minimum of 24, as that's all go-tpm supports.
@@ -101,6 +102,27 @@ type SealOpts struct {
 	Current tpm2.PCRSelection
 	// Target predictively seals data to the given specified PCR values.
 	Target *pb.PCRs
+	// PolicySigner seals data to the PCR policies signed by this key (see
+	// server.SignPCRPolicy), instead of to specific PCR values. This allows
+	// the PCR values to change (such as for firmware or kernel upgrades)
+	// without resealing the data. It cannot be used with Current or Target.
+	// Only RSA and ECDSA (NIST curves) keys are supported.
+	PolicySigner crypto.PublicKey
+	// Policy seals data to an arbitrary Policy, such as a combination of PCR
+	// values, a password and an expiry time. It cannot be used with Current,
+	// Target or PolicySigner. The same Policy must be passed to Unseal().
+	Policy *Policy
+	// Password sets the authorization value of the sealed object. If any
+	// PCRs or policies are used, the password is only required if the
+	// policy contains PolicyAuthValue.
+	Password string
+	// RollbackCounter, if set, is an NV counter index (see
+	// DefineRollbackCounter) that protects the sealed data against rollback.
+	// The data's policy also requires the counter to keep its current value,
+	// so once the counter is incremented (see IncrementRollbackCounter), the
+	// data can no longer be unsealed. Without PCRs or a policy, the data's
+	// policy also requires the Password.
+	RollbackCounter tpmutil.Handle
 }
 
 // UnsealOpts specifies the options that should be used for Unseal().
@@ -114,6 +136,16 @@ type UnsealOpts struct {
 	CertifyCurrent tpm2.PCRSelection
 	// CertifyExpected certifies that the TPM had a specific set of PCR values when sealing.
 	CertifyExpected *pb.PCRs
+	// SignedPolicy is the PCR policy used to unseal data sealed with a
+	// SealOpts.PolicySigner. The TPM verifies its signature, and the
+	// resulting verification ticket authorizes the policy. The current PCR
+	// values must match the policy's PCR values.
+	SignedPolicy *pb.SignedPCRPolicy
+	// Policy is the Policy used to unseal data sealed with SealOpts.Policy.
+	Policy *Policy
+	// Password is the authorization value of the sealed object, set with
+	// SealOpts.Password.
+	Password string
 }
 
 // FullPcrSel will return a full PCR selection based on the total PCR number
@@ -0,0 +1,373 @@
+package client
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/google/go-tpm-tools/internal"
+	pb "github.com/google/go-tpm-tools/proto/tpm"
+	"github.com/google/go-tpm/legacy/tpm2"
+	directtpm2 "github.com/google/go-tpm/tpm2"
+	"github.com/google/go-tpm/tpm2/transport"
+	"github.com/google/go-tpm/tpmutil"
+)
+
+// Policy commands that legacy tpm2 does not define.
+const (
+	cmdPolicyLocality     tpmutil.Command = 0x0000016F
+	cmdPolicyCounterTimer tpmutil.Command = 0x0000016D
+	cmdPolicyAuthValue    tpmutil.Command = 0x0000016B
+	cmdPolicyOR           tpmutil.Command = 0x00000171
+	cmdPolicyNV           tpmutil.Command = 0x00000149
+	cmdPolicyRestart      tpmutil.Command = 0x00000180
+)
+
+// clockOffset is the offset of clockInfo.clock in a TPMS_TIME_INFO structure,
+// which TPM2_PolicyCounterTimer compares against.
+const clockOffset = 8
+
+// Policy is a TPM policy made up of a sequence of policy commands. A Policy
+// can compute its digest offline (to set as the authPolicy of sealed or
+// imported objects), and can be satisfied on a TPM with NewPolicySession.
+//
+// Policies are built by chaining the methods below, for example:
+//
+//	policy := client.NewPolicy().PolicyPCR(pcrs).PolicyAuthValue()
+//
+// Errors in the policy (such as a PolicyOR without enough branches) are
+// returned when computing its digest or satisfying it.
+type Policy struct {
+	steps []policyStep
+}
+
+// policyStep is a single policy command.
+type policyStep interface {
+	// update computes the policy digest after the command, without a TPM.
+	update(digest []byte) ([]byte, error)
+	// execute runs the command on a policy session. The restart function
+	// resets the session to its state before the command. If authHMAC is set,
+	// the session proves knowledge of the object's authorization value with
+	// an HMAC instead of sending the value itself.
+	execute(rw io.ReadWriter, session tpmutil.Handle, authHMAC bool, restart func() error) error
+}
+
+// NewPolicy returns an empty Policy.
+func NewPolicy() *Policy {
+	return &Policy{}
+}
+
+// PolicyPCR requires the PCRs to have the given values.
+func (p *Policy) PolicyPCR(pcrs *pb.PCRs) *Policy {
+	p.steps = append(p.steps, pcrStep{pcrs})
+	return p
+}
+
+// PolicyAuthValue requires the object's authorization value (the password
+// set with SealOpts.Password) to be provided. As legacy tpm2 cannot compute
+// HMAC sessions, the policy is satisfied with TPM2_PolicyPassword (which has
+// the same digest), so the password is sent to the TPM in the clear, unless
+// the key uses encrypted sessions (see Key.SetSessionEncryption).
+func (p *Policy) PolicyAuthValue() *Policy {
+	p.steps = append(p.steps, authValueStep{})
+	return p
+}
+
+// PolicyOR requires any one of the branches to be satisfied. There must be
+// between 2 and 8 branches. When satisfying the policy, the branches are tried
+// in order, and the first branch whose policy commands succeed is used. Some
+// policy commands (PolicyAuthValue and PolicyLocality) are only checked when
+// the session is used, so a branch that relies on them to fail will still be
+// chosen over the later branches.
+func (p *Policy) PolicyOR(branches ...*Policy) *Policy {
+	p.steps = append(p.steps, orStep{branches})
+	return p
+}
+
+// PolicyNV requires the contents of an NV index, starting at offset, to
+// compare to operand using the given operation. The name is the NV index's
+// Name, which is needed to compute the policy digest offline. The NV index
+// must be readable with an empty password.
+func (p *Policy) PolicyNV(index tpmutil.Handle, name []byte, operand []byte, offset uint16, op directtpm2.TPMEO) *Policy {
+	p.steps = append(p.steps, nvStep{index, name, operand, offset, op})
+	return p
+}
+
+// PolicyNVCounter requires the value of an NV counter index to compare to
+// value using the given operation, such as directtpm2.TPMEOUnsignedLE to stop
+// authorizing once the counter is incremented past value.
+func (p *Policy) PolicyNVCounter(index tpmutil.Handle, name []byte, op directtpm2.TPMEO, value uint64) *Policy {
+	return p.PolicyNV(index, name, binary.BigEndian.AppendUint64(nil, value), 0, op)
+}
+
+// PolicyLocality requires the command to be issued from one of the given
+// localities (0 to 4).
+func (p *Policy) PolicyLocality(localities ...uint8) *Policy {
+	p.steps = append(p.steps, localityStep{localities})
+	return p
+}
+
+// PolicyCounterTimer requires the TPM's TPMS_TIME_INFO structure, starting at
+// offset, to compare to operand using the given operation.
+func (p *Policy) PolicyCounterTimer(operand []byte, offset uint16, op directtpm2.TPMEO) *Policy {
+	p.steps = append(p.steps, counterTimerStep{operand, offset, op})
+	return p
+}
+
+// PolicyClockBefore requires the TPM's clock (in milliseconds) to be less than
+// clock. This can be used to make a policy expire. The TPM's clock is not
+// reset on reboot, but can be advanced by the owner.
+func (p *Policy) PolicyClockBefore(clock uint64) *Policy {
+	return p.PolicyCounterTimer(binary.BigEndian.AppendUint64(nil, clock), clockOffset, directtpm2.TPMEOUnsignedLT)
+}
+
+// Digest computes the policy digest, using SessionHashAlg.
+func (p *Policy) Digest() ([]byte, error) {
+	return p.extend(make([]byte, SessionHashAlg.Size()))
+}
+
+// extend computes the policy digest, starting from the given digest.
+func (p *Policy) extend(digest []byte) ([]byte, error) {
+	if len(p.steps) == 0 {
+		return nil, errors.New("policy has no commands")
+	}
+	var err error
+	for i, step := range p.steps {
+		if digest, err = step.update(digest); err != nil {
+			return nil, fmt.Errorf("policy command #%d: %w", i, err)
+		}
+	}
+	return digest, nil
+}
+
+// execute satisfies the policy on a policy session. The restart function
+// resets the session to its state before the policy.
+func (p *Policy) execute(rw io.ReadWriter, session tpmutil.Handle, authHMAC bool, restart func() error) error {
+	for i, step := range p.steps {
+		// Restarting a step requires restarting the session and running the
+		// previous steps again.
+		prefix := p.steps[:i]
+		restartStep := func() error {
+			if err := restart(); err != nil {
+				return err
+			}
+			return (&Policy{prefix}).execute(rw, session, authHMAC, restart)
+		}
+		if err := step.execute(rw, session, authHMAC, restartStep); err != nil {
+			return fmt.Errorf("policy command #%d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// updatePolicy extends a policy digest with a command code and its arguments.
+func updatePolicy(digest []byte, cc tpmutil.Command, args ...[]byte) []byte {
+	hash := SessionHashAlg.New()
+	hash.Write(digest)
+	binary.Write(hash, binary.BigEndian, cc)
+	for _, arg := range args {
+		hash.Write(arg)
+	}
+	return hash.Sum(nil)
+}
+
+// comparisonArgs returns the hashed arguments of TPM2_PolicyNV and
+// TPM2_PolicyCounterTimer.
+func comparisonArgs(operand []byte, offset uint16, op directtpm2.TPMEO) []byte {
+	hash := SessionHashAlg.New()
+	hash.Write(operand)
+	binary.Write(hash, binary.BigEndian, offset)
+	binary.Write(hash, binary.BigEndian, op)
+	return hash.Sum(nil)
+}
+
+// runPolicyCommand runs a policy command that neither legacy tpm2 nor tpm2
+// implements.
+func runPolicyCommand(rw io.ReadWriter, cc tpmutil.Command, session tpmutil.Handle, params ...interface{}) error {
+	_, code, err := tpmutil.RunCommand(rw, tpm2.TagNoSessions, cc, append([]interface{}{session}, params...)...)
+	if err != nil {
+		return err
+	}
+	if code != tpmutil.RCSuccess {
+		return directtpm2.TPMRC(code)
+	}
+	return nil
+}
+
+type pcrStep struct {
+	pcrs *pb.PCRs
+}
+
+func (s pcrStep) update(digest []byte) ([]byte, error) {
+	if len(s.pcrs.GetPcrs()) == 0 {
+		return nil, errors.New("PolicyPCR has no PCRs")
+	}
+	return internal.ExtendPolicyPCR(digest, s.pcrs, SessionHashAlg), nil
+}
+
+func (s pcrStep) execute(rw io.ReadWriter, session tpmutil.Handle, _ bool, _ func() error) error {
+	return tpm2.PolicyPCR(rw, session, internal.PCRDigest(s.pcrs, SessionHashAlg), internal.PCRSelection(s.pcrs))
+}
+
+type authValueStep struct{}
+
+func (authValueStep) update(digest []byte) ([]byte, error) {
+	return updatePolicy(digest, cmdPolicyAuthValue), nil
+}
+
+func (authValueStep) execute(rw io.ReadWriter, session tpmutil.Handle, authHMAC bool, _ func() error) error {
+	if authHMAC {
+		_, err := directtpm2.PolicyAuthValue{
+			PolicySession: directtpm2.TPMHandle(session),
+		}.Execute(transport.FromReadWriter(rw))
+		return err
+	}
+	return tpm2.PolicyPassword(rw, session)
+}
+
+type orStep struct {
+	branches []*Policy
+}
+
+// branchDigests computes the digest of each branch, starting from the digest
+// before the PolicyOR.
+func (s orStep) branchDigests(digest []byte) ([][]byte, error) {
+	if len(s.branches) < 2 || len(s.branches) > 8 {
+		return nil, fmt.Errorf("PolicyOR has %d branches, expected between 2 and 8", len(s.branches))
+	}
+	digests := make([][]byte, len(s.branches))
+	for i, branch := range s.branches {
+		var err error
+		if digests[i], err = branch.extend(digest); err != nil {
+			return nil, fmt.Errorf("PolicyOR branch #%d: %w", i, err)
+		}
+	}
+	return digests, nil
+}
+
+func (s orStep) update(digest []byte) ([]byte, error) {
+	digests, err := s.branchDigests(digest)
+	if err != nil {
+		return nil, err
+	}
+	// TPM2_PolicyOR resets the policy digest before extending it.
+	return updatePolicy(make([]byte, SessionHashAlg.Size()), cmdPolicyOR, digests...), nil
+}
+
+func (s orStep) execute(rw io.ReadWriter, session tpmutil.Handle, authHMAC bool, restart func() error) error {
+	digest, err := tpm2.PolicyGetDigest(rw, session)
+	if err != nil {
+		return err
+	}
+	digests, err := s.branchDigests(digest)
+	if err != nil {
+		return err
+	}
+	hashList := directtpm2.TPMLDigest{}
+	for _, d := range digests {
+		hashList.Digests = append(hashList.Digests, directtpm2.TPM2BDigest{Buffer: d})
+	}
+
+	var errs []error
+	for i, branch := range s.branches {
+		if i > 0 {
+			if err := restart(); err != nil {
+				return fmt.Errorf("failed to restart policy session: %w", err)
+			}
+		}
+		if err := branch.execute(rw, session, authHMAC, restart); err != nil {
+			errs = append(errs, fmt.Errorf("branch #%d: %w", i, err))
+			continue
+		}
+		_, err := directtpm2.PolicyOr{
+			PolicySession: directtpm2.TPMHandle(session),
+			PHashList:     hashList,
+		}.Execute(transport.FromReadWriter(rw))
+		return err
+	}
+	return fmt.Errorf("no PolicyOR branch was satisfied: %w", errors.Join(errs...))
+}
+
+type nvStep struct {
+	index   tpmutil.Handle
+	name    []byte
+	operand []byte
+	offset  uint16
+	op      directtpm2.TPMEO
+}
+
+func (s nvStep) update(digest []byte) ([]byte, error) {
+	if len(s.name) == 0 {
+		return nil, errors.New("PolicyNV has no NV index name")
+	}
+	return updatePolicy(digest, cmdPolicyNV, comparisonArgs(s.operand, s.offset, s.op), s.name), nil
+}
+
+func (s nvStep) execute(rw io.ReadWriter, session tpmutil.Handle, _ bool, _ func() error) error {
+	_, err := directtpm2.PolicyNV{
+		AuthHandle: directtpm2.AuthHandle{
+			Handle: directtpm2.TPMHandle(s.index),
+			Name:   directtpm2.TPM2BName{Buffer: s.name},
+			Auth:   directtpm2.PasswordAuth(nil),
+		},
+		NVIndex: directtpm2.NamedHandle{
+			Handle: directtpm2.TPMHandle(s.index),
+			Name:   directtpm2.TPM2BName{Buffer: s.name},
+		},
+		PolicySession: directtpm2.TPMHandle(session),
+		OperandB:      directtpm2.TPM2BOperand{Buffer: s.operand},
+		Offset:        s.offset,
+		Operation:     s.op,
+	}.Execute(transport.FromReadWriter(rw))
+	return err
+}
+
+type localityStep struct {
+	localities []uint8
+}
+
+// locality returns the TPMA_LOCALITY bitmask of the localities.
+func (s localityStep) locality() (byte, error) {
+	if len(s.localities) == 0 {
+		return 0, errors.New("PolicyLocality has no localities")
+	}
+	var locality byte
+	for _, l := range s.localities {
+		if l > 4 {
+			return 0, fmt.Errorf("invalid locality %d", l)
+		}
+		locality |= 1 << l
+	}
+	return locality, nil
+}
+
+func (s localityStep) update(digest []byte) ([]byte, error) {
+	locality, err := s.locality()
+	if err != nil {
+		return nil, err
+	}
+	return updatePolicy(digest, cmdPolicyLocality, []byte{locality}), nil
+}
+
+func (s localityStep) execute(rw io.ReadWriter, session tpmutil.Handle, _ bool, _ func() error) error {
+	locality, err := s.locality()
+	if err != nil {
+		return err
+	}
+	return runPolicyCommand(rw, cmdPolicyLocality, session, locality)
+}
+
+type counterTimerStep struct {
+	operand []byte
+	offset  uint16
+	op      directtpm2.TPMEO
+}
+
+func (s counterTimerStep) update(digest []byte) ([]byte, error) {
+	return updatePolicy(digest, cmdPolicyCounterTimer, comparisonArgs(s.operand, s.offset, s.op)), nil
+}
+
+func (s counterTimerStep) execute(rw io.ReadWriter, session tpmutil.Handle, _ bool, _ func() error) error {
+	return runPolicyCommand(rw, cmdPolicyCounterTimer, session, tpmutil.U16Bytes(s.operand), s.offset, s.op)
+}
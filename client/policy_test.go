@@ -0,0 +1,280 @@
+package client_test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"io"
+	"math"
+	"testing"
+
+	"github.com/google/go-tpm/legacy/tpm2"
+	directtpm2 "github.com/google/go-tpm/tpm2"
+	"github.com/google/go-tpm/tpm2/transport"
+	"github.com/google/go-tpm/tpmutil"
+
+	"github.com/google/go-tpm-tools/client"
+	"github.com/google/go-tpm-tools/internal/test"
+	pb "github.com/google/go-tpm-tools/proto/tpm"
+)
+
+const testCounterIndex = tpmutil.Handle(0x01500100)
+
+// defineTestCounter defines an NV counter index readable and writable with an
+// empty password, increments it once, and returns its Name.
+func defineTestCounter(t *testing.T, rw io.ReadWriter) []byte {
+	t.Helper()
+	tpm := transport.FromReadWriter(rw)
+	_, err := directtpm2.NVDefineSpace{
+		AuthHandle: directtpm2.TPMRHOwner,
+		PublicInfo: directtpm2.New2B(directtpm2.TPMSNVPublic{
+			NVIndex: directtpm2.TPMHandle(testCounterIndex),
+			NameAlg: directtpm2.TPMAlgSHA256,
+			Attributes: directtpm2.TPMANV{
+				AuthRead:  true,
+				AuthWrite: true,
+				NoDA:      true,
+				NT:        directtpm2.TPMNTCounter,
+			},
+			DataSize: 8,
+		}),
+	}.Execute(tpm)
+	if err != nil {
+		t.Fatalf("failed to define NV counter: %v", err)
+	}
+	t.Cleanup(func() {
+		tpm2.NVUndefineSpace(rw, "", tpm2.HandleOwner, testCounterIndex)
+	})
+	incrementTestCounter(t, rw)
+	return testCounterName(t, rw)
+}
+
+func testCounterName(t *testing.T, rw io.ReadWriter) []byte {
+	t.Helper()
+	pub, err := directtpm2.NVReadPublic{
+		NVIndex: directtpm2.TPMHandle(testCounterIndex),
+	}.Execute(transport.FromReadWriter(rw))
+	if err != nil {
+		t.Fatalf("failed to read NV counter public area: %v", err)
+	}
+	return pub.NVName.Buffer
+}
+
+func incrementTestCounter(t *testing.T, rw io.ReadWriter) {
+	t.Helper()
+	if err := tpm2.NVIncrement(rw, testCounterIndex, ""); err != nil {
+		t.Fatalf("failed to increment NV counter: %v", err)
+	}
+}
+
+func TestPolicyDigest(t *testing.T) {
+	test.SkipForRealTPM(t)
+	rwc := test.GetTPM(t)
+	defer client.CheckedClose(t, rwc)
+
+	sel := tpm2.PCRSelection{Hash: tpm2.AlgSHA256, PCRs: []int{7, test.DebugPCR}}
+	pcrs, err := client.ReadPCRs(rwc, sel)
+	if err != nil {
+		t.Fatalf("failed to read PCRs: %v", err)
+	}
+	otherPCRs := &pb.PCRs{Hash: pcrs.GetHash(), Pcrs: map[uint32][]byte{uint32(test.DebugPCR): make([]byte, sha256.Size)}}
+	counterName := defineTestCounter(t, rwc)
+
+	policies := []struct {
+		name   string
+		policy *client.Policy
+	}{
+		{"PCR", client.NewPolicy().PolicyPCR(pcrs)},
+		{"AuthValue", client.NewPolicy().PolicyAuthValue()},
+		{"Locality", client.NewPolicy().PolicyLocality(0, 3)},
+		{"ClockBefore", client.NewPolicy().PolicyClockBefore(math.MaxUint64)},
+		{"NVCounter", client.NewPolicy().PolicyNVCounter(testCounterIndex, counterName, directtpm2.TPMEOUnsignedLE, 1)},
+		{"Compound", client.NewPolicy().PolicyPCR(pcrs).PolicyAuthValue().PolicyClockBefore(math.MaxUint64)},
+		{"OR", client.NewPolicy().PolicyOR(
+			client.NewPolicy().PolicyPCR(otherPCRs),
+			client.NewPolicy().PolicyPCR(pcrs).PolicyAuthValue(),
+		)},
+		{"NestedOR", client.NewPolicy().PolicyAuthValue().PolicyOR(
+			client.NewPolicy().PolicyClockBefore(0),
+			client.NewPolicy().PolicyOR(
+				client.NewPolicy().PolicyPCR(otherPCRs),
+				client.NewPolicy().PolicyPCR(pcrs),
+			),
+		).PolicyLocality(0)},
+	}
+	for _, p := range policies {
+		t.Run(p.name, func(t *testing.T) {
+			want, err := p.policy.Digest()
+			if err != nil {
+				t.Fatalf("Digest() failed: %v", err)
+			}
+			session, err := client.NewPolicySession(rwc, p.policy)
+			if err != nil {
+				t.Fatalf("failed to create session: %v", err)
+			}
+			defer session.Close()
+			auth, err := session.Auth()
+			if err != nil {
+				t.Fatalf("failed to satisfy policy: %v", err)
+			}
+			got, err := tpm2.PolicyGetDigest(rwc, auth.Session)
+			if err != nil {
+				t.Fatalf("failed to get policy digest: %v", err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("session has policy digest %x, but Digest() returned %x", got, want)
+			}
+		})
+	}
+}
+
+func TestPolicyDigestFail(t *testing.T) {
+	policies := []struct {
+		name   string
+		policy *client.Policy
+	}{
+		{"Empty", client.NewPolicy()},
+		{"NoPCRs", client.NewPolicy().PolicyPCR(&pb.PCRs{})},
+		{"OneORBranch", client.NewPolicy().PolicyOR(client.NewPolicy().PolicyAuthValue())},
+		{"EmptyORBranch", client.NewPolicy().PolicyOR(client.NewPolicy().PolicyAuthValue(), client.NewPolicy())},
+		{"InvalidLocality", client.NewPolicy().PolicyLocality(5)},
+		{"NoNVName", client.NewPolicy().PolicyNVCounter(testCounterIndex, nil, directtpm2.TPMEOEq, 0)},
+	}
+	for _, p := range policies {
+		t.Run(p.name, func(t *testing.T) {
+			if _, err := p.policy.Digest(); err == nil {
+				t.Error("Digest() succeeded, expected failure")
+			}
+		})
+	}
+}
+
+func TestSealWithPolicy(t *testing.T) {
+	test.SkipForRealTPM(t)
+	rwc := test.GetTPM(t)
+	defer client.CheckedClose(t, rwc)
+
+	srk, err := client.StorageRootKeyECC(rwc)
+	if err != nil {
+		t.Fatalf("failed to create SRK: %v", err)
+	}
+	defer srk.Close()
+
+	sel := tpm2.PCRSelection{Hash: tpm2.AlgSHA256, PCRs: []int{test.DebugPCR}}
+	pcrs, err := client.ReadPCRs(rwc, sel)
+	if err != nil {
+		t.Fatalf("failed to read PCRs: %v", err)
+	}
+	// Also allow the PCR values after the next extension.
+	extension := bytes.Repeat([]byte{0xAA}, sha256.Size)
+	nextPCRs := &pb.PCRs{Hash: pcrs.GetHash(), Pcrs: map[uint32][]byte{}}
+	for idx, val := range pcrs.GetPcrs() {
+		nextPCRs.Pcrs[idx] = extendSHA256(val, extension)
+	}
+	counterName := defineTestCounter(t, rwc)
+	policy := client.NewPolicy().
+		PolicyOR(client.NewPolicy().PolicyPCR(pcrs), client.NewPolicy().PolicyPCR(nextPCRs)).
+		PolicyAuthValue().
+		PolicyNVCounter(testCounterIndex, counterName, directtpm2.TPMEOUnsignedLE, 1).
+		PolicyClockBefore(math.MaxUint64)
+
+	secret := []byte("test")
+	sealed, err := srk.Seal(secret, client.SealOpts{Policy: policy, Password: "password"})
+	if err != nil {
+		t.Fatalf("failed to seal: %v", err)
+	}
+	unseal := func(opts client.UnsealOpts) {
+		t.Helper()
+		unsealed, err := srk.Unseal(sealed, opts)
+		if err != nil {
+			t.Fatalf("failed to unseal: %v", err)
+		}
+		if !bytes.Equal(unsealed, secret) {
+			t.Errorf("unsealed (%v) not equal to secret (%v)", unsealed, secret)
+		}
+	}
+	unsealFail := func(opts client.UnsealOpts, msg string) {
+		t.Helper()
+		if _, err := srk.Unseal(sealed, opts); err == nil {
+			t.Errorf("unsealing %s succeeded, expected failure", msg)
+		}
+	}
+
+	unseal(client.UnsealOpts{Policy: policy, Password: "password"})
+	unsealFail(client.UnsealOpts{Policy: policy, Password: "wrong"}, "with the wrong password")
+	unsealFail(client.UnsealOpts{Password: "password"}, "without a policy")
+	unsealFail(client.UnsealOpts{Policy: client.NewPolicy().PolicyAuthValue(), Password: "password"}, "with a different policy")
+
+	// The second PolicyOR branch is satisfied after the extension.
+	if err := tpm2.PCRExtend(rwc, tpmutil.Handle(test.DebugPCR), tpm2.AlgSHA256, extension, ""); err != nil {
+		t.Fatalf("failed to extend PCR: %v", err)
+	}
+	unseal(client.UnsealOpts{Policy: policy, Password: "password"})
+	if err := tpm2.PCRExtend(rwc, tpmutil.Handle(test.DebugPCR), tpm2.AlgSHA256, extension, ""); err != nil {
+		t.Fatalf("failed to extend PCR: %v", err)
+	}
+	unsealFail(client.UnsealOpts{Policy: policy, Password: "password"}, "after PCRs changed")
+	// Reset to the PCR values after the first extension.
+	if err := tpm2.PCRReset(rwc, tpmutil.Handle(test.DebugPCR)); err != nil {
+		t.Fatalf("failed to reset PCR: %v", err)
+	}
+	if err := tpm2.PCRExtend(rwc, tpmutil.Handle(test.DebugPCR), tpm2.AlgSHA256, extension, ""); err != nil {
+		t.Fatalf("failed to extend PCR: %v", err)
+	}
+	unseal(client.UnsealOpts{Policy: policy, Password: "password"})
+
+	incrementTestCounter(t, rwc)
+	unsealFail(client.UnsealOpts{Policy: policy, Password: "password"}, "after the counter was incremented")
+}
+
+func TestSealWithPolicyFail(t *testing.T) {
+	test.SkipForRealTPM(t)
+	rwc := test.GetTPM(t)
+	defer client.CheckedClose(t, rwc)
+
+	srk, err := client.StorageRootKeyRSA(rwc)
+	if err != nil {
+		t.Fatalf("failed to create SRK: %v", err)
+	}
+	defer srk.Close()
+
+	policies := []struct {
+		name   string
+		policy *client.Policy
+	}{
+		{"Expired", client.NewPolicy().PolicyClockBefore(0)},
+		{"WrongLocality", client.NewPolicy().PolicyLocality(3)},
+		{"NoBranchSatisfied", client.NewPolicy().PolicyOR(
+			client.NewPolicy().PolicyClockBefore(0),
+			client.NewPolicy().PolicyPCR(&pb.PCRs{
+				Hash: pb.HashAlgo_SHA256,
+				Pcrs: map[uint32][]byte{uint32(test.DebugPCR): bytes.Repeat([]byte{0xFF}, sha256.Size)},
+			}),
+		)},
+	}
+	for _, p := range policies {
+		t.Run(p.name, func(t *testing.T) {
+			sealed, err := srk.Seal([]byte("test"), client.SealOpts{Policy: p.policy})
+			if err != nil {
+				t.Fatalf("failed to seal: %v", err)
+			}
+			if _, err := srk.Unseal(sealed, client.UnsealOpts{Policy: p.policy}); err == nil {
+				t.Error("unsealing succeeded, expected failure")
+			}
+		})
+	}
+
+	opts := client.SealOpts{
+		Current: tpm2.PCRSelection{Hash: tpm2.AlgSHA256, PCRs: []int{7}},
+		Policy:  client.NewPolicy().PolicyAuthValue(),
+	}
+	if _, err := srk.Seal([]byte("test"), opts); err == nil {
+		t.Error("sealing with both Current and Policy succeeded, expected failure")
+	}
+}
+
+func extendSHA256(pcr, digest []byte) []byte {
+	hash := sha256.New()
+	hash.Write(pcr)
+	hash.Write(digest)
+	return hash.Sum(nil)
+}
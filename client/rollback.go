@@ -0,0 +1,82 @@
+package client
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	directtpm2 "github.com/google/go-tpm/tpm2"
+	"github.com/google/go-tpm/tpm2/transport"
+	"github.com/google/go-tpm/tpmutil"
+)
+
+// DefineRollbackCounter defines an NV counter index for use with
+// SealOpts.RollbackCounter, and initializes it. The counter can be read and
+// incremented with an empty password. When a counter is first initialized,
+// the TPM sets it to the highest value of any counter it has had, so deleting
+// and redefining the index does not allow rolling back sealed data.
+func DefineRollbackCounter(rw io.ReadWriter, index tpmutil.Handle, ownerPassword string) error {
+	opts := NVDefineOpts{Type: directtpm2.TPMNTCounter, OwnerPassword: ownerPassword}
+	if err := NVDefine(rw, index, opts); err != nil {
+		return err
+	}
+	if err := NVIncrement(rw, index, NVAuth{}); err != nil {
+		return fmt.Errorf("failed to initialize rollback counter: %w", err)
+	}
+	return nil
+}
+
+// RollbackCounterValue returns the current value of a rollback counter.
+func RollbackCounterValue(rw io.ReadWriter, index tpmutil.Handle) (uint64, error) {
+	data, err := NVRead(rw, index, NVAuth{})
+	if err != nil {
+		return 0, fmt.Errorf("failed to read rollback counter: %w", err)
+	}
+	return NVCounterValue(data)
+}
+
+// IncrementRollbackCounter increments a rollback counter, so that all the data
+// previously sealed with it can no longer be unsealed. When rotating a sealed
+// secret, the counter must be incremented before sealing the new secret.
+// Returns the new value of the counter.
+func IncrementRollbackCounter(rw io.ReadWriter, index tpmutil.Handle) (uint64, error) {
+	if err := NVIncrement(rw, index, NVAuth{}); err != nil {
+		return 0, fmt.Errorf("failed to increment rollback counter: %w", err)
+	}
+	return RollbackCounterValue(rw, index)
+}
+
+// rollbackCounterStep returns the PolicyNV command requiring a rollback
+// counter to have the given value.
+func rollbackCounterStep(rw io.ReadWriter, index tpmutil.Handle, value uint64) (*nvStep, error) {
+	pub, err := directtpm2.NVReadPublic{
+		NVIndex: directtpm2.TPMHandle(index),
+	}.Execute(transport.FromReadWriter(rw))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rollback counter public area: %w", err)
+	}
+	return &nvStep{
+		index:   index,
+		name:    pub.NVName.Buffer,
+		operand: binary.BigEndian.AppendUint64(nil, value),
+		op:      directtpm2.TPMEOEq,
+	}, nil
+}
+
+// rollbackSession satisfies the policy of data sealed with a rollback
+// counter: the base policy, followed by the counter check.
+type rollbackSession struct {
+	rw      io.ReadWriter
+	base    policyRunner
+	counter *nvStep
+}
+
+func (r rollbackSession) runPolicy(session tpmutil.Handle, authHMAC bool) error {
+	if err := r.base.runPolicy(session, authHMAC); err != nil {
+		return err
+	}
+	if err := r.counter.execute(r.rw, session, authHMAC, nil); err != nil {
+		return fmt.Errorf("rollback counter check failed (the sealed data may be stale): %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package client_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-tpm/legacy/tpm2"
+	directtpm2 "github.com/google/go-tpm/tpm2"
+	"github.com/google/go-tpm/tpmutil"
+
+	"github.com/google/go-tpm-tools/client"
+	"github.com/google/go-tpm-tools/internal/test"
+)
+
+const testRollbackCounter = tpmutil.Handle(0x01500400)
+
+func TestSealWithRollbackCounter(t *testing.T) {
+	rwc := test.GetTPM(t)
+	defer client.CheckedClose(t, rwc)
+
+	srk, err := client.StorageRootKeyRSA(rwc)
+	if err != nil {
+		t.Fatalf("failed to create SRK: %v", err)
+	}
+	defer srk.Close()
+	if err := client.DefineRollbackCounter(rwc, testRollbackCounter, ""); err != nil {
+		t.Fatalf("DefineRollbackCounter() failed: %v", err)
+	}
+	defer client.NVUndefine(rwc, testRollbackCounter, "")
+
+	pcrSel := tpm2.PCRSelection{Hash: tpm2.AlgSHA256, PCRs: []int{7}}
+	pcrs, err := client.ReadPCRs(rwc, pcrSel)
+	if err != nil {
+		t.Fatalf("failed to read PCRs: %v", err)
+	}
+	policy := client.NewPolicy().PolicyPCR(pcrs).PolicyAuthValue()
+	tests := []struct {
+		name  string
+		sOpts client.SealOpts
+		uOpts client.UnsealOpts
+	}{
+		{"NoPCRs", client.SealOpts{}, client.UnsealOpts{}},
+		{"Password", client.SealOpts{Password: "password"}, client.UnsealOpts{Password: "password"}},
+		{"PCRs", client.SealOpts{Current: pcrSel}, client.UnsealOpts{}},
+		{"Policy", client.SealOpts{Policy: policy, Password: "password"}, client.UnsealOpts{Policy: policy, Password: "password"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.sOpts.RollbackCounter = testRollbackCounter
+			oldSecret := []byte("old secret")
+			oldSealed, err := srk.Seal(oldSecret, tc.sOpts)
+			if err != nil {
+				t.Fatalf("failed to seal: %v", err)
+			}
+			unsealed, err := srk.Unseal(oldSealed, tc.uOpts)
+			if err != nil {
+				t.Fatalf("failed to unseal: %v", err)
+			}
+			if !bytes.Equal(unsealed, oldSecret) {
+				t.Errorf("unsealed (%v) not equal to secret (%v)", unsealed, oldSecret)
+			}
+			if tc.sOpts.Password != "" {
+				wrongOpts := tc.uOpts
+				wrongOpts.Password = "wrong"
+				if _, err := srk.Unseal(oldSealed, wrongOpts); err == nil {
+					t.Error("unsealing with the wrong password succeeded, expected failure")
+				}
+			}
+
+			// Rotate the secret.
+			before, err := client.RollbackCounterValue(rwc, testRollbackCounter)
+			if err != nil {
+				t.Fatalf("RollbackCounterValue() failed: %v", err)
+			}
+			after, err := client.IncrementRollbackCounter(rwc, testRollbackCounter)
+			if err != nil {
+				t.Fatalf("IncrementRollbackCounter() failed: %v", err)
+			}
+			if after != before+1 {
+				t.Errorf("IncrementRollbackCounter() = %d, want %d", after, before+1)
+			}
+			newSecret := []byte("new secret")
+			newSealed, err := srk.Seal(newSecret, tc.sOpts)
+			if err != nil {
+				t.Fatalf("failed to seal: %v", err)
+			}
+
+			if _, err := srk.Unseal(oldSealed, tc.uOpts); err == nil {
+				t.Error("unsealing stale data succeeded, expected failure")
+			}
+			// Changing the recorded counter value does not help.
+			oldSealed.RollbackCounterValue = after
+			if _, err := srk.Unseal(oldSealed, tc.uOpts); err == nil {
+				t.Error("unsealing stale data with a modified counter value succeeded, expected failure")
+			}
+			if unsealed, err = srk.Unseal(newSealed, tc.uOpts); err != nil {
+				t.Fatalf("failed to unseal: %v", err)
+			}
+			if !bytes.Equal(unsealed, newSecret) {
+				t.Errorf("unsealed (%v) not equal to secret (%v)", unsealed, newSecret)
+			}
+		})
+	}
+}
+
+func TestSealWithRollbackCounterFail(t *testing.T) {
+	rwc := test.GetTPM(t)
+	defer client.CheckedClose(t, rwc)
+
+	srk, err := client.StorageRootKeyECC(rwc)
+	if err != nil {
+		t.Fatalf("failed to create SRK: %v", err)
+	}
+	defer srk.Close()
+
+	if _, err := srk.Seal([]byte("secret"), client.SealOpts{RollbackCounter: testRollbackCounter}); err == nil {
+		t.Error("sealing with an undefined rollback counter succeeded, expected failure")
+	}
+	// The counter must be initialized before it can be read.
+	if err := client.NVDefine(rwc, testRollbackCounter, client.NVDefineOpts{Type: directtpm2.TPMNTCounter}); err != nil {
+		t.Fatalf("NVDefine() failed: %v", err)
+	}
+	defer client.NVUndefine(rwc, testRollbackCounter, "")
+	if _, err := srk.Seal([]byte("secret"), client.SealOpts{RollbackCounter: testRollbackCounter}); err == nil {
+		t.Error("sealing with an uninitialized rollback counter succeeded, expected failure")
+	}
+}
@@ -1,9 +1,15 @@
 package client
 
 import (
+	"errors"
+	"fmt"
 	"io"
 
+	"github.com/google/go-tpm-tools/internal"
+	pb "github.com/google/go-tpm-tools/proto/tpm"
 	"github.com/google/go-tpm/legacy/tpm2"
+	directtpm2 "github.com/google/go-tpm/tpm2"
+	"github.com/google/go-tpm/tpm2/transport"
 	"github.com/google/go-tpm/tpmutil"
 )
 
@@ -13,6 +19,14 @@ type Session interface {
 	Auth() (tpm2.AuthCommand, error)
 }
 
+// policyRunner is implemented by the policy sessions in this package, so that
+// their policy can also be satisfied on another session, such as a salted
+// session used for parameter encryption. If authHMAC is set, the session
+// proves knowledge of the object's password with an HMAC.
+type policyRunner interface {
+	runPolicy(session tpmutil.Handle, authHMAC bool) error
+}
+
 func startAuthSession(rw io.ReadWriter) (session tpmutil.Handle, err error) {
 	// This session assumes the bus is trusted, so we:
 	// - use nil for tpmKey, encrypted salt, and symmetric
@@ -50,17 +64,137 @@ func NewPCRSession(rw io.ReadWriter, sel tpm2.PCRSelection) (Session, error) {
 
 // Auth returns the AuthCommand for the session.
 func (p PCRSession) Auth() (auth tpm2.AuthCommand, err error) {
-	if err = tpm2.PolicyPCR(p.rw, p.session, nil, p.sel); err != nil {
+	if err = p.runPolicy(p.session, false); err != nil {
 		return
 	}
 	return tpm2.AuthCommand{Session: p.session, Attributes: tpm2.AttrContinueSession}, nil
 }
 
+func (p PCRSession) runPolicy(session tpmutil.Handle, _ bool) error {
+	return tpm2.PolicyPCR(p.rw, session, nil, p.sel)
+}
+
 // Close closes the session.
 func (p PCRSession) Close() error {
 	return tpm2.FlushContext(p.rw, p.session)
 }
 
+// AuthorizedPCRSession is a TPM session that is bound to a set of PCR values
+// approved by a policy signer, using TPM2_PolicyAuthorize.
+type AuthorizedPCRSession struct {
+	rw           io.ReadWriter
+	session      tpmutil.Handle
+	policySigner []byte
+	policy       *pb.SignedPCRPolicy
+}
+
+// NewAuthorizedPCRSession creates a new AuthorizedPCRSession. The policySigner
+// is the public area of the key that signed the policy, encoded as a
+// TPMT_PUBLIC.
+func NewAuthorizedPCRSession(rw io.ReadWriter, policySigner []byte, policy *pb.SignedPCRPolicy) (Session, error) {
+	if len(policy.GetPcrs().GetPcrs()) == 0 {
+		return nil, errors.New("signed PCR policy has no PCRs")
+	}
+	session, err := startAuthSession(rw)
+	return AuthorizedPCRSession{rw, session, policySigner, policy}, err
+}
+
+// Auth returns the AuthCommand for the session. The TPM verifies the policy's
+// signature, and the resulting ticket is used to authorize the policy.
+func (a AuthorizedPCRSession) Auth() (auth tpm2.AuthCommand, err error) {
+	if err = a.runPolicy(a.session, false); err != nil {
+		return
+	}
+	return tpm2.AuthCommand{Session: a.session, Attributes: tpm2.AttrContinueSession}, nil
+}
+
+func (a AuthorizedPCRSession) runPolicy(session tpmutil.Handle, _ bool) error {
+	pcrs := a.policy.GetPcrs()
+	if err := tpm2.PolicyPCR(a.rw, session, nil, internal.PCRSelection(pcrs)); err != nil {
+		return err
+	}
+	signature, err := directtpm2.Unmarshal[directtpm2.TPMTSignature](a.policy.GetRawSig())
+	if err != nil {
+		return fmt.Errorf("failed to decode PCR policy signature: %w", err)
+	}
+
+	tpm := transport.FromReadWriter(a.rw)
+	// The signer must be loaded in a hierarchy other than the null
+	// hierarchy, as TPM2_PolicyAuthorize does not accept null tickets.
+	signer, err := directtpm2.LoadExternal{
+		InPublic:  directtpm2.BytesAs2B[directtpm2.TPMTPublic](a.policySigner),
+		Hierarchy: directtpm2.TPMRHOwner,
+	}.Execute(tpm)
+	if err != nil {
+		return fmt.Errorf("failed to load policy signer: %w", err)
+	}
+	defer directtpm2.FlushContext{FlushHandle: signer.ObjectHandle}.Execute(tpm)
+
+	approvedPolicy := internal.PCRSessionAuth(pcrs, SessionHashAlg)
+	verified, err := directtpm2.VerifySignature{
+		KeyHandle: directtpm2.NamedHandle{
+			Handle: signer.ObjectHandle,
+			Name:   signer.Name,
+		},
+		Digest:    directtpm2.TPM2BDigest{Buffer: internal.AuthorizedPolicyDigest(approvedPolicy, SessionHashAlg)},
+		Signature: *signature,
+	}.Execute(tpm)
+	if err != nil {
+		return fmt.Errorf("failed to verify PCR policy signature: %w", err)
+	}
+	if _, err = (directtpm2.PolicyAuthorize{
+		PolicySession:  directtpm2.TPMHandle(session),
+		ApprovedPolicy: directtpm2.TPM2BDigest{Buffer: approvedPolicy},
+		KeySign:        signer.Name,
+		CheckTicket:    verified.Validation,
+	}).Execute(tpm); err != nil {
+		return fmt.Errorf("failed to authorize PCR policy: %w", err)
+	}
+	return nil
+}
+
+// Close closes the session.
+func (a AuthorizedPCRSession) Close() error {
+	return tpm2.FlushContext(a.rw, a.session)
+}
+
+// PolicySession is a TPM session that satisfies a Policy.
+type PolicySession struct {
+	rw      io.ReadWriter
+	session tpmutil.Handle
+	policy  *Policy
+}
+
+// NewPolicySession creates a new PolicySession.
+func NewPolicySession(rw io.ReadWriter, policy *Policy) (Session, error) {
+	session, err := startAuthSession(rw)
+	return PolicySession{rw, session, policy}, err
+}
+
+// Auth returns the AuthCommand for the session. If the policy contains
+// PolicyAuthValue, the object's password must also be provided to the command.
+func (p PolicySession) Auth() (auth tpm2.AuthCommand, err error) {
+	if err = p.runPolicy(p.session, false); err != nil {
+		return
+	}
+	return tpm2.AuthCommand{Session: p.session, Attributes: tpm2.AttrContinueSession}, nil
+}
+
+func (p PolicySession) runPolicy(session tpmutil.Handle, authHMAC bool) error {
+	restart := func() error {
+		return runPolicyCommand(p.rw, cmdPolicyRestart, session)
+	}
+	if err := p.policy.execute(p.rw, session, authHMAC, restart); err != nil {
+		return fmt.Errorf("failed to satisfy policy: %w", err)
+	}
+	return nil
+}
+
+// Close closes the session.
+func (p PolicySession) Close() error {
+	return tpm2.FlushContext(p.rw, p.session)
+}
+
 // EKSession is a TPM session that is bound to the EK.
 type EKSession struct {
 	rw      io.ReadWriter
@@ -0,0 +1,240 @@
+package client
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/cipher"
+	"errors"
+	"fmt"
+	"hash"
+
+	"github.com/google/go-tpm/legacy/tpm2"
+	directtpm2 "github.com/google/go-tpm/tpm2"
+	"github.com/google/go-tpm/tpm2/transport"
+)
+
+// aesBlockSize is the AES block size in bytes.
+const aesBlockSize = 16
+
+// maxDigestBuffer is the spec minimum of MAX_DIGEST_BUFFER, the largest
+// buffer that can be passed to TPM2_HMAC or TPM2_SequenceUpdate.
+const maxDigestBuffer = 1024
+
+type tpmBlock struct {
+	key *Key
+}
+
+// BlockSize returns the AES block size.
+func (b *tpmBlock) BlockSize() int {
+	return aesBlockSize
+}
+
+// Encrypt encrypts the first block of src into dst with the TPM key.
+func (b *tpmBlock) Encrypt(dst, src []byte) {
+	b.crypt(dst, src, false)
+}
+
+// Decrypt decrypts the first block of src into dst with the TPM key.
+func (b *tpmBlock) Decrypt(dst, src []byte) {
+	b.crypt(dst, src, true)
+}
+
+func (b *tpmBlock) crypt(dst, src []byte, decrypt bool) {
+	if len(src) < aesBlockSize {
+		panic("client: input not full block")
+	}
+	if len(dst) < aesBlockSize {
+		panic("client: output not full block")
+	}
+
+	signerMutex.Lock()
+	defer signerMutex.Unlock()
+
+	handle, err := b.key.passwordHandle()
+	if err != nil {
+		panic(err)
+	}
+	rsp, err := directtpm2.EncryptDecrypt2{
+		KeyHandle: handle,
+		Message:   directtpm2.TPM2BMaxBuffer{Buffer: src[:aesBlockSize]},
+		Decrypt:   directtpm2.TPMIYesNo(decrypt),
+		Mode:      directtpm2.TPMAlgECB,
+	}.Execute(transport.FromReadWriter(b.key.rw))
+	if err != nil {
+		panic(fmt.Errorf("client: TPM2_EncryptDecrypt2 failed: %w", err))
+	}
+	copy(dst, rsp.OutData.Buffer)
+}
+
+// GetBlock returns a cipher.Block wrapping the loaded TPM Key, which must be
+// an AES key (see AESKeyTemplate). The key never leaves the TPM.
+// Each call to Encrypt or Decrypt runs a TPM command, so the Block is only
+// suitable for small messages, such as tokens. As cipher.Block cannot return
+// errors, Encrypt and Decrypt panic if the TPM command fails.
+// Concurrent use of the Block is thread safe, but it is not safe to access the
+// TPM from other sources while using it. The returned Block lasts the lifetime
+// of the Key, and will no longer work once the Key has been closed.
+func (k *Key) GetBlock() (cipher.Block, error) {
+	if k.pubArea.Type != tpm2.AlgSymCipher || k.pubArea.SymCipherParameters == nil {
+		return nil, fmt.Errorf("unsupported key type: %v", k.pubArea.Type)
+	}
+	scheme := k.pubArea.SymCipherParameters.Symmetric
+	if scheme == nil || scheme.Alg != tpm2.AlgAES {
+		return nil, errors.New("key must be an AES key")
+	}
+	// The key's mode, if set, must match the mode of each command.
+	if scheme.Mode != tpm2.AlgNull && scheme.Mode != tpm2.AlgECB {
+		return nil, fmt.Errorf("unsupported key mode: %v", scheme.Mode)
+	}
+	if _, err := k.passwordHandle(); err != nil {
+		return nil, err
+	}
+	return &tpmBlock{k}, nil
+}
+
+// GetAEAD returns an AES-GCM cipher.AEAD, with the standard nonce size,
+// wrapping the loaded TPM Key. See GetBlock for the requirements on the Key.
+func (k *Key) GetAEAD() (cipher.AEAD, error) {
+	block, err := k.GetBlock()
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+type tpmHMAC struct {
+	key  *Key
+	hash crypto.Hash
+	data bytes.Buffer
+}
+
+// Write adds data to the HMAC. The data is buffered until Sum is called.
+func (h *tpmHMAC) Write(p []byte) (int, error) {
+	return h.data.Write(p)
+}
+
+// Sum appends the HMAC of the data written so far to b.
+func (h *tpmHMAC) Sum(b []byte) []byte {
+	mac, err := h.key.HMAC(h.data.Bytes())
+	if err != nil {
+		panic(err)
+	}
+	return append(b, mac...)
+}
+
+// Reset discards the data written so far.
+func (h *tpmHMAC) Reset() {
+	h.data.Reset()
+}
+
+// Size returns the size of the HMAC.
+func (h *tpmHMAC) Size() int {
+	return h.hash.Size()
+}
+
+// BlockSize returns the block size of the HMAC's hash function.
+func (h *tpmHMAC) BlockSize() int {
+	return h.hash.New().BlockSize()
+}
+
+// GetHMAC returns a hash.Hash computing HMACs with the loaded TPM Key, which
+// must be an HMAC key (see HMACKeyTemplate). The key never leaves the TPM.
+// The data written is buffered, and its HMAC is computed by the TPM when Sum
+// is called. As hash.Hash cannot return errors, Sum panics if the TPM commands
+// fail. The same thread safety and lifetime rules as GetBlock apply.
+func (k *Key) GetHMAC() (hash.Hash, error) {
+	hashAlg, err := k.hmacHash()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := k.passwordHandle(); err != nil {
+		return nil, err
+	}
+	return &tpmHMAC{key: k, hash: hashAlg}, nil
+}
+
+// HMAC computes the HMAC of data with the loaded TPM Key, which must be an
+// HMAC key (see HMACKeyTemplate).
+func (k *Key) HMAC(data []byte) ([]byte, error) {
+	if _, err := k.hmacHash(); err != nil {
+		return nil, err
+	}
+	handle, err := k.passwordHandle()
+	if err != nil {
+		return nil, err
+	}
+
+	signerMutex.Lock()
+	defer signerMutex.Unlock()
+
+	tpm := transport.FromReadWriter(k.rw)
+	if len(data) <= maxDigestBuffer {
+		rsp, err := directtpm2.Hmac{
+			Handle:  handle,
+			Buffer:  directtpm2.TPM2BMaxBuffer{Buffer: data},
+			HashAlg: directtpm2.TPMAlgNull,
+		}.Execute(tpm)
+		if err != nil {
+			return nil, fmt.Errorf("TPM2_HMAC failed: %w", err)
+		}
+		return rsp.OutHMAC.Buffer, nil
+	}
+
+	// Larger inputs need an HMAC sequence.
+	start, err := directtpm2.HmacStart{
+		Handle:  handle,
+		HashAlg: directtpm2.TPMAlgNull,
+	}.Execute(tpm)
+	if err != nil {
+		return nil, fmt.Errorf("TPM2_HMAC_Start failed: %w", err)
+	}
+	sequence := directtpm2.AuthHandle{
+		Handle: start.SequenceHandle,
+		Auth:   directtpm2.PasswordAuth(nil),
+	}
+	for len(data) > maxDigestBuffer {
+		if _, err := (directtpm2.SequenceUpdate{
+			SequenceHandle: sequence,
+			Buffer:         directtpm2.TPM2BMaxBuffer{Buffer: data[:maxDigestBuffer]},
+		}).Execute(tpm); err != nil {
+			directtpm2.FlushContext{FlushHandle: start.SequenceHandle}.Execute(tpm)
+			return nil, fmt.Errorf("TPM2_SequenceUpdate failed: %w", err)
+		}
+		data = data[maxDigestBuffer:]
+	}
+	// TPM2_SequenceComplete flushes the sequence.
+	rsp, err := directtpm2.SequenceComplete{
+		SequenceHandle: sequence,
+		Buffer:         directtpm2.TPM2BMaxBuffer{Buffer: data},
+		Hierarchy:      directtpm2.TPMRHNull,
+	}.Execute(tpm)
+	if err != nil {
+		return nil, fmt.Errorf("TPM2_SequenceComplete failed: %w", err)
+	}
+	return rsp.Result.Buffer, nil
+}
+
+// hmacHash returns the hash algorithm of an HMAC key.
+func (k *Key) hmacHash() (crypto.Hash, error) {
+	params := k.pubArea.KeyedHashParameters
+	if k.pubArea.Type != tpm2.AlgKeyedHash || params == nil || params.Alg != tpm2.AlgHMAC {
+		return 0, errors.New("key must be an HMAC key")
+	}
+	return params.Hash.Hash()
+}
+
+// passwordHandle returns the key's handle, authorized with an empty password.
+func (k *Key) passwordHandle() (directtpm2.AuthHandle, error) {
+	if _, ok := k.session.(NullSession); !ok {
+		return directtpm2.AuthHandle{}, errors.New("key must be usable with an empty password")
+	}
+	name, err := k.name.Digest.Encode()
+	if err != nil {
+		return directtpm2.AuthHandle{}, fmt.Errorf("failed to encode key name: %w", err)
+	}
+	return directtpm2.AuthHandle{
+		Handle: directtpm2.TPMHandle(k.Handle()),
+		Name:   directtpm2.TPM2BName{Buffer: name},
+		Auth:   directtpm2.PasswordAuth(nil),
+	}, nil
+}
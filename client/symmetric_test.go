@@ -0,0 +1,213 @@
+package client_test
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"io"
+	"testing"
+
+	"github.com/google/go-tpm/legacy/tpm2"
+	directtpm2 "github.com/google/go-tpm/tpm2"
+	"github.com/google/go-tpm/tpm2/transport"
+	"github.com/google/go-tpm/tpmutil"
+
+	"github.com/google/go-tpm-tools/client"
+	"github.com/google/go-tpm-tools/internal/test"
+)
+
+// loadKnownKey creates a primary key from template with a known key value,
+// so the TPM's results can be checked against a software implementation.
+// The caller must close the key.
+func loadKnownKey(t *testing.T, rw io.ReadWriter, template tpm2.Public, keyValue []byte) *client.Key {
+	t.Helper()
+	// Only keys generated by the TPM have FlagSensitiveDataOrigin.
+	template.Attributes &^= tpm2.FlagSensitiveDataOrigin
+	encoded, err := template.Encode()
+	if err != nil {
+		t.Fatalf("failed to encode template: %v", err)
+	}
+	rsp, err := directtpm2.CreatePrimary{
+		PrimaryHandle: directtpm2.TPMRHOwner,
+		InSensitive: directtpm2.TPM2BSensitiveCreate{
+			Sensitive: &directtpm2.TPMSSensitiveCreate{
+				Data: directtpm2.NewTPMUSensitiveCreate(&directtpm2.TPM2BSensitiveData{Buffer: keyValue}),
+			},
+		},
+		InPublic: directtpm2.BytesAs2B[directtpm2.TPMTPublic](encoded),
+	}.Execute(transport.FromReadWriter(rw))
+	if err != nil {
+		t.Fatalf("failed to create key: %v", err)
+	}
+	key, err := client.LoadCachedKey(rw, tpmutil.Handle(rsp.ObjectHandle), client.NullSession{})
+	if err != nil {
+		t.Fatalf("failed to load key: %v", err)
+	}
+	return key
+}
+
+func TestAESKey(t *testing.T) {
+	rwc := test.GetTPM(t)
+	defer client.CheckedClose(t, rwc)
+
+	keyValue := bytes.Repeat([]byte{0x42}, 16)
+	key := loadKnownKey(t, rwc, client.AESKeyTemplate(), keyValue)
+	defer key.Close()
+	if key.PublicKey() != nil {
+		t.Errorf("PublicKey() = %v, want nil", key.PublicKey())
+	}
+	aead, err := key.GetAEAD()
+	if err != nil {
+		t.Fatalf("GetAEAD() failed: %v", err)
+	}
+	block, err := aes.NewCipher(keyValue)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := cipher.NewGCM(block)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	plaintext := []byte("a token that is longer than one AES block")
+	ciphertext := aead.Seal(nil, nonce, plaintext, []byte("additional data"))
+	if wantCiphertext := want.Seal(nil, nonce, plaintext, []byte("additional data")); !bytes.Equal(ciphertext, wantCiphertext) {
+		t.Errorf("Seal() = %x, want %x", ciphertext, wantCiphertext)
+	}
+	got, err := aead.Open(nil, nonce, ciphertext, []byte("additional data"))
+	if err != nil {
+		t.Fatalf("Open() failed: %v", err)
+	}
+	if !bytes.Equal(got, plaintext) {
+		t.Errorf("Open() = %q, want %q", got, plaintext)
+	}
+	if _, err := aead.Open(nil, nonce, ciphertext, []byte("other data")); err == nil {
+		t.Error("Open() with the wrong additional data succeeded, expected failure")
+	}
+
+	tpmBlock, err := key.GetBlock()
+	if err != nil {
+		t.Fatalf("GetBlock() failed: %v", err)
+	}
+	src := []byte("0123456789abcdef")
+	dst := make([]byte, len(src))
+	tpmBlock.Encrypt(dst, src)
+	wantDst := make([]byte, len(src))
+	block.Encrypt(wantDst, src)
+	if !bytes.Equal(dst, wantDst) {
+		t.Errorf("Encrypt() = %x, want %x", dst, wantDst)
+	}
+	tpmBlock.Decrypt(dst, dst)
+	if !bytes.Equal(dst, src) {
+		t.Errorf("Decrypt() = %q, want %q", dst, src)
+	}
+}
+
+func TestHMACKey(t *testing.T) {
+	rwc := test.GetTPM(t)
+	defer client.CheckedClose(t, rwc)
+
+	keyValue := []byte("HMAC key")
+	key := loadKnownKey(t, rwc, client.HMACKeyTemplate(), keyValue)
+	defer key.Close()
+	for _, size := range []int{0, 100, 1024, 3000} {
+		data := bytes.Repeat([]byte{0xAB}, size)
+		want := hmac.New(sha256.New, keyValue)
+		want.Write(data)
+
+		got, err := key.HMAC(data)
+		if err != nil {
+			t.Fatalf("HMAC() of %d bytes failed: %v", size, err)
+		}
+		if !bytes.Equal(got, want.Sum(nil)) {
+			t.Errorf("HMAC() of %d bytes = %x, want %x", size, got, want.Sum(nil))
+		}
+
+		h, err := key.GetHMAC()
+		if err != nil {
+			t.Fatalf("GetHMAC() failed: %v", err)
+		}
+		h.Write([]byte("discarded"))
+		h.Reset()
+		h.Write(data[:size/2])
+		h.Write(data[size/2:])
+		if got := h.Sum([]byte("prefix")); !bytes.Equal(got, want.Sum([]byte("prefix"))) {
+			t.Errorf("Sum() of %d bytes = %x, want %x", size, got, want.Sum([]byte("prefix")))
+		}
+		if h.Size() != want.Size() || h.BlockSize() != want.BlockSize() {
+			t.Errorf("got Size() %d and BlockSize() %d, want %d and %d", h.Size(), h.BlockSize(), want.Size(), want.BlockSize())
+		}
+	}
+}
+
+func TestSymmetricKeyFromTemplate(t *testing.T) {
+	rwc := test.GetTPM(t)
+	defer client.CheckedClose(t, rwc)
+
+	// Primary keys are derived from the template, so recreating them gives
+	// the same keys, while changing the template's unique field does not.
+	aeads := make([]cipher.AEAD, 2)
+	for i := range aeads {
+		aesKey, err := client.NewKey(rwc, tpm2.HandleOwner, client.AESKeyTemplate())
+		if err != nil {
+			t.Fatalf("failed to create AES key: %v", err)
+		}
+		defer aesKey.Close()
+		if aeads[i], err = aesKey.GetAEAD(); err != nil {
+			t.Fatalf("GetAEAD() failed: %v", err)
+		}
+	}
+	nonce := make([]byte, aeads[0].NonceSize())
+	ciphertext := aeads[0].Seal(nil, nonce, []byte("secret"), nil)
+	if _, err := aeads[1].Open(nil, nonce, ciphertext, nil); err != nil {
+		t.Errorf("Open() with the recreated key failed: %v", err)
+	}
+
+	otherTemplate := client.HMACKeyTemplate()
+	otherTemplate.KeyedHashParameters.Unique = []byte("other key")
+	var macs [][]byte
+	for _, template := range []tpm2.Public{client.HMACKeyTemplate(), otherTemplate} {
+		hmacKey, err := client.NewKey(rwc, tpm2.HandleOwner, template)
+		if err != nil {
+			t.Fatalf("failed to create HMAC key: %v", err)
+		}
+		mac, err := hmacKey.HMAC([]byte("token"))
+		hmacKey.Close()
+		if err != nil {
+			t.Fatalf("HMAC() failed: %v", err)
+		}
+		macs = append(macs, mac)
+	}
+	if bytes.Equal(macs[0], macs[1]) {
+		t.Error("HMAC keys with different unique values computed the same HMAC")
+	}
+}
+
+func TestSymmetricKeyFail(t *testing.T) {
+	rwc := test.GetTPM(t)
+	defer client.CheckedClose(t, rwc)
+
+	ak, err := client.AttestationKeyECC(rwc)
+	if err != nil {
+		t.Fatalf("failed to create AK: %v", err)
+	}
+	defer ak.Close()
+	if _, err := ak.GetAEAD(); err == nil {
+		t.Error("GetAEAD() with an AK succeeded, expected failure")
+	}
+	if _, err := ak.HMAC([]byte("token")); err == nil {
+		t.Error("HMAC() with an AK succeeded, expected failure")
+	}
+
+	aesKey, err := client.NewKey(rwc, tpm2.HandleOwner, client.AESKeyTemplate())
+	if err != nil {
+		t.Fatalf("failed to create AES key: %v", err)
+	}
+	defer aesKey.Close()
+	if _, err := aesKey.GetHMAC(); err == nil {
+		t.Error("GetHMAC() with an AES key succeeded, expected failure")
+	}
+}
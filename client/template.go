@@ -1,7 +1,12 @@
 package client
 
 import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
 	"crypto/sha256"
+	"fmt"
 
 	"github.com/google/go-tpm/legacy/tpm2"
 	"github.com/google/go-tpm/tpmutil"
@@ -141,3 +146,121 @@ func SRKTemplateECC() tpm2.Public {
 		ECCParameters: defaultECCParams(),
 	}
 }
+
+func defaultUnrestrictedAttributes() tpm2.KeyProp {
+	// Not restricted, so the key can be used with any data.
+	return tpm2.FlagFixedTPM | tpm2.FlagFixedParent | tpm2.FlagSensitiveDataOrigin | tpm2.FlagUserWithAuth
+}
+
+// DecryptionKeyTemplateRSA returns an unrestricted RSA 2048 decryption key
+// template, for use with Key.GetDecrypter. The padding scheme is chosen
+// for each decryption.
+func DecryptionKeyTemplateRSA() tpm2.Public {
+	return tpm2.Public{
+		Type:       tpm2.AlgRSA,
+		NameAlg:    tpm2.AlgSHA256,
+		Attributes: defaultUnrestrictedAttributes() | tpm2.FlagDecrypt,
+		RSAParameters: &tpm2.RSAParams{
+			KeyBits: 2048,
+		},
+	}
+}
+
+// DecryptionKeyTemplateECC returns an unrestricted NIST P-256 decryption key
+// template, for key agreement with Key.ECDH.
+func DecryptionKeyTemplateECC() tpm2.Public {
+	params := defaultECCParams()
+	params.Symmetric = nil
+	return tpm2.Public{
+		Type:          tpm2.AlgECC,
+		NameAlg:       tpm2.AlgSHA256,
+		Attributes:    defaultUnrestrictedAttributes() | tpm2.FlagDecrypt,
+		ECCParameters: params,
+	}
+}
+
+// AESKeyTemplate returns an AES-128 key template, for use with Key.GetBlock
+// and Key.GetAEAD. The key can encrypt and decrypt in any mode.
+// Like all primary keys, the key is derived from the hierarchy's seed and the
+// template, so set SymCipherParameters.Unique to create distinct keys.
+func AESKeyTemplate() tpm2.Public {
+	return tpm2.Public{
+		Type:       tpm2.AlgSymCipher,
+		NameAlg:    tpm2.AlgSHA256,
+		Attributes: defaultUnrestrictedAttributes() | tpm2.FlagSign | tpm2.FlagDecrypt,
+		SymCipherParameters: &tpm2.SymCipherParams{
+			Symmetric: &tpm2.SymScheme{
+				Alg:     tpm2.AlgAES,
+				KeyBits: 128,
+				Mode:    tpm2.AlgNull,
+			},
+		},
+	}
+}
+
+// HMACKeyTemplate returns an HMAC-SHA256 key template, for use with Key.HMAC
+// and Key.GetHMAC.
+// Like all primary keys, the key is derived from the hierarchy's seed and the
+// template, so set KeyedHashParameters.Unique to create distinct keys.
+func HMACKeyTemplate() tpm2.Public {
+	return tpm2.Public{
+		Type:       tpm2.AlgKeyedHash,
+		NameAlg:    tpm2.AlgSHA256,
+		Attributes: defaultUnrestrictedAttributes() | tpm2.FlagSign,
+		KeyedHashParameters: &tpm2.KeyedHashParams{
+			Alg:  tpm2.AlgHMAC,
+			Hash: tpm2.AlgSHA256,
+		},
+	}
+}
+
+// policySignerTemplate returns the public area used to load a key that signs
+// PCR policies for TPM2_PolicyAuthorize. The Name of this public area is bound
+// to the sealed object's authPolicy, so it must not change.
+func policySignerTemplate(pub crypto.PublicKey) (tpm2.Public, error) {
+	sigScheme := &tpm2.SigScheme{Hash: tpm2.AlgSHA256}
+	switch pub := pub.(type) {
+	case *rsa.PublicKey:
+		sigScheme.Alg = tpm2.AlgRSASSA
+		return tpm2.Public{
+			Type:       tpm2.AlgRSA,
+			NameAlg:    tpm2.AlgSHA256,
+			Attributes: tpm2.FlagSign | tpm2.FlagUserWithAuth,
+			RSAParameters: &tpm2.RSAParams{
+				Sign:        sigScheme,
+				KeyBits:     uint16(pub.N.BitLen()),
+				ExponentRaw: uint32(pub.E),
+				ModulusRaw:  pub.N.Bytes(),
+			},
+		}, nil
+	case *ecdsa.PublicKey:
+		var curve tpm2.EllipticCurve
+		switch pub.Curve {
+		case elliptic.P256():
+			curve = tpm2.CurveNISTP256
+		case elliptic.P384():
+			curve = tpm2.CurveNISTP384
+		case elliptic.P521():
+			curve = tpm2.CurveNISTP521
+		default:
+			return tpm2.Public{}, fmt.Errorf("unsupported curve: %v", pub.Curve.Params().Name)
+		}
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		sigScheme.Alg = tpm2.AlgECDSA
+		return tpm2.Public{
+			Type:       tpm2.AlgECC,
+			NameAlg:    tpm2.AlgSHA256,
+			Attributes: tpm2.FlagSign | tpm2.FlagUserWithAuth,
+			ECCParameters: &tpm2.ECCParams{
+				Sign:    sigScheme,
+				CurveID: curve,
+				Point: tpm2.ECPoint{
+					XRaw: pub.X.FillBytes(make([]byte, size)),
+					YRaw: pub.Y.FillBytes(make([]byte, size)),
+				},
+			},
+		}, nil
+	default:
+		return tpm2.Public{}, fmt.Errorf("unsupported policy signer key type: %T", pub)
+	}
+}
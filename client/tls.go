@@ -0,0 +1,128 @@
+package client
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/go-tpm-tools/internal"
+	"github.com/google/go-tpm/legacy/tpm2"
+)
+
+// tlsSignatureSchemesRSA are the TLS signature schemes of RSA keys, by hash
+// algorithm. RSASSA-PSS keys are not supported, as the TPM's salt length is
+// not the one required by TLS.
+var tlsSignatureSchemesRSA = map[crypto.Hash]tls.SignatureScheme{
+	crypto.SHA1:   tls.PKCS1WithSHA1,
+	crypto.SHA256: tls.PKCS1WithSHA256,
+	crypto.SHA384: tls.PKCS1WithSHA384,
+	crypto.SHA512: tls.PKCS1WithSHA512,
+}
+
+// tlsSignatureSchemesECDSA are the TLS signature schemes of ECDSA keys, by
+// curve. The key's hash algorithm must match the curve.
+var tlsSignatureSchemesECDSA = map[elliptic.Curve]struct {
+	hash   crypto.Hash
+	scheme tls.SignatureScheme
+}{
+	elliptic.P256(): {crypto.SHA256, tls.ECDSAWithP256AndSHA256},
+	elliptic.P384(): {crypto.SHA384, tls.ECDSAWithP384AndSHA384},
+	elliptic.P521(): {crypto.SHA512, tls.ECDSAWithP521AndSHA512},
+}
+
+// TLSCertificate returns a tls.Certificate for the loaded TPM Key, which must
+// be an unrestricted signing key with a certificate (see SetCert). If
+// certChainFetcher is non-nil, the certificate chain is completed by fetching
+// the intermediate certificates, as with AttestOpts.CertChainFetcher.
+//
+// The Key's signing scheme must be RSASSA or ECDSA, with the hash algorithm
+// matching the curve for ECDSA. RSA keys can only be used with TLS 1.2, as TLS
+// 1.3 requires RSA-PSS signatures. The Certificate uses the Key's Signer (see
+// GetSigner), so it lasts the lifetime of the Key.
+func (k *Key) TLSCertificate(certChainFetcher *http.Client) (tls.Certificate, error) {
+	if k.cert == nil {
+		return tls.Certificate{}, errors.New("key has no certificate")
+	}
+	chain := [][]byte{k.cert.Raw}
+	if certChainFetcher != nil {
+		intermediates, err := internal.GetCertificateChain(k.cert, certChainFetcher)
+		if err != nil {
+			return tls.Certificate{}, fmt.Errorf("failed to fetch certificate chain: %w", err)
+		}
+		chain = append(chain, intermediates...)
+	}
+	return k.tlsCertificate(chain, k.cert)
+}
+
+func (k *Key) tlsCertificate(chain [][]byte, leaf *x509.Certificate) (tls.Certificate, error) {
+	scheme, err := k.tlsSignatureScheme()
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	signer, err := k.GetSigner()
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	return tls.Certificate{
+		Certificate:                  chain,
+		PrivateKey:                   signer,
+		SupportedSignatureAlgorithms: []tls.SignatureScheme{scheme},
+		Leaf:                         leaf,
+	}, nil
+}
+
+// tlsSignatureScheme returns the TLS signature scheme of the key's signatures.
+func (k *Key) tlsSignatureScheme() (tls.SignatureScheme, error) {
+	hashAlg, err := internal.GetSigningHashAlg(k.pubArea)
+	if err != nil {
+		return 0, err
+	}
+	hash, err := hashAlg.Hash()
+	if err != nil {
+		return 0, err
+	}
+	switch pub := k.pubKey.(type) {
+	case *ecdsa.PublicKey:
+		if s, ok := tlsSignatureSchemesECDSA[pub.Curve]; ok && s.hash == hash {
+			return s.scheme, nil
+		}
+	default:
+		if k.pubArea.RSAParameters.Sign.Alg != tpm2.AlgRSASSA {
+			return 0, fmt.Errorf("unsupported signing scheme for TLS: %v", k.pubArea.RSAParameters.Sign.Alg)
+		}
+		if scheme, ok := tlsSignatureSchemesRSA[hash]; ok {
+			return scheme, nil
+		}
+	}
+	return 0, fmt.Errorf("unsupported hash algorithm for TLS: %v", hash)
+}
+
+// x509SignatureAlgorithm returns the X.509 signature algorithm of the key's
+// signatures.
+func (k *Key) x509SignatureAlgorithm() (x509.SignatureAlgorithm, error) {
+	scheme, err := k.tlsSignatureScheme()
+	if err != nil {
+		return 0, err
+	}
+	switch scheme {
+	case tls.PKCS1WithSHA1:
+		return x509.SHA1WithRSA, nil
+	case tls.PKCS1WithSHA256:
+		return x509.SHA256WithRSA, nil
+	case tls.PKCS1WithSHA384:
+		return x509.SHA384WithRSA, nil
+	case tls.PKCS1WithSHA512:
+		return x509.SHA512WithRSA, nil
+	case tls.ECDSAWithP256AndSHA256:
+		return x509.ECDSAWithSHA256, nil
+	case tls.ECDSAWithP384AndSHA384:
+		return x509.ECDSAWithSHA384, nil
+	default:
+		return x509.ECDSAWithSHA512, nil
+	}
+}
@@ -0,0 +1,171 @@
+package client_test
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-tpm/legacy/tpm2"
+
+	"github.com/google/go-tpm-tools/client"
+	"github.com/google/go-tpm-tools/internal/test"
+)
+
+const testServerName = "tpm.example.com"
+
+// issueTLSCert returns a TLS server certificate for pubKey, issued by the
+// provided CA.
+func issueTLSCert(t *testing.T, pubKey crypto.PublicKey, ca *x509.Certificate, caKey *rsa.PrivateKey, issuingURL []string) *x509.Certificate {
+	t.Helper()
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(2),
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().AddDate(1, 0, 0),
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		DNSNames:              []string{testServerName},
+		IssuingCertificateURL: issuingURL,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca, pubKey, caKey)
+	if err != nil {
+		t.Fatalf("Unable to create test certificate: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("Unable to parse test certificate: %v", err)
+	}
+	return cert
+}
+
+// tlsHandshake runs a TLS handshake between a server with cert and a client
+// trusting roots.
+func tlsHandshake(cert tls.Certificate, roots *x509.CertPool, maxVersion uint16) error {
+	serverConn, clientConn := net.Pipe()
+	defer serverConn.Close()
+	defer clientConn.Close()
+
+	clientErr := make(chan error, 1)
+	go func() {
+		conn := tls.Client(clientConn, &tls.Config{RootCAs: roots, ServerName: testServerName})
+		err := conn.Handshake()
+		if err != nil {
+			// Unblock the server.
+			clientConn.Close()
+		}
+		clientErr <- err
+	}()
+	conn := tls.Server(serverConn, &tls.Config{Certificates: []tls.Certificate{cert}, MaxVersion: maxVersion})
+	if err := conn.Handshake(); err != nil {
+		return err
+	}
+	return <-clientErr
+}
+
+func TestTLSCertificate(t *testing.T) {
+	rwc := test.GetTPM(t)
+	defer client.CheckedClose(t, rwc)
+
+	ca, caKey := getTestCert(t, nil, nil, nil)
+	roots := x509.NewCertPool()
+	roots.AddCert(ca)
+
+	keys := []struct {
+		name       string
+		template   tpm2.Public
+		maxVersion uint16
+	}{
+		{"RSA-SHA256", templateSSA(tpm2.AlgSHA256), tls.VersionTLS12},
+		{"RSA-SHA384", templateSSA(tpm2.AlgSHA384), tls.VersionTLS12},
+		{"ECC-P256", templateECC(tpm2.AlgSHA256), tls.VersionTLS13},
+	}
+	for _, k := range keys {
+		t.Run(k.name, func(t *testing.T) {
+			key, err := client.NewKey(rwc, tpm2.HandleEndorsement, k.template)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer key.Close()
+
+			if err := key.SetCert(issueTLSCert(t, key.PublicKey(), ca, caKey, nil)); err != nil {
+				t.Fatal(err)
+			}
+			cert, err := key.TLSCertificate(nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := tlsHandshake(cert, roots, k.maxVersion); err != nil {
+				t.Errorf("TLS handshake failed: %v", err)
+			}
+		})
+	}
+}
+
+func TestTLSCertificateFetchesChain(t *testing.T) {
+	ca, caKey := test.GetTestCert(t, nil, nil, nil)
+	caServer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		rw.Write(ca.Raw)
+	}))
+	defer caServer.Close()
+
+	rwc := test.GetTPM(t)
+	defer client.CheckedClose(t, rwc)
+	key, err := client.NewKey(rwc, tpm2.HandleEndorsement, templateECC(tpm2.AlgSHA256))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer key.Close()
+	if err := key.SetCert(issueTLSCert(t, key.PublicKey(), ca, caKey, []string{caServer.URL})); err != nil {
+		t.Fatal(err)
+	}
+
+	cert, err := key.TLSCertificate(http.DefaultClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cert.Certificate) != 2 || !bytes.Equal(cert.Certificate[1], ca.Raw) {
+		t.Errorf("TLSCertificate() did not include the issuing certificate")
+	}
+}
+
+func TestTLSCertificateFails(t *testing.T) {
+	rwc := test.GetTPM(t)
+	defer client.CheckedClose(t, rwc)
+
+	ca, caKey := getTestCert(t, nil, nil, nil)
+	keys := []struct {
+		name     string
+		template tpm2.Public
+		setCert  bool
+	}{
+		{"NoCert", templateECC(tpm2.AlgSHA256), false},
+		{"PSS", templatePSS(tpm2.AlgSHA256), true},
+		{"ECC-P256-SHA384", templateECC(tpm2.AlgSHA384), true},
+	}
+	for _, k := range keys {
+		t.Run(k.name, func(t *testing.T) {
+			key, err := client.NewKey(rwc, tpm2.HandleEndorsement, k.template)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer key.Close()
+
+			if k.setCert {
+				if err := key.SetCert(issueTLSCert(t, key.PublicKey(), ca, caKey, nil)); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if _, err := key.TLSCertificate(nil); err == nil {
+				t.Error("TLSCertificate() succeeded, want error")
+			}
+		})
+	}
+}
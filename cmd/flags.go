@@ -28,6 +28,7 @@ var (
 	eventLog    string
 	cloudLog    bool
 	customNonce []string
+	envelope    bool
 )
 
 type pcrsFlag struct {
@@ -157,6 +158,12 @@ func addEventLogFlag(cmd *cobra.Command) {
 	cmd.PersistentFlags().StringVar(&eventLog, "event-log", "/sys/kernel/security/tpm0/binary_bios_measurements", "specifies the event log file path.")
 }
 
+// Lets this command seal or unseal data of any size with envelope encryption.
+func addEnvelopeFlag(cmd *cobra.Command) {
+	cmd.PersistentFlags().BoolVar(&envelope, "envelope", false,
+		"encrypt the data with a sealed AES-256-GCM key, so it can be larger than the TPM's limit")
+}
+
 // Lets this command specify an NVDATA index, for use with nvIndex.
 func addIndexFlag(cmd *cobra.Command) {
 	cmd.PersistentFlags().Uint32Var(&nvIndex, "index", 0,
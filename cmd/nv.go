@@ -0,0 +1,264 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/google/go-tpm-tools/client"
+	"github.com/google/go-tpm/legacy/tpm2"
+	directtpm2 "github.com/google/go-tpm/tpm2"
+	"github.com/google/go-tpm/tpmutil"
+	"github.com/spf13/cobra"
+)
+
+var nvTypes = map[string]directtpm2.TPMNT{
+	"ordinary": directtpm2.TPMNTOrdinary,
+	"counter":  directtpm2.TPMNTCounter,
+	"bits":     directtpm2.TPMNTBits,
+	"extend":   directtpm2.TPMNTExtend,
+}
+
+var (
+	nvType     string
+	nvSize     uint16
+	nvHashAlgo = tpm2.AlgSHA256
+	nvPassword string
+)
+
+var nvCmd = &cobra.Command{
+	Use:   "nv",
+	Short: "Manage TPM NV indices",
+	Long: `Define, use and delete NV indices in the TPM's non-volatile memory
+
+Indices are defined in the owner hierarchy (with an empty owner password), and
+are accessed with the index's own authorization: its --password, and (if the
+index was defined with --pcrs) the values of the PCRs when it was defined.
+Indices defined with --pcrs can only be accessed with the same --pcrs and
+--password flags, while the PCRs have the same values.`,
+	Args: cobra.NoArgs,
+}
+
+var nvDefineIndexCmd = &cobra.Command{
+	Use:   "define",
+	Short: "Define an NV index",
+	Long: `Define an NV index at --index
+
+The --type flag selects the type of the index:
+	ordinary - stores --size bytes of data, written with "gotpm nv write"
+	counter  - a 64-bit counter, incremented with "gotpm nv increment"
+	bits     - a 64-bit bit field
+	extend   - a digest using --hash-algo, extended with "gotpm nv extend"`,
+	Args: cobra.NoArgs,
+	RunE: func(*cobra.Command, []string) error {
+		typ, ok := nvTypes[nvType]
+		if !ok {
+			return fmt.Errorf("unknown NV index type %q, expected one of: %s", nvType, nvTypeNames())
+		}
+		rwc, err := openTpm()
+		if err != nil {
+			return err
+		}
+		defer rwc.Close()
+
+		opts := client.NVDefineOpts{
+			Type:     typ,
+			Size:     nvSize,
+			HashAlg:  nvHashAlgo,
+			Password: nvPassword,
+		}
+		if opts.Policy, err = nvPolicy(rwc); err != nil {
+			return err
+		}
+		if opts.Policy != nil {
+			// Only allow access with the policy.
+			opts.Attributes = tpm2.AttrPolicyRead | tpm2.AttrPolicyWrite
+		}
+		if err := client.NVDefine(rwc, tpmutil.Handle(nvIndex), opts); err != nil {
+			return err
+		}
+		fmt.Fprintf(debugOutput(), "Defined NV index 0x%x\n", nvIndex)
+		return nil
+	},
+}
+
+var nvUndefineIndexCmd = &cobra.Command{
+	Use:   "undefine",
+	Short: "Delete an NV index",
+	Long:  `Delete the NV index at --index`,
+	Args:  cobra.NoArgs,
+	RunE: func(*cobra.Command, []string) error {
+		rwc, err := openTpm()
+		if err != nil {
+			return err
+		}
+		defer rwc.Close()
+
+		if err := client.NVUndefine(rwc, tpmutil.Handle(nvIndex), ""); err != nil {
+			return err
+		}
+		fmt.Fprintf(debugOutput(), "Deleted NV index 0x%x\n", nvIndex)
+		return nil
+	},
+}
+
+var nvWriteIndexCmd = &cobra.Command{
+	Use:   "write",
+	Short: "Write data to an NV index",
+	Long:  `Write the input data to the start of the ordinary NV index at --index`,
+	Args:  cobra.NoArgs,
+	RunE: func(*cobra.Command, []string) error {
+		data, err := io.ReadAll(dataInput())
+		if err != nil {
+			return err
+		}
+		return runNVCommand(func(rwc io.ReadWriter, auth client.NVAuth) error {
+			return client.NVWrite(rwc, tpmutil.Handle(nvIndex), data, 0, auth)
+		})
+	},
+}
+
+var nvExtendIndexCmd = &cobra.Command{
+	Use:   "extend",
+	Short: "Extend data into an NV index",
+	Long:  `Extend the input data into the extend NV index at --index`,
+	Args:  cobra.NoArgs,
+	RunE: func(*cobra.Command, []string) error {
+		data, err := io.ReadAll(dataInput())
+		if err != nil {
+			return err
+		}
+		return runNVCommand(func(rwc io.ReadWriter, auth client.NVAuth) error {
+			return client.NVExtend(rwc, tpmutil.Handle(nvIndex), data, auth)
+		})
+	},
+}
+
+var nvIncrementIndexCmd = &cobra.Command{
+	Use:   "increment",
+	Short: "Increment an NV counter",
+	Long:  `Increment the counter NV index at --index`,
+	Args:  cobra.NoArgs,
+	RunE: func(*cobra.Command, []string) error {
+		return runNVCommand(func(rwc io.ReadWriter, auth client.NVAuth) error {
+			return client.NVIncrement(rwc, tpmutil.Handle(nvIndex), auth)
+		})
+	},
+}
+
+var nvReadIndexCmd = &cobra.Command{
+	Use:   "read",
+	Short: "Read data from an NV index",
+	Long: `Read all the data of the NV index at --index
+
+Counter and bit field indices contain a big-endian 64-bit integer.`,
+	Args: cobra.NoArgs,
+	RunE: func(*cobra.Command, []string) error {
+		return runNVCommand(func(rwc io.ReadWriter, auth client.NVAuth) error {
+			data, err := client.NVRead(rwc, tpmutil.Handle(nvIndex), auth)
+			if err != nil {
+				return err
+			}
+			if _, err := dataOutput().Write(data); err != nil {
+				return fmt.Errorf("cannot output NV data: %w", err)
+			}
+			return nil
+		})
+	},
+}
+
+var nvListIndicesCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the NV indices",
+	Long:  `List the defined NV indices, with their sizes and attributes`,
+	Args:  cobra.NoArgs,
+	RunE: func(*cobra.Command, []string) error {
+		rwc, err := openTpm()
+		if err != nil {
+			return err
+		}
+		defer rwc.Close()
+
+		indices, err := client.NVIndices(rwc)
+		if err != nil {
+			return err
+		}
+		out := dataOutput()
+		for _, index := range indices {
+			pub, err := tpm2.NVReadPublic(rwc, index)
+			if err != nil {
+				return fmt.Errorf("reading NV index 0x%x: %w", index, err)
+			}
+			// The attributes are printed as a bitmask, as NVAttr.String
+			// doesn't list them in a consistent order.
+			if _, err := fmt.Fprintf(out, "0x%08x: size=%d hash=%v attributes=0x%08x\n", index, pub.DataSize, pub.NameAlg, uint32(pub.Attributes)); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
+// runNVCommand opens the TPM and runs an NV command with the authorization
+// from the flags.
+func runNVCommand(run func(io.ReadWriter, client.NVAuth) error) error {
+	rwc, err := openTpm()
+	if err != nil {
+		return err
+	}
+	defer rwc.Close()
+
+	auth := client.NVAuth{Password: nvPassword}
+	if auth.Policy, err = nvPolicy(rwc); err != nil {
+		return err
+	}
+	return run(rwc, auth)
+}
+
+// nvPolicy returns the policy of an NV index defined with the --pcrs and
+// --password flags, or nil if --pcrs is not set.
+func nvPolicy(rw io.ReadWriter) (*client.Policy, error) {
+	if len(pcrs) == 0 {
+		return nil, nil
+	}
+	values, err := client.ReadPCRs(rw, tpm2.PCRSelection{Hash: tpm2.AlgSHA256, PCRs: pcrs})
+	if err != nil {
+		return nil, err
+	}
+	policy := client.NewPolicy().PolicyPCR(values)
+	if nvPassword != "" {
+		policy.PolicyAuthValue()
+	}
+	return policy, nil
+}
+
+func nvTypeNames() string {
+	names := make([]string, 0, len(nvTypes))
+	for name := range nvTypes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+func init() {
+	RootCmd.AddCommand(nvCmd)
+	for _, cmd := range []*cobra.Command{nvDefineIndexCmd, nvUndefineIndexCmd, nvWriteIndexCmd, nvExtendIndexCmd, nvIncrementIndexCmd, nvReadIndexCmd} {
+		nvCmd.AddCommand(cmd)
+		addIndexFlag(cmd)
+		cmd.MarkPersistentFlagRequired("index")
+	}
+	nvCmd.AddCommand(nvListIndicesCmd)
+	for _, cmd := range []*cobra.Command{nvDefineIndexCmd, nvWriteIndexCmd, nvExtendIndexCmd, nvIncrementIndexCmd, nvReadIndexCmd} {
+		cmd.PersistentFlags().StringVar(&nvPassword, "password", "", "authorization value of the NV index")
+		addPCRsFlag(cmd)
+	}
+	nvDefineIndexCmd.PersistentFlags().StringVar(&nvType, "type", "ordinary", "type of the NV index: "+nvTypeNames())
+	nvDefineIndexCmd.PersistentFlags().Uint16Var(&nvSize, "size", 0, "size of an ordinary NV index in bytes")
+	addHashAlgoFlag(nvDefineIndexCmd, &nvHashAlgo)
+	addInputFlag(nvWriteIndexCmd)
+	addInputFlag(nvExtendIndexCmd)
+	addOutputFlag(nvReadIndexCmd)
+	addOutputFlag(nvListIndicesCmd)
+}
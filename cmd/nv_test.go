@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/google/go-tpm-tools/client"
+	"github.com/google/go-tpm-tools/internal/test"
+)
+
+const testNVIndex = "0x01500300"
+
+// runNV runs a gotpm nv subcommand, and resets the flags it may have set.
+func runNV(t *testing.T, args ...string) error {
+	t.Helper()
+	RootCmd.SetArgs(append([]string{"nv"}, args...))
+	defer func() {
+		nvType = "ordinary"
+		nvSize = 0
+		nvPassword = ""
+		pcrs = []int{}
+	}()
+	return RootCmd.Execute()
+}
+
+func readNV(t *testing.T, args ...string) []byte {
+	t.Helper()
+	outFile := makeTempFile(t, nil)
+	defer os.Remove(outFile)
+	if err := runNV(t, append([]string{"read", "--index", testNVIndex, "--output", outFile}, args...)...); err != nil {
+		t.Fatalf("nv read failed: %v", err)
+	}
+	data, err := os.ReadFile(outFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestNVOrdinary(t *testing.T) {
+	rwc := test.GetTPM(t)
+	defer client.CheckedClose(t, rwc)
+	ExternalTPM = rwc
+
+	if err := runNV(t, "define", "--quiet", "--index", testNVIndex, "--size", "5", "--password", "pass"); err != nil {
+		t.Fatalf("nv define failed: %v", err)
+	}
+	defer runNV(t, "undefine", "--quiet", "--index", testNVIndex)
+
+	listFile := makeTempFile(t, nil)
+	defer os.Remove(listFile)
+	if err := runNV(t, "list", "--output", listFile); err != nil {
+		t.Fatalf("nv list failed: %v", err)
+	}
+	list, err := os.ReadFile(listFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(list), testNVIndex+": size=5") {
+		t.Errorf("nv list output %q does not contain index %s", list, testNVIndex)
+	}
+
+	inFile := makeTempFile(t, []byte("hello"))
+	defer os.Remove(inFile)
+	if err := runNV(t, "write", "--index", testNVIndex, "--input", inFile, "--password", "pass"); err != nil {
+		t.Fatalf("nv write failed: %v", err)
+	}
+	if got := readNV(t, "--password", "pass"); !bytes.Equal(got, []byte("hello")) {
+		t.Errorf("nv read = %q, want %q", got, "hello")
+	}
+	if err := runNV(t, "write", "--index", testNVIndex, "--input", inFile, "--password", "wrong"); err == nil {
+		t.Error("nv write with the wrong password succeeded, expected failure")
+	}
+
+	if err := runNV(t, "undefine", "--quiet", "--index", testNVIndex); err != nil {
+		t.Fatalf("nv undefine failed: %v", err)
+	}
+	if err := runNV(t, "read", "--index", testNVIndex, "--password", "pass"); err == nil {
+		t.Error("nv read after nv undefine succeeded, expected failure")
+	}
+}
+
+func TestNVCounter(t *testing.T) {
+	rwc := test.GetTPM(t)
+	defer client.CheckedClose(t, rwc)
+	ExternalTPM = rwc
+
+	if err := runNV(t, "define", "--quiet", "--index", testNVIndex, "--type", "counter"); err != nil {
+		t.Fatalf("nv define failed: %v", err)
+	}
+	defer runNV(t, "undefine", "--quiet", "--index", testNVIndex)
+
+	var values []uint64
+	for i := 0; i < 2; i++ {
+		if err := runNV(t, "increment", "--index", testNVIndex); err != nil {
+			t.Fatalf("nv increment failed: %v", err)
+		}
+		value, err := client.NVCounterValue(readNV(t))
+		if err != nil {
+			t.Fatal(err)
+		}
+		values = append(values, value)
+	}
+	if values[1] != values[0]+1 {
+		t.Errorf("counter values %v do not increase by 1", values)
+	}
+}
+
+func TestNVExtendWithPCRs(t *testing.T) {
+	rwc := test.GetTPM(t)
+	defer client.CheckedClose(t, rwc)
+	ExternalTPM = rwc
+
+	pcr := strconv.Itoa(test.DebugPCR)
+	if err := runNV(t, "define", "--quiet", "--index", testNVIndex, "--type", "extend", "--pcrs", pcr, "--password", "pass"); err != nil {
+		t.Fatalf("nv define failed: %v", err)
+	}
+	defer runNV(t, "undefine", "--quiet", "--index", testNVIndex)
+
+	inFile := makeTempFile(t, []byte("event"))
+	defer os.Remove(inFile)
+	if err := runNV(t, "extend", "--index", testNVIndex, "--input", inFile, "--pcrs", pcr, "--password", "pass"); err != nil {
+		t.Fatalf("nv extend failed: %v", err)
+	}
+	if got := readNV(t, "--pcrs", pcr, "--password", "pass"); bytes.Equal(got, make([]byte, len(got))) {
+		t.Errorf("nv read = %x after nv extend, expected a non-zero digest", got)
+	}
+	// The index is only accessible with the policy.
+	if err := runNV(t, "extend", "--index", testNVIndex, "--input", inFile, "--password", "pass"); err == nil {
+		t.Error("nv extend without --pcrs succeeded, expected failure")
+	}
+	if err := runNV(t, "extend", "--index", testNVIndex, "--input", inFile, "--pcrs", pcr, "--password", "wrong"); err == nil {
+		t.Error("nv extend with the wrong password succeeded, expected failure")
+	}
+}
+
+func TestNVDefineUnknownType(t *testing.T) {
+	rwc := test.GetTPM(t)
+	defer client.CheckedClose(t, rwc)
+	ExternalTPM = rwc
+
+	if err := runNV(t, "define", "--quiet", "--index", testNVIndex, "--type", "pinfail"); err == nil {
+		runNV(t, "undefine", "--quiet", "--index", testNVIndex)
+		t.Error("nv define with an unknown type succeeded, expected failure")
+	}
+}
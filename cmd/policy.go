@@ -0,0 +1,227 @@
+package cmd
+
+import (
+	"crypto"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	pb "github.com/google/go-tpm-tools/proto/attest"
+	"github.com/google/go-tpm-tools/server"
+	"github.com/google/go-tpm/legacy/tpm2"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	policyPath   string
+	policyFormat string
+)
+
+// Supported values for --policy-format.
+const (
+	policyFormatTextproto = "textproto"
+	policyFormatJSON      = "json"
+	policyFormatYAML      = "yaml"
+)
+
+var policyCmd = &cobra.Command{
+	Use:   "policy",
+	Short: "Create and inspect attestation policies",
+	Long: `Create and inspect attestation policies for use with "gotpm verify --policy".
+
+Policies are pb.Policy messages (see proto/attest.proto), written as textproto,
+JSON or YAML. In the JSON and YAML formats, field names may be given in either
+snake_case or lowerCamelCase, and bytes fields are base64 encoded.`,
+	Args: cobra.NoArgs,
+}
+
+var policyGenerateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "Generate a starter policy from a known-good attestation",
+	Long: `Generate a starter policy from a known-good attestation.
+
+The attestation is verified using its own AK (as with "gotpm verify debug"),
+so only use attestations from machines that are trusted. The resulting policy
+pins the firmware, Secure Boot, bootloader, kernel command line, and container
+state of the machine, and should be reviewed before use.`,
+	Args: cobra.NoArgs,
+	RunE: func(*cobra.Command, []string) error {
+		attestation, err := readAttestation(dataInput())
+		if err != nil {
+			return err
+		}
+		ms, err := verifyWithOwnAK(attestation)
+		if err != nil {
+			return err
+		}
+		policy, err := server.PolicyFromMachineState([]*pb.MachineState{ms}, nil)
+		if err != nil {
+			return fmt.Errorf("failed to generate policy: %w", err)
+		}
+		return writePolicy(dataOutput(), policy)
+	},
+}
+
+// readAttestation reads an Attestation in the format given by --format.
+func readAttestation(in io.Reader) (*pb.Attestation, error) {
+	attestationBytes, err := io.ReadAll(in)
+	if err != nil {
+		return nil, err
+	}
+	attestation := &pb.Attestation{}
+	if format == "binarypb" {
+		err = proto.Unmarshal(attestationBytes, attestation)
+	} else if format == "textproto" {
+		err = unmarshalOptions.Unmarshal(attestationBytes, attestation)
+	} else {
+		return nil, fmt.Errorf("format should be either binarypb or textproto")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("fail to unmarshal attestation report: %v", err)
+	}
+	return attestation, nil
+}
+
+// verifyWithOwnAK verifies an attestation, trusting the AK it contains.
+func verifyWithOwnAK(attestation *pb.Attestation) (*pb.MachineState, error) {
+	pub, err := tpm2.DecodePublic(attestation.GetAkPub())
+	if err != nil {
+		return nil, err
+	}
+	cryptoPub, err := pub.Key()
+	if err != nil {
+		return nil, err
+	}
+	ms, err := server.VerifyAttestation(attestation, server.VerifyOpts{Nonce: nonce, TrustedAKs: []crypto.PublicKey{cryptoPub}})
+	if err != nil {
+		return nil, fmt.Errorf("verifying TPM attestation: %w", err)
+	}
+	return ms, nil
+}
+
+// readPolicy parses a Policy in the format given by --policy-format.
+func readPolicy(path string) (*pb.Policy, error) {
+	policyBytes, err := readBytes(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy: %w", err)
+	}
+	policy := &pb.Policy{}
+	switch policyFormat {
+	case policyFormatTextproto:
+		err = unmarshalOptions.Unmarshal(policyBytes, policy)
+	case policyFormatJSON:
+		err = protojson.Unmarshal(policyBytes, policy)
+	case policyFormatYAML:
+		var jsonBytes []byte
+		jsonBytes, err = yamlToJSON(policyBytes)
+		if err == nil {
+			err = protojson.Unmarshal(jsonBytes, policy)
+		}
+	default:
+		return nil, fmt.Errorf("unknown policy format %q", policyFormat)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal %s policy: %w", policyFormat, err)
+	}
+	return policy, nil
+}
+
+// writePolicy writes a Policy in the format given by --policy-format.
+func writePolicy(out io.Writer, policy *pb.Policy) error {
+	var policyBytes []byte
+	var err error
+	switch policyFormat {
+	case policyFormatTextproto:
+		policyBytes, err = marshalOptions.Marshal(policy)
+	case policyFormatJSON:
+		policyBytes, err = protojson.MarshalOptions{Multiline: true}.Marshal(policy)
+	case policyFormatYAML:
+		var jsonBytes []byte
+		jsonBytes, err = protojson.Marshal(policy)
+		if err == nil {
+			policyBytes, err = jsonToYAML(jsonBytes)
+		}
+	default:
+		return fmt.Errorf("unknown policy format %q", policyFormat)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s policy: %w", policyFormat, err)
+	}
+	if _, err := out.Write(policyBytes); err != nil {
+		return fmt.Errorf("failed to write policy: %w", err)
+	}
+	return nil
+}
+
+func yamlToJSON(yamlBytes []byte) ([]byte, error) {
+	var obj interface{}
+	if err := yaml.Unmarshal(yamlBytes, &obj); err != nil {
+		return nil, err
+	}
+	if obj == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(obj)
+}
+
+func jsonToYAML(jsonBytes []byte) ([]byte, error) {
+	var obj interface{}
+	if err := json.Unmarshal(jsonBytes, &obj); err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(obj)
+}
+
+// evaluatePolicyFlag evaluates the MachineState against the policy given by
+// --policy, if any. On failure, the returned error lists every violation.
+func evaluatePolicyFlag(ms *pb.MachineState) error {
+	if policyPath == "" {
+		return nil
+	}
+	policy, err := readPolicy(policyPath)
+	if err != nil {
+		return err
+	}
+	err = server.EvaluatePolicy(ms, policy)
+	if err == nil {
+		return nil
+	}
+	var gErr *server.GroupedError
+	if !errors.As(err, &gErr) {
+		return fmt.Errorf("failed to evaluate policy: %w", err)
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "attestation violates policy %s (%d violations):", policyPath, len(gErr.Errors))
+	for _, violation := range gErr.Errors {
+		fmt.Fprintf(&sb, "\n  - %v", violation)
+	}
+	return errors.New(sb.String())
+}
+
+// Lets this command specify a policy to evaluate the attestation against.
+func addPolicyFlag(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVar(&policyPath, "policy", "",
+		"path to a policy the verified machine state must comply with (see --policy-format)")
+}
+
+// Lets this command specify the format of policy files.
+func addPolicyFormatFlag(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVar(&policyFormat, "policy-format", policyFormatTextproto,
+		"format of the policy file <textproto|json|yaml>")
+}
+
+func init() {
+	RootCmd.AddCommand(policyCmd)
+	policyCmd.AddCommand(policyGenerateCmd)
+	hideHelp(policyCmd)
+	addNonceFlag(policyGenerateCmd)
+	addInputFlag(policyGenerateCmd)
+	addOutputFlag(policyGenerateCmd)
+	addFormatFlag(policyGenerateCmd)
+	addPolicyFormatFlag(policyGenerateCmd)
+}
@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/go-tpm-tools/client"
+	"github.com/google/go-tpm-tools/internal/test"
+	pb "github.com/google/go-tpm-tools/proto/attest"
+	"google.golang.org/protobuf/proto"
+)
+
+// resetPolicyFlags keeps policy flags set by one test from leaking into others.
+func resetPolicyFlags(t *testing.T) {
+	t.Cleanup(func() {
+		policyPath = ""
+		policyFormat = policyFormatTextproto
+	})
+}
+
+func TestPolicyFormatRoundTrip(t *testing.T) {
+	resetPolicyFlags(t)
+	policy := &pb.Policy{
+		Platform: &pb.PlatformPolicy{
+			MinimumGceFirmwareVersion: 1,
+			MinimumTechnology:         pb.GCEConfidentialTechnology_AMD_SEV,
+		},
+		Bootloader: &pb.BootloaderPolicy{
+			AllowedEfiAppDigests: [][]byte{{0x01, 0x02, 0x03}},
+		},
+		Kernel: &pb.KernelPolicy{
+			RequiredCommandLineArgs: []string{"lockdown=confidentiality"},
+		},
+	}
+	for _, f := range []string{policyFormatTextproto, policyFormatJSON, policyFormatYAML} {
+		t.Run(f, func(t *testing.T) {
+			policyFormat = f
+			var buf bytes.Buffer
+			if err := writePolicy(&buf, policy); err != nil {
+				t.Fatal(err)
+			}
+			path := makeOutputFile(t, "policy")
+			defer os.RemoveAll(path)
+			if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+				t.Fatal(err)
+			}
+			got, err := readPolicy(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !proto.Equal(got, policy) {
+				t.Errorf("readPolicy() = %v, want %v", got, policy)
+			}
+		})
+	}
+}
+
+func TestReadPolicyYAMLSnakeCase(t *testing.T) {
+	resetPolicyFlags(t)
+	policyFormat = policyFormatYAML
+	path := makeOutputFile(t, "policy")
+	defer os.RemoveAll(path)
+	yamlPolicy := `
+platform:
+  minimum_gce_firmware_version: 2
+  minimum_technology: AMD_SEV_SNP
+kernel:
+  forbidden_command_line_args:
+    - nokaslr
+`
+	if err := os.WriteFile(path, []byte(yamlPolicy), 0644); err != nil {
+		t.Fatal(err)
+	}
+	got, err := readPolicy(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &pb.Policy{
+		Platform: &pb.PlatformPolicy{
+			MinimumGceFirmwareVersion: 2,
+			MinimumTechnology:         pb.GCEConfidentialTechnology_AMD_SEV_SNP,
+		},
+		Kernel: &pb.KernelPolicy{ForbiddenCommandLineArgs: []string{"nokaslr"}},
+	}
+	if !proto.Equal(got, want) {
+		t.Errorf("readPolicy() = %v, want %v", got, want)
+	}
+}
+
+func TestVerifyWithGeneratedPolicy(t *testing.T) {
+	resetPolicyFlags(t)
+	rwc := test.GetTPM(t)
+	defer client.CheckedClose(t, rwc)
+	ExternalTPM = rwc
+
+	attestFile := makeOutputFile(t, "attest")
+	policyFile := makeOutputFile(t, "policy")
+	verifyFile := makeOutputFile(t, "verify")
+	defer os.RemoveAll(attestFile)
+	defer os.RemoveAll(policyFile)
+	defer os.RemoveAll(verifyFile)
+
+	RootCmd.SetArgs([]string{"attest", "--nonce", "1234", "--key", "AK", "--tee-nonce", "", "--output", attestFile, "--tee-technology", ""})
+	if err := RootCmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, f := range []string{policyFormatTextproto, policyFormatJSON, policyFormatYAML} {
+		t.Run(f, func(t *testing.T) {
+			RootCmd.SetArgs([]string{"policy", "generate", "--nonce", "1234", "--input", attestFile, "--output", policyFile, "--policy-format", f})
+			if err := RootCmd.Execute(); err != nil {
+				t.Fatal(err)
+			}
+			RootCmd.SetArgs([]string{"verify", "debug", "--nonce", "1234", "--input", attestFile, "--output", verifyFile, "--policy", policyFile, "--policy-format", f})
+			if err := RootCmd.Execute(); err != nil {
+				t.Errorf("verify with generated policy failed: %v", err)
+			}
+		})
+	}
+}
+
+func TestVerifyPolicyViolation(t *testing.T) {
+	resetPolicyFlags(t)
+	rwc := test.GetTPM(t)
+	defer client.CheckedClose(t, rwc)
+	ExternalTPM = rwc
+
+	attestFile := makeOutputFile(t, "attest")
+	policyFile := makeOutputFile(t, "policy")
+	verifyFile := makeOutputFile(t, "verify")
+	defer os.RemoveAll(attestFile)
+	defer os.RemoveAll(policyFile)
+	defer os.RemoveAll(verifyFile)
+
+	RootCmd.SetArgs([]string{"attest", "--nonce", "1234", "--key", "AK", "--tee-nonce", "", "--output", attestFile, "--tee-technology", ""})
+	if err := RootCmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	policy := "platform { minimum_gce_firmware_version: 2 }\nkernel { required_command_line_args: \"lockdown\" }\n"
+	if err := os.WriteFile(policyFile, []byte(policy), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	RootCmd.SetArgs([]string{"verify", "debug", "--nonce", "1234", "--input", attestFile, "--output", verifyFile, "--policy", policyFile, "--policy-format", policyFormatTextproto})
+	err := RootCmd.Execute()
+	if err == nil {
+		t.Fatal("verify succeeded, expected a policy violation")
+	}
+	if !strings.Contains(err.Error(), "2 violations") {
+		t.Errorf("verify error %q does not list both violations", err)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"io"
 
 	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/proto"
 
 	"github.com/google/go-tpm-tools/client"
 	pb "github.com/google/go-tpm-tools/proto/tpm"
@@ -25,7 +26,12 @@ the encryption.
 Optionally (using the --pcrs flag), this decryption can be furthur restricted to
 only work if certain Platform Control Registers (PCRs) are in the correct state.
 This allows a key (i.e. a disk encryption key) to be bound to specific machine
-state (like Secure Boot).`,
+state (like Secure Boot).
+
+The TPM can only seal small amounts of data (usually 128 bytes). To seal larger
+data (like a configuration bundle or a TLS key), use the --envelope flag. The
+data is then encrypted with a random AES-256-GCM key, and only that key is
+sealed to the TPM. Data sealed with --envelope must be unsealed with --envelope.`,
 	Args: cobra.NoArgs,
 	RunE: func(*cobra.Command, []string) error {
 		rwc, err := openTpm()
@@ -51,7 +57,12 @@ state (like Secure Boot).`,
 		opts := client.SealOpts{Current: tpm2.PCRSelection{
 			Hash: sealHashAlgo,
 			PCRs: pcrs}}
-		sealed, err := srk.Seal(secret, opts)
+		var sealed proto.Message
+		if envelope {
+			sealed, err = srk.SealEnvelope(secret, opts)
+		} else {
+			sealed, err = srk.Seal(secret, opts)
+		}
 		if err != nil {
 			return fmt.Errorf("sealing data: %w", err)
 		}
@@ -85,6 +96,8 @@ We do support an optional "certification" process. A list of PCRs may be
 provided with --pcrs, and the unwrapping will fail if the PCR values when
 sealing differ from the current PCR values. This allows for verification of the
 machine state when sealing took place.
+
+Data sealed with "gotpm seal --envelope" must be unsealed with --envelope.
 `,
 	Args: cobra.NoArgs,
 	RunE: func(*cobra.Command, []string) error {
@@ -100,12 +113,20 @@ machine state when sealing took place.
 			return err
 		}
 		var sealed pb.SealedBytes
-		if err := unmarshalOptions.Unmarshal(data, &sealed); err != nil {
-			return err
+		var envelopeSealed pb.EnvelopeSealedBytes
+		if envelope {
+			if err := unmarshalOptions.Unmarshal(data, &envelopeSealed); err != nil {
+				return err
+			}
+			keyAlgo = tpm2.Algorithm(envelopeSealed.GetSealedKey().GetSrk())
+		} else {
+			if err := unmarshalOptions.Unmarshal(data, &sealed); err != nil {
+				return err
+			}
+			keyAlgo = tpm2.Algorithm(sealed.GetSrk())
 		}
 
 		fmt.Fprintln(debugOutput(), "Loading SRK")
-		keyAlgo = tpm2.Algorithm(sealed.GetSrk())
 		srk, err := getSRK(rwc)
 		if err != nil {
 			return err
@@ -117,7 +138,12 @@ machine state when sealing took place.
 		opts := client.UnsealOpts{CertifyCurrent: tpm2.PCRSelection{
 			Hash: client.CertifyHashAlgTpm,
 			PCRs: pcrs}}
-		secret, err := srk.Unseal(&sealed, opts)
+		var secret []byte
+		if envelope {
+			secret, err = srk.UnsealEnvelope(&envelopeSealed, opts)
+		} else {
+			secret, err = srk.Unseal(&sealed, opts)
+		}
 		if err != nil {
 			return fmt.Errorf("unsealing data: %w", err)
 		}
@@ -143,4 +169,6 @@ func init() {
 	addHashAlgoFlag(sealCmd, &sealHashAlgo)
 	addPCRsFlag(unsealCmd)
 	addPublicKeyAlgoFlag(sealCmd)
+	addEnvelopeFlag(sealCmd)
+	addEnvelopeFlag(unsealCmd)
 }
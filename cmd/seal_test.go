@@ -90,6 +90,39 @@ func TestSealPlain(t *testing.T) {
 	}
 }
 
+func TestSealEnvelope(t *testing.T) {
+	rwc := test.GetTPM(t)
+	defer client.CheckedClose(t, rwc)
+	ExternalTPM = rwc
+	defer func() { envelope = false }()
+
+	secretIn := bytes.Repeat([]byte("Hello"), 1000)
+	secretFile1 := makeTempFile(t, secretIn)
+	defer os.Remove(secretFile1)
+	sealedFile := makeTempFile(t, nil)
+	defer os.Remove(sealedFile)
+	secretFile2 := makeTempFile(t, nil)
+	defer os.Remove(secretFile2)
+
+	RootCmd.SetArgs([]string{"seal", "--quiet", "--envelope", "--input", secretFile1, "--output", sealedFile, "--pcrs", "7"})
+	if err := RootCmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	pcrs = []int{} // "flush" pcrs value in last Execute() cmd
+
+	RootCmd.SetArgs([]string{"unseal", "--quiet", "--envelope", "--input", sealedFile, "--output", secretFile2})
+	if err := RootCmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	secretOut, err := os.ReadFile(secretFile2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(secretIn, secretOut) {
+		t.Errorf("Expected %s, got %s", secretIn, secretOut)
+	}
+}
+
 func TestUnsealFail(t *testing.T) {
 	rwc := test.GetTPM(t)
 	defer client.CheckedClose(t, rwc)
@@ -1,17 +1,13 @@
 package cmd
 
 import (
-	"crypto"
 	"fmt"
-	"io"
 
 	"github.com/google/go-sev-guest/proto/sevsnp"
 	sv "github.com/google/go-sev-guest/verify"
 	"github.com/google/go-tdx-guest/proto/tdx"
 	tv "github.com/google/go-tdx-guest/verify"
 	pb "github.com/google/go-tpm-tools/proto/attest"
-	"github.com/google/go-tpm-tools/server"
-	"github.com/google/go-tpm/legacy/tpm2"
 	"github.com/spf13/cobra"
 	"google.golang.org/protobuf/proto"
 )
@@ -25,36 +21,15 @@ var debugCmd = &cobra.Command{
 	Use:   "debug",
 	Short: "Debug the contents of an attestation report without verifying its root-of-trust (e.g., attestation key certificate). For debugging purposes only",
 	RunE: func(*cobra.Command, []string) error {
-		attestationBytes, err := io.ReadAll(dataInput())
-		if err != nil {
-			return err
-		}
-		attestation := &pb.Attestation{}
-
-		if format == "binarypb" {
-			err = proto.Unmarshal(attestationBytes, attestation)
-		} else if format == "textproto" {
-			err = unmarshalOptions.Unmarshal(attestationBytes, attestation)
-		} else {
-			return fmt.Errorf("format should be either binarypb or textproto")
-		}
-		if err != nil {
-			return fmt.Errorf("fail to unmarshal attestation report: %v", err)
-		}
-
-		pub, err := tpm2.DecodePublic(attestation.GetAkPub())
-		if err != nil {
-			return err
-		}
-		cryptoPub, err := pub.Key()
+		attestation, err := readAttestation(dataInput())
 		if err != nil {
 			return err
 		}
 
 		// TODO(#524): create separate, discrete subcommands that verifies SNP and TDX attestation.
-		ms, err := server.VerifyAttestation(attestation, server.VerifyOpts{Nonce: nonce, TrustedAKs: []crypto.PublicKey{cryptoPub}})
+		ms, err := verifyWithOwnAK(attestation)
 		if err != nil {
-			return fmt.Errorf("verifying TPM attestation: %w", err)
+			return err
 		}
 		err = verifyGceTechnology(attestation)
 		if err != nil {
@@ -65,6 +40,9 @@ var debugCmd = &cobra.Command{
 			return fmt.Errorf("failed to parse machineState from TEE attestation: %w", err)
 		}
 		ms.TeeAttestation = teeMS.TeeAttestation
+		if err := evaluatePolicyFlag(ms); err != nil {
+			return err
+		}
 		out, err := marshalOptions.Marshal(ms)
 		if err != nil {
 			return nil
@@ -159,6 +137,8 @@ func init() {
 	addFormatFlag(debugCmd)
 	addTeeNonceflag(debugCmd)
 	addCertifiedAKBlobFlag(debugCmd)
+	addPolicyFlag(debugCmd)
+	addPolicyFormatFlag(debugCmd)
 	debugCmd.AddCommand(verifySVSMCmd)
 	addEKPubFlag(verifySVSMCmd)
 	addTeeTechnology(verifySVSMCmd)
@@ -106,6 +106,9 @@ var verifySVSMCmd = &cobra.Command{
 		ms.TeeAttestation = &apb.MachineState_SevSnpAttestation{
 			SevSnpAttestation: svsmAttestation.SevSnpAttestation,
 		}
+		if err := evaluatePolicyFlag(ms); err != nil {
+			return err
+		}
 		out, err := marshalOptions.Marshal(ms)
 		if err != nil {
 			return nil
@@ -5,6 +5,7 @@ go 1.22
 toolchain go1.24.8
 
 require (
+	github.com/google/gce-tcb-verifier v0.3.1
 	github.com/google/go-attestation v0.5.1
 	github.com/google/go-cmp v0.6.0
 	github.com/google/go-configfs-tsm v0.3.3-0.20240919001351-b4b5b84fdcbc
@@ -15,6 +16,11 @@ require (
 	google.golang.org/protobuf v1.35.1
 )
 
+require (
+	github.com/pkg/errors v0.9.1 // indirect
+	golang.org/x/exp v0.0.0-20240409090435-93d18d7e34b8 // indirect
+)
+
 require (
 	github.com/google/certificate-transparency-go v1.1.2 // indirect
 	github.com/google/go-eventlog v0.0.2-0.20241003021507-01bb555f7cba
@@ -0,0 +1,26 @@
+package internal
+
+import (
+	"crypto/sha256"
+	"encoding/asn1"
+)
+
+// AttestedTLSExtensionOID identifies the X.509 extension holding a serialized
+// pb.Attestation in the self-issued certificates of attested TLS.
+var AttestedTLSExtensionOID = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 11129, 2, 1, 32}
+
+// attestedTLSNoncePrefix separates attested TLS nonces from other nonces.
+const attestedTLSNoncePrefix = "go-tpm-tools attested TLS\x00"
+
+// AttestedTLSNonce returns the nonce of the attestation in an attested TLS
+// certificate, binding the attestation to the certificate's DER encoded
+// SubjectPublicKeyInfo and to the application's nonce.
+func AttestedTLSNonce(subjectPublicKeyInfo []byte, nonce []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte(attestedTLSNoncePrefix))
+	// The DER encoding is self-delimiting, so the nonce cannot be confused
+	// with a part of it.
+	h.Write(subjectPublicKeyInfo)
+	h.Write(nonce)
+	return h.Sum(nil)
+}
@@ -0,0 +1,74 @@
+package internal
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/subtle"
+	"fmt"
+
+	pb "github.com/google/go-tpm-tools/proto/tpm"
+	"github.com/google/go-tpm/legacy/tpm2"
+)
+
+// VerifyCertification performs the following checks to validate a
+// CertifiedBlob, and returns the certified object's public area:
+//   - the provided signature is generated by the trusted public key
+//   - the signature signs the provided certification data
+//   - the certification data is a valid TPMS_CERTIFY_INFO
+//   - the certified name matches the provided public area
+//   - the provided extraData matches that in the certification data
+//   - the signature hash algorithm must be in SignatureHashAlgs
+//
+// Note that the caller must have already established trust in the provided
+// public key before validating the CertifiedBlob.
+func VerifyCertification(blob *pb.CertifiedBlob, trustedPub crypto.PublicKey, extraData []byte) (tpm2.Public, error) {
+	sig, err := tpm2.DecodeSignature(bytes.NewBuffer(blob.GetRawSig()))
+	if err != nil {
+		return tpm2.Public{}, fmt.Errorf("signature decoding failed: %v", err)
+	}
+
+	hash, err := verifyHashAlg(sig)
+	if err != nil {
+		return tpm2.Public{}, err
+	}
+
+	switch pub := trustedPub.(type) {
+	case *ecdsa.PublicKey:
+		err = verifyECDSAQuoteSignature(pub, hash, blob.GetCertifyInfo(), sig)
+	case *rsa.PublicKey:
+		err = verifyRSASSAQuoteSignature(pub, hash, blob.GetCertifyInfo(), sig)
+	default:
+		err = fmt.Errorf("only RSA and ECC public keys are currently supported, received type: %T", pub)
+	}
+	if err != nil {
+		return tpm2.Public{}, err
+	}
+
+	// DecodeAttestationData checks for the magic TPMS_GENERATED_VALUE.
+	attestationData, err := tpm2.DecodeAttestationData(blob.GetCertifyInfo())
+	if err != nil {
+		return tpm2.Public{}, fmt.Errorf("decoding attestation data failed: %v", err)
+	}
+	if attestationData.Type != tpm2.TagAttestCertify {
+		return tpm2.Public{}, fmt.Errorf("expected certify tag, got: %v", attestationData.Type)
+	}
+	if subtle.ConstantTimeCompare(attestationData.ExtraData, extraData) == 0 {
+		return tpm2.Public{}, fmt.Errorf("certification extraData %v did not match expected extraData %v",
+			attestationData.ExtraData, extraData)
+	}
+
+	pubArea, err := tpm2.DecodePublic(blob.GetPubArea())
+	if err != nil {
+		return tpm2.Public{}, fmt.Errorf("decoding public area failed: %v", err)
+	}
+	matches, err := attestationData.AttestedCertifyInfo.Name.MatchesPublic(pubArea)
+	if err != nil {
+		return tpm2.Public{}, err
+	}
+	if !matches {
+		return tpm2.Public{}, fmt.Errorf("certified name does not match the public area")
+	}
+	return pubArea, nil
+}
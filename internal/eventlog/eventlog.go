@@ -0,0 +1,190 @@
+// Package eventlog parses crypto-agile TCG PC Client event logs.
+//
+// go-attestation only parses the SHA-1 and SHA-256 digests of an event log,
+// so this package is used to access the digests of the other PCR banks (such
+// as SHA-384 and SHA-512).
+package eventlog
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/google/go-tpm/legacy/tpm2"
+)
+
+// NoAction is the EV_NO_ACTION event type. These events are not extended
+// into any PCR.
+const NoAction uint32 = 0x00000003
+
+var (
+	specIDEventSignature     = []byte("Spec ID Event03\x00")
+	startupLocalitySignature = []byte("StartupLocality\x00")
+)
+
+// Event is a single event of a crypto-agile event log.
+type Event struct {
+	Index   uint32
+	Type    uint32
+	Data    []byte
+	Digests map[tpm2.Algorithm][]byte
+}
+
+// StartupLocality returns the locality from which the TPM2_Startup command was
+// issued, if the event is a StartupLocality event. This locality is the
+// initial value of PCR0.
+func (e Event) StartupLocality() (uint8, bool) {
+	if e.Type != NoAction || e.Index != 0 || len(e.Data) != len(startupLocalitySignature)+1 ||
+		!bytes.HasPrefix(e.Data, startupLocalitySignature) {
+		return 0, false
+	}
+	return e.Data[len(startupLocalitySignature)], true
+}
+
+// Log is a parsed crypto-agile event log.
+type Log struct {
+	// Algs are the digest algorithms from the Spec ID Event, in log order.
+	// Every event has a digest for each of them.
+	Algs   []tpm2.Algorithm
+	Events []Event
+
+	digestSizes []digestSize
+}
+
+// Parse parses a crypto-agile (TCG_PCR_EVENT2) event log. The Spec ID Event at
+// the start of the log is not included in the returned events. Logs using the
+// legacy SHA-1 format are not supported.
+func Parse(rawEventLog []byte) (*Log, error) {
+	buf := bytes.NewBuffer(rawEventLog)
+	digestSizes, err := parseSpecIDEvent(buf)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Spec ID Event: %w", err)
+	}
+	log := &Log{digestSizes: digestSizes}
+	for _, size := range digestSizes {
+		log.Algs = append(log.Algs, size.alg)
+	}
+	if log.Events, err = log.ParseEvents(buf.Bytes()); err != nil {
+		return nil, err
+	}
+	return log, nil
+}
+
+// ParseEvents parses a series of TCG_PCR_EVENT2 structures, such as the events
+// appended to the log since it was parsed, using the digest algorithms of the
+// log. The events are not added to the log.
+func (l *Log) ParseEvents(rawEvents []byte) ([]Event, error) {
+	var events []Event
+	buf := bytes.NewBuffer(rawEvents)
+	for buf.Len() > 0 {
+		event, err := parseEvent(buf, l.digestSizes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid event #%d: %w", len(events), err)
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+type digestSize struct {
+	alg  tpm2.Algorithm
+	size uint16
+}
+
+// parseSpecIDEvent parses the TCG_EfiSpecIDEvent, which is logged with the
+// legacy SHA-1 TCG_PCR_EVENT format, and returns the digest sizes it lists.
+func parseSpecIDEvent(buf *bytes.Buffer) ([]digestSize, error) {
+	var header struct {
+		Index     uint32
+		Type      uint32
+		Digest    [20]byte
+		EventSize uint32
+	}
+	if err := binary.Read(buf, binary.LittleEndian, &header); err != nil {
+		return nil, err
+	}
+	if header.Type != NoAction {
+		return nil, fmt.Errorf("event type is %#x, expected EV_NO_ACTION", header.Type)
+	}
+	data := bytes.NewBuffer(buf.Next(int(header.EventSize)))
+	if data.Len() != int(header.EventSize) {
+		return nil, errors.New("event data is truncated")
+	}
+	if !bytes.Equal(data.Next(len(specIDEventSignature)), specIDEventSignature) {
+		return nil, errors.New("log is not in the crypto-agile format")
+	}
+
+	var spec struct {
+		PlatformClass    uint32
+		SpecVersionMinor uint8
+		SpecVersionMajor uint8
+		SpecErrata       uint8
+		UintnSize        uint8
+		NumAlgs          uint32
+	}
+	if err := binary.Read(data, binary.LittleEndian, &spec); err != nil {
+		return nil, err
+	}
+	sizes := make([]digestSize, spec.NumAlgs)
+	for i := range sizes {
+		if err := binary.Read(data, binary.LittleEndian, &sizes[i].alg); err != nil {
+			return nil, err
+		}
+		if err := binary.Read(data, binary.LittleEndian, &sizes[i].size); err != nil {
+			return nil, err
+		}
+	}
+	return sizes, nil
+}
+
+// parseEvent parses a TCG_PCR_EVENT2 structure.
+func parseEvent(buf *bytes.Buffer, digestSizes []digestSize) (Event, error) {
+	var header struct {
+		Index      uint32
+		Type       uint32
+		NumDigests uint32
+	}
+	if err := binary.Read(buf, binary.LittleEndian, &header); err != nil {
+		return Event{}, err
+	}
+	event := Event{
+		Index:   header.Index,
+		Type:    header.Type,
+		Digests: make(map[tpm2.Algorithm][]byte),
+	}
+	for i := uint32(0); i < header.NumDigests; i++ {
+		var alg tpm2.Algorithm
+		if err := binary.Read(buf, binary.LittleEndian, &alg); err != nil {
+			return Event{}, err
+		}
+		size, ok := findDigestSize(digestSizes, alg)
+		if !ok {
+			return Event{}, fmt.Errorf("digest algorithm %v is not in the Spec ID Event", alg)
+		}
+		digest := buf.Next(int(size))
+		if len(digest) != int(size) {
+			return Event{}, fmt.Errorf("%v digest is truncated", alg)
+		}
+		event.Digests[alg] = digest
+	}
+
+	var eventSize uint32
+	if err := binary.Read(buf, binary.LittleEndian, &eventSize); err != nil {
+		return Event{}, err
+	}
+	event.Data = buf.Next(int(eventSize))
+	if len(event.Data) != int(eventSize) {
+		return Event{}, errors.New("event data is truncated")
+	}
+	return event, nil
+}
+
+func findDigestSize(digestSizes []digestSize, alg tpm2.Algorithm) (uint16, bool) {
+	for _, size := range digestSizes {
+		if size.alg == alg {
+			return size.size, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,71 @@
+package eventlog_test
+
+import (
+	"crypto"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-tpm-tools/internal/eventlog"
+	"github.com/google/go-tpm-tools/internal/test"
+	"github.com/google/go-tpm/legacy/tpm2"
+)
+
+func TestParse(t *testing.T) {
+	log, err := eventlog.Parse(test.Rhel8EventLog)
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+	wantAlgs := []tpm2.Algorithm{tpm2.AlgSHA1, tpm2.AlgSHA256, tpm2.AlgSHA384}
+	if diff := cmp.Diff(wantAlgs, log.Algs); diff != "" {
+		t.Errorf("unexpected digest algorithms (-want +got):\n%s", diff)
+	}
+	if got := len(log.Events); got != 82 {
+		t.Errorf("got %d events, want 82", got)
+	}
+	for i, event := range log.Events {
+		for _, alg := range wantAlgs {
+			hash, _ := alg.Hash()
+			if got := len(event.Digests[alg]); got != hash.Size() {
+				t.Errorf("event #%d has a %d byte %v digest, want %d bytes", i, got, alg, hash.Size())
+			}
+		}
+	}
+}
+
+func TestParseStartupLocality(t *testing.T) {
+	log, err := eventlog.Parse(test.CreateTpm2EventLog(1))
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+	if _, ok := log.Events[0].StartupLocality(); ok {
+		t.Error("SCRTM version event was parsed as a StartupLocality event")
+	}
+	locality := eventlog.Event{
+		Type: eventlog.NoAction,
+		Data: []byte("StartupLocality\x00\x03"),
+		Digests: map[tpm2.Algorithm][]byte{
+			tpm2.AlgSHA384: make([]byte, crypto.SHA384.Size()),
+		},
+	}
+	if got, ok := locality.StartupLocality(); !ok || got != 3 {
+		t.Errorf("StartupLocality() = %d, %v, want 3, true", got, ok)
+	}
+}
+
+func TestParseFail(t *testing.T) {
+	tests := []struct {
+		name string
+		log  []byte
+	}{
+		{"Empty", nil},
+		{"LegacyFormat", test.Debian10EventLog},
+		{"Truncated", test.Rhel8EventLog[:len(test.Rhel8EventLog)-1]},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := eventlog.Parse(tc.log); err == nil {
+				t.Error("Parse() succeeded, expected error")
+			}
+		})
+	}
+}
@@ -91,7 +91,12 @@ func SamePCRSelection(p *pb.PCRs, sel tpm2.PCRSelection) bool {
 // PCRSessionAuth calculates the authorization value for the given PCRs.
 func PCRSessionAuth(p *pb.PCRs, hashAlg crypto.Hash) []byte {
 	// Start with all zeros, we only use a single policy command on our session.
-	oldDigest := make([]byte, hashAlg.Size())
+	return ExtendPolicyPCR(make([]byte, hashAlg.Size()), p, hashAlg)
+}
+
+// ExtendPolicyPCR extends a policy digest with a TPM2_PolicyPCR command for the
+// given PCRs, for policies that contain multiple policy commands.
+func ExtendPolicyPCR(oldDigest []byte, p *pb.PCRs, hashAlg crypto.Hash) []byte {
 	ccPolicyPCR, _ := tpmutil.Pack(tpm2.CmdPolicyPCR)
 
 	// Extend the policy digest, see TPM2_PolicyPCR in Part 3 of the spec.
@@ -104,6 +109,36 @@ func PCRSessionAuth(p *pb.PCRs, hashAlg crypto.Hash) []byte {
 	return newDigest[:]
 }
 
+// cmdPolicyAuthorize is TPM_CC_PolicyAuthorize, which legacy tpm2 does not
+// define.
+const cmdPolicyAuthorize tpmutil.Command = 0x0000016A
+
+// PolicyAuthorizeAuth calculates the authorization value for objects that can
+// be used with any policy approved by the key with the given Name.
+func PolicyAuthorizeAuth(keySignName []byte, hashAlg crypto.Hash) []byte {
+	ccPolicyAuthorize, _ := tpmutil.Pack(cmdPolicyAuthorize)
+
+	// See TPM2_PolicyAuthorize in Part 3 of the spec. The policy digest is
+	// reset to all zeros, then extended with the command code and key Name,
+	// and then with the (empty) policyRef.
+	hash := hashAlg.New()
+	hash.Write(make([]byte, hashAlg.Size()))
+	hash.Write(ccPolicyAuthorize)
+	hash.Write(keySignName)
+	digest := hash.Sum(nil)
+	hash.Reset()
+	hash.Write(digest)
+	return hash.Sum(nil)
+}
+
+// AuthorizedPolicyDigest computes the digest that a policy signer signs to
+// approve a policy for TPM2_PolicyAuthorize. The policyRef is always empty.
+func AuthorizedPolicyDigest(approvedPolicy []byte, hashAlg crypto.Hash) []byte {
+	hash := hashAlg.New()
+	hash.Write(approvedPolicy)
+	return hash.Sum(nil)
+}
+
 // PCRDigest computes the digest of the Pcrs. Note that the digest hash
 // algorithm may differ from the PCRs' hash (which denotes the PCR bank).
 func PCRDigest(p *pb.PCRs, hashAlg crypto.Hash) []byte {
@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/google/go-attestation/attest"
+	"github.com/google/go-tpm-tools/internal/eventlog"
 	"github.com/google/go-tpm-tools/simulator"
 	"github.com/google/go-tpm/legacy/tpm2"
 	gtpm2 "github.com/google/go-tpm/tpm2"
@@ -126,8 +127,6 @@ func simulateEventLogEvents(tb testing.TB, rw io.ReadWriter, eventLog []byte) {
 		tb.Fatalf("Failed to parse test event log: %v", err)
 	}
 
-	// TODO: The Ubuntu 21.04 event log also includes SHA384, but this is not yet
-	// supported by go-attestation or go-tpm-tools.
 	hashAlgs := map[tpm2.Algorithm]attest.HashAlg{
 		tpm2.AlgSHA1:   attest.HashSHA1,
 		tpm2.AlgSHA256: attest.HashSHA256,
@@ -143,6 +142,24 @@ func simulateEventLogEvents(tb testing.TB, rw io.ReadWriter, eventLog []byte) {
 			extendOnePcr(tb, rw, event.Index, tpm2Alg, event.Digest)
 		}
 	}
+
+	// go-attestation only parses SHA-1 and SHA-256 digests, so the digests for
+	// the larger banks come from the crypto-agile log, if the log is one.
+	agileLog, err := eventlog.Parse(eventLog)
+	if err != nil {
+		return
+	}
+	for _, alg := range agileLog.Algs {
+		if _, ok := hashAlgs[alg]; ok {
+			continue
+		}
+		for _, event := range agileLog.Events {
+			if event.Type == eventlog.NoAction {
+				continue
+			}
+			extendOnePcr(tb, rw, int(event.Index), alg, event.Digests[alg])
+		}
+	}
 }
 
 func extendOnePcr(tb testing.TB, rw io.ReadWriter, pcr int, hashAlg tpm2.Algorithm, hash []byte) {
@@ -30,6 +30,7 @@ import (
 	"github.com/google/go-tpm-tools/launcher/internal/signaturediscovery"
 	"github.com/google/go-tpm-tools/launcher/spec"
 	pb "github.com/google/go-tpm-tools/proto/attest"
+	tpb "github.com/google/go-tpm-tools/proto/tpm"
 	"github.com/google/go-tpm-tools/verifier"
 	"github.com/google/go-tpm-tools/verifier/models"
 	"github.com/google/go-tpm-tools/verifier/oci"
@@ -49,6 +50,12 @@ type AttestationAgent interface {
 	MeasureEvent(cel.Content) error
 	Attest(context.Context, AttestAgentOpts) ([]byte, error)
 	AttestWithClient(ctx context.Context, opts AttestAgentOpts, client verifier.Client) ([]byte, error)
+	AttestEvidence(nonce []byte) (*pb.Attestation, error)
+	WorkloadKey() (*tpb.CertifiedBlob, error)
+	WorkloadSign(data []byte) ([]byte, error)
+	WorkloadSeal(data []byte) (*tpb.SealedBytes, error)
+	WorkloadUnseal(sealed *tpb.SealedBytes) ([]byte, error)
+	ImportSecret(blob *tpb.ImportBlob) ([]byte, error)
 	Refresh(context.Context) error
 	Close() error
 }
@@ -59,7 +66,8 @@ type attestRoot interface {
 	// GetCEL fetches the CEL with events corresponding to the sequence of Extended measurements
 	// to this attestation root
 	GetCEL() *cel.CEL
-	// Attest fetches a technology-specific quote from the root of trust.
+	// Attest fetches a technology-specific quote from the root of trust, along
+	// with the CEL of the events measured to it when the quote was taken.
 	Attest(nonce []byte) (any, error)
 }
 
@@ -72,6 +80,7 @@ type AttestAgentOpts struct {
 type agent struct {
 	measuredRots     []attestRoot
 	avRot            attestRoot
+	tpmRot           *tpmAttestRoot
 	fetchedAK        *client.Key
 	client           verifier.Client
 	principalFetcher principalIDTokenFetcher
@@ -112,6 +121,7 @@ func CreateAttestationAgent(tpm io.ReadWriteCloser, akFetcher util.TpmKeyFetcher
 		tpm:       tpm,
 	}
 	attestAgent.measuredRots = append(attestAgent.measuredRots, tpmAR)
+	attestAgent.tpmRot = tpmAR
 
 	// check if is a TDX machine
 	qp, err := tg.GetQuoteProvider()
@@ -205,11 +215,6 @@ func (a *agent) AttestWithClient(ctx context.Context, opts AttestAgentOpts, clie
 		return nil, fmt.Errorf("failed to attest: %v", err)
 	}
 
-	var cosCel bytes.Buffer
-	if err := a.avRot.GetCEL().EncodeCEL(&cosCel); err != nil {
-		return nil, err
-	}
-
 	req := verifier.VerifyAttestationRequest{
 		Challenge:      challenge,
 		GcpCredentials: principalTokens,
@@ -220,7 +225,6 @@ func (a *agent) AttestWithClient(ctx context.Context, opts AttestAgentOpts, clie
 	case *pb.Attestation:
 		a.logger.Info("attestation through TPM quote")
 
-		v.CanonicalEventLog = cosCel.Bytes()
 		req.Attestation = v
 	case *verifier.TDCCELAttestation:
 		a.logger.Info("attestation through TDX quote")
@@ -230,7 +234,6 @@ func (a *agent) AttestWithClient(ctx context.Context, opts AttestAgentOpts, clie
 			return nil, fmt.Errorf("failed when fetching certificate chain: %w", err)
 		}
 
-		v.CanonicalEventLog = cosCel.Bytes()
 		v.IntermediateCerts = certChain
 		v.AkCert = a.fetchedAK.CertDERBytes()
 		req.TDCCELAttestation = v
@@ -261,6 +264,25 @@ func (a *agent) AttestWithClient(ctx context.Context, opts AttestAgentOpts, clie
 	return resp.ClaimsToken, nil
 }
 
+// AttestEvidence returns the raw attestation of the instance's vTPM for the
+// provided nonce, for workloads verifying it themselves. The attestation
+// contains the CEL of the measured events and, on SEV-SNP and TDX instances,
+// the TEE's attestation report. Unlike Attest, AttestEvidence does not call the
+// Attestation Service.
+func (a *agent) AttestEvidence(nonce []byte) (*pb.Attestation, error) {
+	// Pass the TEE device explicitly, so that failing to collect its report
+	// is an error rather than silently omitting the report.
+	device := teeDevice()
+	if device != nil {
+		defer device.Close()
+	}
+	attestation, err := a.tpmRot.attestWithTEE(nonce, device)
+	if err != nil {
+		return nil, fmt.Errorf("failed to attest: %v", err)
+	}
+	return attestation, nil
+}
+
 func convertOCIToContainerSignature(ociSig oci.Signature) (*verifier.ContainerSignature, error) {
 	payload, err := ociSig.Payload()
 	if err != nil {
@@ -281,10 +303,11 @@ func convertOCIToContainerSignature(ociSig oci.Signature) (*verifier.ContainerSi
 }
 
 type tpmAttestRoot struct {
-	tpmMu     sync.Mutex
-	fetchedAK *client.Key
-	tpm       io.ReadWriteCloser
-	cosCel    cel.CEL
+	tpmMu       sync.Mutex
+	fetchedAK   *client.Key
+	tpm         io.ReadWriteCloser
+	cosCel      cel.CEL
+	workloadKey *workloadKey
 }
 
 func (t *tpmAttestRoot) GetCEL() *cel.CEL {
@@ -292,17 +315,49 @@ func (t *tpmAttestRoot) GetCEL() *cel.CEL {
 }
 
 func (t *tpmAttestRoot) Extend(c cel.Content) error {
+	t.tpmMu.Lock()
+	defer t.tpmMu.Unlock()
+
 	return t.cosCel.AppendEventPCR(t.tpm, cel.CosEventPCR, c)
 }
 
 func (t *tpmAttestRoot) Attest(nonce []byte) (any, error) {
+	return t.attestWithTEE(nonce, nil)
+}
+
+// attestWithTEE attests to the TPM, adding the attestation report of the
+// device if it is not nil, and the CEL. The CEL is encoded under the same lock
+// as the quote, so it matches the quoted PCRs.
+func (t *tpmAttestRoot) attestWithTEE(nonce []byte, device client.TEEDevice) (*pb.Attestation, error) {
 	t.tpmMu.Lock()
 	defer t.tpmMu.Unlock()
 
-	return t.fetchedAK.Attest(client.AttestOpts{
+	attestation, err := t.fetchedAK.Attest(client.AttestOpts{
 		Nonce:            nonce,
 		CertChainFetcher: http.DefaultClient,
+		TEEDevice:        device,
 	})
+	if err != nil {
+		return nil, err
+	}
+	var cosCel bytes.Buffer
+	if err := t.cosCel.EncodeCEL(&cosCel); err != nil {
+		return nil, err
+	}
+	attestation.CanonicalEventLog = cosCel.Bytes()
+	return attestation, nil
+}
+
+// teeDevice returns the instance's SEV-SNP or TDX attestation device, or nil if
+// it has neither.
+func teeDevice() client.TEEDevice {
+	if device, err := client.CreateSevSnpQuoteProvider(); err == nil {
+		return device
+	}
+	if device, err := client.CreateTdxQuoteProvider(); err == nil {
+		return device
+	}
+	return nil
 }
 
 type tdxAttestRoot struct {
@@ -317,6 +372,9 @@ func (t *tdxAttestRoot) GetCEL() *cel.CEL {
 }
 
 func (t *tdxAttestRoot) Extend(c cel.Content) error {
+	t.tdxMu.Lock()
+	defer t.tdxMu.Unlock()
+
 	return t.cosCel.AppendEventRTMR(t.tsmClient, cel.CosRTMR, c)
 }
 
@@ -340,11 +398,16 @@ func (t *tdxAttestRoot) Attest(nonce []byte) (any, error) {
 	if err != nil {
 		return nil, err
 	}
+	var cosCel bytes.Buffer
+	if err := t.cosCel.EncodeCEL(&cosCel); err != nil {
+		return nil, err
+	}
 
 	return &verifier.TDCCELAttestation{
-		CcelAcpiTable: ccelTable,
-		CcelData:      ccelData,
-		TdQuote:       rawQuote,
+		CcelAcpiTable:     ccelTable,
+		CcelData:          ccelData,
+		TdQuote:           rawQuote,
+		CanonicalEventLog: cosCel.Bytes(),
 	}, nil
 }
 
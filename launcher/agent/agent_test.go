@@ -1,7 +1,9 @@
 package agent
 
 import (
+	"bytes"
 	"context"
+	"crypto"
 	"crypto/rand"
 	"crypto/rsa"
 	"encoding/base64"
@@ -15,6 +17,8 @@ import (
 	"github.com/cenkalti/backoff/v4"
 	"github.com/golang-jwt/jwt/v4"
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-eventlog/proto/state"
+	"github.com/google/go-eventlog/register"
 	"github.com/google/go-tpm-tools/cel"
 	"github.com/google/go-tpm-tools/client"
 	"github.com/google/go-tpm-tools/internal/test"
@@ -22,6 +26,8 @@ import (
 	"github.com/google/go-tpm-tools/launcher/internal/signaturediscovery"
 	"github.com/google/go-tpm-tools/launcher/spec"
 	attestpb "github.com/google/go-tpm-tools/proto/attest"
+	tpmpb "github.com/google/go-tpm-tools/proto/tpm"
+	"github.com/google/go-tpm-tools/server"
 	"github.com/google/go-tpm-tools/verifier"
 	"github.com/google/go-tpm-tools/verifier/fake"
 	"github.com/google/go-tpm-tools/verifier/oci"
@@ -173,6 +179,105 @@ func TestAttest(t *testing.T) {
 	}
 }
 
+func TestAttestEvidence(t *testing.T) {
+	tpm := test.GetTPM(t)
+	defer client.CheckedClose(t, tpm)
+
+	attestAgent, err := CreateAttestationAgent(tpm, client.AttestationKeyECC, nil, placeholderPrincipalFetcher, signaturediscovery.NewFakeClient(), spec.LaunchSpec{}, logging.SimpleLogger())
+	if err != nil {
+		t.Fatalf("failed to create an attestation agent %v", err)
+	}
+	defer attestAgent.Close()
+	if err := measureFakeEvents(attestAgent); err != nil {
+		t.Fatalf("failed to measure events: %v", err)
+	}
+
+	nonce := []byte("workload nonce")
+	attestation, err := attestAgent.AttestEvidence(nonce)
+	if err != nil {
+		t.Fatalf("failed to attest: %v", err)
+	}
+
+	akPub := attestAgent.(*agent).fetchedAK.PublicKey()
+	if _, err := server.VerifyAttestation(attestation, server.VerifyOpts{Nonce: nonce, TrustedAKs: []crypto.PublicKey{akPub}}); err != nil {
+		t.Fatalf("failed to verify attestation: %v", err)
+	}
+	// VerifyAttestation doesn't parse the CEL, so replay it against the
+	// quoted PCRs.
+	var pcrBank register.PCRBank
+	for _, quote := range attestation.GetQuotes() {
+		pcrs := quote.GetPcrs()
+		if pcrs.GetHash() != tpmpb.HashAlgo_SHA256 {
+			continue
+		}
+		pcrBank.TCGHashAlgo = state.HashAlgo(pcrs.GetHash())
+		for index, digest := range pcrs.GetPcrs() {
+			pcrBank.PCRs = append(pcrBank.PCRs, register.PCR{Index: int(index), Digest: digest, DigestAlg: crypto.SHA256})
+		}
+	}
+	cosState, err := server.ParseCosCELPCR(attestation.GetCanonicalEventLog(), pcrBank)
+	if err != nil {
+		t.Fatalf("failed to replay CEL against the quoted PCRs: %v", err)
+	}
+	validateContainerState(t, cosState)
+
+	if _, err := server.VerifyAttestation(attestation, server.VerifyOpts{Nonce: []byte("other nonce"), TrustedAKs: []crypto.PublicKey{akPub}}); err == nil {
+		t.Error("VerifyAttestation() succeeded with the wrong nonce")
+	}
+}
+
+func TestAttestEvidenceRacingMeasureEvent(t *testing.T) {
+	tpm := test.GetTPM(t)
+	defer client.CheckedClose(t, tpm)
+
+	attestAgent, err := CreateAttestationAgent(tpm, client.AttestationKeyECC, nil, placeholderPrincipalFetcher, signaturediscovery.NewFakeClient(), spec.LaunchSpec{}, logging.SimpleLogger())
+	if err != nil {
+		t.Fatalf("failed to create an attestation agent %v", err)
+	}
+	defer attestAgent.Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if err := attestAgent.MeasureEvent(cel.CosTlv{EventType: cel.EnvVarType, EventContent: []byte(env)}); err != nil {
+				t.Error(err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			attestation, err := attestAgent.AttestEvidence([]byte("workload nonce"))
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			// The CEL must match the quoted PCRs, even if events are
+			// measured while attesting.
+			var pcrBank register.PCRBank
+			for _, quote := range attestation.GetQuotes() {
+				pcrs := quote.GetPcrs()
+				if pcrs.GetHash() != tpmpb.HashAlgo_SHA256 {
+					continue
+				}
+				pcrBank.TCGHashAlgo = state.HashAlgo(pcrs.GetHash())
+				for index, digest := range pcrs.GetPcrs() {
+					pcrBank.PCRs = append(pcrBank.PCRs, register.PCR{Index: int(index), Digest: digest, DigestAlg: crypto.SHA256})
+				}
+			}
+			decodedCEL, err := cel.DecodeToCEL(bytes.NewBuffer(attestation.GetCanonicalEventLog()))
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if err := decodedCEL.Replay(pcrBank); err != nil {
+				t.Errorf("failed to replay CEL against the quoted PCRs: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
 func placeholderPrincipalFetcher(_ string) ([][]byte, error) {
 	return [][]byte{}, nil
 }
@@ -0,0 +1,40 @@
+package agent
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/google/go-tpm-tools/client"
+	tpb "github.com/google/go-tpm-tools/proto/tpm"
+)
+
+// ImportSecret decrypts a secret created with server.CreateImportBlob for
+// either the RSA or the ECC endorsement key of the TPM. Secrets bound to PCRs
+// are only imported if the PCRs have the expected values.
+func (a *agent) ImportSecret(blob *tpb.ImportBlob) ([]byte, error) {
+	a.tpmRot.tpmMu.Lock()
+	defer a.tpmRot.tpmMu.Unlock()
+
+	var errs []error
+	for _, ekFunc := range []func(io.ReadWriter) (*client.Key, error){
+		client.EndorsementKeyRSA,
+		client.EndorsementKeyECC,
+	} {
+		secret, err := importWithEK(a.tpmRot.tpm, ekFunc, blob)
+		if err == nil {
+			return secret, nil
+		}
+		errs = append(errs, err)
+	}
+	return nil, fmt.Errorf("failed to import secret with the RSA or ECC EK: %w", errors.Join(errs...))
+}
+
+func importWithEK(tpm io.ReadWriter, ekFunc func(io.ReadWriter) (*client.Key, error), blob *tpb.ImportBlob) ([]byte, error) {
+	ek, err := ekFunc(tpm)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create EK: %w", err)
+	}
+	defer ek.Close()
+	return ek.Import(blob)
+}
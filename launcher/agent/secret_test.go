@@ -0,0 +1,61 @@
+package agent
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/google/go-tpm-tools/client"
+	"github.com/google/go-tpm-tools/internal/test"
+	"github.com/google/go-tpm-tools/launcher/internal/logging"
+	"github.com/google/go-tpm-tools/launcher/internal/signaturediscovery"
+	"github.com/google/go-tpm-tools/launcher/spec"
+	tpb "github.com/google/go-tpm-tools/proto/tpm"
+	"github.com/google/go-tpm-tools/server"
+)
+
+func TestImportSecret(t *testing.T) {
+	tpm := test.GetTPM(t)
+	defer client.CheckedClose(t, tpm)
+
+	attestAgent, err := CreateAttestationAgent(tpm, client.AttestationKeyECC, nil, placeholderPrincipalFetcher, signaturediscovery.NewFakeClient(), spec.LaunchSpec{}, logging.SimpleLogger())
+	if err != nil {
+		t.Fatalf("failed to create an attestation agent %v", err)
+	}
+	defer attestAgent.Close()
+
+	secret := []byte("workload secret")
+	for _, ekFunc := range []func(io.ReadWriter) (*client.Key, error){
+		client.EndorsementKeyRSA,
+		client.EndorsementKeyECC,
+	} {
+		ek, err := ekFunc(tpm)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ekPub := ek.PublicKey()
+		ek.Close()
+
+		blob, err := server.CreateImportBlob(ekPub, secret, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		imported, err := attestAgent.ImportSecret(blob)
+		if err != nil {
+			t.Fatalf("ImportSecret() failed: %v", err)
+		}
+		if !bytes.Equal(imported, secret) {
+			t.Errorf("ImportSecret() = %q, want %q", imported, secret)
+		}
+
+		// The secret is bound to a PCR value the TPM doesn't have.
+		pcrs := &tpb.PCRs{Hash: tpb.HashAlgo_SHA256, Pcrs: map[uint32][]byte{0: bytes.Repeat([]byte{0xff}, 32)}}
+		boundBlob, err := server.CreateImportBlob(ekPub, secret, pcrs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := attestAgent.ImportSecret(boundBlob); err == nil {
+			t.Error("ImportSecret() succeeded with the wrong PCR values")
+		}
+	}
+}
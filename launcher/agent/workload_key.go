@@ -0,0 +1,149 @@
+package agent
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+
+	"github.com/google/go-tpm-tools/cel"
+	"github.com/google/go-tpm-tools/client"
+	tpb "github.com/google/go-tpm-tools/proto/tpm"
+	"github.com/google/go-tpm/legacy/tpm2"
+)
+
+// workloadSealPCRs are the PCRs that the workload's data is sealed to: the
+// boot PCRs measuring the bootloader and kernel (4), the Secure Boot state (7),
+// the GRUB commands and kernel command line (8) and the files GRUB loads (9),
+// and the PCR measuring the COS CEL events.
+var workloadSealPCRs = tpm2.PCRSelection{Hash: tpm2.AlgSHA256, PCRs: []int{4, 7, 8, 9, cel.CosEventPCR}}
+
+// workloadKeyTemplate returns the template of the workload key, an
+// unrestricted ECDSA P-256 signing key.
+func workloadKeyTemplate() tpm2.Public {
+	template := client.AKTemplateECC()
+	template.Attributes &= ^tpm2.FlagRestricted
+	return template
+}
+
+// workloadKey is a key signing on behalf of the workload, created under the
+// SRK when first used by the launcher, so it is different on each boot. Only
+// its blobs are kept, and it is loaded while signing.
+type workloadKey struct {
+	public        []byte
+	private       []byte
+	certification *tpb.CertifiedBlob
+}
+
+// WorkloadKey returns the workload key's certification by the AK, creating the
+// key if needed. The certification contains the key's public area, and can be
+// checked with server.VerifyCertifiedKey (with empty extraData) against an AK
+// verified with an attestation.
+func (a *agent) WorkloadKey() (*tpb.CertifiedBlob, error) {
+	a.tpmRot.tpmMu.Lock()
+	defer a.tpmRot.tpmMu.Unlock()
+
+	key, err := a.tpmRot.getWorkloadKey()
+	if err != nil {
+		return nil, err
+	}
+	return key.certification, nil
+}
+
+// WorkloadSign signs the SHA-256 digest of the data with the workload key,
+// returning an ASN.1 encoded ECDSA signature.
+func (a *agent) WorkloadSign(data []byte) ([]byte, error) {
+	a.tpmRot.tpmMu.Lock()
+	defer a.tpmRot.tpmMu.Unlock()
+
+	key, err := a.tpmRot.loadWorkloadKey()
+	if err != nil {
+		return nil, err
+	}
+	defer key.Close()
+
+	signer, err := key.GetSigner()
+	if err != nil {
+		return nil, err
+	}
+	digest := sha256.Sum256(data)
+	return signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+}
+
+// WorkloadSeal seals the workload's data to the current values of the boot
+// PCRs and the PCR measuring the COS CEL events, so that it can only be
+// unsealed by the same workload, booted with the same image and Secure Boot
+// state, and with the same launch configuration and measured events.
+func (a *agent) WorkloadSeal(data []byte) (*tpb.SealedBytes, error) {
+	a.tpmRot.tpmMu.Lock()
+	defer a.tpmRot.tpmMu.Unlock()
+
+	srk, err := client.StorageRootKeyECC(a.tpmRot.tpm)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SRK: %w", err)
+	}
+	defer srk.Close()
+	return srk.Seal(data, client.SealOpts{Current: workloadSealPCRs})
+}
+
+// WorkloadUnseal unseals the workload's data sealed by WorkloadSeal.
+func (a *agent) WorkloadUnseal(sealed *tpb.SealedBytes) ([]byte, error) {
+	a.tpmRot.tpmMu.Lock()
+	defer a.tpmRot.tpmMu.Unlock()
+
+	srk, err := client.StorageRootKeyECC(a.tpmRot.tpm)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SRK: %w", err)
+	}
+	defer srk.Close()
+	return srk.Unseal(sealed, client.UnsealOpts{})
+}
+
+// getWorkloadKey returns the workload key, creating it and certifying it with
+// the AK on first use. The caller must hold tpmMu.
+func (t *tpmAttestRoot) getWorkloadKey() (*workloadKey, error) {
+	if t.workloadKey != nil {
+		return t.workloadKey, nil
+	}
+
+	srk, err := client.StorageRootKeyECC(t.tpm)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SRK: %w", err)
+	}
+	defer srk.Close()
+	public, private, err := srk.CreateChildKey(workloadKeyTemplate())
+	if err != nil {
+		return nil, err
+	}
+	key, err := srk.LoadChildKey(public, private)
+	if err != nil {
+		return nil, err
+	}
+	defer key.Close()
+	certification, err := t.fetchedAK.Certify(key, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to certify workload key: %w", err)
+	}
+
+	t.workloadKey = &workloadKey{
+		public:        public,
+		private:       private,
+		certification: certification,
+	}
+	return t.workloadKey, nil
+}
+
+// loadWorkloadKey loads the workload key, which the caller must close. The
+// caller must hold tpmMu.
+func (t *tpmAttestRoot) loadWorkloadKey() (*client.Key, error) {
+	wk, err := t.getWorkloadKey()
+	if err != nil {
+		return nil, err
+	}
+	srk, err := client.StorageRootKeyECC(t.tpm)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SRK: %w", err)
+	}
+	defer srk.Close()
+	return srk.LoadChildKey(wk.public, wk.private)
+}
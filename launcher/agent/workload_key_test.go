@@ -0,0 +1,104 @@
+package agent
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"slices"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-tpm-tools/cel"
+	"github.com/google/go-tpm-tools/client"
+	"github.com/google/go-tpm-tools/internal/test"
+	"github.com/google/go-tpm-tools/launcher/internal/logging"
+	"github.com/google/go-tpm-tools/launcher/internal/signaturediscovery"
+	"github.com/google/go-tpm-tools/launcher/spec"
+	"github.com/google/go-tpm-tools/server"
+)
+
+func TestWorkloadSign(t *testing.T) {
+	tpm := test.GetTPM(t)
+	defer client.CheckedClose(t, tpm)
+
+	attestAgent, err := CreateAttestationAgent(tpm, client.AttestationKeyECC, nil, placeholderPrincipalFetcher, signaturediscovery.NewFakeClient(), spec.LaunchSpec{}, logging.SimpleLogger())
+	if err != nil {
+		t.Fatalf("failed to create an attestation agent %v", err)
+	}
+	defer attestAgent.Close()
+
+	certification, err := attestAgent.WorkloadKey()
+	if err != nil {
+		t.Fatalf("WorkloadKey() failed: %v", err)
+	}
+	akPub := attestAgent.(*agent).fetchedAK.PublicKey()
+	pub, err := server.VerifyCertifiedKey(certification, akPub, nil)
+	if err != nil {
+		t.Fatalf("failed to verify workload key certification: %v", err)
+	}
+	ecdsaPub, ok := pub.(*ecdsa.PublicKey)
+	if !ok {
+		t.Fatalf("workload key has type %T, want *ecdsa.PublicKey", pub)
+	}
+
+	// The workload key is the same for each call.
+	again, err := attestAgent.WorkloadKey()
+	if err != nil {
+		t.Fatalf("WorkloadKey() failed: %v", err)
+	}
+	if !bytes.Equal(again.GetPubArea(), certification.GetPubArea()) {
+		t.Error("WorkloadKey() returned different keys")
+	}
+
+	data := []byte("workload data")
+	sig, err := attestAgent.WorkloadSign(data)
+	if err != nil {
+		t.Fatalf("WorkloadSign() failed: %v", err)
+	}
+	digest := sha256.Sum256(data)
+	if !ecdsa.VerifyASN1(ecdsaPub, digest[:], sig) {
+		t.Error("failed to verify workload signature")
+	}
+}
+
+func TestWorkloadSealUnseal(t *testing.T) {
+	tpm := test.GetTPM(t)
+	defer client.CheckedClose(t, tpm)
+
+	attestAgent, err := CreateAttestationAgent(tpm, client.AttestationKeyECC, nil, placeholderPrincipalFetcher, signaturediscovery.NewFakeClient(), spec.LaunchSpec{}, logging.SimpleLogger())
+	if err != nil {
+		t.Fatalf("failed to create an attestation agent %v", err)
+	}
+	defer attestAgent.Close()
+	if err := measureFakeEvents(attestAgent); err != nil {
+		t.Fatalf("failed to measure events: %v", err)
+	}
+
+	secret := []byte("workload secret")
+	sealed, err := attestAgent.WorkloadSeal(secret)
+	if err != nil {
+		t.Fatalf("WorkloadSeal() failed: %v", err)
+	}
+	pcrs := slices.Sorted(slices.Values(sealed.GetPcrs()))
+	if diff := cmp.Diff([]uint32{4, 7, 8, 9, uint32(cel.CosEventPCR)}, pcrs); diff != "" {
+		t.Errorf("WorkloadSeal() sealed to unexpected PCRs (-want +got):\n%s", diff)
+	}
+	unsealed, err := attestAgent.WorkloadUnseal(sealed)
+	if err != nil {
+		t.Fatalf("WorkloadUnseal() failed: %v", err)
+	}
+	if !bytes.Equal(unsealed, secret) {
+		t.Errorf("WorkloadUnseal() = %q, want %q", unsealed, secret)
+	}
+
+	content, err := cel.FormatWorkloadEvent("model", []byte("digest"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := attestAgent.MeasureEvent(cel.CosTlv{EventType: cel.WorkloadEventType, EventContent: content}); err != nil {
+		t.Fatalf("failed to measure workload event: %v", err)
+	}
+	if _, err := attestAgent.WorkloadUnseal(sealed); err == nil {
+		t.Error("WorkloadUnseal() succeeded after a workload event was measured")
+	}
+}
@@ -31,6 +31,7 @@ import (
 	"github.com/google/go-tpm-tools/launcher/internal/healthmonitoring/nodeproblemdetector"
 	"github.com/google/go-tpm-tools/launcher/internal/logging"
 	"github.com/google/go-tpm-tools/launcher/internal/signaturediscovery"
+	"github.com/google/go-tpm-tools/launcher/internal/tokenrefresh"
 	"github.com/google/go-tpm-tools/launcher/launcherfile"
 	"github.com/google/go-tpm-tools/launcher/registryauth"
 	"github.com/google/go-tpm-tools/launcher/spec"
@@ -67,17 +68,6 @@ const (
 	nofile = 131072 // Max number of file descriptor
 )
 
-const (
-	// defaultRefreshMultiplier is a multiplier on the current token expiration
-	// time, at which the refresher goroutine will collect a new token.
-	// defaultRefreshMultiplier+defaultRefreshJitter should be <1.
-	defaultRefreshMultiplier = 0.8
-	// defaultRefreshJitter is a random component applied additively to the
-	// refresh multiplier. The refresher will wait for some time in the range
-	// [defaultRefreshMultiplier-defaultRefreshJitter, defaultRefreshMultiplier+defaultRefreshJitter]
-	defaultRefreshJitter = 0.1
-)
-
 // Default OOM score for a CS container.
 const defaultOOMScore = 1000
 
@@ -93,6 +83,9 @@ func NewRunner(ctx context.Context, cdClient *containerd.Client, token oauth2.To
 		mounts = append(mounts, lsMnt.SpecsMount())
 	}
 	mounts = appendTokenMounts(mounts)
+	if len(launchSpec.Secrets) != 0 {
+		mounts = appendSecretsMount(mounts)
+	}
 	var cgroupOpts []oci.SpecOpts
 	if launchSpec.CgroupNamespace {
 		mounts = appendCgroupRw(mounts)
@@ -343,6 +336,9 @@ func (r *ContainerRunner) measureCELEvents(ctx context.Context) error {
 	if err := r.measureMemoryMonitor(); err != nil {
 		return fmt.Errorf("failed to measure memory monitoring state: %v", err)
 	}
+	if err := measureSecrets(r.attestAgent, r.launchSpec.Secrets); err != nil {
+		return fmt.Errorf("failed to measure secrets: %v", err)
+	}
 
 	separator := cel.CosTlv{
 		EventType:    cel.LaunchSeparatorType,
@@ -467,7 +463,7 @@ func (r *ContainerRunner) refreshToken(ctx context.Context) (time.Duration, erro
 
 	r.logger.Info("successfully refreshed attestation token", "token", mapClaims)
 
-	return getNextRefreshFromExpiration(time.Until(claims.ExpiresAt.Time), rand.Float64()), nil
+	return tokenrefresh.NextRefreshFromExpiration(time.Until(claims.ExpiresAt.Time), rand.Float64()), nil
 }
 
 // ctx must be a cancellable context.
@@ -522,16 +518,6 @@ func (r *ContainerRunner) fetchAndWriteTokenWithRetry(ctx context.Context,
 	return nil
 }
 
-// getNextRefreshFromExpiration returns the Duration for the next run of the
-// token refresher goroutine. It expects pre-validation that expiration is in
-// the future (e.g., time.Now < expiration).
-func getNextRefreshFromExpiration(expiration time.Duration, random float64) time.Duration {
-	diff := defaultRefreshJitter * float64(expiration)
-	center := defaultRefreshMultiplier * float64(expiration)
-	minRange := center - diff
-	return time.Duration(minRange + random*2*diff)
-}
-
 /*
 defaultRetryPolicy retries as follows:
 
@@ -591,6 +577,13 @@ func (r *ContainerRunner) Run(ctx context.Context) error {
 		}
 	}
 
+	if len(r.launchSpec.Secrets) != 0 {
+		if err := r.importSecrets(); err != nil {
+			return fmt.Errorf("failed to import secrets: %v", err)
+		}
+		r.logger.Info("Imported secrets", "secret_count", len(r.launchSpec.Secrets))
+	}
+
 	// create and start the TEE server
 	r.logger.Info("EnableOnDemandAttestation is enabled: initializing TEE server.")
 
@@ -789,6 +782,10 @@ func (r *ContainerRunner) Close(ctx context.Context) {
 	// close the agent
 	r.attestAgent.Close()
 
+	if len(r.launchSpec.Secrets) != 0 {
+		r.unmountSecrets()
+	}
+
 	// Exit gracefully:
 	// Delete container and close connection to attestation service.
 	r.container.Delete(ctx, containerd.WithSnapshotCleanup)
@@ -5,6 +5,7 @@ import (
 	"context"
 	"crypto/rand"
 	"crypto/rsa"
+	"crypto/sha256"
 	"errors"
 	"fmt"
 	"os"
@@ -26,6 +27,8 @@ import (
 	"github.com/google/go-tpm-tools/launcher/internal/logging"
 	"github.com/google/go-tpm-tools/launcher/launcherfile"
 	"github.com/google/go-tpm-tools/launcher/spec"
+	attestpb "github.com/google/go-tpm-tools/proto/attest"
+	tpmpb "github.com/google/go-tpm-tools/proto/tpm"
 	"github.com/google/go-tpm-tools/verifier"
 	"github.com/opencontainers/go-digest"
 	v1 "github.com/opencontainers/image-spec/specs-go/v1"
@@ -43,6 +46,7 @@ type fakeAttestationAgent struct {
 	attestFunc       func(context.Context, agent.AttestAgentOpts) ([]byte, error)
 	sigsCache        []string
 	sigsFetcherFunc  func(context.Context) []string
+	importSecretFunc func(*tpmpb.ImportBlob) ([]byte, error)
 
 	// attMu sits on top of attempts field and protects attempts.
 	attMu    sync.Mutex
@@ -69,6 +73,33 @@ func (f *fakeAttestationAgent) AttestWithClient(_ context.Context, _ agent.Attes
 	return nil, fmt.Errorf("unimplemented")
 }
 
+func (f *fakeAttestationAgent) AttestEvidence(_ []byte) (*attestpb.Attestation, error) {
+	return nil, fmt.Errorf("unimplemented")
+}
+
+func (f *fakeAttestationAgent) WorkloadKey() (*tpmpb.CertifiedBlob, error) {
+	return nil, fmt.Errorf("unimplemented")
+}
+
+func (f *fakeAttestationAgent) WorkloadSign(_ []byte) ([]byte, error) {
+	return nil, fmt.Errorf("unimplemented")
+}
+
+func (f *fakeAttestationAgent) WorkloadSeal(_ []byte) (*tpmpb.SealedBytes, error) {
+	return nil, fmt.Errorf("unimplemented")
+}
+
+func (f *fakeAttestationAgent) WorkloadUnseal(_ *tpmpb.SealedBytes) ([]byte, error) {
+	return nil, fmt.Errorf("unimplemented")
+}
+
+func (f *fakeAttestationAgent) ImportSecret(blob *tpmpb.ImportBlob) ([]byte, error) {
+	if f.importSecretFunc != nil {
+		return f.importSecretFunc(blob)
+	}
+	return nil, fmt.Errorf("unimplemented")
+}
+
 // Refresh simulates the behavior of an actual agent.
 func (f *fakeAttestationAgent) Refresh(ctx context.Context) error {
 	if f.sigsFetcherFunc != nil {
@@ -516,22 +547,6 @@ func TestFetchAndWriteTokenWithTokenRefresh(t *testing.T) {
 	}
 }
 
-func TestGetNextRefresh(t *testing.T) {
-	// 0 <= random < 1.
-	for _, randNum := range []float64{0, .1415926, .5, .75, .999999999} {
-		// expiration should always be >0.
-		// 0 or negative expiration means the token has already expired.
-		for _, expInt := range []int64{1, 10, 100, 1000, 10000, 1000000} {
-			expDuration := time.Duration(expInt)
-			next := getNextRefreshFromExpiration(expDuration, randNum)
-			if next >= expDuration {
-				t.Errorf("getNextRefreshFromExpiration(%v, %v) = %v next refresh. expected %v (next refresh) < %v (expiration)",
-					expDuration, randNum, next, next, expDuration)
-			}
-		}
-	}
-}
-
 func TestInitImageDockerPublic(t *testing.T) {
 	// testing image fetching using a dummy token and a docker repo url
 	containerdClient, err := containerd.New(defaults.DefaultAddress)
@@ -596,6 +611,27 @@ func TestMeasureCELEvents(t *testing.T) {
 				Cmd:  []string{"hello world"},
 			},
 		},
+		{
+			name: "measure container events, secrets, and launch separator event",
+			wantCELEvents: []cel.CosType{
+				cel.ImageRefType,
+				cel.ImageDigestType,
+				cel.RestartPolicyType,
+				cel.ImageIDType,
+				cel.ArgType,
+				cel.EnvVarType,
+				cel.MemoryMonitorType,
+				cel.SecretType,
+				cel.SecretType,
+				cel.LaunchSeparatorType,
+			},
+			launchSpec: spec.LaunchSpec{
+				Secrets: []spec.Secret{
+					{Name: "api-key", BlobDigest: make([]byte, sha256.Size)},
+					{Name: "db.password", BlobDigest: make([]byte, sha256.Size)},
+				},
+			},
+		},
 		{
 			name: "measure partial container events, memory monitoring event, and launch separator event",
 			wantCELEvents: []cel.CosType{
@@ -0,0 +1,26 @@
+// Package tokenrefresh determines when the launcher refreshes attestation
+// tokens before they expire.
+package tokenrefresh
+
+import "time"
+
+const (
+	// defaultRefreshMultiplier is a multiplier on the current token expiration
+	// time, at which a new token will be collected.
+	// defaultRefreshMultiplier+defaultRefreshJitter should be <1.
+	defaultRefreshMultiplier = 0.8
+	// defaultRefreshJitter is a random component applied additively to the
+	// refresh multiplier. The refresh will happen after some time in the range
+	// [defaultRefreshMultiplier-defaultRefreshJitter, defaultRefreshMultiplier+defaultRefreshJitter]
+	defaultRefreshJitter = 0.1
+)
+
+// NextRefreshFromExpiration returns the Duration until the next refresh of a
+// token expiring after expiration, given a random number in [0, 1). It expects
+// pre-validation that expiration is in the future (e.g., time.Now < expiration).
+func NextRefreshFromExpiration(expiration time.Duration, random float64) time.Duration {
+	diff := defaultRefreshJitter * float64(expiration)
+	center := defaultRefreshMultiplier * float64(expiration)
+	minRange := center - diff
+	return time.Duration(minRange + random*2*diff)
+}
@@ -9,4 +9,8 @@ const (
 	ContainerRuntimeMountPath = "/run/container_launcher/"
 	// AttestationVerifierTokenFilename defines the name of the file the attestation token is stored in.
 	AttestationVerifierTokenFilename = "attestation_verifier_claims_token"
+	// HostSecretsPath defines the directory in the host where the tmpfs storing the workload's secrets is mounted.
+	HostSecretsPath = "/tmp/container_launcher_secrets/"
+	// ContainerSecretsMountPath defines the directory in the container storing the workload's secrets.
+	ContainerSecretsMountPath = "/run/secrets/"
 )
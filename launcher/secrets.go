@@ -0,0 +1,84 @@
+package launcher
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"syscall"
+
+	"github.com/google/go-tpm-tools/cel"
+	"github.com/google/go-tpm-tools/launcher/agent"
+	"github.com/google/go-tpm-tools/launcher/launcherfile"
+	"github.com/google/go-tpm-tools/launcher/spec"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+// secretsTmpfsOptions are the options of the tmpfs storing the workload's
+// secrets. Each secret is at most 128 bytes, the TPM's limit on sealed data.
+const secretsTmpfsOptions = "mode=0755,size=1m"
+
+// appendSecretsMount appends the mount spec for the workload's secrets.
+func appendSecretsMount(mounts []specs.Mount) []specs.Mount {
+	m := specs.Mount{}
+	m.Destination = launcherfile.ContainerSecretsMountPath
+	m.Type = "bind"
+	m.Source = launcherfile.HostSecretsPath
+	m.Options = []string{"rbind", "ro"}
+
+	return append(mounts, m)
+}
+
+// measureSecrets measures the name and ImportBlob digest of each secret, so
+// that the secrets imported for the workload are attested.
+func measureSecrets(attestAgent agent.AttestationAgent, secrets []spec.Secret) error {
+	for _, secret := range secrets {
+		content, err := cel.FormatSecret(secret.Name, secret.BlobDigest)
+		if err != nil {
+			return err
+		}
+		if err := attestAgent.MeasureEvent(cel.CosTlv{EventType: cel.SecretType, EventContent: content}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// importSecrets imports the workload's secrets to the TPM, and writes them to
+// a tmpfs mounted in the container, so they are never stored on disk and the
+// workload doesn't need to fetch them over the network. It must be called
+// after the launch events are measured, so that secrets bound to PCRs can be
+// imported. It is called after the first token (if any) is fetched, so that
+// the secrets of a launch that fails attestation are never imported.
+func (r *ContainerRunner) importSecrets() error {
+	if err := os.MkdirAll(launcherfile.HostSecretsPath, 0755); err != nil {
+		return err
+	}
+	if err := syscall.Mount("tmpfs", launcherfile.HostSecretsPath, "tmpfs",
+		syscall.MS_NOSUID|syscall.MS_NODEV|syscall.MS_NOEXEC, secretsTmpfsOptions); err != nil {
+		return fmt.Errorf("failed to mount tmpfs for secrets: %v", err)
+	}
+	return writeSecrets(r.attestAgent, r.launchSpec.Secrets, launcherfile.HostSecretsPath)
+}
+
+// writeSecrets imports each secret to the TPM, and writes it to a file named
+// after the secret in dir.
+func writeSecrets(attestAgent agent.AttestationAgent, secrets []spec.Secret, dir string) error {
+	for _, secret := range secrets {
+		data, err := attestAgent.ImportSecret(secret.ImportBlob)
+		if err != nil {
+			return fmt.Errorf("failed to import secret %q: %v", secret.Name, err)
+		}
+		if err := os.WriteFile(path.Join(dir, secret.Name), data, 0444); err != nil {
+			return fmt.Errorf("failed to write secret %q: %v", secret.Name, err)
+		}
+	}
+	return nil
+}
+
+// unmountSecrets unmounts the tmpfs storing the workload's secrets, erasing
+// them.
+func (r *ContainerRunner) unmountSecrets() {
+	if err := syscall.Unmount(launcherfile.HostSecretsPath, 0); err != nil && err != syscall.EINVAL {
+		r.logger.Error(fmt.Sprintf("failed to unmount secrets: %v", err))
+	}
+}
@@ -0,0 +1,83 @@
+package launcher
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/google/go-tpm-tools/cel"
+	"github.com/google/go-tpm-tools/launcher/spec"
+	tpmpb "github.com/google/go-tpm-tools/proto/tpm"
+)
+
+func TestWriteSecrets(t *testing.T) {
+	secrets := []spec.Secret{
+		{Name: "api-key", ImportBlob: &tpmpb.ImportBlob{Duplicate: []byte("api key")}},
+		{Name: "db.password", ImportBlob: &tpmpb.ImportBlob{Duplicate: []byte("password")}},
+	}
+	fakeAgent := &fakeAttestationAgent{
+		importSecretFunc: func(blob *tpmpb.ImportBlob) ([]byte, error) {
+			return blob.GetDuplicate(), nil
+		},
+	}
+
+	dir := t.TempDir()
+	if err := writeSecrets(fakeAgent, secrets, dir); err != nil {
+		t.Fatalf("writeSecrets() failed: %v", err)
+	}
+	for _, secret := range secrets {
+		data, err := os.ReadFile(path.Join(dir, secret.Name))
+		if err != nil {
+			t.Fatalf("failed to read secret %q: %v", secret.Name, err)
+		}
+		if !bytes.Equal(data, secret.ImportBlob.GetDuplicate()) {
+			t.Errorf("secret %q has content %q, want %q", secret.Name, data, secret.ImportBlob.GetDuplicate())
+		}
+	}
+}
+
+func TestWriteSecretsImportError(t *testing.T) {
+	secrets := []spec.Secret{{Name: "api-key", ImportBlob: &tpmpb.ImportBlob{}}}
+	fakeAgent := &fakeAttestationAgent{
+		importSecretFunc: func(*tpmpb.ImportBlob) ([]byte, error) {
+			return nil, errors.New("import failed")
+		},
+	}
+
+	dir := t.TempDir()
+	if err := writeSecrets(fakeAgent, secrets, dir); err == nil {
+		t.Fatal("writeSecrets() succeeded, want error")
+	}
+	if _, err := os.Stat(path.Join(dir, "api-key")); !os.IsNotExist(err) {
+		t.Errorf("secret file exists after failed import: %v", err)
+	}
+}
+
+func TestMeasureSecrets(t *testing.T) {
+	digest := sha256.Sum256([]byte("blob"))
+	secrets := []spec.Secret{{Name: "api-key", BlobDigest: digest[:]}}
+	var got []cel.CosTlv
+	fakeAgent := &fakeAttestationAgent{
+		measureEventFunc: func(content cel.Content) error {
+			got = append(got, content.(cel.CosTlv))
+			return nil
+		},
+	}
+
+	if err := measureSecrets(fakeAgent, secrets); err != nil {
+		t.Fatalf("measureSecrets() failed: %v", err)
+	}
+	if len(got) != 1 || got[0].EventType != cel.SecretType {
+		t.Fatalf("got events %v, want one SecretType event", got)
+	}
+	name, blobDigest, err := cel.ParseSecret(got[0].EventContent)
+	if err != nil {
+		t.Fatalf("ParseSecret() failed: %v", err)
+	}
+	if name != "api-key" || !bytes.Equal(blobDigest, digest[:]) {
+		t.Errorf("got secret %q with digest %x, want %q with digest %x", name, blobDigest, "api-key", digest)
+	}
+}
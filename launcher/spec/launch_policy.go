@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/google/go-tpm-tools/launcher/internal/logging"
+	"github.com/google/go-tpm-tools/launcher/launcherfile"
 )
 
 // LaunchPolicy contains policies on starting the container.
@@ -21,6 +22,7 @@ type LaunchPolicy struct {
 	DebugImageMonitoring     MonitoringType
 	PrivilegedCaps           bool
 	AllowCgroups             bool
+	AllowSecrets             bool
 }
 
 type policy int
@@ -113,6 +115,7 @@ const (
 	mountDestinations = "tee.launch_policy.allow_mount_destinations"
 	privilegedCaps    = "tee.launch_policy.allow_capabilities"
 	allowCgroups      = "tee.launch_policy.allow_cgroups"
+	allowSecrets      = "tee.launch_policy.allow_secrets"
 )
 
 func configureMonitoringPolicy(imageLabels map[string]string, launchPolicy *LaunchPolicy, logger logging.Logger) error {
@@ -233,6 +236,12 @@ func GetLaunchPolicy(imageLabels map[string]string, logger logging.Logger) (Laun
 		}
 	}
 
+	if v, ok := imageLabels[allowSecrets]; ok {
+		if launchPolicy.AllowSecrets, err = strconv.ParseBool(v); err != nil {
+			return LaunchPolicy{}, fmt.Errorf("invalid image LABEL '%s' (not a boolean)", allowSecrets)
+		}
+	}
+
 	return launchPolicy, nil
 }
 
@@ -301,6 +310,15 @@ func (p LaunchPolicy) Verify(ls LaunchSpec) error {
 		return errors.New("cgroups usage is not allowed")
 	}
 
+	if len(ls.Secrets) != 0 {
+		if !p.AllowSecrets {
+			return errors.New("secrets are not allowed")
+		}
+		if err := p.verifyMountDestination(launcherfile.ContainerSecretsMountPath); err != nil {
+			return fmt.Errorf("secrets mount point is not allowed: %v", err)
+		}
+	}
+
 	return nil
 }
 
@@ -54,6 +54,15 @@ func TestLaunchPolicy(t *testing.T) {
 				AllowedCmdOverride: false,
 			},
 		},
+		{
+			"secrets allowed",
+			map[string]string{
+				allowSecrets: "true",
+			},
+			LaunchPolicy{
+				AllowSecrets: true,
+			},
+		},
 	}
 
 	for _, testcase := range testCases {
@@ -564,6 +573,38 @@ func TestVerify(t *testing.T) {
 			},
 			true,
 		},
+		{
+			"secrets allowed and used",
+			LaunchPolicy{
+				AllowSecrets:             true,
+				AllowedMountDestinations: []string{"/run"},
+			},
+			LaunchSpec{
+				Secrets: []Secret{{Name: "api-key"}},
+			},
+			false,
+		},
+		{
+			"secrets not allowed but used",
+			LaunchPolicy{
+				AllowedMountDestinations: []string{"/run"},
+			},
+			LaunchSpec{
+				Secrets: []Secret{{Name: "api-key"}},
+			},
+			true,
+		},
+		{
+			"secrets allowed but mount destination not allowed",
+			LaunchPolicy{
+				AllowSecrets:             true,
+				AllowedMountDestinations: []string{"/run/tmp"},
+			},
+			LaunchSpec{
+				Secrets: []Secret{{Name: "api-key"}},
+			},
+			true,
+		},
 	}
 	for _, testCase := range testCases {
 		t.Run(testCase.testName, func(t *testing.T) {
@@ -4,12 +4,15 @@ package spec
 
 import (
 	"context"
+	"crypto/sha256"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"path"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -21,8 +24,10 @@ import (
 	"github.com/google/go-tpm-tools/launcher/internal/launchermount"
 	"github.com/google/go-tpm-tools/launcher/internal/logging"
 	"github.com/google/go-tpm-tools/launcher/launcherfile"
+	tpmpb "github.com/google/go-tpm-tools/proto/tpm"
 	"github.com/google/go-tpm-tools/verifier"
 	"github.com/google/go-tpm-tools/verifier/util"
+	"google.golang.org/protobuf/proto"
 )
 
 // MaxInt64 is the maximum value of a signed int64.
@@ -93,6 +98,7 @@ const (
 	itaKey                     = "ita-api-key"
 	addedCaps                  = "tee-added-capabilities"
 	cgroupNS                   = "tee-cgroup-ns"
+	secretsKey                 = "tee-secrets"
 )
 
 const (
@@ -101,12 +107,27 @@ const (
 
 var errImageRefNotSpecified = fmt.Errorf("%s is not specified in the custom metadata", imageRefKey)
 
+// secretNameRegexp matches secret names, which are used as file names.
+var secretNameRegexp = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
 // EnvVar represent a single environment variable key/value pair.
 type EnvVar struct {
 	Name  string
 	Value string
 }
 
+// Secret is a secret for the workload, encrypted to the TPM's endorsement key
+// with server.CreateImportBlob, which the launcher imports to the TPM and
+// writes to a tmpfs mounted in the container.
+type Secret struct {
+	// Name is the name of the secret's file in the container.
+	Name       string
+	ImportBlob *tpmpb.ImportBlob
+	// BlobDigest is the SHA-256 digest of the serialized ImportBlob, which is
+	// measured before the secret is imported.
+	BlobDigest []byte
+}
+
 // LaunchSpec contains specification set by the operator who wants to
 // launch a container.
 type LaunchSpec struct {
@@ -132,6 +153,7 @@ type LaunchSpec struct {
 	DevShmSize        int64
 	AddedCapabilities []string
 	CgroupNamespace   bool
+	Secrets           []Secret
 }
 
 // UnmarshalJSON unmarshals an instance attributes list in JSON format from the metadata
@@ -290,9 +312,42 @@ func (s *LaunchSpec) UnmarshalJSON(b []byte) error {
 		}
 	}
 
+	// Populate secrets.
+	if val, ok := unmarshaledMap[secretsKey]; ok && val != "" {
+		secrets, err := parseSecrets(val)
+		if err != nil {
+			return fmt.Errorf("invalid value for %v: %v", secretsKey, err)
+		}
+		s.Secrets = secrets
+	}
+
 	return nil
 }
 
+// parseSecrets parses a JSON object mapping secret names to base64 encoded
+// ImportBlob protos, ordered by name.
+func parseSecrets(val string) ([]Secret, error) {
+	var encodedSecrets map[string][]byte
+	if err := json.Unmarshal([]byte(val), &encodedSecrets); err != nil {
+		return nil, err
+	}
+
+	secrets := make([]Secret, 0, len(encodedSecrets))
+	for name, encoded := range encodedSecrets {
+		if !secretNameRegexp.MatchString(name) {
+			return nil, fmt.Errorf("invalid secret name %q, must match %v", name, secretNameRegexp)
+		}
+		blob := &tpmpb.ImportBlob{}
+		if err := proto.Unmarshal(encoded, blob); err != nil {
+			return nil, fmt.Errorf("failed to parse secret %q as an ImportBlob: %v", name, err)
+		}
+		blobDigest := sha256.Sum256(encoded)
+		secrets = append(secrets, Secret{Name: name, ImportBlob: blob, BlobDigest: blobDigest[:]})
+	}
+	sort.Slice(secrets, func(i, j int) bool { return secrets[i].Name < secrets[j].Name })
+	return secrets, nil
+}
+
 // LogFriendly creates a copy of the spec that is safe to log by censoring
 func (s *LaunchSpec) LogFriendly() LaunchSpec {
 	safeSpec := *s
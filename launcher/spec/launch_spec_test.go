@@ -1,13 +1,18 @@
 package spec
 
 import (
+	"crypto/sha256"
+	"encoding/json"
 	"regexp"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-tpm-tools/launcher/internal/experiments"
 	"github.com/google/go-tpm-tools/launcher/internal/launchermount"
+	tpmpb "github.com/google/go-tpm-tools/proto/tpm"
 	"github.com/google/go-tpm-tools/verifier"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/testing/protocmp"
 )
 
 func TestLaunchSpecUnmarshalJSONHappyCases(t *testing.T) {
@@ -299,3 +304,75 @@ func TestLaunchSpecUnmarshalJSONWithBadMounts(t *testing.T) {
 		})
 	}
 }
+
+func TestLaunchSpecUnmarshalJSONWithSecrets(t *testing.T) {
+	apiKey := &tpmpb.ImportBlob{Duplicate: []byte("api key"), EncryptedSeed: []byte("seed")}
+	password := &tpmpb.ImportBlob{Duplicate: []byte("password"), Pcrs: &tpmpb.PCRs{Hash: tpmpb.HashAlgo_SHA256}}
+	encodedSecrets := map[string][]byte{}
+	for name, blob := range map[string]*tpmpb.ImportBlob{"db.password": password, "api-key": apiKey} {
+		encoded, err := proto.Marshal(blob)
+		if err != nil {
+			t.Fatal(err)
+		}
+		encodedSecrets[name] = encoded
+	}
+	secretsVal, err := json.Marshal(encodedSecrets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mdsJSON, err := json.Marshal(map[string]string{
+		"tee-image-reference": "docker.io/library/hello-world:latest",
+		"tee-secrets":         string(secretsVal),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	spec := &LaunchSpec{}
+	if err := spec.UnmarshalJSON(mdsJSON); err != nil {
+		t.Fatal(err)
+	}
+	apiKeyDigest := sha256.Sum256(encodedSecrets["api-key"])
+	passwordDigest := sha256.Sum256(encodedSecrets["db.password"])
+	want := []Secret{
+		{Name: "api-key", ImportBlob: apiKey, BlobDigest: apiKeyDigest[:]},
+		{Name: "db.password", ImportBlob: password, BlobDigest: passwordDigest[:]},
+	}
+	if diff := cmp.Diff(want, spec.Secrets, protocmp.Transform()); diff != "" {
+		t.Errorf("LaunchSpec.Secrets mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestLaunchSpecUnmarshalJSONWithBadSecrets(t *testing.T) {
+	var testCases = []struct {
+		testName   string
+		secretsVal string
+		errMatch   string
+	}{
+		{"Not JSON", `api-key`, "invalid value for tee-secrets"},
+		{"Not Base64", `{"api-key": "!"}`, "invalid value for tee-secrets"},
+		{"Path Name", `{"../api-key": "CgNkdXA="}`, "invalid secret name"},
+		{"Empty Name", `{"": "CgNkdXA="}`, "invalid secret name"},
+		// "/w==" is a single 0xff byte.
+		{"Not ImportBlob", `{"api-key": "/w=="}`, "failed to parse secret"},
+	}
+	for _, testcase := range testCases {
+		t.Run(testcase.testName, func(t *testing.T) {
+			mdsJSON, err := json.Marshal(map[string]string{
+				"tee-image-reference": "docker.io/library/hello-world:latest",
+				"tee-secrets":         testcase.secretsVal,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			spec := &LaunchSpec{}
+			err = spec.UnmarshalJSON(mdsJSON)
+			if err == nil {
+				t.Fatal("expected JSON parsing err")
+			}
+			if match, _ := regexp.MatchString(testcase.errMatch, err.Error()); !match {
+				t.Errorf("got %v error, but expected %v error", err, testcase.errMatch)
+			}
+		})
+	}
+}
@@ -5,10 +5,16 @@ package teeserver
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"mime"
 	"net"
 	"net/http"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/google/go-tpm-tools/cel"
 	"github.com/google/go-tpm-tools/launcher/agent"
 	"github.com/google/go-tpm-tools/launcher/internal/logging"
 	"github.com/google/go-tpm-tools/launcher/spec"
@@ -16,13 +22,44 @@ import (
 	"github.com/google/go-tpm-tools/verifier/models"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
 )
 
 const (
-	gcaEndpoint = "/v1/token"
-	itaEndpoint = "/v1/intel/token"
+	gcaEndpoint      = "/v1/token"
+	itaEndpoint      = "/v1/intel/token"
+	evidenceEndpoint = "/v1/evidence"
+
+	workloadEventEndpoint = "/v1/workload/events"
+	workloadLockEndpoint  = "/v1/workload/lock"
+)
+
+// Limits on the workload's measurements, which are extended into the TPM and
+// kept in the launcher's memory.
+const (
+	maxWorkloadEventNameSize    = 64
+	maxWorkloadEventContentSize = 1024
+	maxWorkloadEvents           = 256
+	minWorkloadEventInterval    = 100 * time.Millisecond
+	// maxWorkloadEventRequestSize bounds the JSON (and base64) encoded request.
+	maxWorkloadEventRequestSize = 4 * maxWorkloadEventContentSize
 )
 
+const (
+	// maxEvidenceNonceSize is the size limit of the TPM quote's qualifying data.
+	maxEvidenceNonceSize = 64
+	contentTypeJSON      = "application/json"
+	contentTypeProto     = "application/x-protobuf"
+)
+
+// EvidenceRequest is the body of a POST request to the evidence endpoint.
+type EvidenceRequest struct {
+	// Nonce is the workload's nonce, base64 encoded in JSON, which the
+	// attestation is bound to. It must be between 1 and 64 bytes.
+	Nonce []byte `json:"nonce"`
+}
+
 var clientErrorCodes = map[codes.Code]struct{}{
 	codes.InvalidArgument:    {},
 	codes.FailedPrecondition: {},
@@ -34,6 +71,26 @@ var clientErrorCodes = map[codes.Code]struct{}{
 	codes.Canceled:           {},
 }
 
+// WorkloadEventRequest is the body of a POST request to the workload event
+// endpoint, measuring an event into the canonical event log.
+type WorkloadEventRequest struct {
+	// Name identifies the event, such as "model" or "config". It must start
+	// with an alpha character, followed by alphanumeric characters, '_', '-' or
+	// '.', and be at most 64 characters.
+	Name string `json:"name"`
+	// Content is the event's content, base64 encoded in JSON, such as the
+	// digest of a loaded model. It must be at most 1024 bytes.
+	Content []byte `json:"content"`
+}
+
+// workloadEvents tracks the workload's measurements to enforce their limits.
+type workloadEvents struct {
+	mu           sync.Mutex
+	count        int
+	lastMeasured time.Time
+	locked       bool
+}
+
 // AttestClients contains clients for supported verifier services that can be used to
 // get attestation tokens.
 type AttestClients struct {
@@ -48,6 +105,9 @@ type attestHandler struct {
 	logger     logging.Logger
 	launchSpec spec.LaunchSpec
 	clients    AttestClients
+
+	workloadEvents workloadEvents
+	tokenCache     tokenCache
 }
 
 // TeeServer is a server that can be called from a container through a unix
@@ -87,9 +147,32 @@ func (a *attestHandler) Handler() http.Handler {
 	// to test custom token:
 	// curl -d '{"audience":"<aud>", "nonces":["<nonce1>"]}' -H "Content-Type: application/json" -X POST
 	//   --unix-socket /tmp/container_launcher/teeserver.sock http://localhost/v1/token
+	// to test raw evidence:
+	// curl -d '{"nonce":"<base64 nonce>"}' -H "Content-Type: application/json" -X POST
+	//   --unix-socket /tmp/container_launcher/teeserver.sock http://localhost/v1/evidence
 
 	mux.HandleFunc(gcaEndpoint, a.getToken)
 	mux.HandleFunc(itaEndpoint, a.getITAToken)
+	// to test workload measurements:
+	// curl -d '{"name":"model", "content":"<base64 content>"}' -H "Content-Type: application/json" -X POST
+	//   --unix-socket /tmp/container_launcher/teeserver.sock http://localhost/v1/workload/events
+	// curl -X POST --unix-socket /tmp/container_launcher/teeserver.sock http://localhost/v1/workload/lock
+
+	mux.HandleFunc(evidenceEndpoint, a.getEvidence)
+	mux.HandleFunc(workloadEventEndpoint, a.measureWorkloadEvent)
+	mux.HandleFunc(workloadLockEndpoint, a.lockWorkloadEvents)
+	// to test the workload key:
+	// curl --unix-socket /tmp/container_launcher/teeserver.sock http://localhost/v1/workload/key
+	// curl -d '{"data":"<base64 data>"}' -H "Content-Type: application/json" -X POST
+	//   --unix-socket /tmp/container_launcher/teeserver.sock http://localhost/v1/workload/sign
+	// curl -d '{"data":"<base64 data>"}' -H "Content-Type: application/json" -X POST
+	//   --unix-socket /tmp/container_launcher/teeserver.sock http://localhost/v1/workload/seal
+	// curl -d '{"sealed":"<base64 sealed>"}' -H "Content-Type: application/json" -X POST
+	//   --unix-socket /tmp/container_launcher/teeserver.sock http://localhost/v1/workload/unseal
+	mux.HandleFunc(workloadKeyEndpoint, a.getWorkloadKey)
+	mux.HandleFunc(workloadSignEndpoint, a.workloadSign)
+	mux.HandleFunc(workloadSealEndpoint, a.workloadSeal)
+	mux.HandleFunc(workloadUnsealEndpoint, a.workloadUnseal)
 	return mux
 }
 
@@ -179,7 +262,15 @@ func (a *attestHandler) attest(w http.ResponseWriter, r *http.Request, client ve
 		opts := agent.AttestAgentOpts{
 			TokenOptions: &tokenOptions,
 		}
-		tok, err := a.attestAgent.AttestWithClient(a.ctx, opts, client)
+		attest := func() ([]byte, error) {
+			return a.attestAgent.AttestWithClient(a.ctx, opts, client)
+		}
+		var tok []byte
+		if key, ok := tokenCacheKey(r.URL.Path, tokenOptions); ok {
+			tok, err = a.tokenCache.get(key, attest)
+		} else {
+			tok, err = attest()
+		}
 		if err != nil {
 			a.handleAttestError(w, err, "failed to retrieve custom attestation service token")
 			return
@@ -195,6 +286,157 @@ func (a *attestHandler) attest(w http.ResponseWriter, r *http.Request, client ve
 	}
 }
 
+// getEvidence returns the raw attestation bound to the workload's nonce, for
+// workloads verifying it themselves (for example, with a peer). The attestation
+// is returned in the JSON encoding of pb.Attestation, or in its binary encoding
+// if the request accepts application/x-protobuf.
+func (a *attestHandler) getEvidence(w http.ResponseWriter, r *http.Request) {
+	a.logger.Info(fmt.Sprintf("%s called", evidenceEndpoint))
+
+	if r.Method != http.MethodPost {
+		err := fmt.Errorf("TEE server received an invalid HTTP method: %s", r.Method)
+		a.logAndWriteHTTPError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var evidenceRequest EvidenceRequest
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&evidenceRequest); err != nil {
+		err = fmt.Errorf("failed to parse POST body as EvidenceRequest: %v", err)
+		a.logAndWriteHTTPError(w, http.StatusBadRequest, err)
+		return
+	}
+	if len(evidenceRequest.Nonce) == 0 || len(evidenceRequest.Nonce) > maxEvidenceNonceSize {
+		err := fmt.Errorf("nonce must be between 1 and %d bytes, got %d bytes", maxEvidenceNonceSize, len(evidenceRequest.Nonce))
+		a.logAndWriteHTTPError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	attestation, err := a.attestAgent.AttestEvidence(evidenceRequest.Nonce)
+	if err != nil {
+		a.logAndWriteHTTPError(w, http.StatusInternalServerError, fmt.Errorf("failed to retrieve attestation evidence: %w", err))
+		return
+	}
+
+	contentType, body, err := marshalProto(attestation, r.Header.Get("Accept"))
+	if err != nil {
+		a.logAndWriteHTTPError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
+}
+
+// marshalProto encodes the message in the format accepted by the client,
+// returning the encoding's content type.
+func marshalProto(m proto.Message, accept string) (string, []byte, error) {
+	for _, mediaRange := range strings.Split(accept, ",") {
+		mediaType, _, err := mime.ParseMediaType(mediaRange)
+		if err == nil && mediaType == contentTypeProto {
+			body, err := proto.Marshal(m)
+			if err != nil {
+				return "", nil, fmt.Errorf("failed to marshal %s: %v", m.ProtoReflect().Descriptor().Name(), err)
+			}
+			return contentTypeProto, body, nil
+		}
+	}
+	body, err := protojson.Marshal(m)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to marshal %s: %v", m.ProtoReflect().Descriptor().Name(), err)
+	}
+	return contentTypeJSON, body, nil
+}
+
+// measureWorkloadEvent measures the workload's event into the canonical event
+// log, so that it is part of later attestations.
+func (a *attestHandler) measureWorkloadEvent(w http.ResponseWriter, r *http.Request) {
+	a.logger.Info(fmt.Sprintf("%s called", workloadEventEndpoint))
+
+	if r.Method != http.MethodPost {
+		err := fmt.Errorf("TEE server received an invalid HTTP method: %s", r.Method)
+		a.logAndWriteHTTPError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var eventRequest WorkloadEventRequest
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxWorkloadEventRequestSize))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&eventRequest); err != nil {
+		err = fmt.Errorf("failed to parse POST body as WorkloadEventRequest: %v", err)
+		a.logAndWriteHTTPError(w, http.StatusBadRequest, err)
+		return
+	}
+	if len(eventRequest.Name) > maxWorkloadEventNameSize {
+		err := fmt.Errorf("workload event name must be at most %d bytes, got %d bytes", maxWorkloadEventNameSize, len(eventRequest.Name))
+		a.logAndWriteHTTPError(w, http.StatusBadRequest, err)
+		return
+	}
+	if len(eventRequest.Content) > maxWorkloadEventContentSize {
+		err := fmt.Errorf("workload event content must be at most %d bytes, got %d bytes", maxWorkloadEventContentSize, len(eventRequest.Content))
+		a.logAndWriteHTTPError(w, http.StatusBadRequest, err)
+		return
+	}
+	content, err := cel.FormatWorkloadEvent(eventRequest.Name, eventRequest.Content)
+	if err != nil {
+		a.logAndWriteHTTPError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	events := &a.workloadEvents
+	events.mu.Lock()
+	defer events.mu.Unlock()
+	if events.locked {
+		a.logAndWriteHTTPError(w, http.StatusConflict, errors.New("workload events are locked"))
+		return
+	}
+	if events.count >= maxWorkloadEvents {
+		err := fmt.Errorf("workload measured the maximum of %d events", maxWorkloadEvents)
+		a.logAndWriteHTTPError(w, http.StatusTooManyRequests, err)
+		return
+	}
+	if since := time.Since(events.lastMeasured); since < minWorkloadEventInterval {
+		err := fmt.Errorf("workload events must be at least %v apart, got %v", minWorkloadEventInterval, since)
+		a.logAndWriteHTTPError(w, http.StatusTooManyRequests, err)
+		return
+	}
+
+	if err := a.attestAgent.MeasureEvent(cel.CosTlv{EventType: cel.WorkloadEventType, EventContent: content}); err != nil {
+		a.logAndWriteHTTPError(w, http.StatusInternalServerError, fmt.Errorf("failed to measure workload event: %w", err))
+		return
+	}
+	a.tokenCache.clear()
+	events.count++
+	events.lastMeasured = time.Now()
+	w.WriteHeader(http.StatusOK)
+}
+
+// lockWorkloadEvents measures a separator after the workload's events, so that
+// no more can be measured. Locking the events again has no effect.
+func (a *attestHandler) lockWorkloadEvents(w http.ResponseWriter, r *http.Request) {
+	a.logger.Info(fmt.Sprintf("%s called", workloadLockEndpoint))
+
+	if r.Method != http.MethodPost {
+		err := fmt.Errorf("TEE server received an invalid HTTP method: %s", r.Method)
+		a.logAndWriteHTTPError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	events := &a.workloadEvents
+	events.mu.Lock()
+	defer events.mu.Unlock()
+	if !events.locked {
+		if err := a.attestAgent.MeasureEvent(cel.CosTlv{EventType: cel.WorkloadSeparatorType}); err != nil {
+			a.logAndWriteHTTPError(w, http.StatusInternalServerError, fmt.Errorf("failed to lock workload events: %w", err))
+			return
+		}
+		a.tokenCache.clear()
+		events.locked = true
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
 func (a *attestHandler) logAndWriteHTTPError(w http.ResponseWriter, statusCode int, err error) {
 	a.logger.Error(err.Error())
 	w.WriteHeader(statusCode)
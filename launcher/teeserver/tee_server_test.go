@@ -9,15 +9,21 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-tpm-tools/cel"
 	"github.com/google/go-tpm-tools/launcher/agent"
 	"github.com/google/go-tpm-tools/launcher/internal/logging"
+	attestpb "github.com/google/go-tpm-tools/proto/attest"
+	tpmpb "github.com/google/go-tpm-tools/proto/tpm"
 	"github.com/google/go-tpm-tools/verifier"
 	"github.com/google/go-tpm-tools/verifier/models"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/testing/protocmp"
 )
 
 // Implements verifier.Client interface so it can be used to initialize test attestHandlers
@@ -39,6 +45,11 @@ type fakeAttestationAgent struct {
 	measureEventFunc     func(cel.Content) error
 	attestFunc           func(context.Context, agent.AttestAgentOpts) ([]byte, error)
 	attestWithClientFunc func(context.Context, agent.AttestAgentOpts, verifier.Client) ([]byte, error)
+	attestEvidenceFunc   func([]byte) (*attestpb.Attestation, error)
+	workloadKeyFunc      func() (*tpmpb.CertifiedBlob, error)
+	workloadSignFunc     func([]byte) ([]byte, error)
+	workloadSealFunc     func([]byte) (*tpmpb.SealedBytes, error)
+	workloadUnsealFunc   func(*tpmpb.SealedBytes) ([]byte, error)
 }
 
 func (f fakeAttestationAgent) Attest(c context.Context, a agent.AttestAgentOpts) ([]byte, error) {
@@ -49,6 +60,30 @@ func (f fakeAttestationAgent) AttestWithClient(c context.Context, a agent.Attest
 	return f.attestWithClientFunc(c, a, v)
 }
 
+func (f fakeAttestationAgent) AttestEvidence(nonce []byte) (*attestpb.Attestation, error) {
+	return f.attestEvidenceFunc(nonce)
+}
+
+func (f fakeAttestationAgent) WorkloadKey() (*tpmpb.CertifiedBlob, error) {
+	return f.workloadKeyFunc()
+}
+
+func (f fakeAttestationAgent) WorkloadSign(data []byte) ([]byte, error) {
+	return f.workloadSignFunc(data)
+}
+
+func (f fakeAttestationAgent) WorkloadSeal(data []byte) (*tpmpb.SealedBytes, error) {
+	return f.workloadSealFunc(data)
+}
+
+func (f fakeAttestationAgent) WorkloadUnseal(sealed *tpmpb.SealedBytes) ([]byte, error) {
+	return f.workloadUnsealFunc(sealed)
+}
+
+func (f fakeAttestationAgent) ImportSecret(_ *tpmpb.ImportBlob) ([]byte, error) {
+	return nil, fmt.Errorf("unimplemented")
+}
+
 func (f fakeAttestationAgent) MeasureEvent(c cel.Content) error {
 	return f.measureEventFunc(c)
 }
@@ -507,3 +542,194 @@ func TestCustomTokenDataParsedSuccessfully(t *testing.T) {
 		}
 	}
 }
+
+func TestGetEvidence(t *testing.T) {
+	nonce := []byte("workload nonce")
+	wantAttestation := &attestpb.Attestation{
+		AkPub:             []byte("ak pub"),
+		CanonicalEventLog: []byte("cel"),
+	}
+	ah := attestHandler{
+		logger: logging.SimpleLogger(),
+		attestAgent: fakeAttestationAgent{
+			attestEvidenceFunc: func(gotNonce []byte) (*attestpb.Attestation, error) {
+				if diff := cmp.Diff(nonce, gotNonce); diff != "" {
+					t.Errorf("AttestEvidence() got unexpected nonce (-want +got):\n%s", diff)
+				}
+				return wantAttestation, nil
+			},
+		}}
+
+	tests := []struct {
+		testName        string
+		accept          string
+		wantContentType string
+		unmarshal       func([]byte, proto.Message) error
+	}{
+		{"JSONByDefault", "", "application/json", protojson.Unmarshal},
+		{"JSON", "application/json", "application/json", protojson.Unmarshal},
+		{"Proto", "application/json;q=0.5, application/x-protobuf", "application/x-protobuf", proto.Unmarshal},
+	}
+	for _, tc := range tests {
+		t.Run(tc.testName, func(t *testing.T) {
+			body := `{"nonce": "d29ya2xvYWQgbm9uY2U="}`
+			req := httptest.NewRequest(http.MethodPost, "/v1/evidence", strings.NewReader(body))
+			if tc.accept != "" {
+				req.Header.Set("Accept", tc.accept)
+			}
+			w := httptest.NewRecorder()
+			ah.getEvidence(w, req)
+
+			data, err := io.ReadAll(w.Result().Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if w.Code != http.StatusOK {
+				t.Fatalf("got return code: %d, want: %d (%s)", w.Code, http.StatusOK, data)
+			}
+			if got := w.Result().Header.Get("Content-Type"); got != tc.wantContentType {
+				t.Errorf("got Content-Type: %q, want: %q", got, tc.wantContentType)
+			}
+			gotAttestation := &attestpb.Attestation{}
+			if err := tc.unmarshal(data, gotAttestation); err != nil {
+				t.Fatalf("failed to unmarshal response: %v", err)
+			}
+			if diff := cmp.Diff(wantAttestation, gotAttestation, protocmp.Transform()); diff != "" {
+				t.Errorf("getEvidence() response body mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestGetEvidenceError(t *testing.T) {
+	tests := []struct {
+		testName string
+		method   string
+		body     string
+		agentErr error
+		wantCode int
+	}{
+		{"GetNotAllowed", http.MethodGet, "", nil, http.StatusBadRequest},
+		{"InvalidJSON", http.MethodPost, `{"nonces": "bm9uY2U="}`, nil, http.StatusBadRequest},
+		{"MissingNonce", http.MethodPost, `{}`, nil, http.StatusBadRequest},
+		{"NonceTooLong", http.MethodPost, fmt.Sprintf(`{"nonce": "%s"}`, strings.Repeat("A", 88)), nil, http.StatusBadRequest},
+		{"AgentError", http.MethodPost, `{"nonce": "bm9uY2U="}`, errors.New("tpm error"), http.StatusInternalServerError},
+	}
+	for _, tc := range tests {
+		t.Run(tc.testName, func(t *testing.T) {
+			ah := attestHandler{
+				logger: logging.SimpleLogger(),
+				attestAgent: fakeAttestationAgent{
+					attestEvidenceFunc: func([]byte) (*attestpb.Attestation, error) {
+						if tc.agentErr == nil {
+							t.Error("AttestEvidence() should not be called")
+						}
+						return nil, tc.agentErr
+					},
+				}}
+
+			req := httptest.NewRequest(tc.method, "/v1/evidence", strings.NewReader(tc.body))
+			w := httptest.NewRecorder()
+			ah.getEvidence(w, req)
+
+			if w.Code != tc.wantCode {
+				t.Errorf("got return code: %d, want: %d", w.Code, tc.wantCode)
+			}
+		})
+	}
+}
+
+func TestMeasureWorkloadEvent(t *testing.T) {
+	var measured []cel.CosTlv
+	ah := attestHandler{
+		logger: logging.SimpleLogger(),
+		attestAgent: fakeAttestationAgent{
+			measureEventFunc: func(c cel.Content) error {
+				measured = append(measured, c.(cel.CosTlv))
+				return nil
+			},
+		}}
+
+	post := func(handler func(http.ResponseWriter, *http.Request), url, body string) int {
+		req := httptest.NewRequest(http.MethodPost, url, strings.NewReader(body))
+		w := httptest.NewRecorder()
+		handler(w, req)
+		return w.Code
+	}
+
+	// "c2hhMjU2OmFiY2Q=" is "sha256:abcd".
+	if code := post(ah.measureWorkloadEvent, "/v1/workload/events", `{"name": "model", "content": "c2hhMjU2OmFiY2Q="}`); code != http.StatusOK {
+		t.Fatalf("got return code: %d, want: %d", code, http.StatusOK)
+	}
+	// Measuring again immediately exceeds the rate limit.
+	if code := post(ah.measureWorkloadEvent, "/v1/workload/events", `{"name": "config"}`); code != http.StatusTooManyRequests {
+		t.Errorf("got return code: %d, want: %d", code, http.StatusTooManyRequests)
+	}
+	ah.workloadEvents.lastMeasured = time.Time{}
+	if code := post(ah.measureWorkloadEvent, "/v1/workload/events", `{"name": "config"}`); code != http.StatusOK {
+		t.Fatalf("got return code: %d, want: %d", code, http.StatusOK)
+	}
+
+	// Locking twice only measures one separator.
+	for i := 0; i < 2; i++ {
+		if code := post(ah.lockWorkloadEvents, "/v1/workload/lock", ""); code != http.StatusOK {
+			t.Fatalf("got return code: %d, want: %d", code, http.StatusOK)
+		}
+	}
+	ah.workloadEvents.lastMeasured = time.Time{}
+	if code := post(ah.measureWorkloadEvent, "/v1/workload/events", `{"name": "model"}`); code != http.StatusConflict {
+		t.Errorf("got return code: %d, want: %d", code, http.StatusConflict)
+	}
+
+	want := []cel.CosTlv{
+		{EventType: cel.WorkloadEventType, EventContent: []byte("model=sha256:abcd")},
+		{EventType: cel.WorkloadEventType, EventContent: []byte("config=")},
+		{EventType: cel.WorkloadSeparatorType},
+	}
+	if diff := cmp.Diff(want, measured); diff != "" {
+		t.Errorf("measured events mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestMeasureWorkloadEventError(t *testing.T) {
+	tests := []struct {
+		testName   string
+		method     string
+		body       string
+		count      int
+		measureErr error
+		wantCode   int
+	}{
+		{"GetNotAllowed", http.MethodGet, "", 0, nil, http.StatusBadRequest},
+		{"InvalidJSON", http.MethodPost, `{"name": "model", "digest": ""}`, 0, nil, http.StatusBadRequest},
+		{"BadName", http.MethodPost, `{"name": "my model"}`, 0, nil, http.StatusBadRequest},
+		{"NameTooLong", http.MethodPost, fmt.Sprintf(`{"name": "%s"}`, strings.Repeat("a", 65)), 0, nil, http.StatusBadRequest},
+		{"ContentTooLarge", http.MethodPost, fmt.Sprintf(`{"name": "model", "content": "%s"}`, strings.Repeat("A", 1368)), 0, nil, http.StatusBadRequest},
+		{"RequestTooLarge", http.MethodPost, fmt.Sprintf(`{"name": "model", "content": "%s"}`, strings.Repeat("A", 4096)), 0, nil, http.StatusBadRequest},
+		{"TooManyEvents", http.MethodPost, `{"name": "model"}`, 256, nil, http.StatusTooManyRequests},
+		{"MeasureError", http.MethodPost, `{"name": "model"}`, 0, errors.New("tpm error"), http.StatusInternalServerError},
+	}
+	for _, tc := range tests {
+		t.Run(tc.testName, func(t *testing.T) {
+			ah := attestHandler{
+				logger: logging.SimpleLogger(),
+				attestAgent: fakeAttestationAgent{
+					measureEventFunc: func(cel.Content) error {
+						if tc.measureErr == nil {
+							t.Error("MeasureEvent() should not be called")
+						}
+						return tc.measureErr
+					},
+				}}
+			ah.workloadEvents.count = tc.count
+
+			req := httptest.NewRequest(tc.method, "/v1/workload/events", strings.NewReader(tc.body))
+			w := httptest.NewRecorder()
+			ah.measureWorkloadEvent(w, req)
+
+			if w.Code != tc.wantCode {
+				t.Errorf("got return code: %d, want: %d", w.Code, tc.wantCode)
+			}
+		})
+	}
+}
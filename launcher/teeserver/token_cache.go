@@ -0,0 +1,152 @@
+package teeserver
+
+import (
+	"encoding/json"
+	"math/rand"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/go-tpm-tools/launcher/internal/tokenrefresh"
+	"github.com/google/go-tpm-tools/verifier/models"
+	"golang.org/x/sync/singleflight"
+)
+
+// maxCachedTokens bounds the number of tokens cached for different options.
+const maxCachedTokens = 64
+
+// tokenCache caches the tokens requested by the workload until they are due
+// for refresh, and deduplicates concurrent requests for the same token, so
+// that bursty workloads don't make an attestation for each request. The zero
+// value is an empty cache.
+type tokenCache struct {
+	mu     sync.Mutex
+	tokens map[string]cachedToken
+	// generation is incremented when the cache is cleared, so that tokens
+	// requested before then are neither cached nor shared with later requests.
+	generation uint64
+	group      singleflight.Group
+	// now is used instead of time.Now if set.
+	now func() time.Time
+}
+
+type cachedToken struct {
+	token     []byte
+	refreshAt time.Time
+}
+
+// tokenCacheKey returns the cache key of the token options requested from the
+// endpoint. Options with nonces are not cached, as each request expects a new
+// token bound to its nonces.
+func tokenCacheKey(endpoint string, opts models.TokenOptions) (string, bool) {
+	if len(opts.Nonces) != 0 {
+		return "", false
+	}
+	opts.Nonces = nil
+	// The order of the allowed key IDs doesn't change the token.
+	if tags := opts.PrincipalTagOptions; tags != nil && tags.AllowedPrincipalTags != nil && tags.AllowedPrincipalTags.ContainerImageSignatures != nil {
+		keyIDs := append([]string(nil), tags.AllowedPrincipalTags.ContainerImageSignatures.KeyIDs...)
+		sort.Strings(keyIDs)
+		opts.PrincipalTagOptions = &models.AWSPrincipalTagsOptions{
+			AllowedPrincipalTags: &models.AllowedPrincipalTags{
+				ContainerImageSignatures: &models.ContainerImageSignatures{KeyIDs: keyIDs},
+			},
+		}
+	}
+	encoded, err := json.Marshal(opts)
+	if err != nil {
+		return "", false
+	}
+	return endpoint + "\x00" + string(encoded), true
+}
+
+// get returns the cached token for the key, or calls attest to get a new token
+// if none is cached or the cached token is due for refresh. Concurrent calls
+// for the same key share a single call to attest.
+func (c *tokenCache) get(key string, attest func() ([]byte, error)) ([]byte, error) {
+	c.mu.Lock()
+	generation := c.generation
+	c.mu.Unlock()
+	if token, ok := c.lookup(key); ok {
+		return token, nil
+	}
+	token, err, _ := c.group.Do(strconv.FormatUint(generation, 10)+"\x00"+key, func() (any, error) {
+		// The token may have been cached since the lookup.
+		if token, ok := c.lookup(key); ok {
+			return token, nil
+		}
+		token, err := attest()
+		if err != nil {
+			return nil, err
+		}
+		c.store(key, token, generation)
+		return token, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return token.([]byte), nil
+}
+
+func (c *tokenCache) lookup(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cached, ok := c.tokens[key]
+	if !ok || !c.timeNow().Before(cached.refreshAt) {
+		return nil, false
+	}
+	return cached.token, true
+}
+
+// store caches the token until it is due for refresh. Tokens without a valid
+// expiration, or requested before the cache was last cleared, are not cached.
+func (c *tokenCache) store(key string, token []byte, generation uint64) {
+	claims := &jwt.RegisteredClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(string(token), claims); err != nil || claims.ExpiresAt == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if generation != c.generation {
+		return
+	}
+	now := c.timeNow()
+	expiration := claims.ExpiresAt.Time.Sub(now)
+	if expiration <= 0 {
+		return
+	}
+	for k, cached := range c.tokens {
+		if !now.Before(cached.refreshAt) {
+			delete(c.tokens, k)
+		}
+	}
+	if len(c.tokens) >= maxCachedTokens {
+		return
+	}
+	if c.tokens == nil {
+		c.tokens = make(map[string]cachedToken)
+	}
+	c.tokens[key] = cachedToken{
+		token:     token,
+		refreshAt: now.Add(tokenrefresh.NextRefreshFromExpiration(expiration, rand.Float64())),
+	}
+}
+
+// clear drops the cached tokens. It is called when the workload measures
+// events, as the cached tokens no longer reflect its canonical event log.
+func (c *tokenCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.tokens = nil
+	c.generation++
+}
+
+func (c *tokenCache) timeNow() time.Time {
+	if c.now != nil {
+		return c.now()
+	}
+	return time.Now()
+}
@@ -0,0 +1,262 @@
+package teeserver
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/go-tpm-tools/cel"
+	"github.com/google/go-tpm-tools/launcher/agent"
+	"github.com/google/go-tpm-tools/launcher/internal/logging"
+	"github.com/google/go-tpm-tools/verifier"
+	"github.com/google/go-tpm-tools/verifier/models"
+)
+
+func newTestToken(t *testing.T, audience string, expiresAt time.Time) []byte {
+	t.Helper()
+	claims := jwt.RegisteredClaims{
+		Audience:  jwt.ClaimStrings{audience},
+		ExpiresAt: jwt.NewNumericDate(expiresAt),
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("test key"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return []byte(token)
+}
+
+func TestTokenCacheKey(t *testing.T) {
+	withKeyIDs := func(keyIDs ...string) models.TokenOptions {
+		return models.TokenOptions{
+			Audience:  "audience",
+			TokenType: "AWS_PRINCIPALTAGS",
+			PrincipalTagOptions: &models.AWSPrincipalTagsOptions{
+				AllowedPrincipalTags: &models.AllowedPrincipalTags{
+					ContainerImageSignatures: &models.ContainerImageSignatures{KeyIDs: keyIDs},
+				},
+			},
+		}
+	}
+	opts := models.TokenOptions{Audience: "audience", TokenType: "OIDC"}
+
+	if _, ok := tokenCacheKey(gcaEndpoint, models.TokenOptions{Audience: "audience", TokenType: "OIDC", Nonces: []string{"nonce"}}); ok {
+		t.Error("tokenCacheKey() returned a key for options with nonces")
+	}
+
+	key, ok := tokenCacheKey(gcaEndpoint, opts)
+	if !ok {
+		t.Fatal("tokenCacheKey() returned no key")
+	}
+	if emptyNonces, _ := tokenCacheKey(gcaEndpoint, models.TokenOptions{Audience: "audience", TokenType: "OIDC", Nonces: []string{}}); emptyNonces != key {
+		t.Error("tokenCacheKey() returned different keys for nil and empty nonces")
+	}
+	if itaKey, _ := tokenCacheKey(itaEndpoint, opts); itaKey == key {
+		t.Error("tokenCacheKey() returned the same key for different endpoints")
+	}
+	if otherAudience, _ := tokenCacheKey(gcaEndpoint, models.TokenOptions{Audience: "other", TokenType: "OIDC"}); otherAudience == key {
+		t.Error("tokenCacheKey() returned the same key for different audiences")
+	}
+
+	keyIDs := withKeyIDs("key2", "key1")
+	sortedKey, _ := tokenCacheKey(gcaEndpoint, withKeyIDs("key1", "key2"))
+	if unsortedKey, _ := tokenCacheKey(gcaEndpoint, keyIDs); unsortedKey != sortedKey {
+		t.Error("tokenCacheKey() returned different keys for the same key IDs")
+	}
+	if got := keyIDs.PrincipalTagOptions.AllowedPrincipalTags.ContainerImageSignatures.KeyIDs; got[0] != "key2" {
+		t.Errorf("tokenCacheKey() modified the options' key IDs: %v", got)
+	}
+}
+
+func TestCustomTokenCached(t *testing.T) {
+	now := time.Now()
+	var calls int
+	ah := attestHandler{
+		logger: logging.SimpleLogger(),
+		clients: AttestClients{
+			GCA: &fakeVerifierClient{},
+			ITA: &fakeVerifierClient{},
+		},
+		attestAgent: fakeAttestationAgent{
+			attestWithClientFunc: func(_ context.Context, opts agent.AttestAgentOpts, _ verifier.Client) ([]byte, error) {
+				calls++
+				return newTestToken(t, opts.TokenOptions.Audience, now.Add(time.Hour)), nil
+			},
+		},
+		tokenCache: tokenCache{now: func() time.Time { return now }},
+	}
+
+	post := func(body string) string {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodPost, gcaEndpoint, strings.NewReader(body))
+		w := httptest.NewRecorder()
+		ah.getToken(w, req)
+
+		data, err := io.ReadAll(w.Result().Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if w.Code != http.StatusOK {
+			t.Fatalf("got return code: %d, want: %d (%s)", w.Code, http.StatusOK, data)
+		}
+		return string(data)
+	}
+
+	first := post(`{"audience": "audience", "token_type": "OIDC"}`)
+	if second := post(`{"audience": "audience", "token_type": "OIDC"}`); second != first || calls != 1 {
+		t.Errorf("second request made %d attestations, want 1 (same token: %v)", calls, second == first)
+	}
+
+	post(`{"audience": "other", "token_type": "OIDC"}`)
+	if calls != 2 {
+		t.Errorf("request for another audience made %d attestations, want 2", calls)
+	}
+
+	for i := 0; i < 2; i++ {
+		post(`{"audience": "audience", "nonces": ["thisIsAcustomNonce"], "token_type": "OIDC"}`)
+	}
+	if calls != 4 {
+		t.Errorf("requests with nonces made %d attestations, want 4", calls)
+	}
+
+	// After 90% of the token's lifetime, the token is due for refresh.
+	now = now.Add(54 * time.Minute)
+	post(`{"audience": "audience", "token_type": "OIDC"}`)
+	if calls != 5 {
+		t.Errorf("request after the refresh time made %d attestations, want 5", calls)
+	}
+}
+
+func TestTokenCacheClearedByWorkloadEvents(t *testing.T) {
+	var calls int
+	ah := attestHandler{
+		logger: logging.SimpleLogger(),
+		clients: AttestClients{
+			GCA: &fakeVerifierClient{},
+			ITA: &fakeVerifierClient{},
+		},
+		attestAgent: fakeAttestationAgent{
+			attestWithClientFunc: func(_ context.Context, opts agent.AttestAgentOpts, _ verifier.Client) ([]byte, error) {
+				calls++
+				return newTestToken(t, opts.TokenOptions.Audience, time.Now().Add(time.Hour)), nil
+			},
+			measureEventFunc: func(cel.Content) error { return nil },
+		},
+	}
+
+	post := func(handler func(http.ResponseWriter, *http.Request), url, body string) {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodPost, url, strings.NewReader(body))
+		w := httptest.NewRecorder()
+		handler(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("got return code: %d, want: %d", w.Code, http.StatusOK)
+		}
+	}
+	getToken := func() {
+		t.Helper()
+		post(ah.getToken, gcaEndpoint, `{"audience": "audience", "token_type": "OIDC"}`)
+	}
+
+	getToken()
+	getToken()
+	if calls != 1 {
+		t.Fatalf("repeated requests made %d attestations, want 1", calls)
+	}
+	post(ah.measureWorkloadEvent, "/v1/workload/events", `{"name": "model"}`)
+	getToken()
+	if calls != 2 {
+		t.Errorf("request after measuring a workload event made %d attestations, want 2", calls)
+	}
+	post(ah.lockWorkloadEvents, "/v1/workload/lock", "")
+	getToken()
+	if calls != 3 {
+		t.Errorf("request after locking workload events made %d attestations, want 3", calls)
+	}
+}
+
+func TestTokenCacheClearedDuringAttestation(t *testing.T) {
+	var cache tokenCache
+	var calls int
+	token := newTestToken(t, "audience", time.Now().Add(time.Hour))
+	for i := 0; i < 2; i++ {
+		cache.get("key", func() ([]byte, error) {
+			calls++
+			// Events measured during the attestation may not be in the token.
+			cache.clear()
+			return token, nil
+		})
+	}
+	if calls != 2 {
+		t.Errorf("got %d attestations, want 2", calls)
+	}
+}
+
+func TestTokenCacheNotCached(t *testing.T) {
+	tests := []struct {
+		testName string
+		token    []byte
+		err      error
+	}{
+		{"Error", nil, errors.New("attestation failed")},
+		{"NotJWT", []byte("token"), nil},
+		{"Expired", newTestToken(t, "audience", time.Now().Add(-time.Minute)), nil},
+	}
+	for _, tc := range tests {
+		t.Run(tc.testName, func(t *testing.T) {
+			var cache tokenCache
+			var calls int
+			for i := 0; i < 2; i++ {
+				cache.get("key", func() ([]byte, error) {
+					calls++
+					return tc.token, tc.err
+				})
+			}
+			if calls != 2 {
+				t.Errorf("got %d attestations, want 2", calls)
+			}
+		})
+	}
+}
+
+func TestTokenCacheConcurrentRequests(t *testing.T) {
+	var cache tokenCache
+	var calls atomic.Int32
+	release := make(chan struct{})
+	token := newTestToken(t, "audience", time.Now().Add(time.Hour))
+
+	const requests = 10
+	var started, done sync.WaitGroup
+	started.Add(requests)
+	done.Add(requests)
+	for i := 0; i < requests; i++ {
+		go func() {
+			defer done.Done()
+			started.Done()
+			got, err := cache.get("key", func() ([]byte, error) {
+				calls.Add(1)
+				<-release
+				return token, nil
+			})
+			if err != nil || string(got) != string(token) {
+				t.Errorf("get() = %q, %v, want %q", got, err, token)
+			}
+		}()
+	}
+	started.Wait()
+	// Give the requests time to join the in-flight attestation.
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	done.Wait()
+
+	if got := calls.Load(); got != 1 {
+		t.Errorf("got %d attestations for concurrent requests, want 1", got)
+	}
+}
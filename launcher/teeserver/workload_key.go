@@ -0,0 +1,185 @@
+package teeserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	tpmpb "github.com/google/go-tpm-tools/proto/tpm"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	workloadKeyEndpoint    = "/v1/workload/key"
+	workloadSignEndpoint   = "/v1/workload/sign"
+	workloadSealEndpoint   = "/v1/workload/seal"
+	workloadUnsealEndpoint = "/v1/workload/unseal"
+)
+
+const (
+	maxWorkloadSignDataSize = 64 * 1024
+	// maxWorkloadSealDataSize is the TPM's limit on sealed data (MAX_SYM_DATA).
+	maxWorkloadSealDataSize = 128
+	maxWorkloadSealedSize   = 4096
+)
+
+// WorkloadData is the body of a POST request to the workload sign and seal
+// endpoints, and of the response of the workload unseal endpoint.
+type WorkloadData struct {
+	// Data is base64 encoded in JSON. Signed data must be at most 64 KiB, and
+	// sealed data at most 128 bytes.
+	Data []byte `json:"data"`
+}
+
+// WorkloadSealed is the body of the response of the workload seal endpoint,
+// and of a POST request to the workload unseal endpoint.
+type WorkloadSealed struct {
+	// Sealed is the opaque sealed data, base64 encoded in JSON.
+	Sealed []byte `json:"sealed"`
+}
+
+// WorkloadSignature is the body of the response of the workload sign endpoint.
+type WorkloadSignature struct {
+	// Signature is the ASN.1 encoded ECDSA signature of the SHA-256 digest of
+	// the data, base64 encoded in JSON.
+	Signature []byte `json:"signature"`
+}
+
+// getWorkloadKey returns the certification of the workload key by the AK,
+// containing the key's public area. A peer can verify it against the AK from
+// an attestation, to trust the workload's signatures. The certification is
+// returned in the JSON encoding of tpmpb.CertifiedBlob, or in its binary
+// encoding if the request accepts application/x-protobuf.
+func (a *attestHandler) getWorkloadKey(w http.ResponseWriter, r *http.Request) {
+	a.logger.Info(fmt.Sprintf("%s called", workloadKeyEndpoint))
+
+	if r.Method != http.MethodGet {
+		err := fmt.Errorf("TEE server received an invalid HTTP method: %s", r.Method)
+		a.logAndWriteHTTPError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	certification, err := a.attestAgent.WorkloadKey()
+	if err != nil {
+		a.logAndWriteHTTPError(w, http.StatusInternalServerError, fmt.Errorf("failed to get workload key: %w", err))
+		return
+	}
+	contentType, body, err := marshalProto(certification, r.Header.Get("Accept"))
+	if err != nil {
+		a.logAndWriteHTTPError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
+}
+
+// workloadSign signs the workload's data with the workload key.
+func (a *attestHandler) workloadSign(w http.ResponseWriter, r *http.Request) {
+	a.logger.Info(fmt.Sprintf("%s called", workloadSignEndpoint))
+
+	var dataRequest WorkloadData
+	// Allow for the base64 encoding and the JSON around the data.
+	if !a.decodePOSTBody(w, r, &dataRequest, 2*maxWorkloadSignDataSize) {
+		return
+	}
+	if len(dataRequest.Data) > maxWorkloadSignDataSize {
+		err := fmt.Errorf("signed data must be at most %d bytes, got %d bytes", maxWorkloadSignDataSize, len(dataRequest.Data))
+		a.logAndWriteHTTPError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	signature, err := a.attestAgent.WorkloadSign(dataRequest.Data)
+	if err != nil {
+		a.logAndWriteHTTPError(w, http.StatusInternalServerError, fmt.Errorf("failed to sign workload data: %w", err))
+		return
+	}
+	a.writeJSON(w, WorkloadSignature{Signature: signature})
+}
+
+// workloadSeal seals the workload's data to the current boot and canonical
+// event log PCRs. Any later workload event changes the PCR, and the data can no
+// longer be unsealed, so the workload should seal data after measuring its
+// events.
+func (a *attestHandler) workloadSeal(w http.ResponseWriter, r *http.Request) {
+	a.logger.Info(fmt.Sprintf("%s called", workloadSealEndpoint))
+
+	var dataRequest WorkloadData
+	if !a.decodePOSTBody(w, r, &dataRequest, 4*maxWorkloadSealDataSize) {
+		return
+	}
+	if len(dataRequest.Data) == 0 || len(dataRequest.Data) > maxWorkloadSealDataSize {
+		err := fmt.Errorf("sealed data must be between 1 and %d bytes, got %d bytes", maxWorkloadSealDataSize, len(dataRequest.Data))
+		a.logAndWriteHTTPError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	sealed, err := a.attestAgent.WorkloadSeal(dataRequest.Data)
+	if err != nil {
+		a.logAndWriteHTTPError(w, http.StatusInternalServerError, fmt.Errorf("failed to seal workload data: %w", err))
+		return
+	}
+	sealedBytes, err := proto.Marshal(sealed)
+	if err != nil {
+		a.logAndWriteHTTPError(w, http.StatusInternalServerError, fmt.Errorf("failed to marshal sealed data: %w", err))
+		return
+	}
+	a.writeJSON(w, WorkloadSealed{Sealed: sealedBytes})
+}
+
+// workloadUnseal unseals the workload's data sealed by workloadSeal.
+func (a *attestHandler) workloadUnseal(w http.ResponseWriter, r *http.Request) {
+	a.logger.Info(fmt.Sprintf("%s called", workloadUnsealEndpoint))
+
+	var sealedRequest WorkloadSealed
+	if !a.decodePOSTBody(w, r, &sealedRequest, 2*maxWorkloadSealedSize) {
+		return
+	}
+	if len(sealedRequest.Sealed) > maxWorkloadSealedSize {
+		err := fmt.Errorf("sealed data must be at most %d bytes, got %d bytes", maxWorkloadSealedSize, len(sealedRequest.Sealed))
+		a.logAndWriteHTTPError(w, http.StatusBadRequest, err)
+		return
+	}
+	sealed := &tpmpb.SealedBytes{}
+	if err := proto.Unmarshal(sealedRequest.Sealed, sealed); err != nil {
+		a.logAndWriteHTTPError(w, http.StatusBadRequest, fmt.Errorf("failed to parse sealed data: %v", err))
+		return
+	}
+
+	data, err := a.attestAgent.WorkloadUnseal(sealed)
+	if err != nil {
+		a.logAndWriteHTTPError(w, http.StatusInternalServerError, fmt.Errorf("failed to unseal workload data: %w", err))
+		return
+	}
+	a.writeJSON(w, WorkloadData{Data: data})
+}
+
+// decodePOSTBody decodes the JSON body of a POST request of at most maxSize
+// bytes into v, writing an error and returning false on failure.
+func (a *attestHandler) decodePOSTBody(w http.ResponseWriter, r *http.Request, v any, maxSize int64) bool {
+	if r.Method != http.MethodPost {
+		err := fmt.Errorf("TEE server received an invalid HTTP method: %s", r.Method)
+		a.logAndWriteHTTPError(w, http.StatusBadRequest, err)
+		return false
+	}
+
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxSize))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		err = fmt.Errorf("failed to parse POST body as %T: %v", v, err)
+		a.logAndWriteHTTPError(w, http.StatusBadRequest, err)
+		return false
+	}
+	return true
+}
+
+func (a *attestHandler) writeJSON(w http.ResponseWriter, v any) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		a.logAndWriteHTTPError(w, http.StatusInternalServerError, fmt.Errorf("failed to marshal response: %w", err))
+		return
+	}
+	w.Header().Set("Content-Type", contentTypeJSON)
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
+}
@@ -0,0 +1,141 @@
+package teeserver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-tpm-tools/launcher/internal/logging"
+	tpmpb "github.com/google/go-tpm-tools/proto/tpm"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+func newWorkloadKeyHandler(t *testing.T, agentErr error) attestHandler {
+	return attestHandler{
+		logger: logging.SimpleLogger(),
+		attestAgent: fakeAttestationAgent{
+			workloadKeyFunc: func() (*tpmpb.CertifiedBlob, error) {
+				if agentErr != nil {
+					return nil, agentErr
+				}
+				return &tpmpb.CertifiedBlob{PubArea: []byte("pub"), CertifyInfo: []byte("info"), RawSig: []byte("sig")}, nil
+			},
+			workloadSignFunc: func(data []byte) ([]byte, error) {
+				if agentErr != nil {
+					return nil, agentErr
+				}
+				return append([]byte("signed:"), data...), nil
+			},
+			workloadSealFunc: func(data []byte) (*tpmpb.SealedBytes, error) {
+				if agentErr != nil {
+					return nil, agentErr
+				}
+				return &tpmpb.SealedBytes{Priv: data, Pcrs: []uint32{4, 7, 8, 9, 13}}, nil
+			},
+			workloadUnsealFunc: func(sealed *tpmpb.SealedBytes) ([]byte, error) {
+				if agentErr != nil {
+					return nil, agentErr
+				}
+				if diff := cmp.Diff([]uint32{4, 7, 8, 9, 13}, sealed.GetPcrs()); diff != "" {
+					t.Errorf("WorkloadUnseal() got unexpected PCRs (-want +got):\n%s", diff)
+				}
+				return sealed.GetPriv(), nil
+			},
+		}}
+}
+
+func TestWorkloadKeyEndpoints(t *testing.T) {
+	ah := newWorkloadKeyHandler(t, nil)
+
+	do := func(handler func(http.ResponseWriter, *http.Request), method, url, body string, v any) {
+		t.Helper()
+		req := httptest.NewRequest(method, url, strings.NewReader(body))
+		w := httptest.NewRecorder()
+		handler(w, req)
+
+		data, err := io.ReadAll(w.Result().Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if w.Code != http.StatusOK {
+			t.Fatalf("got return code: %d, want: %d (%s)", w.Code, http.StatusOK, data)
+		}
+		if err := json.Unmarshal(data, v); err != nil {
+			t.Fatalf("failed to unmarshal response: %v", err)
+		}
+	}
+
+	var rawCertification json.RawMessage
+	do(ah.getWorkloadKey, http.MethodGet, "/v1/workload/key", "", &rawCertification)
+	certification := &tpmpb.CertifiedBlob{}
+	if err := protojson.Unmarshal(rawCertification, certification); err != nil {
+		t.Fatalf("failed to unmarshal certification: %v", err)
+	}
+	wantCertification := &tpmpb.CertifiedBlob{PubArea: []byte("pub"), CertifyInfo: []byte("info"), RawSig: []byte("sig")}
+	if diff := cmp.Diff(wantCertification, certification, protocmp.Transform()); diff != "" {
+		t.Errorf("getWorkloadKey() response body mismatch (-want +got):\n%s", diff)
+	}
+
+	// "ZGF0YQ==" is "data".
+	var signature WorkloadSignature
+	do(ah.workloadSign, http.MethodPost, "/v1/workload/sign", `{"data": "ZGF0YQ=="}`, &signature)
+	if got, want := string(signature.Signature), "signed:data"; got != want {
+		t.Errorf("workloadSign() got signature %q, want %q", got, want)
+	}
+
+	var sealed WorkloadSealed
+	do(ah.workloadSeal, http.MethodPost, "/v1/workload/seal", `{"data": "ZGF0YQ=="}`, &sealed)
+	sealedBody, err := json.Marshal(sealed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var unsealed WorkloadData
+	do(ah.workloadUnseal, http.MethodPost, "/v1/workload/unseal", string(sealedBody), &unsealed)
+	if got, want := string(unsealed.Data), "data"; got != want {
+		t.Errorf("workloadUnseal() got data %q, want %q", got, want)
+	}
+}
+
+func TestWorkloadKeyEndpointsError(t *testing.T) {
+	ah := newWorkloadKeyHandler(t, nil)
+	errAh := newWorkloadKeyHandler(t, errors.New("tpm error"))
+
+	tests := []struct {
+		testName string
+		handler  func(http.ResponseWriter, *http.Request)
+		method   string
+		body     string
+		wantCode int
+	}{
+		{"KeyPostNotAllowed", ah.getWorkloadKey, http.MethodPost, "", http.StatusBadRequest},
+		{"KeyAgentError", errAh.getWorkloadKey, http.MethodGet, "", http.StatusInternalServerError},
+		{"SignGetNotAllowed", ah.workloadSign, http.MethodGet, "", http.StatusBadRequest},
+		{"SignInvalidJSON", ah.workloadSign, http.MethodPost, `{"datum": "ZGF0YQ=="}`, http.StatusBadRequest},
+		{"SignDataTooLong", ah.workloadSign, http.MethodPost, fmt.Sprintf(`{"data": "%s"}`, strings.Repeat("A", 4*maxWorkloadSignDataSize)), http.StatusBadRequest},
+		{"SignAgentError", errAh.workloadSign, http.MethodPost, `{"data": "ZGF0YQ=="}`, http.StatusInternalServerError},
+		{"SealMissingData", ah.workloadSeal, http.MethodPost, `{}`, http.StatusBadRequest},
+		// 172 base64 characters encode 129 bytes.
+		{"SealDataTooLong", ah.workloadSeal, http.MethodPost, fmt.Sprintf(`{"data": "%s"}`, strings.Repeat("A", 172)), http.StatusBadRequest},
+		{"SealAgentError", errAh.workloadSeal, http.MethodPost, `{"data": "ZGF0YQ=="}`, http.StatusInternalServerError},
+		{"UnsealInvalidSealed", ah.workloadUnseal, http.MethodPost, `{"sealed": "ZGF0YQ=="}`, http.StatusBadRequest},
+		{"UnsealAgentError", errAh.workloadUnseal, http.MethodPost, `{"sealed": ""}`, http.StatusInternalServerError},
+	}
+	for _, tc := range tests {
+		t.Run(tc.testName, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, "/v1/workload", strings.NewReader(tc.body))
+			w := httptest.NewRecorder()
+			tc.handler(w, req)
+
+			if w.Code != tc.wantCode {
+				t.Errorf("got return code: %d, want: %d", w.Code, tc.wantCode)
+			}
+		})
+	}
+}
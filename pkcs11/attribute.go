@@ -0,0 +1,78 @@
+package pkcs11
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math/bits"
+)
+
+// Attribute is an attribute of an object (CK_ATTRIBUTE). CK_ULONG values are
+// encoded in the native byte order and size of a uint, and CK_BBOOL values
+// are a single byte.
+type Attribute struct {
+	Type  uint
+	Value []byte
+}
+
+// NewAttribute returns an attribute with the encoded value, which must be a
+// bool, uint, string or []byte.
+func NewAttribute(typ uint, value any) Attribute {
+	switch v := value.(type) {
+	case bool:
+		return Attribute{typ, encodeBool(v)}
+	case uint:
+		return Attribute{typ, encodeULong(v)}
+	case string:
+		return Attribute{typ, []byte(v)}
+	case []byte:
+		return Attribute{typ, v}
+	default:
+		panic(fmt.Sprintf("pkcs11: unsupported attribute value type %T", value))
+	}
+}
+
+func encodeBool(b bool) []byte {
+	if b {
+		return []byte{1}
+	}
+	return []byte{0}
+}
+
+func encodeULong(v uint) []byte {
+	if bits.UintSize == 32 {
+		return binary.NativeEndian.AppendUint32(nil, uint32(v))
+	}
+	return binary.NativeEndian.AppendUint64(nil, uint64(v))
+}
+
+type object struct {
+	handle uint
+	attrs  map[uint][]byte
+}
+
+// newObject returns the object at index kind of the slot at index slotIndex,
+// with the union of the attributes.
+func newObject(slotIndex int, kind int, attrs ...map[uint][]byte) *object {
+	obj := &object{
+		handle: uint(slotIndex)<<2 | uint(kind+1),
+		attrs:  make(map[uint][]byte),
+	}
+	for _, a := range attrs {
+		for typ, value := range a {
+			obj.attrs[typ] = value
+		}
+	}
+	return obj
+}
+
+// matches returns whether the object has all the attributes of the template.
+func (obj *object) matches(template []Attribute) bool {
+	for _, attr := range template {
+		value, ok := obj.attrs[attr.Type]
+		if !ok || !bytes.Equal(value, attr.Value) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,160 @@
+package pkcs11
+
+import "fmt"
+
+// Return values (CK_RV) from the PKCS #11 v2.40 specification.
+const (
+	CKR_OK                             Error = 0x000
+	CKR_HOST_MEMORY                    Error = 0x002
+	CKR_SLOT_ID_INVALID                Error = 0x003
+	CKR_GENERAL_ERROR                  Error = 0x005
+	CKR_FUNCTION_FAILED                Error = 0x006
+	CKR_ARGUMENTS_BAD                  Error = 0x007
+	CKR_ATTRIBUTE_TYPE_INVALID         Error = 0x012
+	CKR_DATA_INVALID                   Error = 0x020
+	CKR_DATA_LEN_RANGE                 Error = 0x021
+	CKR_DEVICE_ERROR                   Error = 0x030
+	CKR_FUNCTION_NOT_SUPPORTED         Error = 0x054
+	CKR_KEY_HANDLE_INVALID             Error = 0x060
+	CKR_MECHANISM_INVALID              Error = 0x070
+	CKR_OBJECT_HANDLE_INVALID          Error = 0x082
+	CKR_OPERATION_ACTIVE               Error = 0x090
+	CKR_OPERATION_NOT_INITIALIZED      Error = 0x091
+	CKR_SESSION_HANDLE_INVALID         Error = 0x0B3
+	CKR_SESSION_PARALLEL_NOT_SUPPORTED Error = 0x0B4
+	CKR_USER_TYPE_INVALID              Error = 0x103
+	CKR_BUFFER_TOO_SMALL               Error = 0x150
+	CKR_CRYPTOKI_NOT_INITIALIZED       Error = 0x190
+	CKR_CRYPTOKI_ALREADY_INITIALIZED   Error = 0x191
+)
+
+var errorNames = map[Error]string{
+	CKR_OK:                             "CKR_OK",
+	CKR_HOST_MEMORY:                    "CKR_HOST_MEMORY",
+	CKR_SLOT_ID_INVALID:                "CKR_SLOT_ID_INVALID",
+	CKR_GENERAL_ERROR:                  "CKR_GENERAL_ERROR",
+	CKR_FUNCTION_FAILED:                "CKR_FUNCTION_FAILED",
+	CKR_ARGUMENTS_BAD:                  "CKR_ARGUMENTS_BAD",
+	CKR_ATTRIBUTE_TYPE_INVALID:         "CKR_ATTRIBUTE_TYPE_INVALID",
+	CKR_DATA_INVALID:                   "CKR_DATA_INVALID",
+	CKR_DATA_LEN_RANGE:                 "CKR_DATA_LEN_RANGE",
+	CKR_DEVICE_ERROR:                   "CKR_DEVICE_ERROR",
+	CKR_FUNCTION_NOT_SUPPORTED:         "CKR_FUNCTION_NOT_SUPPORTED",
+	CKR_KEY_HANDLE_INVALID:             "CKR_KEY_HANDLE_INVALID",
+	CKR_MECHANISM_INVALID:              "CKR_MECHANISM_INVALID",
+	CKR_OBJECT_HANDLE_INVALID:          "CKR_OBJECT_HANDLE_INVALID",
+	CKR_OPERATION_ACTIVE:               "CKR_OPERATION_ACTIVE",
+	CKR_OPERATION_NOT_INITIALIZED:      "CKR_OPERATION_NOT_INITIALIZED",
+	CKR_SESSION_HANDLE_INVALID:         "CKR_SESSION_HANDLE_INVALID",
+	CKR_SESSION_PARALLEL_NOT_SUPPORTED: "CKR_SESSION_PARALLEL_NOT_SUPPORTED",
+	CKR_USER_TYPE_INVALID:              "CKR_USER_TYPE_INVALID",
+	CKR_BUFFER_TOO_SMALL:               "CKR_BUFFER_TOO_SMALL",
+	CKR_CRYPTOKI_NOT_INITIALIZED:       "CKR_CRYPTOKI_NOT_INITIALIZED",
+	CKR_CRYPTOKI_ALREADY_INITIALIZED:   "CKR_CRYPTOKI_ALREADY_INITIALIZED",
+}
+
+// Error is a PKCS #11 return value (CK_RV) other than CKR_OK.
+type Error uint
+
+func (e Error) Error() string {
+	if name, ok := errorNames[e]; ok {
+		return "pkcs11: " + name
+	}
+	return fmt.Sprintf("pkcs11: error 0x%X", uint(e))
+}
+
+// Object classes (CK_OBJECT_CLASS).
+const (
+	CKO_CERTIFICATE uint = 0x1
+	CKO_PUBLIC_KEY  uint = 0x2
+	CKO_PRIVATE_KEY uint = 0x3
+)
+
+// Key types (CK_KEY_TYPE).
+const (
+	CKK_RSA uint = 0x0
+	CKK_EC  uint = 0x3
+)
+
+// Certificate types (CK_CERTIFICATE_TYPE).
+const (
+	CKC_X_509 uint = 0x0
+)
+
+// Attribute types (CK_ATTRIBUTE_TYPE).
+const (
+	CKA_CLASS               uint = 0x000
+	CKA_TOKEN               uint = 0x001
+	CKA_PRIVATE             uint = 0x002
+	CKA_LABEL               uint = 0x003
+	CKA_VALUE               uint = 0x011
+	CKA_CERTIFICATE_TYPE    uint = 0x080
+	CKA_ISSUER              uint = 0x081
+	CKA_SERIAL_NUMBER       uint = 0x082
+	CKA_KEY_TYPE            uint = 0x100
+	CKA_SUBJECT             uint = 0x101
+	CKA_ID                  uint = 0x102
+	CKA_SENSITIVE           uint = 0x103
+	CKA_ENCRYPT             uint = 0x104
+	CKA_DECRYPT             uint = 0x105
+	CKA_WRAP                uint = 0x106
+	CKA_UNWRAP              uint = 0x107
+	CKA_SIGN                uint = 0x108
+	CKA_SIGN_RECOVER        uint = 0x109
+	CKA_VERIFY              uint = 0x10A
+	CKA_DERIVE              uint = 0x10C
+	CKA_MODULUS             uint = 0x120
+	CKA_MODULUS_BITS        uint = 0x121
+	CKA_PUBLIC_EXPONENT     uint = 0x122
+	CKA_EXTRACTABLE         uint = 0x162
+	CKA_LOCAL               uint = 0x163
+	CKA_NEVER_EXTRACTABLE   uint = 0x164
+	CKA_ALWAYS_SENSITIVE    uint = 0x165
+	CKA_MODIFIABLE          uint = 0x170
+	CKA_EC_PARAMS           uint = 0x180
+	CKA_EC_POINT            uint = 0x181
+	CKA_ALWAYS_AUTHENTICATE uint = 0x202
+)
+
+// Mechanism types (CK_MECHANISM_TYPE).
+const (
+	CKM_RSA_PKCS            uint = 0x0001
+	CKM_SHA1_RSA_PKCS       uint = 0x0006
+	CKM_RSA_PKCS_PSS        uint = 0x000D
+	CKM_SHA1_RSA_PKCS_PSS   uint = 0x000E
+	CKM_SHA256_RSA_PKCS     uint = 0x0040
+	CKM_SHA384_RSA_PKCS     uint = 0x0041
+	CKM_SHA512_RSA_PKCS     uint = 0x0042
+	CKM_SHA256_RSA_PKCS_PSS uint = 0x0043
+	CKM_SHA384_RSA_PKCS_PSS uint = 0x0044
+	CKM_SHA512_RSA_PKCS_PSS uint = 0x0045
+	CKM_ECDSA               uint = 0x1041
+	CKM_ECDSA_SHA1          uint = 0x1042
+	CKM_ECDSA_SHA256        uint = 0x1044
+	CKM_ECDSA_SHA384        uint = 0x1045
+	CKM_ECDSA_SHA512        uint = 0x1046
+)
+
+// Flags (CK_FLAGS) of slots, tokens, sessions and mechanisms.
+const (
+	CKF_TOKEN_PRESENT     uint = 0x001
+	CKF_HW_SLOT           uint = 0x004
+	CKF_RNG               uint = 0x001
+	CKF_TOKEN_INITIALIZED uint = 0x400
+	CKF_RW_SESSION        uint = 0x002
+	CKF_SERIAL_SESSION    uint = 0x004
+	CKF_HW                uint = 0x001
+	CKF_SIGN              uint = 0x800
+)
+
+// Session states (CK_STATE).
+const (
+	CKS_RO_PUBLIC_SESSION uint = 0
+	CKS_RW_PUBLIC_SESSION uint = 2
+)
+
+// User types (CK_USER_TYPE).
+const (
+	CKU_SO   uint = 0
+	CKU_USER uint = 1
+)
@@ -0,0 +1,93 @@
+#include <stddef.h>
+
+#include "pkcs11.h"
+#include "_cgo_export.h"
+
+// unsupported is the entry of every function the module does not implement.
+// It ignores its arguments, which is safe with the C calling conventions of
+// the supported platforms, where the caller cleans up the stack.
+static CK_RV unsupported(void) { return CKR_FUNCTION_NOT_SUPPORTED; }
+
+#define FUNCTION(f) ((CK_FUNCTION)(f))
+
+static CK_FUNCTION_LIST function_list = {
+    {2, 40},
+    {
+        FUNCTION(C_Initialize),
+        FUNCTION(C_Finalize),
+        FUNCTION(C_GetInfo),
+        FUNCTION(C_GetFunctionList),
+        FUNCTION(C_GetSlotList),
+        FUNCTION(C_GetSlotInfo),
+        FUNCTION(C_GetTokenInfo),
+        FUNCTION(C_GetMechanismList),
+        FUNCTION(C_GetMechanismInfo),
+        unsupported,  // C_InitToken
+        unsupported,  // C_InitPIN
+        unsupported,  // C_SetPIN
+        FUNCTION(C_OpenSession),
+        FUNCTION(C_CloseSession),
+        FUNCTION(C_CloseAllSessions),
+        FUNCTION(C_GetSessionInfo),
+        unsupported,  // C_GetOperationState
+        unsupported,  // C_SetOperationState
+        FUNCTION(C_Login),
+        FUNCTION(C_Logout),
+        unsupported,  // C_CreateObject
+        unsupported,  // C_CopyObject
+        unsupported,  // C_DestroyObject
+        unsupported,  // C_GetObjectSize
+        FUNCTION(C_GetAttributeValue),
+        unsupported,  // C_SetAttributeValue
+        FUNCTION(C_FindObjectsInit),
+        FUNCTION(C_FindObjects),
+        FUNCTION(C_FindObjectsFinal),
+        unsupported,  // C_EncryptInit
+        unsupported,  // C_Encrypt
+        unsupported,  // C_EncryptUpdate
+        unsupported,  // C_EncryptFinal
+        unsupported,  // C_DecryptInit
+        unsupported,  // C_Decrypt
+        unsupported,  // C_DecryptUpdate
+        unsupported,  // C_DecryptFinal
+        unsupported,  // C_DigestInit
+        unsupported,  // C_Digest
+        unsupported,  // C_DigestUpdate
+        unsupported,  // C_DigestKey
+        unsupported,  // C_DigestFinal
+        FUNCTION(C_SignInit),
+        FUNCTION(C_Sign),
+        FUNCTION(C_SignUpdate),
+        FUNCTION(C_SignFinal),
+        unsupported,  // C_SignRecoverInit
+        unsupported,  // C_SignRecover
+        unsupported,  // C_VerifyInit
+        unsupported,  // C_Verify
+        unsupported,  // C_VerifyUpdate
+        unsupported,  // C_VerifyFinal
+        unsupported,  // C_VerifyRecoverInit
+        unsupported,  // C_VerifyRecover
+        unsupported,  // C_DigestEncryptUpdate
+        unsupported,  // C_DecryptDigestUpdate
+        unsupported,  // C_SignEncryptUpdate
+        unsupported,  // C_DecryptVerifyUpdate
+        unsupported,  // C_GenerateKey
+        unsupported,  // C_GenerateKeyPair
+        unsupported,  // C_WrapKey
+        unsupported,  // C_UnwrapKey
+        unsupported,  // C_DeriveKey
+        unsupported,  // C_SeedRandom
+        FUNCTION(C_GenerateRandom),
+        unsupported,  // C_GetFunctionStatus
+        unsupported,  // C_CancelFunction
+        unsupported,  // C_WaitForSlotEvent
+    },
+};
+
+CK_RV C_GetFunctionList(CK_FUNCTION_LIST_PTR *ppFunctionList) {
+  if (ppFunctionList == NULL) {
+    return 0x00000007UL;  // CKR_ARGUMENTS_BAD
+  }
+  *ppFunctionList = &function_list;
+  return CKR_OK;
+}
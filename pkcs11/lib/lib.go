@@ -0,0 +1,502 @@
+//go:build cgo && !windows
+// +build cgo,!windows
+
+package main
+
+// #include "pkcs11.h"
+import "C"
+
+import (
+	"errors"
+	"io"
+	"os"
+	"sync"
+	"unsafe"
+
+	"github.com/google/go-tpm/legacy/tpm2"
+
+	"github.com/google/go-tpm-tools/pkcs11"
+)
+
+var (
+	mu     sync.Mutex
+	tpm    io.ReadWriteCloser
+	module *pkcs11.Module
+)
+
+// rv converts an error to a PKCS #11 return value.
+func rv(err error) C.CK_RV {
+	if err == nil {
+		return C.CKR_OK
+	}
+	var pkcs11Err pkcs11.Error
+	if errors.As(err, &pkcs11Err) {
+		return C.CK_RV(pkcs11Err)
+	}
+	return C.CK_RV(pkcs11.CKR_FUNCTION_FAILED)
+}
+
+func getModule() (*pkcs11.Module, error) {
+	mu.Lock()
+	defer mu.Unlock()
+	if module == nil {
+		return nil, pkcs11.CKR_CRYPTOKI_NOT_INITIALIZED
+	}
+	return module, nil
+}
+
+func openTPM() (io.ReadWriteCloser, error) {
+	if path := os.Getenv("GOTPM_PKCS11_TPM"); path != "" {
+		return tpm2.OpenTPM(path)
+	}
+	return tpm2.OpenTPM()
+}
+
+// setString sets a blank padded PKCS #11 string, truncating s if needed.
+func setString[T ~uint8](dst []T, s string) {
+	for i := range dst {
+		if i < len(s) {
+			dst[i] = T(s[i])
+		} else {
+			dst[i] = ' '
+		}
+	}
+}
+
+// setList sets a list returned with the PKCS #11 buffer conventions.
+func setList[T ~uint32 | ~uint64](values []uint, list *T, count *C.CK_ULONG) error {
+	if list == nil {
+		*count = C.CK_ULONG(len(values))
+		return nil
+	}
+	if int(*count) < len(values) {
+		*count = C.CK_ULONG(len(values))
+		return pkcs11.CKR_BUFFER_TOO_SMALL
+	}
+	dst := unsafe.Slice(list, len(values))
+	for i, v := range values {
+		dst[i] = T(v)
+	}
+	*count = C.CK_ULONG(len(values))
+	return nil
+}
+
+// setBytes sets a byte array returned with the PKCS #11 buffer conventions.
+func setBytes(value []byte, buf *C.CK_BYTE, length *C.CK_ULONG) error {
+	if buf != nil {
+		if int(*length) < len(value) {
+			*length = C.CK_ULONG(len(value))
+			return pkcs11.CKR_BUFFER_TOO_SMALL
+		}
+		copy(unsafe.Slice((*byte)(unsafe.Pointer(buf)), len(value)), value)
+	}
+	*length = C.CK_ULONG(len(value))
+	return nil
+}
+
+//export C_Initialize
+func C_Initialize(pInitArgs C.CK_VOID_PTR) C.CK_RV {
+	mu.Lock()
+	defer mu.Unlock()
+	if module != nil {
+		return rv(pkcs11.CKR_CRYPTOKI_ALREADY_INITIALIZED)
+	}
+	rwc, err := openTPM()
+	if err != nil {
+		return rv(pkcs11.CKR_DEVICE_ERROR)
+	}
+	var slots []pkcs11.SlotConfig
+	if config := os.Getenv("GOTPM_PKCS11_SLOTS"); config != "" {
+		slots, err = pkcs11.ParseSlots(config)
+	} else {
+		slots, err = pkcs11.PersistentSlots(rwc)
+	}
+	if err == nil {
+		module, err = pkcs11.NewModule(rwc, slots)
+	}
+	if err != nil {
+		rwc.Close()
+		return rv(err)
+	}
+	tpm = rwc
+	return C.CKR_OK
+}
+
+//export C_Finalize
+func C_Finalize(pReserved C.CK_VOID_PTR) C.CK_RV {
+	mu.Lock()
+	defer mu.Unlock()
+	if module == nil {
+		return rv(pkcs11.CKR_CRYPTOKI_NOT_INITIALIZED)
+	}
+	module.Close()
+	module = nil
+	// The module is finalized even if closing the TPM fails.
+	tpm.Close()
+	tpm = nil
+	return C.CKR_OK
+}
+
+//export C_GetInfo
+func C_GetInfo(pInfo *C.CK_INFO) C.CK_RV {
+	if _, err := getModule(); err != nil {
+		return rv(err)
+	}
+	if pInfo == nil {
+		return rv(pkcs11.CKR_ARGUMENTS_BAD)
+	}
+	*pInfo = C.CK_INFO{
+		cryptokiVersion: C.CK_VERSION{major: 2, minor: 40},
+		libraryVersion:  C.CK_VERSION{major: 0, minor: 1},
+	}
+	setString(pInfo.manufacturerID[:], "go-tpm-tools")
+	setString(pInfo.libraryDescription[:], "TPM 2.0 PKCS #11 module")
+	return C.CKR_OK
+}
+
+//export C_GetSlotList
+func C_GetSlotList(tokenPresent C.CK_BBOOL, pSlotList *C.CK_SLOT_ID, pulCount *C.CK_ULONG) C.CK_RV {
+	m, err := getModule()
+	if err != nil {
+		return rv(err)
+	}
+	if pulCount == nil {
+		return rv(pkcs11.CKR_ARGUMENTS_BAD)
+	}
+	// All slots have a token present.
+	return rv(setList(m.Slots(), pSlotList, pulCount))
+}
+
+//export C_GetSlotInfo
+func C_GetSlotInfo(slotID C.CK_SLOT_ID, pInfo *C.CK_SLOT_INFO) C.CK_RV {
+	m, err := getModule()
+	if err != nil {
+		return rv(err)
+	}
+	if pInfo == nil {
+		return rv(pkcs11.CKR_ARGUMENTS_BAD)
+	}
+	info, err := m.SlotInfo(uint(slotID))
+	if err != nil {
+		return rv(err)
+	}
+	*pInfo = C.CK_SLOT_INFO{flags: C.CK_FLAGS(info.Flags)}
+	setString(pInfo.slotDescription[:], info.Description)
+	setString(pInfo.manufacturerID[:], info.ManufacturerID)
+	return C.CKR_OK
+}
+
+//export C_GetTokenInfo
+func C_GetTokenInfo(slotID C.CK_SLOT_ID, pInfo *C.CK_TOKEN_INFO) C.CK_RV {
+	m, err := getModule()
+	if err != nil {
+		return rv(err)
+	}
+	if pInfo == nil {
+		return rv(pkcs11.CKR_ARGUMENTS_BAD)
+	}
+	info, err := m.TokenInfo(uint(slotID))
+	if err != nil {
+		return rv(err)
+	}
+	const unavailable = C.CK_UNAVAILABLE_INFORMATION
+	*pInfo = C.CK_TOKEN_INFO{
+		flags:                C.CK_FLAGS(info.Flags),
+		ulMaxSessionCount:    unavailable,
+		ulSessionCount:       unavailable,
+		ulMaxRwSessionCount:  unavailable,
+		ulRwSessionCount:     unavailable,
+		ulTotalPublicMemory:  unavailable,
+		ulFreePublicMemory:   unavailable,
+		ulTotalPrivateMemory: unavailable,
+		ulFreePrivateMemory:  unavailable,
+	}
+	setString(pInfo.label[:], info.Label)
+	setString(pInfo.manufacturerID[:], info.ManufacturerID)
+	setString(pInfo.model[:], info.Model)
+	setString(pInfo.serialNumber[:], info.SerialNumber)
+	return C.CKR_OK
+}
+
+//export C_GetMechanismList
+func C_GetMechanismList(slotID C.CK_SLOT_ID, pMechanismList *C.CK_MECHANISM_TYPE, pulCount *C.CK_ULONG) C.CK_RV {
+	m, err := getModule()
+	if err != nil {
+		return rv(err)
+	}
+	if pulCount == nil {
+		return rv(pkcs11.CKR_ARGUMENTS_BAD)
+	}
+	mechanisms, err := m.Mechanisms(uint(slotID))
+	if err != nil {
+		return rv(err)
+	}
+	return rv(setList(mechanisms, pMechanismList, pulCount))
+}
+
+//export C_GetMechanismInfo
+func C_GetMechanismInfo(slotID C.CK_SLOT_ID, mechanismType C.CK_MECHANISM_TYPE, pInfo *C.CK_MECHANISM_INFO) C.CK_RV {
+	m, err := getModule()
+	if err != nil {
+		return rv(err)
+	}
+	if pInfo == nil {
+		return rv(pkcs11.CKR_ARGUMENTS_BAD)
+	}
+	info, err := m.MechanismInfo(uint(slotID), uint(mechanismType))
+	if err != nil {
+		return rv(err)
+	}
+	*pInfo = C.CK_MECHANISM_INFO{
+		ulMinKeySize: C.CK_ULONG(info.MinKeySize),
+		ulMaxKeySize: C.CK_ULONG(info.MaxKeySize),
+		flags:        C.CK_FLAGS(info.Flags),
+	}
+	return C.CKR_OK
+}
+
+//export C_OpenSession
+func C_OpenSession(slotID C.CK_SLOT_ID, flags C.CK_FLAGS, pApplication C.CK_VOID_PTR, notify C.CK_NOTIFY, phSession *C.CK_SESSION_HANDLE) C.CK_RV {
+	m, err := getModule()
+	if err != nil {
+		return rv(err)
+	}
+	if phSession == nil {
+		return rv(pkcs11.CKR_ARGUMENTS_BAD)
+	}
+	// The module never calls notify, as it has no notifications.
+	session, err := m.OpenSession(uint(slotID), uint(flags))
+	if err != nil {
+		return rv(err)
+	}
+	*phSession = C.CK_SESSION_HANDLE(session)
+	return C.CKR_OK
+}
+
+//export C_CloseSession
+func C_CloseSession(hSession C.CK_SESSION_HANDLE) C.CK_RV {
+	m, err := getModule()
+	if err != nil {
+		return rv(err)
+	}
+	return rv(m.CloseSession(uint(hSession)))
+}
+
+//export C_CloseAllSessions
+func C_CloseAllSessions(slotID C.CK_SLOT_ID) C.CK_RV {
+	m, err := getModule()
+	if err != nil {
+		return rv(err)
+	}
+	return rv(m.CloseAllSessions(uint(slotID)))
+}
+
+//export C_GetSessionInfo
+func C_GetSessionInfo(hSession C.CK_SESSION_HANDLE, pInfo *C.CK_SESSION_INFO) C.CK_RV {
+	m, err := getModule()
+	if err != nil {
+		return rv(err)
+	}
+	if pInfo == nil {
+		return rv(pkcs11.CKR_ARGUMENTS_BAD)
+	}
+	info, err := m.SessionInfo(uint(hSession))
+	if err != nil {
+		return rv(err)
+	}
+	*pInfo = C.CK_SESSION_INFO{
+		slotID: C.CK_SLOT_ID(info.SlotID),
+		state:  C.CK_STATE(info.State),
+		flags:  C.CK_FLAGS(info.Flags),
+	}
+	return C.CKR_OK
+}
+
+//export C_Login
+func C_Login(hSession C.CK_SESSION_HANDLE, userType C.CK_USER_TYPE, pPin *C.CK_UTF8CHAR, ulPinLen C.CK_ULONG) C.CK_RV {
+	m, err := getModule()
+	if err != nil {
+		return rv(err)
+	}
+	return rv(m.Login(uint(hSession), uint(userType)))
+}
+
+//export C_Logout
+func C_Logout(hSession C.CK_SESSION_HANDLE) C.CK_RV {
+	m, err := getModule()
+	if err != nil {
+		return rv(err)
+	}
+	return rv(m.Logout(uint(hSession)))
+}
+
+//export C_GetAttributeValue
+func C_GetAttributeValue(hSession C.CK_SESSION_HANDLE, hObject C.CK_OBJECT_HANDLE, pTemplate *C.CK_ATTRIBUTE, ulCount C.CK_ULONG) C.CK_RV {
+	m, err := getModule()
+	if err != nil {
+		return rv(err)
+	}
+	if pTemplate == nil && ulCount != 0 {
+		return rv(pkcs11.CKR_ARGUMENTS_BAD)
+	}
+	template := unsafe.Slice(pTemplate, ulCount)
+	types := make([]uint, len(template))
+	for i, attr := range template {
+		types[i] = uint(attr._type)
+	}
+	attrs, err := m.GetAttributeValue(uint(hSession), uint(hObject), types)
+	if err != nil && !errors.Is(err, pkcs11.CKR_ATTRIBUTE_TYPE_INVALID) {
+		return rv(err)
+	}
+	// Every attribute is processed, even if some of them are invalid.
+	for i, attr := range attrs {
+		if attr.Value == nil {
+			template[i].ulValueLen = C.CK_UNAVAILABLE_INFORMATION
+			continue
+		}
+		if setErr := setBytes(attr.Value, (*C.CK_BYTE)(template[i].pValue), &template[i].ulValueLen); setErr != nil {
+			template[i].ulValueLen = C.CK_UNAVAILABLE_INFORMATION
+			if err == nil {
+				err = setErr
+			}
+		}
+	}
+	return rv(err)
+}
+
+//export C_FindObjectsInit
+func C_FindObjectsInit(hSession C.CK_SESSION_HANDLE, pTemplate *C.CK_ATTRIBUTE, ulCount C.CK_ULONG) C.CK_RV {
+	m, err := getModule()
+	if err != nil {
+		return rv(err)
+	}
+	if pTemplate == nil && ulCount != 0 {
+		return rv(pkcs11.CKR_ARGUMENTS_BAD)
+	}
+	var template []pkcs11.Attribute
+	for _, attr := range unsafe.Slice(pTemplate, ulCount) {
+		template = append(template, pkcs11.Attribute{
+			Type:  uint(attr._type),
+			Value: C.GoBytes(unsafe.Pointer(attr.pValue), C.int(attr.ulValueLen)),
+		})
+	}
+	return rv(m.FindObjectsInit(uint(hSession), template))
+}
+
+//export C_FindObjects
+func C_FindObjects(hSession C.CK_SESSION_HANDLE, phObject *C.CK_OBJECT_HANDLE, ulMaxObjectCount C.CK_ULONG, pulObjectCount *C.CK_ULONG) C.CK_RV {
+	m, err := getModule()
+	if err != nil {
+		return rv(err)
+	}
+	if phObject == nil || pulObjectCount == nil {
+		return rv(pkcs11.CKR_ARGUMENTS_BAD)
+	}
+	objects, err := m.FindObjects(uint(hSession), int(ulMaxObjectCount))
+	if err != nil {
+		return rv(err)
+	}
+	*pulObjectCount = ulMaxObjectCount
+	return rv(setList(objects, phObject, pulObjectCount))
+}
+
+//export C_FindObjectsFinal
+func C_FindObjectsFinal(hSession C.CK_SESSION_HANDLE) C.CK_RV {
+	m, err := getModule()
+	if err != nil {
+		return rv(err)
+	}
+	return rv(m.FindObjectsFinal(uint(hSession)))
+}
+
+//export C_SignInit
+func C_SignInit(hSession C.CK_SESSION_HANDLE, pMechanism *C.CK_MECHANISM, hKey C.CK_OBJECT_HANDLE) C.CK_RV {
+	m, err := getModule()
+	if err != nil {
+		return rv(err)
+	}
+	if pMechanism == nil {
+		return rv(pkcs11.CKR_ARGUMENTS_BAD)
+	}
+	// The mechanism parameters of RSA-PSS are ignored, as the TPM chooses the
+	// salt length.
+	return rv(m.SignInit(uint(hSession), uint(pMechanism.mechanism), uint(hKey)))
+}
+
+// signatureBuffer checks the buffer of a signature, returning whether the
+// signature can be computed.
+func signatureBuffer(m *pkcs11.Module, hSession C.CK_SESSION_HANDLE, pSignature *C.CK_BYTE, pulSignatureLen *C.CK_ULONG) (bool, error) {
+	if pulSignatureLen == nil {
+		return false, pkcs11.CKR_ARGUMENTS_BAD
+	}
+	length, err := m.SignatureLength(uint(hSession))
+	if err != nil {
+		return false, err
+	}
+	if pSignature == nil || int(*pulSignatureLen) < length {
+		*pulSignatureLen = C.CK_ULONG(length)
+		if pSignature != nil {
+			return false, pkcs11.CKR_BUFFER_TOO_SMALL
+		}
+		return false, nil
+	}
+	return true, nil
+}
+
+//export C_Sign
+func C_Sign(hSession C.CK_SESSION_HANDLE, pData *C.CK_BYTE, ulDataLen C.CK_ULONG, pSignature *C.CK_BYTE, pulSignatureLen *C.CK_ULONG) C.CK_RV {
+	m, err := getModule()
+	if err != nil {
+		return rv(err)
+	}
+	if ok, err := signatureBuffer(m, hSession, pSignature, pulSignatureLen); !ok {
+		return rv(err)
+	}
+	sig, err := m.Sign(uint(hSession), C.GoBytes(unsafe.Pointer(pData), C.int(ulDataLen)))
+	if err != nil {
+		return rv(err)
+	}
+	return rv(setBytes(sig, pSignature, pulSignatureLen))
+}
+
+//export C_SignUpdate
+func C_SignUpdate(hSession C.CK_SESSION_HANDLE, pPart *C.CK_BYTE, ulPartLen C.CK_ULONG) C.CK_RV {
+	m, err := getModule()
+	if err != nil {
+		return rv(err)
+	}
+	return rv(m.SignUpdate(uint(hSession), C.GoBytes(unsafe.Pointer(pPart), C.int(ulPartLen))))
+}
+
+//export C_SignFinal
+func C_SignFinal(hSession C.CK_SESSION_HANDLE, pSignature *C.CK_BYTE, pulSignatureLen *C.CK_ULONG) C.CK_RV {
+	m, err := getModule()
+	if err != nil {
+		return rv(err)
+	}
+	if ok, err := signatureBuffer(m, hSession, pSignature, pulSignatureLen); !ok {
+		return rv(err)
+	}
+	sig, err := m.SignFinal(uint(hSession))
+	if err != nil {
+		return rv(err)
+	}
+	return rv(setBytes(sig, pSignature, pulSignatureLen))
+}
+
+//export C_GenerateRandom
+func C_GenerateRandom(hSession C.CK_SESSION_HANDLE, pRandomData *C.CK_BYTE, ulRandomLen C.CK_ULONG) C.CK_RV {
+	m, err := getModule()
+	if err != nil {
+		return rv(err)
+	}
+	if pRandomData == nil && ulRandomLen != 0 {
+		return rv(pkcs11.CKR_ARGUMENTS_BAD)
+	}
+	random, err := m.GenerateRandom(uint(hSession), int(ulRandomLen))
+	if err != nil {
+		return rv(err)
+	}
+	copy(unsafe.Slice((*byte)(unsafe.Pointer(pRandomData)), len(random)), random)
+	return C.CKR_OK
+}
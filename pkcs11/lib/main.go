@@ -0,0 +1,12 @@
+// Command lib builds a PKCS #11 module exposing the TPM's signing keys, for
+// use by PKCS #11 applications (such as OpenSSL, Java and ssh-agent):
+//
+//	go build -buildmode=c-shared -o libgotpm-pkcs11.so ./pkcs11/lib
+//
+// C_Initialize opens the TPM at $GOTPM_PKCS11_TPM (defaulting to /dev/tpmrm0
+// then /dev/tpm0), and exposes the slots in $GOTPM_PKCS11_SLOTS (in the format
+// of pkcs11.ParseSlots), or all the TPM's persistent signing keys if unset.
+// See the pkcs11 package for the supported objects and mechanisms.
+package main
+
+func main() {}
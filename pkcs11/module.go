@@ -0,0 +1,517 @@
+// Package pkcs11 exposes signing keys resident in a TPM as PKCS #11 tokens.
+//
+// Each persistent key is a slot, whose ID is the key's persistent handle, and
+// whose token holds the key's private key, public key and (optionally)
+// certificate objects. The private key never leaves the TPM: signatures are
+// computed with client.Key.GetSigner for unrestricted keys, and with
+// client.Key.SignData (which also supports restricted keys, such as AKs).
+// Tokens are read-only and do not require a login.
+//
+// Module implements the PKCS #11 operations in Go. The pkcs11/lib directory
+// builds it into a shared library implementing the C interface, for use by
+// applications such as OpenSSL, Java and ssh-agent.
+package pkcs11
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/google/go-tpm-tools/client"
+	"github.com/google/go-tpm/legacy/tpm2"
+	"github.com/google/go-tpm/tpmutil"
+)
+
+// manufacturerID is the manufacturer of the slots and tokens.
+const manufacturerID = "go-tpm-tools"
+
+// SlotConfig specifies a TPM key exposed as a PKCS #11 slot.
+type SlotConfig struct {
+	// Handle is the persistent handle of the key, which is also the slot ID.
+	// The key must be a signing key usable with an empty password.
+	Handle tpmutil.Handle
+	// Label is the token label, which defaults to the handle in hex.
+	Label string
+	// CertNVIndex, if set, is the NV index holding the key's DER encoded
+	// certificate, which is read with owner authorization.
+	CertNVIndex uint32
+}
+
+// PersistentSlots returns a SlotConfig for each persistent key in the TPM that
+// can be used by a Module (a signing key with a supported scheme).
+func PersistentSlots(rw io.ReadWriter) ([]SlotConfig, error) {
+	handles, err := client.Handles(rw, tpm2.HandleTypePersistent)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list persistent handles: %w", err)
+	}
+	var slots []SlotConfig
+	for _, handle := range handles {
+		pub, _, _, err := tpm2.ReadPublic(rw, handle)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read public area of 0x%x: %w", handle, err)
+		}
+		if _, _, err := signingScheme(pub); err != nil {
+			continue
+		}
+		slots = append(slots, SlotConfig{Handle: handle})
+	}
+	return slots, nil
+}
+
+// ParseSlots parses a comma separated list of slots, each of which is a
+// persistent handle optionally followed by a colon and the NV index of its
+// certificate, for example "0x81000001:0x01c10000,0x81000002".
+func ParseSlots(s string) ([]SlotConfig, error) {
+	var slots []SlotConfig
+	for _, field := range strings.Split(s, ",") {
+		handle, index, hasIndex := strings.Cut(strings.TrimSpace(field), ":")
+		h, err := strconv.ParseUint(handle, 0, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid handle %q: %w", handle, err)
+		}
+		slot := SlotConfig{Handle: tpmutil.Handle(h)}
+		if hasIndex {
+			i, err := strconv.ParseUint(index, 0, 32)
+			if err != nil {
+				return nil, fmt.Errorf("invalid NV index %q: %w", index, err)
+			}
+			slot.CertNVIndex = uint32(i)
+		}
+		slots = append(slots, slot)
+	}
+	return slots, nil
+}
+
+// SlotInfo describes a slot (CK_SLOT_INFO).
+type SlotInfo struct {
+	Description    string
+	ManufacturerID string
+	Flags          uint
+}
+
+// TokenInfo describes a token (CK_TOKEN_INFO).
+type TokenInfo struct {
+	Label          string
+	ManufacturerID string
+	Model          string
+	SerialNumber   string
+	Flags          uint
+}
+
+// MechanismInfo describes a mechanism supported by a token (CK_MECHANISM_INFO).
+type MechanismInfo struct {
+	MinKeySize uint
+	MaxKeySize uint
+	Flags      uint
+}
+
+// SessionInfo describes a session (CK_SESSION_INFO).
+type SessionInfo struct {
+	SlotID uint
+	State  uint
+	Flags  uint
+}
+
+type session struct {
+	slot  *slot
+	flags uint
+	// found is nil unless a search is active.
+	found []uint
+	// sign is nil unless a signing operation is active.
+	sign *signOperation
+}
+
+type signOperation struct {
+	mechanism uint
+	data      []byte
+}
+
+// Module is a PKCS #11 module with a slot for each configured TPM key.
+// All methods are thread safe, but it is not safe to access the TPM from other
+// sources while a method is executing.
+type Module struct {
+	mu          sync.Mutex
+	rw          io.ReadWriter
+	slots       []*slot
+	sessions    map[uint]*session
+	lastSession uint
+}
+
+// NewModule loads the keys of the slots from the TPM. Close must be called
+// to release the keys.
+func NewModule(rw io.ReadWriter, slots []SlotConfig) (*Module, error) {
+	m := &Module{rw: rw, sessions: make(map[uint]*session)}
+	for i, config := range slots {
+		s, err := newSlot(rw, config, i)
+		if err != nil {
+			m.Close()
+			return nil, fmt.Errorf("slot 0x%x: %w", config.Handle, err)
+		}
+		m.slots = append(m.slots, s)
+	}
+	return m, nil
+}
+
+// Close closes all sessions and releases the keys. The TPM is not closed.
+func (m *Module) Close() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, s := range m.slots {
+		s.key.Close()
+	}
+	m.slots = nil
+	m.sessions = make(map[uint]*session)
+}
+
+// Slots returns the IDs of all slots. Every slot has a token present.
+func (m *Module) Slots() []uint {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	ids := make([]uint, len(m.slots))
+	for i, s := range m.slots {
+		ids[i] = s.id
+	}
+	return ids
+}
+
+// SlotInfo returns information about a slot.
+func (m *Module) SlotInfo(slotID uint) (SlotInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, err := m.slot(slotID)
+	if err != nil {
+		return SlotInfo{}, err
+	}
+	return SlotInfo{
+		Description:    fmt.Sprintf("TPM key 0x%08x", s.id),
+		ManufacturerID: manufacturerID,
+		Flags:          CKF_TOKEN_PRESENT | CKF_HW_SLOT,
+	}, nil
+}
+
+// TokenInfo returns information about the token in a slot.
+func (m *Module) TokenInfo(slotID uint) (TokenInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, err := m.slot(slotID)
+	if err != nil {
+		return TokenInfo{}, err
+	}
+	return TokenInfo{
+		Label:          s.label,
+		ManufacturerID: manufacturerID,
+		Model:          "TPM 2.0",
+		SerialNumber:   fmt.Sprintf("%08x", s.id),
+		Flags:          CKF_RNG | CKF_TOKEN_INITIALIZED,
+	}, nil
+}
+
+// Mechanisms returns the signing mechanisms supported by the token in a slot.
+func (m *Module) Mechanisms(slotID uint) ([]uint, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, err := m.slot(slotID)
+	if err != nil {
+		return nil, err
+	}
+	return append([]uint(nil), s.mechanisms...), nil
+}
+
+// MechanismInfo returns information about a mechanism of the token in a slot.
+func (m *Module) MechanismInfo(slotID uint, mechanism uint) (MechanismInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, err := m.slot(slotID)
+	if err != nil {
+		return MechanismInfo{}, err
+	}
+	if !s.supports(mechanism) {
+		return MechanismInfo{}, CKR_MECHANISM_INVALID
+	}
+	return MechanismInfo{
+		MinKeySize: s.keyBits,
+		MaxKeySize: s.keyBits,
+		Flags:      CKF_HW | CKF_SIGN,
+	}, nil
+}
+
+// OpenSession opens a session with the token in a slot, and returns the
+// session handle. The flags must include CKF_SERIAL_SESSION.
+func (m *Module) OpenSession(slotID uint, flags uint) (uint, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, err := m.slot(slotID)
+	if err != nil {
+		return 0, err
+	}
+	if flags&CKF_SERIAL_SESSION == 0 {
+		return 0, CKR_SESSION_PARALLEL_NOT_SUPPORTED
+	}
+	m.lastSession++
+	m.sessions[m.lastSession] = &session{slot: s, flags: flags & (CKF_SERIAL_SESSION | CKF_RW_SESSION)}
+	return m.lastSession, nil
+}
+
+// CloseSession closes a session.
+func (m *Module) CloseSession(sessionHandle uint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, err := m.session(sessionHandle); err != nil {
+		return err
+	}
+	delete(m.sessions, sessionHandle)
+	return nil
+}
+
+// CloseAllSessions closes all sessions with the token in a slot.
+func (m *Module) CloseAllSessions(slotID uint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, err := m.slot(slotID)
+	if err != nil {
+		return err
+	}
+	for handle, sess := range m.sessions {
+		if sess.slot == s {
+			delete(m.sessions, handle)
+		}
+	}
+	return nil
+}
+
+// SessionInfo returns information about a session.
+func (m *Module) SessionInfo(sessionHandle uint) (SessionInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	sess, err := m.session(sessionHandle)
+	if err != nil {
+		return SessionInfo{}, err
+	}
+	state := CKS_RO_PUBLIC_SESSION
+	if sess.flags&CKF_RW_SESSION != 0 {
+		state = CKS_RW_PUBLIC_SESSION
+	}
+	return SessionInfo{SlotID: sess.slot.id, State: state, Flags: sess.flags}, nil
+}
+
+// Login checks the user type, but otherwise does nothing, as all objects of
+// the tokens are public and the keys are used with an empty password.
+func (m *Module) Login(sessionHandle uint, userType uint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, err := m.session(sessionHandle); err != nil {
+		return err
+	}
+	if userType != CKU_USER && userType != CKU_SO {
+		return CKR_USER_TYPE_INVALID
+	}
+	return nil
+}
+
+// Logout does nothing, see Login.
+func (m *Module) Logout(sessionHandle uint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, err := m.session(sessionHandle)
+	return err
+}
+
+// FindObjectsInit starts a search for the objects of the session's token
+// matching all the attributes of the template.
+func (m *Module) FindObjectsInit(sessionHandle uint, template []Attribute) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	sess, err := m.session(sessionHandle)
+	if err != nil {
+		return err
+	}
+	if sess.found != nil {
+		return CKR_OPERATION_ACTIVE
+	}
+	sess.found = []uint{}
+	for _, obj := range sess.slot.objects {
+		if obj.matches(template) {
+			sess.found = append(sess.found, obj.handle)
+		}
+	}
+	return nil
+}
+
+// FindObjects returns the handles of up to max more objects found by the
+// search of the session.
+func (m *Module) FindObjects(sessionHandle uint, max int) ([]uint, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	sess, err := m.session(sessionHandle)
+	if err != nil {
+		return nil, err
+	}
+	if sess.found == nil {
+		return nil, CKR_OPERATION_NOT_INITIALIZED
+	}
+	n := min(max, len(sess.found))
+	handles := sess.found[:n:n]
+	sess.found = sess.found[n:]
+	return handles, nil
+}
+
+// FindObjectsFinal ends the search of the session.
+func (m *Module) FindObjectsFinal(sessionHandle uint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	sess, err := m.session(sessionHandle)
+	if err != nil {
+		return err
+	}
+	if sess.found == nil {
+		return CKR_OPERATION_NOT_INITIALIZED
+	}
+	sess.found = nil
+	return nil
+}
+
+// GetAttributeValue returns the attributes of the given types of an object.
+// If the object does not have an attribute, its Value is nil, and
+// CKR_ATTRIBUTE_TYPE_INVALID is returned along with all the attributes.
+func (m *Module) GetAttributeValue(sessionHandle uint, objectHandle uint, types []uint) ([]Attribute, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	sess, err := m.session(sessionHandle)
+	if err != nil {
+		return nil, err
+	}
+	obj, err := sess.slot.object(objectHandle)
+	if err != nil {
+		return nil, err
+	}
+	attrs := make([]Attribute, len(types))
+	for i, typ := range types {
+		attrs[i] = Attribute{Type: typ, Value: obj.attrs[typ]}
+		if attrs[i].Value == nil {
+			err = CKR_ATTRIBUTE_TYPE_INVALID
+		}
+	}
+	return attrs, err
+}
+
+// SignInit starts a signing operation with a mechanism and the private key
+// object of the session's token.
+func (m *Module) SignInit(sessionHandle uint, mechanism uint, keyHandle uint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	sess, err := m.session(sessionHandle)
+	if err != nil {
+		return err
+	}
+	if sess.sign != nil {
+		return CKR_OPERATION_ACTIVE
+	}
+	if keyHandle != sess.slot.objects[privateKeyObject].handle {
+		return CKR_KEY_HANDLE_INVALID
+	}
+	if !sess.slot.supports(mechanism) {
+		return CKR_MECHANISM_INVALID
+	}
+	sess.sign = &signOperation{mechanism: mechanism}
+	return nil
+}
+
+// SignatureLength returns the length of the signatures of the session's
+// signing operation.
+func (m *Module) SignatureLength(sessionHandle uint) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	sess, err := m.session(sessionHandle)
+	if err != nil {
+		return 0, err
+	}
+	if sess.sign == nil {
+		return 0, CKR_OPERATION_NOT_INITIALIZED
+	}
+	return sess.slot.signatureLength(), nil
+}
+
+// Sign signs data, ending the session's signing operation. For the
+// mechanisms that do not hash the data (CKM_RSA_PKCS, CKM_RSA_PKCS_PSS and
+// CKM_ECDSA), the data must be hashed with the hash algorithm of the key's
+// signing scheme (in a DigestInfo for CKM_RSA_PKCS).
+func (m *Module) Sign(sessionHandle uint, data []byte) ([]byte, error) {
+	if err := m.SignUpdate(sessionHandle, data); err != nil {
+		return nil, err
+	}
+	return m.SignFinal(sessionHandle)
+}
+
+// SignUpdate adds data to the session's signing operation.
+func (m *Module) SignUpdate(sessionHandle uint, data []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	sess, err := m.session(sessionHandle)
+	if err != nil {
+		return err
+	}
+	if sess.sign == nil {
+		return CKR_OPERATION_NOT_INITIALIZED
+	}
+	sess.sign.data = append(sess.sign.data, data...)
+	return nil
+}
+
+// SignFinal signs the data added by SignUpdate, ending the session's signing
+// operation.
+func (m *Module) SignFinal(sessionHandle uint) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	sess, err := m.session(sessionHandle)
+	if err != nil {
+		return nil, err
+	}
+	if sess.sign == nil {
+		return nil, CKR_OPERATION_NOT_INITIALIZED
+	}
+	op := sess.sign
+	sess.sign = nil
+	return sess.slot.sign(op.mechanism, op.data)
+}
+
+// GenerateRandom returns n random bytes from the TPM.
+func (m *Module) GenerateRandom(sessionHandle uint, n int) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, err := m.session(sessionHandle); err != nil {
+		return nil, err
+	}
+	random := make([]byte, 0, n)
+	for len(random) < n {
+		// The TPM may return fewer bytes than requested.
+		b, err := tpm2.GetRandom(m.rw, uint16(min(n-len(random), 32)))
+		if err != nil {
+			return nil, err
+		}
+		if len(b) == 0 {
+			return nil, errors.New("TPM returned no random bytes")
+		}
+		random = append(random, b...)
+	}
+	return random, nil
+}
+
+func (m *Module) slot(slotID uint) (*slot, error) {
+	for _, s := range m.slots {
+		if s.id == slotID {
+			return s, nil
+		}
+	}
+	return nil, CKR_SLOT_ID_INVALID
+}
+
+func (m *Module) session(sessionHandle uint) (*session, error) {
+	sess, ok := m.sessions[sessionHandle]
+	if !ok {
+		return nil, CKR_SESSION_HANDLE_INVALID
+	}
+	return sess, nil
+}
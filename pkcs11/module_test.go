@@ -0,0 +1,437 @@
+package pkcs11_test
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"io"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/go-tpm/legacy/tpm2"
+	"github.com/google/go-tpm/tpmutil"
+
+	"github.com/google/go-tpm-tools/client"
+	"github.com/google/go-tpm-tools/internal/test"
+	"github.com/google/go-tpm-tools/pkcs11"
+)
+
+const (
+	testHandle      = tpmutil.Handle(0x81000100)
+	testOtherHandle = tpmutil.Handle(0x81000101)
+	testCertIndex   = 0x01500500
+)
+
+func signingKeyTemplate(scheme tpm2.Algorithm) tpm2.Public {
+	attrs := tpm2.FlagFixedTPM | tpm2.FlagFixedParent | tpm2.FlagSensitiveDataOrigin | tpm2.FlagUserWithAuth | tpm2.FlagSign
+	sigScheme := &tpm2.SigScheme{Alg: scheme, Hash: tpm2.AlgSHA256}
+	if scheme == tpm2.AlgECDSA {
+		return tpm2.Public{
+			Type:       tpm2.AlgECC,
+			NameAlg:    tpm2.AlgSHA256,
+			Attributes: attrs,
+			ECCParameters: &tpm2.ECCParams{
+				Sign:    sigScheme,
+				CurveID: tpm2.CurveNISTP256,
+			},
+		}
+	}
+	return tpm2.Public{
+		Type:       tpm2.AlgRSA,
+		NameAlg:    tpm2.AlgSHA256,
+		Attributes: attrs,
+		RSAParameters: &tpm2.RSAParams{
+			Sign:    sigScheme,
+			KeyBits: 2048,
+		},
+	}
+}
+
+// persistKey creates a key from template at the persistent handle.
+func persistKey(t *testing.T, rw io.ReadWriter, template tpm2.Public, handle tpmutil.Handle) crypto.PublicKey {
+	t.Helper()
+	key, err := client.NewCachedKey(rw, tpm2.HandleOwner, template, handle)
+	if err != nil {
+		t.Fatalf("failed to create persistent key: %v", err)
+	}
+	defer key.Close()
+	return key.PublicKey()
+}
+
+// writeCert writes a certificate for pub, issued by a software CA, to the NV
+// index.
+func writeCert(t *testing.T, rw io.ReadWriter, pub crypto.PublicKey, index uint32) *x509.Certificate {
+	t.Helper()
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1234),
+		Subject:      pkix.Name{CommonName: "TPM key"},
+		Issuer:       pkix.Name{CommonName: "Test CA"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, pub, caKey)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.NVDefine(rw, tpmutil.Handle(index), client.NVDefineOpts{
+		Size:       uint16(len(der)),
+		Attributes: tpm2.AttrOwnerRead | tpm2.AttrOwnerWrite,
+	}); err != nil {
+		t.Fatalf("NVDefine() failed: %v", err)
+	}
+	if err := client.NVWrite(rw, tpmutil.Handle(index), der, 0, client.NVAuth{Hierarchy: tpm2.HandleOwner}); err != nil {
+		t.Fatalf("NVWrite() failed: %v", err)
+	}
+	return cert
+}
+
+func openSession(t *testing.T, m *pkcs11.Module, slotID uint) uint {
+	t.Helper()
+	session, err := m.OpenSession(slotID, pkcs11.CKF_SERIAL_SESSION)
+	if err != nil {
+		t.Fatalf("OpenSession() failed: %v", err)
+	}
+	return session
+}
+
+func findObjects(t *testing.T, m *pkcs11.Module, session uint, template ...pkcs11.Attribute) []uint {
+	t.Helper()
+	if err := m.FindObjectsInit(session, template); err != nil {
+		t.Fatalf("FindObjectsInit() failed: %v", err)
+	}
+	defer m.FindObjectsFinal(session)
+	var found []uint
+	for {
+		objects, err := m.FindObjects(session, 1)
+		if err != nil {
+			t.Fatalf("FindObjects() failed: %v", err)
+		}
+		if len(objects) == 0 {
+			return found
+		}
+		found = append(found, objects...)
+	}
+}
+
+func verify(pub crypto.PublicKey, scheme tpm2.Algorithm, digest []byte, sig []byte) error {
+	switch pub := pub.(type) {
+	case *rsa.PublicKey:
+		if scheme == tpm2.AlgRSAPSS {
+			return rsa.VerifyPSS(pub, crypto.SHA256, digest, sig, nil)
+		}
+		return rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest, sig)
+	case *ecdsa.PublicKey:
+		if len(sig) != 64 {
+			return errors.New("invalid signature length")
+		}
+		if !ecdsa.Verify(pub, digest, new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:])) {
+			return errors.New("invalid signature")
+		}
+		return nil
+	}
+	return errors.New("unsupported key type")
+}
+
+func TestModuleSign(t *testing.T) {
+	test.SkipForRealTPM(t)
+	sha256DigestInfo := []byte{0x30, 0x31, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x01, 0x05, 0x00, 0x04, 0x20}
+	keys := []struct {
+		name            string
+		template        tpm2.Public
+		scheme          tpm2.Algorithm
+		hashMechanism   uint
+		digestMechanism uint
+	}{
+		{"RSASSA", signingKeyTemplate(tpm2.AlgRSASSA), tpm2.AlgRSASSA, pkcs11.CKM_SHA256_RSA_PKCS, pkcs11.CKM_RSA_PKCS},
+		{"RSAPSS", signingKeyTemplate(tpm2.AlgRSAPSS), tpm2.AlgRSAPSS, pkcs11.CKM_SHA256_RSA_PKCS_PSS, pkcs11.CKM_RSA_PKCS_PSS},
+		{"ECDSA", signingKeyTemplate(tpm2.AlgECDSA), tpm2.AlgECDSA, pkcs11.CKM_ECDSA_SHA256, pkcs11.CKM_ECDSA},
+		{"AKRSA", client.AKTemplateRSA(), tpm2.AlgRSASSA, pkcs11.CKM_SHA256_RSA_PKCS, 0},
+		{"AKECC", client.AKTemplateECC(), tpm2.AlgECDSA, pkcs11.CKM_ECDSA_SHA256, 0},
+	}
+	for _, k := range keys {
+		t.Run(k.name, func(t *testing.T) {
+			rwc := test.GetTPM(t)
+			defer client.CheckedClose(t, rwc)
+			pub := persistKey(t, rwc, k.template, testHandle)
+
+			m, err := pkcs11.NewModule(rwc, []pkcs11.SlotConfig{{Handle: testHandle}})
+			if err != nil {
+				t.Fatalf("NewModule() failed: %v", err)
+			}
+			defer m.Close()
+			slotID := uint(testHandle)
+			wantMechanisms := []uint{k.hashMechanism}
+			if k.digestMechanism != 0 {
+				wantMechanisms = append(wantMechanisms, k.digestMechanism)
+			}
+			mechanisms, err := m.Mechanisms(slotID)
+			if err != nil {
+				t.Fatalf("Mechanisms() failed: %v", err)
+			}
+			if !reflect.DeepEqual(mechanisms, wantMechanisms) {
+				t.Errorf("Mechanisms() = %v, want %v", mechanisms, wantMechanisms)
+			}
+
+			session := openSession(t, m, slotID)
+			keys := findObjects(t, m, session, pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_PRIVATE_KEY))
+			if len(keys) != 1 {
+				t.Fatalf("found %d private keys, want 1", len(keys))
+			}
+
+			data := []byte("data to sign")
+			digest := crypto.SHA256.New()
+			digest.Write(data)
+			inputs := map[uint][]byte{k.hashMechanism: data}
+			switch k.digestMechanism {
+			case pkcs11.CKM_RSA_PKCS:
+				inputs[k.digestMechanism] = append(sha256DigestInfo, digest.Sum(nil)...)
+			case pkcs11.CKM_RSA_PKCS_PSS, pkcs11.CKM_ECDSA:
+				inputs[k.digestMechanism] = digest.Sum(nil)
+			}
+			for _, mechanism := range mechanisms {
+				if err := m.SignInit(session, mechanism, keys[0]); err != nil {
+					t.Fatalf("SignInit(0x%x) failed: %v", mechanism, err)
+				}
+				length, err := m.SignatureLength(session)
+				if err != nil {
+					t.Fatalf("SignatureLength() failed: %v", err)
+				}
+				sig, err := m.Sign(session, inputs[mechanism])
+				if err != nil {
+					t.Fatalf("Sign() with mechanism 0x%x failed: %v", mechanism, err)
+				}
+				if len(sig) != length {
+					t.Errorf("signature length is %d, want %d", len(sig), length)
+				}
+				if err := verify(pub, k.scheme, digest.Sum(nil), sig); err != nil {
+					t.Errorf("signature with mechanism 0x%x did not verify: %v", mechanism, err)
+				}
+			}
+
+			// Multi-part signing with SignUpdate and SignFinal.
+			if err := m.SignInit(session, k.hashMechanism, keys[0]); err != nil {
+				t.Fatalf("SignInit() failed: %v", err)
+			}
+			for _, part := range [][]byte{data[:4], data[4:]} {
+				if err := m.SignUpdate(session, part); err != nil {
+					t.Fatalf("SignUpdate() failed: %v", err)
+				}
+			}
+			sig, err := m.SignFinal(session)
+			if err != nil {
+				t.Fatalf("SignFinal() failed: %v", err)
+			}
+			if err := verify(pub, k.scheme, digest.Sum(nil), sig); err != nil {
+				t.Errorf("multi-part signature did not verify: %v", err)
+			}
+			if _, err := m.SignFinal(session); !errors.Is(err, pkcs11.CKR_OPERATION_NOT_INITIALIZED) {
+				t.Errorf("SignFinal() after the operation ended: got %v, want %v", err, pkcs11.CKR_OPERATION_NOT_INITIALIZED)
+			}
+		})
+	}
+}
+
+func TestModuleObjects(t *testing.T) {
+	test.SkipForRealTPM(t)
+	rwc := test.GetTPM(t)
+	defer client.CheckedClose(t, rwc)
+	pub := persistKey(t, rwc, signingKeyTemplate(tpm2.AlgECDSA), testHandle).(*ecdsa.PublicKey)
+	cert := writeCert(t, rwc, pub, testCertIndex)
+
+	m, err := pkcs11.NewModule(rwc, []pkcs11.SlotConfig{{Handle: testHandle, Label: "signing key", CertNVIndex: testCertIndex}})
+	if err != nil {
+		t.Fatalf("NewModule() failed: %v", err)
+	}
+	defer m.Close()
+	slotID := uint(testHandle)
+	if slots := m.Slots(); !reflect.DeepEqual(slots, []uint{slotID}) {
+		t.Errorf("Slots() = %v, want [%v]", slots, slotID)
+	}
+	info, err := m.TokenInfo(slotID)
+	if err != nil {
+		t.Fatalf("TokenInfo() failed: %v", err)
+	}
+	if info.Label != "signing key" {
+		t.Errorf("token label is %q, want %q", info.Label, "signing key")
+	}
+
+	session := openSession(t, m, slotID)
+	id := pkcs11.NewAttribute(pkcs11.CKA_ID, []byte{0x81, 0x00, 0x01, 0x00})
+	if objects := findObjects(t, m, session, id); len(objects) != 3 {
+		t.Errorf("found %d objects with the key's ID, want 3", len(objects))
+	}
+	certs := findObjects(t, m, session, pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_CERTIFICATE), id)
+	if len(certs) != 1 {
+		t.Fatalf("found %d certificates, want 1", len(certs))
+	}
+	attrs, err := m.GetAttributeValue(session, certs[0], []uint{pkcs11.CKA_VALUE, pkcs11.CKA_SUBJECT, pkcs11.CKA_LABEL})
+	if err != nil {
+		t.Fatalf("GetAttributeValue() failed: %v", err)
+	}
+	want := []pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_VALUE, cert.Raw),
+		pkcs11.NewAttribute(pkcs11.CKA_SUBJECT, cert.RawSubject),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, "signing key"),
+	}
+	if !reflect.DeepEqual(attrs, want) {
+		t.Errorf("GetAttributeValue() = %v, want %v", attrs, want)
+	}
+
+	pubKeys := findObjects(t, m, session, pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_PUBLIC_KEY))
+	if len(pubKeys) != 1 {
+		t.Fatalf("found %d public keys, want 1", len(pubKeys))
+	}
+	attrs, err = m.GetAttributeValue(session, pubKeys[0], []uint{pkcs11.CKA_EC_POINT, pkcs11.CKA_MODULUS})
+	if !errors.Is(err, pkcs11.CKR_ATTRIBUTE_TYPE_INVALID) {
+		t.Errorf("GetAttributeValue() of an invalid type: got %v, want %v", err, pkcs11.CKR_ATTRIBUTE_TYPE_INVALID)
+	}
+	if attrs[1].Value != nil {
+		t.Errorf("got value %x for an invalid attribute type, want nil", attrs[1].Value)
+	}
+	var point []byte
+	if _, err := asn1.Unmarshal(attrs[0].Value, &point); err != nil {
+		t.Fatalf("failed to decode EC point: %v", err)
+	}
+	ecdhPub, err := pub.ECDH()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(point, ecdhPub.Bytes()) {
+		t.Errorf("EC point is %x, want %x", point, ecdhPub.Bytes())
+	}
+
+	privKeys := findObjects(t, m, session, pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_PRIVATE_KEY), pkcs11.NewAttribute(pkcs11.CKA_SIGN, true))
+	if len(privKeys) != 1 {
+		t.Fatalf("found %d private keys, want 1", len(privKeys))
+	}
+	attrs, err = m.GetAttributeValue(session, privKeys[0], []uint{pkcs11.CKA_KEY_TYPE, pkcs11.CKA_EXTRACTABLE, pkcs11.CKA_VALUE})
+	if !errors.Is(err, pkcs11.CKR_ATTRIBUTE_TYPE_INVALID) {
+		t.Errorf("GetAttributeValue() of the private value: got %v, want %v", err, pkcs11.CKR_ATTRIBUTE_TYPE_INVALID)
+	}
+	want = []pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_KEY_TYPE, pkcs11.CKK_EC),
+		pkcs11.NewAttribute(pkcs11.CKA_EXTRACTABLE, false),
+		{Type: pkcs11.CKA_VALUE},
+	}
+	if !reflect.DeepEqual(attrs, want) {
+		t.Errorf("GetAttributeValue() = %v, want %v", attrs, want)
+	}
+}
+
+func TestPersistentSlots(t *testing.T) {
+	test.SkipForRealTPM(t)
+	rwc := test.GetTPM(t)
+	defer client.CheckedClose(t, rwc)
+	persistKey(t, rwc, client.AKTemplateRSA(), testHandle)
+	persistKey(t, rwc, client.SRKTemplateRSA(), testOtherHandle)
+
+	slots, err := pkcs11.PersistentSlots(rwc)
+	if err != nil {
+		t.Fatalf("PersistentSlots() failed: %v", err)
+	}
+	want := []pkcs11.SlotConfig{{Handle: testHandle}}
+	if !reflect.DeepEqual(slots, want) {
+		t.Errorf("PersistentSlots() = %v, want %v", slots, want)
+	}
+	if _, err := pkcs11.NewModule(rwc, []pkcs11.SlotConfig{{Handle: testOtherHandle}}); err == nil {
+		t.Error("NewModule() with a storage key succeeded, expected failure")
+	}
+}
+
+func TestParseSlots(t *testing.T) {
+	slots, err := pkcs11.ParseSlots("0x81000001:0x01c10000, 0x81000002")
+	if err != nil {
+		t.Fatalf("ParseSlots() failed: %v", err)
+	}
+	want := []pkcs11.SlotConfig{
+		{Handle: 0x81000001, CertNVIndex: 0x01c10000},
+		{Handle: 0x81000002},
+	}
+	if !reflect.DeepEqual(slots, want) {
+		t.Errorf("ParseSlots() = %v, want %v", slots, want)
+	}
+	for _, s := range []string{"", "0x81000001:", "key", "0x81000001:index"} {
+		if _, err := pkcs11.ParseSlots(s); err == nil {
+			t.Errorf("ParseSlots(%q) succeeded, expected failure", s)
+		}
+	}
+}
+
+func TestModuleFail(t *testing.T) {
+	test.SkipForRealTPM(t)
+	rwc := test.GetTPM(t)
+	defer client.CheckedClose(t, rwc)
+	persistKey(t, rwc, client.AKTemplateRSA(), testHandle)
+	otherPub := persistKey(t, rwc, signingKeyTemplate(tpm2.AlgECDSA), testOtherHandle)
+	writeCert(t, rwc, otherPub, testCertIndex)
+
+	if _, err := pkcs11.NewModule(rwc, []pkcs11.SlotConfig{{Handle: testHandle, CertNVIndex: testCertIndex}}); err == nil {
+		t.Error("NewModule() with another key's certificate succeeded, expected failure")
+	}
+	m, err := pkcs11.NewModule(rwc, []pkcs11.SlotConfig{{Handle: testHandle}, {Handle: testOtherHandle}})
+	if err != nil {
+		t.Fatalf("NewModule() failed: %v", err)
+	}
+	defer m.Close()
+
+	if _, err := m.OpenSession(0x81000000, pkcs11.CKF_SERIAL_SESSION); !errors.Is(err, pkcs11.CKR_SLOT_ID_INVALID) {
+		t.Errorf("OpenSession() with an invalid slot: got %v, want %v", err, pkcs11.CKR_SLOT_ID_INVALID)
+	}
+	if _, err := m.OpenSession(uint(testHandle), 0); !errors.Is(err, pkcs11.CKR_SESSION_PARALLEL_NOT_SUPPORTED) {
+		t.Errorf("OpenSession() without CKF_SERIAL_SESSION: got %v, want %v", err, pkcs11.CKR_SESSION_PARALLEL_NOT_SUPPORTED)
+	}
+	session := openSession(t, m, uint(testHandle))
+	otherSession := openSession(t, m, uint(testOtherHandle))
+	key := findObjects(t, m, session, pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_PRIVATE_KEY))[0]
+	otherKey := findObjects(t, m, otherSession, pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_PRIVATE_KEY))[0]
+
+	if err := m.SignInit(session, pkcs11.CKM_RSA_PKCS, key); !errors.Is(err, pkcs11.CKR_MECHANISM_INVALID) {
+		t.Errorf("SignInit() with a restricted key and CKM_RSA_PKCS: got %v, want %v", err, pkcs11.CKR_MECHANISM_INVALID)
+	}
+	if err := m.SignInit(session, pkcs11.CKM_SHA256_RSA_PKCS, otherKey); !errors.Is(err, pkcs11.CKR_KEY_HANDLE_INVALID) {
+		t.Errorf("SignInit() with another token's key: got %v, want %v", err, pkcs11.CKR_KEY_HANDLE_INVALID)
+	}
+	if _, err := m.Sign(session, []byte("data")); !errors.Is(err, pkcs11.CKR_OPERATION_NOT_INITIALIZED) {
+		t.Errorf("Sign() without SignInit(): got %v, want %v", err, pkcs11.CKR_OPERATION_NOT_INITIALIZED)
+	}
+	if err := m.SignInit(otherSession, pkcs11.CKM_ECDSA, otherKey); err != nil {
+		t.Fatalf("SignInit() failed: %v", err)
+	}
+	if _, err := m.Sign(otherSession, []byte("not a digest")); !errors.Is(err, pkcs11.CKR_DATA_LEN_RANGE) {
+		t.Errorf("Sign() of an invalid digest: got %v, want %v", err, pkcs11.CKR_DATA_LEN_RANGE)
+	}
+
+	if err := m.CloseAllSessions(uint(testHandle)); err != nil {
+		t.Fatalf("CloseAllSessions() failed: %v", err)
+	}
+	if _, err := m.SessionInfo(session); !errors.Is(err, pkcs11.CKR_SESSION_HANDLE_INVALID) {
+		t.Errorf("SessionInfo() of a closed session: got %v, want %v", err, pkcs11.CKR_SESSION_HANDLE_INVALID)
+	}
+	if _, err := m.SessionInfo(otherSession); err != nil {
+		t.Errorf("SessionInfo() of another token's session failed: %v", err)
+	}
+	random, err := m.GenerateRandom(otherSession, 100)
+	if err != nil {
+		t.Fatalf("GenerateRandom() failed: %v", err)
+	}
+	if len(random) != 100 {
+		t.Errorf("GenerateRandom() returned %d bytes, want 100", len(random))
+	}
+}
@@ -0,0 +1,317 @@
+package pkcs11
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/google/go-tpm-tools/client"
+	"github.com/google/go-tpm-tools/internal"
+	"github.com/google/go-tpm/legacy/tpm2"
+	"github.com/google/go-tpm/tpmutil"
+)
+
+// Indices of a token's objects in slot.objects.
+const (
+	privateKeyObject = iota
+	publicKeyObject
+	certificateObject
+)
+
+// hashMechanisms are the mechanisms hashing the data, by signature scheme and
+// hash algorithm. These work with restricted keys.
+var hashMechanisms = map[tpm2.Algorithm]map[crypto.Hash]uint{
+	tpm2.AlgRSASSA: {
+		crypto.SHA1:   CKM_SHA1_RSA_PKCS,
+		crypto.SHA256: CKM_SHA256_RSA_PKCS,
+		crypto.SHA384: CKM_SHA384_RSA_PKCS,
+		crypto.SHA512: CKM_SHA512_RSA_PKCS,
+	},
+	tpm2.AlgRSAPSS: {
+		crypto.SHA1:   CKM_SHA1_RSA_PKCS_PSS,
+		crypto.SHA256: CKM_SHA256_RSA_PKCS_PSS,
+		crypto.SHA384: CKM_SHA384_RSA_PKCS_PSS,
+		crypto.SHA512: CKM_SHA512_RSA_PKCS_PSS,
+	},
+	tpm2.AlgECDSA: {
+		crypto.SHA1:   CKM_ECDSA_SHA1,
+		crypto.SHA256: CKM_ECDSA_SHA256,
+		crypto.SHA384: CKM_ECDSA_SHA384,
+		crypto.SHA512: CKM_ECDSA_SHA512,
+	},
+}
+
+// digestMechanisms are the mechanisms signing a digest, by signature scheme.
+// These only work with unrestricted keys.
+var digestMechanisms = map[tpm2.Algorithm]uint{
+	tpm2.AlgRSASSA: CKM_RSA_PKCS,
+	tpm2.AlgRSAPSS: CKM_RSA_PKCS_PSS,
+	tpm2.AlgECDSA:  CKM_ECDSA,
+}
+
+// digestInfoPrefixes are the DER encoded DigestInfo prefixes of the digests
+// signed with CKM_RSA_PKCS, see RFC 8017 section 9.2.
+var digestInfoPrefixes = map[crypto.Hash][]byte{
+	crypto.SHA1:   {0x30, 0x21, 0x30, 0x09, 0x06, 0x05, 0x2b, 0x0e, 0x03, 0x02, 0x1a, 0x05, 0x00, 0x04, 0x14},
+	crypto.SHA256: {0x30, 0x31, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x01, 0x05, 0x00, 0x04, 0x20},
+	crypto.SHA384: {0x30, 0x41, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x02, 0x05, 0x00, 0x04, 0x30},
+	crypto.SHA512: {0x30, 0x51, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x03, 0x05, 0x00, 0x04, 0x40},
+}
+
+// curveOIDs are the named curves of CKA_EC_PARAMS, see RFC 5480.
+var curveOIDs = map[elliptic.Curve]asn1.ObjectIdentifier{
+	elliptic.P224(): {1, 3, 132, 0, 33},
+	elliptic.P256(): {1, 2, 840, 10045, 3, 1, 7},
+	elliptic.P384(): {1, 3, 132, 0, 34},
+	elliptic.P521(): {1, 3, 132, 0, 35},
+}
+
+type slot struct {
+	id      uint
+	label   string
+	key     *client.Key
+	scheme  tpm2.Algorithm
+	hash    crypto.Hash
+	keyBits uint
+	// signer is nil for restricted keys.
+	signer     crypto.Signer
+	mechanisms []uint
+	objects    []*object
+}
+
+// signingScheme returns the signature scheme and hash algorithm of a key,
+// failing if the key cannot be used by a Module.
+func signingScheme(pub tpm2.Public) (tpm2.Algorithm, crypto.Hash, error) {
+	hashAlg, err := internal.GetSigningHashAlg(pub)
+	if err != nil {
+		return tpm2.AlgNull, 0, err
+	}
+	hash, err := hashAlg.Hash()
+	if err != nil {
+		return tpm2.AlgNull, 0, err
+	}
+	var scheme tpm2.Algorithm
+	if pub.Type == tpm2.AlgRSA {
+		scheme = pub.RSAParameters.Sign.Alg
+	} else {
+		scheme = pub.ECCParameters.Sign.Alg
+	}
+	if _, ok := hashMechanisms[scheme][hash]; !ok {
+		return tpm2.AlgNull, 0, fmt.Errorf("unsupported signing scheme: %v with %v", scheme, hash)
+	}
+	if pub.Attributes&tpm2.FlagUserWithAuth == 0 {
+		return tpm2.AlgNull, 0, errors.New("key must allow password authorization")
+	}
+	return scheme, hash, nil
+}
+
+func newSlot(rw io.ReadWriter, config SlotConfig, index int) (s *slot, err error) {
+	key, err := client.LoadCachedKey(rw, config.Handle, client.NullSession{})
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			key.Close()
+		}
+	}()
+	s = &slot{id: uint(config.Handle), label: config.Label, key: key}
+	if s.label == "" {
+		s.label = fmt.Sprintf("0x%08x", s.id)
+	}
+	if s.scheme, s.hash, err = signingScheme(key.PublicArea()); err != nil {
+		return nil, err
+	}
+	s.mechanisms = []uint{hashMechanisms[s.scheme][s.hash]}
+	if key.PublicArea().Attributes&tpm2.FlagRestricted == 0 {
+		if s.signer, err = key.GetSigner(); err != nil {
+			return nil, err
+		}
+		s.mechanisms = append(s.mechanisms, digestMechanisms[s.scheme])
+	}
+
+	if config.CertNVIndex != 0 {
+		certASN1, err := client.NVRead(rw, tpmutil.Handle(config.CertNVIndex), client.NVAuth{Hierarchy: tpm2.HandleOwner})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read certificate: %w", err)
+		}
+		cert, err := x509.ParseCertificate(certASN1)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse certificate: %w", err)
+		}
+		if err := key.SetCert(cert); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.addObjects(index); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// addObjects adds the token's objects. The handle of an object is unique to
+// the slot's index in the Module and the object's index in the slot.
+func (s *slot) addObjects(index int) error {
+	common := map[uint][]byte{
+		CKA_TOKEN:      encodeBool(true),
+		CKA_PRIVATE:    encodeBool(false),
+		CKA_MODIFIABLE: encodeBool(false),
+		CKA_LABEL:      []byte(s.label),
+		CKA_ID:         binary.BigEndian.AppendUint32(nil, uint32(s.id)),
+	}
+	keyAttrs := map[uint][]byte{
+		CKA_LOCAL:  encodeBool(true),
+		CKA_DERIVE: encodeBool(false),
+	}
+	pubKeyAttrs := map[uint][]byte{}
+	switch pub := s.key.PublicKey().(type) {
+	case *rsa.PublicKey:
+		keyAttrs[CKA_KEY_TYPE] = encodeULong(CKK_RSA)
+		keyAttrs[CKA_MODULUS] = pub.N.Bytes()
+		keyAttrs[CKA_PUBLIC_EXPONENT] = big.NewInt(int64(pub.E)).Bytes()
+		s.keyBits = uint(pub.N.BitLen())
+		pubKeyAttrs[CKA_MODULUS_BITS] = encodeULong(s.keyBits)
+	case *ecdsa.PublicKey:
+		oid, ok := curveOIDs[pub.Curve]
+		if !ok {
+			return fmt.Errorf("unsupported curve: %v", pub.Curve.Params().Name)
+		}
+		params, err := asn1.Marshal(oid)
+		if err != nil {
+			return err
+		}
+		ecdhPub, err := pub.ECDH()
+		if err != nil {
+			return err
+		}
+		point, err := asn1.Marshal(ecdhPub.Bytes())
+		if err != nil {
+			return err
+		}
+		keyAttrs[CKA_KEY_TYPE] = encodeULong(CKK_EC)
+		keyAttrs[CKA_EC_PARAMS] = params
+		s.keyBits = uint(pub.Curve.Params().BitSize)
+		pubKeyAttrs[CKA_EC_POINT] = point
+	default:
+		return fmt.Errorf("unsupported key type: %T", pub)
+	}
+
+	privateKey := newObject(index, privateKeyObject, common, keyAttrs, map[uint][]byte{
+		CKA_CLASS:               encodeULong(CKO_PRIVATE_KEY),
+		CKA_SIGN:                encodeBool(true),
+		CKA_SIGN_RECOVER:        encodeBool(false),
+		CKA_DECRYPT:             encodeBool(false),
+		CKA_UNWRAP:              encodeBool(false),
+		CKA_SENSITIVE:           encodeBool(true),
+		CKA_ALWAYS_SENSITIVE:    encodeBool(true),
+		CKA_EXTRACTABLE:         encodeBool(false),
+		CKA_NEVER_EXTRACTABLE:   encodeBool(true),
+		CKA_ALWAYS_AUTHENTICATE: encodeBool(false),
+	})
+	publicKey := newObject(index, publicKeyObject, common, keyAttrs, pubKeyAttrs, map[uint][]byte{
+		CKA_CLASS:   encodeULong(CKO_PUBLIC_KEY),
+		CKA_VERIFY:  encodeBool(true),
+		CKA_ENCRYPT: encodeBool(false),
+		CKA_WRAP:    encodeBool(false),
+	})
+	s.objects = []*object{privateKey, publicKey}
+
+	if cert := s.key.Cert(); cert != nil {
+		serial, err := asn1.Marshal(cert.SerialNumber)
+		if err != nil {
+			return err
+		}
+		s.objects = append(s.objects, newObject(index, certificateObject, common, map[uint][]byte{
+			CKA_CLASS:            encodeULong(CKO_CERTIFICATE),
+			CKA_CERTIFICATE_TYPE: encodeULong(CKC_X_509),
+			CKA_VALUE:            cert.Raw,
+			CKA_SUBJECT:          cert.RawSubject,
+			CKA_ISSUER:           cert.RawIssuer,
+			CKA_SERIAL_NUMBER:    serial,
+		}))
+	}
+	return nil
+}
+
+func (s *slot) object(handle uint) (*object, error) {
+	for _, obj := range s.objects {
+		if obj.handle == handle {
+			return obj, nil
+		}
+	}
+	return nil, CKR_OBJECT_HANDLE_INVALID
+}
+
+func (s *slot) supports(mechanism uint) bool {
+	for _, m := range s.mechanisms {
+		if m == mechanism {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *slot) signatureLength() int {
+	if s.scheme == tpm2.AlgECDSA {
+		return 2 * int((s.keyBits+7)/8)
+	}
+	return int((s.keyBits + 7) / 8)
+}
+
+// sign signs the data with a supported mechanism, returning the signature in
+// the PKCS #11 format.
+func (s *slot) sign(mechanism uint, data []byte) ([]byte, error) {
+	var sig []byte
+	var err error
+	if mechanism == hashMechanisms[s.scheme][s.hash] {
+		sig, err = s.key.SignData(data)
+	} else {
+		sig, err = s.signDigest(data)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if s.scheme != tpm2.AlgECDSA {
+		return sig, nil
+	}
+
+	// PKCS #11 ECDSA signatures are r || s, instead of the ASN.1 encoding.
+	var ecdsaSig struct{ R, S *big.Int }
+	if _, err := asn1.Unmarshal(sig, &ecdsaSig); err != nil {
+		return nil, fmt.Errorf("failed to decode ECDSA signature: %w", err)
+	}
+	size := s.signatureLength() / 2
+	raw := make([]byte, 2*size)
+	ecdsaSig.R.FillBytes(raw[:size])
+	ecdsaSig.S.FillBytes(raw[size:])
+	return raw, nil
+}
+
+// signDigest signs a digest, which is in a DigestInfo for CKM_RSA_PKCS.
+func (s *slot) signDigest(digest []byte) ([]byte, error) {
+	var opts crypto.SignerOpts = s.hash
+	switch s.scheme {
+	case tpm2.AlgRSASSA:
+		prefix := digestInfoPrefixes[s.hash]
+		if !bytes.HasPrefix(digest, prefix) {
+			return nil, CKR_DATA_INVALID
+		}
+		digest = digest[len(prefix):]
+	case tpm2.AlgRSAPSS:
+		opts = &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthAuto, Hash: s.hash}
+	}
+	if len(digest) != s.hash.Size() {
+		return nil, CKR_DATA_LEN_RANGE
+	}
+	return s.signer.Sign(nil, digest, opts)
+}
@@ -365,6 +365,9 @@ type Attestation struct {
 	//	*Attestation_SevSnpAttestation
 	//	*Attestation_TdxAttestation
 	TeeAttestation isAttestation_TeeAttestation `protobuf_oneof:"tee_attestation"`
+	// Linux IMA runtime measurement list, in either the binary or ASCII format.
+	// Optional.
+	ImaMeasurementList []byte `protobuf:"bytes,10,opt,name=ima_measurement_list,json=imaMeasurementList,proto3" json:"ima_measurement_list,omitempty"`
 }
 
 func (x *Attestation) Reset() {
@@ -469,6 +472,13 @@ func (x *Attestation) GetTdxAttestation() *tdx.QuoteV4 {
 	return nil
 }
 
+func (x *Attestation) GetImaMeasurementList() []byte {
+	if x != nil {
+		return x.ImaMeasurementList
+	}
+	return nil
+}
+
 type isAttestation_TeeAttestation interface {
 	isAttestation_TeeAttestation()
 }
@@ -788,8 +798,9 @@ func (x *GrubState) GetCommands() []string {
 }
 
 // The state of the Linux kernel.
-// At the moment, parsing LinuxKernelState relies on parsing the GrubState.
-// To do so, use ParseOpts{Loader: GRUB} when calling ParseMachineState.
+// At the moment, parsing LinuxKernelState relies on parsing the GrubState or
+// the SystemdBootState. To do so, use ParseOpts{Loader: GRUB} or
+// ParseOpts{Loader: SystemdBoot} when calling ParseMachineState.
 type LinuxKernelState struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -838,6 +849,136 @@ func (x *LinuxKernelState) GetCommandLine() string {
 	return ""
 }
 
+// A blob measured by systemd-boot or systemd-stub.
+type SystemdMeasurement struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// For UKI sections, the PE section name (e.g. ".linux"), which is verified
+	// against a separate measurement of the name. Otherwise, the untrusted event
+	// description (e.g. "Credentials initrd").
+	Description string `protobuf:"bytes,1,opt,name=description,proto3" json:"description,omitempty"`
+	// The digest of the measured blob (e.g. the section contents).
+	Digest []byte `protobuf:"bytes,2,opt,name=digest,proto3" json:"digest,omitempty"`
+}
+
+func (x *SystemdMeasurement) Reset() {
+	*x = SystemdMeasurement{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_attest_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SystemdMeasurement) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SystemdMeasurement) ProtoMessage() {}
+
+func (x *SystemdMeasurement) ProtoReflect() protoreflect.Message {
+	mi := &file_attest_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SystemdMeasurement.ProtoReflect.Descriptor instead.
+func (*SystemdMeasurement) Descriptor() ([]byte, []int) {
+	return file_attest_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *SystemdMeasurement) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *SystemdMeasurement) GetDigest() []byte {
+	if x != nil {
+		return x.Digest
+	}
+	return nil
+}
+
+// The state of a Linux boot using systemd-boot and/or a Unified Kernel Image
+// (UKI) with systemd-stub. See https://systemd.io/TPM2_PCR_MEASUREMENTS/.
+type SystemdBootState struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The UKI PE sections (e.g. .linux, .osrel, .cmdline, .initrd, .uname)
+	// measured by systemd-stub into PCR11, in measurement order.
+	UkiSections []*SystemdMeasurement `protobuf:"bytes,1,rep,name=uki_sections,json=ukiSections,proto3" json:"uki_sections,omitempty"`
+	// The digests of the initrds measured by the Linux EFI stub into PCR9.
+	InitrdDigests [][]byte `protobuf:"bytes,2,rep,name=initrd_digests,json=initrdDigests,proto3" json:"initrd_digests,omitempty"`
+	// Credentials, system extensions and other resources measured by
+	// systemd-stub into PCR12.
+	Extensions []*SystemdMeasurement `protobuf:"bytes,3,rep,name=extensions,proto3" json:"extensions,omitempty"`
+}
+
+func (x *SystemdBootState) Reset() {
+	*x = SystemdBootState{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_attest_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SystemdBootState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SystemdBootState) ProtoMessage() {}
+
+func (x *SystemdBootState) ProtoReflect() protoreflect.Message {
+	mi := &file_attest_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SystemdBootState.ProtoReflect.Descriptor instead.
+func (*SystemdBootState) Descriptor() ([]byte, []int) {
+	return file_attest_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *SystemdBootState) GetUkiSections() []*SystemdMeasurement {
+	if x != nil {
+		return x.UkiSections
+	}
+	return nil
+}
+
+func (x *SystemdBootState) GetInitrdDigests() [][]byte {
+	if x != nil {
+		return x.InitrdDigests
+	}
+	return nil
+}
+
+func (x *SystemdBootState) GetExtensions() []*SystemdMeasurement {
+	if x != nil {
+		return x.Extensions
+	}
+	return nil
+}
+
 // A parsed event from the TCG event log
 type Event struct {
 	state         protoimpl.MessageState
@@ -862,7 +1003,7 @@ type Event struct {
 func (x *Event) Reset() {
 	*x = Event{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_attest_proto_msgTypes[7]
+		mi := &file_attest_proto_msgTypes[9]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -875,7 +1016,7 @@ func (x *Event) String() string {
 func (*Event) ProtoMessage() {}
 
 func (x *Event) ProtoReflect() protoreflect.Message {
-	mi := &file_attest_proto_msgTypes[7]
+	mi := &file_attest_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -888,7 +1029,7 @@ func (x *Event) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Event.ProtoReflect.Descriptor instead.
 func (*Event) Descriptor() ([]byte, []int) {
-	return file_attest_proto_rawDescGZIP(), []int{7}
+	return file_attest_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *Event) GetPcrIndex() uint32 {
@@ -945,7 +1086,7 @@ type Certificate struct {
 func (x *Certificate) Reset() {
 	*x = Certificate{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_attest_proto_msgTypes[8]
+		mi := &file_attest_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -958,7 +1099,7 @@ func (x *Certificate) String() string {
 func (*Certificate) ProtoMessage() {}
 
 func (x *Certificate) ProtoReflect() protoreflect.Message {
-	mi := &file_attest_proto_msgTypes[8]
+	mi := &file_attest_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -971,7 +1112,7 @@ func (x *Certificate) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Certificate.ProtoReflect.Descriptor instead.
 func (*Certificate) Descriptor() ([]byte, []int) {
-	return file_attest_proto_rawDescGZIP(), []int{8}
+	return file_attest_proto_rawDescGZIP(), []int{10}
 }
 
 func (m *Certificate) GetRepresentation() isCertificate_Representation {
@@ -1026,7 +1167,7 @@ type Database struct {
 func (x *Database) Reset() {
 	*x = Database{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_attest_proto_msgTypes[9]
+		mi := &file_attest_proto_msgTypes[11]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1039,7 +1180,7 @@ func (x *Database) String() string {
 func (*Database) ProtoMessage() {}
 
 func (x *Database) ProtoReflect() protoreflect.Message {
-	mi := &file_attest_proto_msgTypes[9]
+	mi := &file_attest_proto_msgTypes[11]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1052,7 +1193,7 @@ func (x *Database) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Database.ProtoReflect.Descriptor instead.
 func (*Database) Descriptor() ([]byte, []int) {
-	return file_attest_proto_rawDescGZIP(), []int{9}
+	return file_attest_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *Database) GetCerts() []*Certificate {
@@ -1093,7 +1234,7 @@ type SecureBootState struct {
 func (x *SecureBootState) Reset() {
 	*x = SecureBootState{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_attest_proto_msgTypes[10]
+		mi := &file_attest_proto_msgTypes[12]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1106,7 +1247,7 @@ func (x *SecureBootState) String() string {
 func (*SecureBootState) ProtoMessage() {}
 
 func (x *SecureBootState) ProtoReflect() protoreflect.Message {
-	mi := &file_attest_proto_msgTypes[10]
+	mi := &file_attest_proto_msgTypes[12]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1119,7 +1260,7 @@ func (x *SecureBootState) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SecureBootState.ProtoReflect.Descriptor instead.
 func (*SecureBootState) Descriptor() ([]byte, []int) {
-	return file_attest_proto_rawDescGZIP(), []int{10}
+	return file_attest_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *SecureBootState) GetEnabled() bool {
@@ -1164,44 +1305,54 @@ func (x *SecureBootState) GetKek() *Database {
 	return nil
 }
 
-type ContainerState struct {
+// The state of the shim first-stage bootloader, as measured by shim into
+// PCR7 and PCR14. See https://github.com/rhboot/shim/blob/main/README.tpm.
+type ShimState struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	ImageReference string `protobuf:"bytes,1,opt,name=image_reference,json=imageReference,proto3" json:"image_reference,omitempty"`
-	// Digest of the registry's image manifest, which contains a list of the
-	// layers comprising the image.
-	ImageDigest   string        `protobuf:"bytes,2,opt,name=image_digest,json=imageDigest,proto3" json:"image_digest,omitempty"`
-	RestartPolicy RestartPolicy `protobuf:"varint,3,opt,name=restart_policy,json=restartPolicy,proto3,enum=attest.RestartPolicy" json:"restart_policy,omitempty"`
-	// Digest of the local image configuration object, containing config items
-	// such as local layer digests.
-	ImageId string            `protobuf:"bytes,4,opt,name=image_id,json=imageId,proto3" json:"image_id,omitempty"`
-	Args    []string          `protobuf:"bytes,5,rep,name=args,proto3" json:"args,omitempty"`
-	EnvVars map[string]string `protobuf:"bytes,6,rep,name=env_vars,json=envVars,proto3" json:"env_vars,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-	// Record operator input Env Vars and Args, should be subsets of the above
-	// Env Vars and Args.
-	OverriddenArgs    []string          `protobuf:"bytes,7,rep,name=overridden_args,json=overriddenArgs,proto3" json:"overridden_args,omitempty"`
-	OverriddenEnvVars map[string]string `protobuf:"bytes,8,rep,name=overridden_env_vars,json=overriddenEnvVars,proto3" json:"overridden_env_vars,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-}
-
-func (x *ContainerState) Reset() {
-	*x = ContainerState{}
+	// The SBAT revocation level (SbatLevel) enforced by shim, such as
+	// "sbat,1,2021030218\n".
+	SbatLevel string `protobuf:"bytes,1,opt,name=sbat_level,json=sbatLevel,proto3" json:"sbat_level,omitempty"`
+	// Shim's built-in vendor certificates and hashes that were used to verify
+	// booted components.
+	VendorAuthority *Database `protobuf:"bytes,2,opt,name=vendor_authority,json=vendorAuthority,proto3" json:"vendor_authority,omitempty"`
+	// Machine Owner Key (MOK) certificates and hashes that were used to verify
+	// booted components. Any entry here was enrolled by the machine's owner
+	// rather than built into shim or the Secure Boot db.
+	MokAuthority *Database `protobuf:"bytes,3,opt,name=mok_authority,json=mokAuthority,proto3" json:"mok_authority,omitempty"`
+	// The digest of the MokList variable measured into PCR14. The contents of
+	// MokList are not in the event log, so this can only be compared against
+	// known-good values.
+	MokListDigest []byte `protobuf:"bytes,4,opt,name=mok_list_digest,json=mokListDigest,proto3" json:"mok_list_digest,omitempty"`
+	// The digest of the MokListX (forbidden MOK) variable measured into PCR14.
+	MokListXDigest []byte `protobuf:"bytes,5,opt,name=mok_list_x_digest,json=mokListXDigest,proto3" json:"mok_list_x_digest,omitempty"`
+	// Whether the MOK list is trusted by the kernel for its own keyrings
+	// (MokListTrusted).
+	MokListTrusted bool `protobuf:"varint,6,opt,name=mok_list_trusted,json=mokListTrusted,proto3" json:"mok_list_trusted,omitempty"`
+	// Whether shim's image validation was disabled by the machine's owner
+	// (MokSBState).
+	ValidationDisabled bool `protobuf:"varint,7,opt,name=validation_disabled,json=validationDisabled,proto3" json:"validation_disabled,omitempty"`
+}
+
+func (x *ShimState) Reset() {
+	*x = ShimState{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_attest_proto_msgTypes[11]
+		mi := &file_attest_proto_msgTypes[13]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *ContainerState) String() string {
+func (x *ShimState) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ContainerState) ProtoMessage() {}
+func (*ShimState) ProtoMessage() {}
 
-func (x *ContainerState) ProtoReflect() protoreflect.Message {
-	mi := &file_attest_proto_msgTypes[11]
+func (x *ShimState) ProtoReflect() protoreflect.Message {
+	mi := &file_attest_proto_msgTypes[13]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1212,94 +1363,101 @@ func (x *ContainerState) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ContainerState.ProtoReflect.Descriptor instead.
-func (*ContainerState) Descriptor() ([]byte, []int) {
-	return file_attest_proto_rawDescGZIP(), []int{11}
-}
-
-func (x *ContainerState) GetImageReference() string {
-	if x != nil {
-		return x.ImageReference
-	}
-	return ""
+// Deprecated: Use ShimState.ProtoReflect.Descriptor instead.
+func (*ShimState) Descriptor() ([]byte, []int) {
+	return file_attest_proto_rawDescGZIP(), []int{13}
 }
 
-func (x *ContainerState) GetImageDigest() string {
+func (x *ShimState) GetSbatLevel() string {
 	if x != nil {
-		return x.ImageDigest
+		return x.SbatLevel
 	}
 	return ""
 }
 
-func (x *ContainerState) GetRestartPolicy() RestartPolicy {
+func (x *ShimState) GetVendorAuthority() *Database {
 	if x != nil {
-		return x.RestartPolicy
+		return x.VendorAuthority
 	}
-	return RestartPolicy_Always
+	return nil
 }
 
-func (x *ContainerState) GetImageId() string {
+func (x *ShimState) GetMokAuthority() *Database {
 	if x != nil {
-		return x.ImageId
+		return x.MokAuthority
 	}
-	return ""
+	return nil
 }
 
-func (x *ContainerState) GetArgs() []string {
+func (x *ShimState) GetMokListDigest() []byte {
 	if x != nil {
-		return x.Args
+		return x.MokListDigest
 	}
 	return nil
 }
 
-func (x *ContainerState) GetEnvVars() map[string]string {
+func (x *ShimState) GetMokListXDigest() []byte {
 	if x != nil {
-		return x.EnvVars
+		return x.MokListXDigest
 	}
 	return nil
 }
 
-func (x *ContainerState) GetOverriddenArgs() []string {
+func (x *ShimState) GetMokListTrusted() bool {
 	if x != nil {
-		return x.OverriddenArgs
+		return x.MokListTrusted
 	}
-	return nil
+	return false
 }
 
-func (x *ContainerState) GetOverriddenEnvVars() map[string]string {
+func (x *ShimState) GetValidationDisabled() bool {
 	if x != nil {
-		return x.OverriddenEnvVars
+		return x.ValidationDisabled
 	}
-	return nil
+	return false
 }
 
-type SemanticVersion struct {
+// A single entry of the Linux Integrity Measurement Architecture (IMA) runtime
+// measurement list. See
+// https://www.kernel.org/doc/html/latest/security/IMA-templates.html.
+type ImaMeasurement struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Major uint32 `protobuf:"varint,1,opt,name=major,proto3" json:"major,omitempty"`
-	Minor uint32 `protobuf:"varint,2,opt,name=minor,proto3" json:"minor,omitempty"`
-	Patch uint32 `protobuf:"varint,3,opt,name=patch,proto3" json:"patch,omitempty"`
+	// The PCR the entry was extended into, usually 10.
+	PcrIndex uint32 `protobuf:"varint,1,opt,name=pcr_index,json=pcrIndex,proto3" json:"pcr_index,omitempty"`
+	// The IMA template describing the entry, such as "ima-ng" or "ima-sig".
+	Template string `protobuf:"bytes,2,opt,name=template,proto3" json:"template,omitempty"`
+	// The path of the measured file, or the name of the measured buffer for the
+	// "ima-buf" template. Empty for templates that are not understood.
+	Path string `protobuf:"bytes,3,opt,name=path,proto3" json:"path,omitempty"`
+	// The digest of the measured file or buffer.
+	Digest []byte `protobuf:"bytes,4,opt,name=digest,proto3" json:"digest,omitempty"`
+	// The hash algorithm of digest, as named by IMA (such as "sha256").
+	DigestAlgorithm string `protobuf:"bytes,5,opt,name=digest_algorithm,json=digestAlgorithm,proto3" json:"digest_algorithm,omitempty"`
+	// Whether this entry records a violation, such as a file being measured
+	// while it was open for writing. Such entries have no valid digest.
+	Violation bool `protobuf:"varint,6,opt,name=violation,proto3" json:"violation,omitempty"`
 }
 
-func (x *SemanticVersion) Reset() {
-	*x = SemanticVersion{}
+func (x *ImaMeasurement) Reset() {
+	*x = ImaMeasurement{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_attest_proto_msgTypes[12]
+		mi := &file_attest_proto_msgTypes[14]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *SemanticVersion) String() string {
+func (x *ImaMeasurement) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SemanticVersion) ProtoMessage() {}
+func (*ImaMeasurement) ProtoMessage() {}
 
-func (x *SemanticVersion) ProtoReflect() protoreflect.Message {
-	mi := &file_attest_proto_msgTypes[12]
+func (x *ImaMeasurement) ProtoReflect() protoreflect.Message {
+	mi := &file_attest_proto_msgTypes[14]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1310,58 +1468,79 @@ func (x *SemanticVersion) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SemanticVersion.ProtoReflect.Descriptor instead.
-func (*SemanticVersion) Descriptor() ([]byte, []int) {
-	return file_attest_proto_rawDescGZIP(), []int{12}
+// Deprecated: Use ImaMeasurement.ProtoReflect.Descriptor instead.
+func (*ImaMeasurement) Descriptor() ([]byte, []int) {
+	return file_attest_proto_rawDescGZIP(), []int{14}
 }
 
-func (x *SemanticVersion) GetMajor() uint32 {
+func (x *ImaMeasurement) GetPcrIndex() uint32 {
 	if x != nil {
-		return x.Major
+		return x.PcrIndex
 	}
 	return 0
 }
 
-func (x *SemanticVersion) GetMinor() uint32 {
+func (x *ImaMeasurement) GetTemplate() string {
 	if x != nil {
-		return x.Minor
+		return x.Template
 	}
-	return 0
+	return ""
 }
 
-func (x *SemanticVersion) GetPatch() uint32 {
+func (x *ImaMeasurement) GetPath() string {
 	if x != nil {
-		return x.Patch
+		return x.Path
 	}
-	return 0
+	return ""
 }
 
-type HealthMonitoringState struct {
+func (x *ImaMeasurement) GetDigest() []byte {
+	if x != nil {
+		return x.Digest
+	}
+	return nil
+}
+
+func (x *ImaMeasurement) GetDigestAlgorithm() string {
+	if x != nil {
+		return x.DigestAlgorithm
+	}
+	return ""
+}
+
+func (x *ImaMeasurement) GetViolation() bool {
+	if x != nil {
+		return x.Violation
+	}
+	return false
+}
+
+// The Linux IMA measurements made before the PCRs were quoted.
+type ImaState struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// Whether memory monitoring is enabled.
-	MemoryEnabled *bool `protobuf:"varint,1,opt,name=memory_enabled,json=memoryEnabled,proto3,oneof" json:"memory_enabled,omitempty"`
+	Measurements []*ImaMeasurement `protobuf:"bytes,1,rep,name=measurements,proto3" json:"measurements,omitempty"`
 }
 
-func (x *HealthMonitoringState) Reset() {
-	*x = HealthMonitoringState{}
+func (x *ImaState) Reset() {
+	*x = ImaState{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_attest_proto_msgTypes[13]
+		mi := &file_attest_proto_msgTypes[15]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *HealthMonitoringState) String() string {
+func (x *ImaState) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*HealthMonitoringState) ProtoMessage() {}
+func (*ImaState) ProtoMessage() {}
 
-func (x *HealthMonitoringState) ProtoReflect() protoreflect.Message {
-	mi := &file_attest_proto_msgTypes[13]
+func (x *ImaState) ProtoReflect() protoreflect.Message {
+	mi := &file_attest_proto_msgTypes[15]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1372,32 +1551,252 @@ func (x *HealthMonitoringState) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use HealthMonitoringState.ProtoReflect.Descriptor instead.
-func (*HealthMonitoringState) Descriptor() ([]byte, []int) {
-	return file_attest_proto_rawDescGZIP(), []int{13}
+// Deprecated: Use ImaState.ProtoReflect.Descriptor instead.
+func (*ImaState) Descriptor() ([]byte, []int) {
+	return file_attest_proto_rawDescGZIP(), []int{15}
 }
 
-func (x *HealthMonitoringState) GetMemoryEnabled() bool {
-	if x != nil && x.MemoryEnabled != nil {
-		return *x.MemoryEnabled
+func (x *ImaState) GetMeasurements() []*ImaMeasurement {
+	if x != nil {
+		return x.Measurements
 	}
-	return false
+	return nil
 }
 
-type GpuDeviceState struct {
+type ContainerState struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// Whether Confidential Computing mode is enabled for GPU.
-	CcMode GPUDeviceCCMode `protobuf:"varint,1,opt,name=cc_mode,json=ccMode,proto3,enum=attest.GPUDeviceCCMode" json:"cc_mode,omitempty"`
-}
-
-func (x *GpuDeviceState) Reset() {
-	*x = GpuDeviceState{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_attest_proto_msgTypes[14]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ImageReference string `protobuf:"bytes,1,opt,name=image_reference,json=imageReference,proto3" json:"image_reference,omitempty"`
+	// Digest of the registry's image manifest, which contains a list of the
+	// layers comprising the image.
+	ImageDigest   string        `protobuf:"bytes,2,opt,name=image_digest,json=imageDigest,proto3" json:"image_digest,omitempty"`
+	RestartPolicy RestartPolicy `protobuf:"varint,3,opt,name=restart_policy,json=restartPolicy,proto3,enum=attest.RestartPolicy" json:"restart_policy,omitempty"`
+	// Digest of the local image configuration object, containing config items
+	// such as local layer digests.
+	ImageId string            `protobuf:"bytes,4,opt,name=image_id,json=imageId,proto3" json:"image_id,omitempty"`
+	Args    []string          `protobuf:"bytes,5,rep,name=args,proto3" json:"args,omitempty"`
+	EnvVars map[string]string `protobuf:"bytes,6,rep,name=env_vars,json=envVars,proto3" json:"env_vars,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// Record operator input Env Vars and Args, should be subsets of the above
+	// Env Vars and Args.
+	OverriddenArgs    []string          `protobuf:"bytes,7,rep,name=overridden_args,json=overriddenArgs,proto3" json:"overridden_args,omitempty"`
+	OverriddenEnvVars map[string]string `protobuf:"bytes,8,rep,name=overridden_env_vars,json=overriddenEnvVars,proto3" json:"overridden_env_vars,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *ContainerState) Reset() {
+	*x = ContainerState{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_attest_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ContainerState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ContainerState) ProtoMessage() {}
+
+func (x *ContainerState) ProtoReflect() protoreflect.Message {
+	mi := &file_attest_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ContainerState.ProtoReflect.Descriptor instead.
+func (*ContainerState) Descriptor() ([]byte, []int) {
+	return file_attest_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ContainerState) GetImageReference() string {
+	if x != nil {
+		return x.ImageReference
+	}
+	return ""
+}
+
+func (x *ContainerState) GetImageDigest() string {
+	if x != nil {
+		return x.ImageDigest
+	}
+	return ""
+}
+
+func (x *ContainerState) GetRestartPolicy() RestartPolicy {
+	if x != nil {
+		return x.RestartPolicy
+	}
+	return RestartPolicy_Always
+}
+
+func (x *ContainerState) GetImageId() string {
+	if x != nil {
+		return x.ImageId
+	}
+	return ""
+}
+
+func (x *ContainerState) GetArgs() []string {
+	if x != nil {
+		return x.Args
+	}
+	return nil
+}
+
+func (x *ContainerState) GetEnvVars() map[string]string {
+	if x != nil {
+		return x.EnvVars
+	}
+	return nil
+}
+
+func (x *ContainerState) GetOverriddenArgs() []string {
+	if x != nil {
+		return x.OverriddenArgs
+	}
+	return nil
+}
+
+func (x *ContainerState) GetOverriddenEnvVars() map[string]string {
+	if x != nil {
+		return x.OverriddenEnvVars
+	}
+	return nil
+}
+
+type SemanticVersion struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Major uint32 `protobuf:"varint,1,opt,name=major,proto3" json:"major,omitempty"`
+	Minor uint32 `protobuf:"varint,2,opt,name=minor,proto3" json:"minor,omitempty"`
+	Patch uint32 `protobuf:"varint,3,opt,name=patch,proto3" json:"patch,omitempty"`
+}
+
+func (x *SemanticVersion) Reset() {
+	*x = SemanticVersion{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_attest_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SemanticVersion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SemanticVersion) ProtoMessage() {}
+
+func (x *SemanticVersion) ProtoReflect() protoreflect.Message {
+	mi := &file_attest_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SemanticVersion.ProtoReflect.Descriptor instead.
+func (*SemanticVersion) Descriptor() ([]byte, []int) {
+	return file_attest_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *SemanticVersion) GetMajor() uint32 {
+	if x != nil {
+		return x.Major
+	}
+	return 0
+}
+
+func (x *SemanticVersion) GetMinor() uint32 {
+	if x != nil {
+		return x.Minor
+	}
+	return 0
+}
+
+func (x *SemanticVersion) GetPatch() uint32 {
+	if x != nil {
+		return x.Patch
+	}
+	return 0
+}
+
+type HealthMonitoringState struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Whether memory monitoring is enabled.
+	MemoryEnabled *bool `protobuf:"varint,1,opt,name=memory_enabled,json=memoryEnabled,proto3,oneof" json:"memory_enabled,omitempty"`
+}
+
+func (x *HealthMonitoringState) Reset() {
+	*x = HealthMonitoringState{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_attest_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *HealthMonitoringState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HealthMonitoringState) ProtoMessage() {}
+
+func (x *HealthMonitoringState) ProtoReflect() protoreflect.Message {
+	mi := &file_attest_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HealthMonitoringState.ProtoReflect.Descriptor instead.
+func (*HealthMonitoringState) Descriptor() ([]byte, []int) {
+	return file_attest_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *HealthMonitoringState) GetMemoryEnabled() bool {
+	if x != nil && x.MemoryEnabled != nil {
+		return *x.MemoryEnabled
+	}
+	return false
+}
+
+type GpuDeviceState struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Whether Confidential Computing mode is enabled for GPU.
+	CcMode GPUDeviceCCMode `protobuf:"varint,1,opt,name=cc_mode,json=ccMode,proto3,enum=attest.GPUDeviceCCMode" json:"cc_mode,omitempty"`
+}
+
+func (x *GpuDeviceState) Reset() {
+	*x = GpuDeviceState{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_attest_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
@@ -1409,7 +1808,7 @@ func (x *GpuDeviceState) String() string {
 func (*GpuDeviceState) ProtoMessage() {}
 
 func (x *GpuDeviceState) ProtoReflect() protoreflect.Message {
-	mi := &file_attest_proto_msgTypes[14]
+	mi := &file_attest_proto_msgTypes[19]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1422,7 +1821,7 @@ func (x *GpuDeviceState) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GpuDeviceState.ProtoReflect.Descriptor instead.
 func (*GpuDeviceState) Descriptor() ([]byte, []int) {
-	return file_attest_proto_rawDescGZIP(), []int{14}
+	return file_attest_proto_rawDescGZIP(), []int{19}
 }
 
 func (x *GpuDeviceState) GetCcMode() GPUDeviceCCMode {
@@ -1432,35 +1831,734 @@ func (x *GpuDeviceState) GetCcMode() GPUDeviceCCMode {
 	return GPUDeviceCCMode_UNSET
 }
 
-type AttestedCosState struct {
+// An event measured by the workload after launch, such as the digest of a
+// loaded model or configuration.
+type WorkloadEvent struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Container        *ContainerState        `protobuf:"bytes,1,opt,name=container,proto3" json:"container,omitempty"`
-	CosVersion       *SemanticVersion       `protobuf:"bytes,2,opt,name=cos_version,json=cosVersion,proto3" json:"cos_version,omitempty"`
-	LauncherVersion  *SemanticVersion       `protobuf:"bytes,3,opt,name=launcher_version,json=launcherVersion,proto3" json:"launcher_version,omitempty"`
-	HealthMonitoring *HealthMonitoringState `protobuf:"bytes,4,opt,name=health_monitoring,json=healthMonitoring,proto3" json:"health_monitoring,omitempty"`
-	GpuDeviceState   *GpuDeviceState        `protobuf:"bytes,5,opt,name=gpu_device_state,json=gpuDeviceState,proto3" json:"gpu_device_state,omitempty"`
+	Name    string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Content []byte `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
 }
 
-func (x *AttestedCosState) Reset() {
-	*x = AttestedCosState{}
+func (x *WorkloadEvent) Reset() {
+	*x = WorkloadEvent{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_attest_proto_msgTypes[15]
+		mi := &file_attest_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WorkloadEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkloadEvent) ProtoMessage() {}
+
+func (x *WorkloadEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_attest_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkloadEvent.ProtoReflect.Descriptor instead.
+func (*WorkloadEvent) Descriptor() ([]byte, []int) {
+	return file_attest_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *WorkloadEvent) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *WorkloadEvent) GetContent() []byte {
+	if x != nil {
+		return x.Content
+	}
+	return nil
+}
+
+// A secret imported to the TPM by the launcher and mounted in the container.
+type ImportedSecret struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The name of the secret's file in the container.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// The SHA-256 digest of the secret's serialized ImportBlob.
+	BlobDigest []byte `protobuf:"bytes,2,opt,name=blob_digest,json=blobDigest,proto3" json:"blob_digest,omitempty"`
+}
+
+func (x *ImportedSecret) Reset() {
+	*x = ImportedSecret{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_attest_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ImportedSecret) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportedSecret) ProtoMessage() {}
+
+func (x *ImportedSecret) ProtoReflect() protoreflect.Message {
+	mi := &file_attest_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportedSecret.ProtoReflect.Descriptor instead.
+func (*ImportedSecret) Descriptor() ([]byte, []int) {
+	return file_attest_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ImportedSecret) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ImportedSecret) GetBlobDigest() []byte {
+	if x != nil {
+		return x.BlobDigest
+	}
+	return nil
+}
+
+type AttestedCosState struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Container        *ContainerState        `protobuf:"bytes,1,opt,name=container,proto3" json:"container,omitempty"`
+	CosVersion       *SemanticVersion       `protobuf:"bytes,2,opt,name=cos_version,json=cosVersion,proto3" json:"cos_version,omitempty"`
+	LauncherVersion  *SemanticVersion       `protobuf:"bytes,3,opt,name=launcher_version,json=launcherVersion,proto3" json:"launcher_version,omitempty"`
+	HealthMonitoring *HealthMonitoringState `protobuf:"bytes,4,opt,name=health_monitoring,json=healthMonitoring,proto3" json:"health_monitoring,omitempty"`
+	GpuDeviceState   *GpuDeviceState        `protobuf:"bytes,5,opt,name=gpu_device_state,json=gpuDeviceState,proto3" json:"gpu_device_state,omitempty"`
+	// Events measured by the workload, in measurement order.
+	WorkloadEvents []*WorkloadEvent `protobuf:"bytes,6,rep,name=workload_events,json=workloadEvents,proto3" json:"workload_events,omitempty"`
+	// Whether the workload locked its measurements, so no events can follow
+	// workload_events.
+	WorkloadEventsLocked bool `protobuf:"varint,7,opt,name=workload_events_locked,json=workloadEventsLocked,proto3" json:"workload_events_locked,omitempty"`
+	// Secrets imported for the workload, ordered by name.
+	Secrets []*ImportedSecret `protobuf:"bytes,8,rep,name=secrets,proto3" json:"secrets,omitempty"`
+}
+
+func (x *AttestedCosState) Reset() {
+	*x = AttestedCosState{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_attest_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AttestedCosState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AttestedCosState) ProtoMessage() {}
+
+func (x *AttestedCosState) ProtoReflect() protoreflect.Message {
+	mi := &file_attest_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AttestedCosState.ProtoReflect.Descriptor instead.
+func (*AttestedCosState) Descriptor() ([]byte, []int) {
+	return file_attest_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *AttestedCosState) GetContainer() *ContainerState {
+	if x != nil {
+		return x.Container
+	}
+	return nil
+}
+
+func (x *AttestedCosState) GetCosVersion() *SemanticVersion {
+	if x != nil {
+		return x.CosVersion
+	}
+	return nil
+}
+
+func (x *AttestedCosState) GetLauncherVersion() *SemanticVersion {
+	if x != nil {
+		return x.LauncherVersion
+	}
+	return nil
+}
+
+func (x *AttestedCosState) GetHealthMonitoring() *HealthMonitoringState {
+	if x != nil {
+		return x.HealthMonitoring
+	}
+	return nil
+}
+
+func (x *AttestedCosState) GetGpuDeviceState() *GpuDeviceState {
+	if x != nil {
+		return x.GpuDeviceState
+	}
+	return nil
+}
+
+func (x *AttestedCosState) GetWorkloadEvents() []*WorkloadEvent {
+	if x != nil {
+		return x.WorkloadEvents
+	}
+	return nil
+}
+
+func (x *AttestedCosState) GetWorkloadEventsLocked() bool {
+	if x != nil {
+		return x.WorkloadEventsLocked
+	}
+	return false
+}
+
+func (x *AttestedCosState) GetSecrets() []*ImportedSecret {
+	if x != nil {
+		return x.Secrets
+	}
+	return nil
+}
+
+type EfiApp struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The PE/COFF digest of the EFI application (pulled from the raw event digest).
+	Digest []byte `protobuf:"bytes,1,opt,name=digest,proto3" json:"digest,omitempty"`
+}
+
+func (x *EfiApp) Reset() {
+	*x = EfiApp{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_attest_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EfiApp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EfiApp) ProtoMessage() {}
+
+func (x *EfiApp) ProtoReflect() protoreflect.Message {
+	mi := &file_attest_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EfiApp.ProtoReflect.Descriptor instead.
+func (*EfiApp) Descriptor() ([]byte, []int) {
+	return file_attest_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *EfiApp) GetDigest() []byte {
+	if x != nil {
+		return x.Digest
+	}
+	return nil
+}
+
+// The verified state of EFI Applications. Policy usage on this machine state
+// should check the entire set of EFI App digests matches, not a subset.
+type EfiState struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// UEFI's OS Loader code is required to measure attempts to load and execute
+	// UEFI applications.
+	// UEFI applications are typically bootloaders such as shim and GRUB.
+	// These run and are measured using the UEFI LoadImage() service.
+	Apps []*EfiApp `protobuf:"bytes,1,rep,name=apps,proto3" json:"apps,omitempty"`
+}
+
+func (x *EfiState) Reset() {
+	*x = EfiState{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_attest_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EfiState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EfiState) ProtoMessage() {}
+
+func (x *EfiState) ProtoReflect() protoreflect.Message {
+	mi := &file_attest_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EfiState.ProtoReflect.Descriptor instead.
+func (*EfiState) Descriptor() ([]byte, []int) {
+	return file_attest_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *EfiState) GetApps() []*EfiApp {
+	if x != nil {
+		return x.Apps
+	}
+	return nil
+}
+
+// The verified state of a booted machine, obtained from an Attestation
+type MachineState struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Platform   *PlatformState   `protobuf:"bytes,1,opt,name=platform,proto3" json:"platform,omitempty"`
+	SecureBoot *SecureBootState `protobuf:"bytes,2,opt,name=secure_boot,json=secureBoot,proto3" json:"secure_boot,omitempty"`
+	// The complete parsed TCG Event Log, including those events used to
+	// create the PlatformState.
+	RawEvents []*Event `protobuf:"bytes,3,rep,name=raw_events,json=rawEvents,proto3" json:"raw_events,omitempty"`
+	// The hash algorithm used when verifying the Attestation. This indicates:
+	//   - which PCR bank was used for for quote validation and event log replay
+	//   - the hash algorithm used to calculate event digests
+	Hash        tpm.HashAlgo      `protobuf:"varint,4,opt,name=hash,proto3,enum=tpm.HashAlgo" json:"hash,omitempty"`
+	Grub        *GrubState        `protobuf:"bytes,5,opt,name=grub,proto3" json:"grub,omitempty"`
+	LinuxKernel *LinuxKernelState `protobuf:"bytes,6,opt,name=linux_kernel,json=linuxKernel,proto3" json:"linux_kernel,omitempty"`
+	Cos         *AttestedCosState `protobuf:"bytes,7,opt,name=cos,proto3" json:"cos,omitempty"`
+	Efi         *EfiState         `protobuf:"bytes,8,opt,name=efi,proto3" json:"efi,omitempty"`
+	// Attestation/Quote representations from TEEs whose signatures are already
+	// verified.
+	//
+	// Types that are assignable to TeeAttestation:
+	//
+	//	*MachineState_SevSnpAttestation
+	//	*MachineState_TdxAttestation
+	TeeAttestation isMachineState_TeeAttestation `protobuf_oneof:"tee_attestation"`
+	SystemdBoot    *SystemdBootState             `protobuf:"bytes,11,opt,name=systemd_boot,json=systemdBoot,proto3" json:"systemd_boot,omitempty"`
+	Shim           *ShimState                    `protobuf:"bytes,12,opt,name=shim,proto3" json:"shim,omitempty"`
+	Ima            *ImaState                     `protobuf:"bytes,13,opt,name=ima,proto3" json:"ima,omitempty"`
+}
+
+func (x *MachineState) Reset() {
+	*x = MachineState{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_attest_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MachineState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MachineState) ProtoMessage() {}
+
+func (x *MachineState) ProtoReflect() protoreflect.Message {
+	mi := &file_attest_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MachineState.ProtoReflect.Descriptor instead.
+func (*MachineState) Descriptor() ([]byte, []int) {
+	return file_attest_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *MachineState) GetPlatform() *PlatformState {
+	if x != nil {
+		return x.Platform
+	}
+	return nil
+}
+
+func (x *MachineState) GetSecureBoot() *SecureBootState {
+	if x != nil {
+		return x.SecureBoot
+	}
+	return nil
+}
+
+func (x *MachineState) GetRawEvents() []*Event {
+	if x != nil {
+		return x.RawEvents
+	}
+	return nil
+}
+
+func (x *MachineState) GetHash() tpm.HashAlgo {
+	if x != nil {
+		return x.Hash
+	}
+	return tpm.HashAlgo(0)
+}
+
+func (x *MachineState) GetGrub() *GrubState {
+	if x != nil {
+		return x.Grub
+	}
+	return nil
+}
+
+func (x *MachineState) GetLinuxKernel() *LinuxKernelState {
+	if x != nil {
+		return x.LinuxKernel
+	}
+	return nil
+}
+
+func (x *MachineState) GetCos() *AttestedCosState {
+	if x != nil {
+		return x.Cos
+	}
+	return nil
+}
+
+func (x *MachineState) GetEfi() *EfiState {
+	if x != nil {
+		return x.Efi
+	}
+	return nil
+}
+
+func (m *MachineState) GetTeeAttestation() isMachineState_TeeAttestation {
+	if m != nil {
+		return m.TeeAttestation
+	}
+	return nil
+}
+
+func (x *MachineState) GetSevSnpAttestation() *sevsnp.Attestation {
+	if x, ok := x.GetTeeAttestation().(*MachineState_SevSnpAttestation); ok {
+		return x.SevSnpAttestation
+	}
+	return nil
+}
+
+func (x *MachineState) GetTdxAttestation() *tdx.QuoteV4 {
+	if x, ok := x.GetTeeAttestation().(*MachineState_TdxAttestation); ok {
+		return x.TdxAttestation
+	}
+	return nil
+}
+
+func (x *MachineState) GetSystemdBoot() *SystemdBootState {
+	if x != nil {
+		return x.SystemdBoot
+	}
+	return nil
+}
+
+func (x *MachineState) GetShim() *ShimState {
+	if x != nil {
+		return x.Shim
+	}
+	return nil
+}
+
+func (x *MachineState) GetIma() *ImaState {
+	if x != nil {
+		return x.Ima
+	}
+	return nil
+}
+
+type isMachineState_TeeAttestation interface {
+	isMachineState_TeeAttestation()
+}
+
+type MachineState_SevSnpAttestation struct {
+	SevSnpAttestation *sevsnp.Attestation `protobuf:"bytes,9,opt,name=sev_snp_attestation,json=sevSnpAttestation,proto3,oneof"`
+}
+
+type MachineState_TdxAttestation struct {
+	TdxAttestation *tdx.QuoteV4 `protobuf:"bytes,10,opt,name=tdx_attestation,json=tdxAttestation,proto3,oneof"`
+}
+
+func (*MachineState_SevSnpAttestation) isMachineState_TeeAttestation() {}
+
+func (*MachineState_TdxAttestation) isMachineState_TeeAttestation() {}
+
+// A policy dictating which values of PlatformState to allow
+type PlatformPolicy struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// If PlatformState.firmware contains a scrtm_version_id, it must appear
+	// in this list. For use with a GCE VM, minimum_gce_firmware_version is
+	// often a better alternative.
+	AllowedScrtmVersionIds [][]byte `protobuf:"bytes,1,rep,name=allowed_scrtm_version_ids,json=allowedScrtmVersionIds,proto3" json:"allowed_scrtm_version_ids,omitempty"`
+	// If PlatformState.firmware contains a minimum_gce_firmware_version, it must
+	// be greater than or equal to this value. Currently, the max version is 1.
+	MinimumGceFirmwareVersion uint32 `protobuf:"varint,2,opt,name=minimum_gce_firmware_version,json=minimumGceFirmwareVersion,proto3" json:"minimum_gce_firmware_version,omitempty"`
+	// The PlatformState's technology must be at least as secure as
+	// the specified minimum_technology (i.e. AMD_SEV_ES > AMD_SEV > NONE).
+	MinimumTechnology GCEConfidentialTechnology `protobuf:"varint,3,opt,name=minimum_technology,json=minimumTechnology,proto3,enum=attest.GCEConfidentialTechnology" json:"minimum_technology,omitempty"`
+}
+
+func (x *PlatformPolicy) Reset() {
+	*x = PlatformPolicy{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_attest_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PlatformPolicy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlatformPolicy) ProtoMessage() {}
+
+func (x *PlatformPolicy) ProtoReflect() protoreflect.Message {
+	mi := &file_attest_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlatformPolicy.ProtoReflect.Descriptor instead.
+func (*PlatformPolicy) Descriptor() ([]byte, []int) {
+	return file_attest_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *PlatformPolicy) GetAllowedScrtmVersionIds() [][]byte {
+	if x != nil {
+		return x.AllowedScrtmVersionIds
+	}
+	return nil
+}
+
+func (x *PlatformPolicy) GetMinimumGceFirmwareVersion() uint32 {
+	if x != nil {
+		return x.MinimumGceFirmwareVersion
+	}
+	return 0
+}
+
+func (x *PlatformPolicy) GetMinimumTechnology() GCEConfidentialTechnology {
+	if x != nil {
+		return x.MinimumTechnology
+	}
+	return GCEConfidentialTechnology_NONE
+}
+
+// A policy about what parts of a RIM to compare against machine state as
+// reflected in a quote or (verified) event log. Reference measurements for
+// a component are expected to be addressable by the machine state's reported
+// digest, or otherwise presented as cached collateral with the attestation
+// itself. The method of delivery is vendor-specific.
+type RIMPolicy struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// If true, the signed measurement must be available (by means that can be
+	// vendor-specific), and the target measurement must be among the listed
+	// signed measurements.  If false, then only error if there is a problem
+	// verifying the signed measurements when they are available.
+	RequireSigned bool `protobuf:"varint,1,opt,name=require_signed,json=requireSigned,proto3" json:"require_signed,omitempty"`
+	// x.509 certificates in ASN.1 DER format.
+	RootCerts [][]byte `protobuf:"bytes,2,rep,name=root_certs,json=rootCerts,proto3" json:"root_certs,omitempty"`
+}
+
+func (x *RIMPolicy) Reset() {
+	*x = RIMPolicy{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_attest_proto_msgTypes[27]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RIMPolicy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RIMPolicy) ProtoMessage() {}
+
+func (x *RIMPolicy) ProtoReflect() protoreflect.Message {
+	mi := &file_attest_proto_msgTypes[27]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RIMPolicy.ProtoReflect.Descriptor instead.
+func (*RIMPolicy) Descriptor() ([]byte, []int) {
+	return file_attest_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *RIMPolicy) GetRequireSigned() bool {
+	if x != nil {
+		return x.RequireSigned
+	}
+	return false
+}
+
+func (x *RIMPolicy) GetRootCerts() [][]byte {
+	if x != nil {
+		return x.RootCerts
+	}
+	return nil
+}
+
+// Represent minimal decisions about attestation fields until necessary to
+// add for policy reasons.
+type SevSnpPolicy struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The policy for checking the signed reference values for the UEFI at launch.
+	Uefi *RIMPolicy `protobuf:"bytes,1,opt,name=uefi,proto3" json:"uefi,omitempty"`
+}
+
+func (x *SevSnpPolicy) Reset() {
+	*x = SevSnpPolicy{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_attest_proto_msgTypes[28]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SevSnpPolicy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SevSnpPolicy) ProtoMessage() {}
+
+func (x *SevSnpPolicy) ProtoReflect() protoreflect.Message {
+	mi := &file_attest_proto_msgTypes[28]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SevSnpPolicy.ProtoReflect.Descriptor instead.
+func (*SevSnpPolicy) Descriptor() ([]byte, []int) {
+	return file_attest_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *SevSnpPolicy) GetUefi() *RIMPolicy {
+	if x != nil {
+		return x.Uefi
+	}
+	return nil
+}
+
+// A policy dictating which values of SecureBootState to allow
+type SecureBootPolicy struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// If true, SecureBootState.enabled must be true.
+	RequireEnabled bool `protobuf:"varint,1,opt,name=require_enabled,json=requireEnabled,proto3" json:"require_enabled,omitempty"`
+	// Every certificate and hash in this database must appear in the
+	// SecureBootState's dbx. This can be used to require that known revocations
+	// (such as those for BootHole) have been applied.
+	RequiredDbx *Database `protobuf:"bytes,2,opt,name=required_dbx,json=requiredDbx,proto3" json:"required_dbx,omitempty"`
+	// No certificate or hash in this database may appear in the
+	// SecureBootState's db.
+	ForbiddenDb *Database `protobuf:"bytes,3,opt,name=forbidden_db,json=forbiddenDb,proto3" json:"forbidden_db,omitempty"`
+	// If non-empty, every certificate in the SecureBootState's authority
+	// database (i.e. those used to verify booted components) must appear in this
+	// database.
+	AllowedAuthority *Database `protobuf:"bytes,4,opt,name=allowed_authority,json=allowedAuthority,proto3" json:"allowed_authority,omitempty"`
+	// If non-empty, every certificate in the SecureBootState's pk must appear in
+	// this database.
+	AllowedPk *Database `protobuf:"bytes,5,opt,name=allowed_pk,json=allowedPk,proto3" json:"allowed_pk,omitempty"`
+}
+
+func (x *SecureBootPolicy) Reset() {
+	*x = SecureBootPolicy{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_attest_proto_msgTypes[29]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *AttestedCosState) String() string {
+func (x *SecureBootPolicy) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AttestedCosState) ProtoMessage() {}
+func (*SecureBootPolicy) ProtoMessage() {}
 
-func (x *AttestedCosState) ProtoReflect() protoreflect.Message {
-	mi := &file_attest_proto_msgTypes[15]
+func (x *SecureBootPolicy) ProtoReflect() protoreflect.Message {
+	mi := &file_attest_proto_msgTypes[29]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1471,72 +2569,84 @@ func (x *AttestedCosState) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AttestedCosState.ProtoReflect.Descriptor instead.
-func (*AttestedCosState) Descriptor() ([]byte, []int) {
-	return file_attest_proto_rawDescGZIP(), []int{15}
+// Deprecated: Use SecureBootPolicy.ProtoReflect.Descriptor instead.
+func (*SecureBootPolicy) Descriptor() ([]byte, []int) {
+	return file_attest_proto_rawDescGZIP(), []int{29}
 }
 
-func (x *AttestedCosState) GetContainer() *ContainerState {
+func (x *SecureBootPolicy) GetRequireEnabled() bool {
 	if x != nil {
-		return x.Container
+		return x.RequireEnabled
 	}
-	return nil
+	return false
 }
 
-func (x *AttestedCosState) GetCosVersion() *SemanticVersion {
+func (x *SecureBootPolicy) GetRequiredDbx() *Database {
 	if x != nil {
-		return x.CosVersion
+		return x.RequiredDbx
 	}
 	return nil
 }
 
-func (x *AttestedCosState) GetLauncherVersion() *SemanticVersion {
+func (x *SecureBootPolicy) GetForbiddenDb() *Database {
 	if x != nil {
-		return x.LauncherVersion
+		return x.ForbiddenDb
 	}
 	return nil
 }
 
-func (x *AttestedCosState) GetHealthMonitoring() *HealthMonitoringState {
+func (x *SecureBootPolicy) GetAllowedAuthority() *Database {
 	if x != nil {
-		return x.HealthMonitoring
+		return x.AllowedAuthority
 	}
 	return nil
 }
 
-func (x *AttestedCosState) GetGpuDeviceState() *GpuDeviceState {
+func (x *SecureBootPolicy) GetAllowedPk() *Database {
 	if x != nil {
-		return x.GpuDeviceState
+		return x.AllowedPk
 	}
 	return nil
 }
 
-type EfiApp struct {
+// A policy dictating which shim configurations to allow. If the machine did not
+// boot using shim, only allowed_mok_list_digests is enforced.
+type ShimPolicy struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// The PE/COFF digest of the EFI application (pulled from the raw event digest).
-	Digest []byte `protobuf:"bytes,1,opt,name=digest,proto3" json:"digest,omitempty"`
-}
-
-func (x *EfiApp) Reset() {
-	*x = EfiApp{}
+	// If true, ShimState.validation_disabled must be false.
+	RequireValidation bool `protobuf:"varint,1,opt,name=require_validation,json=requireValidation,proto3" json:"require_validation,omitempty"`
+	// If true, ShimState.mok_authority must be empty, i.e. no booted component
+	// may be verified using a user-enrolled MOK.
+	ForbidMokAuthority bool `protobuf:"varint,2,opt,name=forbid_mok_authority,json=forbidMokAuthority,proto3" json:"forbid_mok_authority,omitempty"`
+	// If true, ShimState.mok_list_trusted must be false.
+	ForbidMokListTrusted bool `protobuf:"varint,3,opt,name=forbid_mok_list_trusted,json=forbidMokListTrusted,proto3" json:"forbid_mok_list_trusted,omitempty"`
+	// If non-empty, ShimState.mok_list_digest must appear in this list. This can
+	// be used to reject machines with user-enrolled MOKs that were not used
+	// during boot. Digests must be computed with the same hash algorithm as
+	// MachineState.hash.
+	AllowedMokListDigests [][]byte `protobuf:"bytes,4,rep,name=allowed_mok_list_digests,json=allowedMokListDigests,proto3" json:"allowed_mok_list_digests,omitempty"`
+}
+
+func (x *ShimPolicy) Reset() {
+	*x = ShimPolicy{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_attest_proto_msgTypes[16]
+		mi := &file_attest_proto_msgTypes[30]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *EfiApp) String() string {
+func (x *ShimPolicy) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*EfiApp) ProtoMessage() {}
+func (*ShimPolicy) ProtoMessage() {}
 
-func (x *EfiApp) ProtoReflect() protoreflect.Message {
-	mi := &file_attest_proto_msgTypes[16]
+func (x *ShimPolicy) ProtoReflect() protoreflect.Message {
+	mi := &file_attest_proto_msgTypes[30]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1547,49 +2657,72 @@ func (x *EfiApp) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use EfiApp.ProtoReflect.Descriptor instead.
-func (*EfiApp) Descriptor() ([]byte, []int) {
-	return file_attest_proto_rawDescGZIP(), []int{16}
+// Deprecated: Use ShimPolicy.ProtoReflect.Descriptor instead.
+func (*ShimPolicy) Descriptor() ([]byte, []int) {
+	return file_attest_proto_rawDescGZIP(), []int{30}
 }
 
-func (x *EfiApp) GetDigest() []byte {
+func (x *ShimPolicy) GetRequireValidation() bool {
 	if x != nil {
-		return x.Digest
+		return x.RequireValidation
+	}
+	return false
+}
+
+func (x *ShimPolicy) GetForbidMokAuthority() bool {
+	if x != nil {
+		return x.ForbidMokAuthority
+	}
+	return false
+}
+
+func (x *ShimPolicy) GetForbidMokListTrusted() bool {
+	if x != nil {
+		return x.ForbidMokListTrusted
+	}
+	return false
+}
+
+func (x *ShimPolicy) GetAllowedMokListDigests() [][]byte {
+	if x != nil {
+		return x.AllowedMokListDigests
 	}
 	return nil
 }
 
-// The verified state of EFI Applications. Policy usage on this machine state
-// should check the entire set of EFI App digests matches, not a subset.
-type EfiState struct {
+// A policy dictating which EFI applications and GRUB-measured files to allow
+type BootloaderPolicy struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// UEFI's OS Loader code is required to measure attempts to load and execute
-	// UEFI applications.
-	// UEFI applications are typically bootloaders such as shim and GRUB.
-	// These run and are measured using the UEFI LoadImage() service.
-	Apps []*EfiApp `protobuf:"bytes,1,rep,name=apps,proto3" json:"apps,omitempty"`
+	// If non-empty, every digest in EfiState.apps must appear in this list.
+	// Digests must be computed with the same hash algorithm as
+	// MachineState.hash.
+	AllowedEfiAppDigests [][]byte `protobuf:"bytes,1,rep,name=allowed_efi_app_digests,json=allowedEfiAppDigests,proto3" json:"allowed_efi_app_digests,omitempty"`
+	// If non-empty, every digest in GrubState.files must appear in this list.
+	// Digests must be computed with the same hash algorithm as
+	// MachineState.hash.
+	AllowedGrubFileDigests [][]byte `protobuf:"bytes,2,rep,name=allowed_grub_file_digests,json=allowedGrubFileDigests,proto3" json:"allowed_grub_file_digests,omitempty"`
 }
 
-func (x *EfiState) Reset() {
-	*x = EfiState{}
+func (x *BootloaderPolicy) Reset() {
+	*x = BootloaderPolicy{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_attest_proto_msgTypes[17]
+		mi := &file_attest_proto_msgTypes[31]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *EfiState) String() string {
+func (x *BootloaderPolicy) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*EfiState) ProtoMessage() {}
+func (*BootloaderPolicy) ProtoMessage() {}
 
-func (x *EfiState) ProtoReflect() protoreflect.Message {
-	mi := &file_attest_proto_msgTypes[17]
+func (x *BootloaderPolicy) ProtoReflect() protoreflect.Message {
+	mi := &file_attest_proto_msgTypes[31]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1600,64 +2733,61 @@ func (x *EfiState) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use EfiState.ProtoReflect.Descriptor instead.
-func (*EfiState) Descriptor() ([]byte, []int) {
-	return file_attest_proto_rawDescGZIP(), []int{17}
+// Deprecated: Use BootloaderPolicy.ProtoReflect.Descriptor instead.
+func (*BootloaderPolicy) Descriptor() ([]byte, []int) {
+	return file_attest_proto_rawDescGZIP(), []int{31}
 }
 
-func (x *EfiState) GetApps() []*EfiApp {
+func (x *BootloaderPolicy) GetAllowedEfiAppDigests() [][]byte {
 	if x != nil {
-		return x.Apps
+		return x.AllowedEfiAppDigests
 	}
 	return nil
 }
 
-// The verified state of a booted machine, obtained from an Attestation
-type MachineState struct {
+func (x *BootloaderPolicy) GetAllowedGrubFileDigests() [][]byte {
+	if x != nil {
+		return x.AllowedGrubFileDigests
+	}
+	return nil
+}
+
+// A policy dictating which values of LinuxKernelState to allow
+type KernelPolicy struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Platform   *PlatformState   `protobuf:"bytes,1,opt,name=platform,proto3" json:"platform,omitempty"`
-	SecureBoot *SecureBootState `protobuf:"bytes,2,opt,name=secure_boot,json=secureBoot,proto3" json:"secure_boot,omitempty"`
-	// The complete parsed TCG Event Log, including those events used to
-	// create the PlatformState.
-	RawEvents []*Event `protobuf:"bytes,3,rep,name=raw_events,json=rawEvents,proto3" json:"raw_events,omitempty"`
-	// The hash algorithm used when verifying the Attestation. This indicates:
-	//   - which PCR bank was used for for quote validation and event log replay
-	//   - the hash algorithm used to calculate event digests
-	Hash        tpm.HashAlgo      `protobuf:"varint,4,opt,name=hash,proto3,enum=tpm.HashAlgo" json:"hash,omitempty"`
-	Grub        *GrubState        `protobuf:"bytes,5,opt,name=grub,proto3" json:"grub,omitempty"`
-	LinuxKernel *LinuxKernelState `protobuf:"bytes,6,opt,name=linux_kernel,json=linuxKernel,proto3" json:"linux_kernel,omitempty"`
-	Cos         *AttestedCosState `protobuf:"bytes,7,opt,name=cos,proto3" json:"cos,omitempty"`
-	Efi         *EfiState         `protobuf:"bytes,8,opt,name=efi,proto3" json:"efi,omitempty"`
-	// Attestation/Quote representations from TEEs whose signatures are already
-	// verified.
-	//
-	// Types that are assignable to TeeAttestation:
-	//
-	//	*MachineState_SevSnpAttestation
-	//	*MachineState_TdxAttestation
-	TeeAttestation isMachineState_TeeAttestation `protobuf_oneof:"tee_attestation"`
-}
-
-func (x *MachineState) Reset() {
-	*x = MachineState{}
+	// If non-empty, LinuxKernelState.command_line must exactly match one of
+	// these values.
+	AllowedCommandLines []string `protobuf:"bytes,1,rep,name=allowed_command_lines,json=allowedCommandLines,proto3" json:"allowed_command_lines,omitempty"`
+	// Each of these arguments must appear in the (whitespace-separated) kernel
+	// command line. An argument matches only if it is identical, so both
+	// "ro" and "lockdown=integrity" are valid entries.
+	RequiredCommandLineArgs []string `protobuf:"bytes,2,rep,name=required_command_line_args,json=requiredCommandLineArgs,proto3" json:"required_command_line_args,omitempty"`
+	// None of these arguments may appear in the kernel command line. An entry
+	// without an '=' also forbids any value being assigned to it (e.g.
+	// forbidding "init" forbids "init=/bin/sh").
+	ForbiddenCommandLineArgs []string `protobuf:"bytes,3,rep,name=forbidden_command_line_args,json=forbiddenCommandLineArgs,proto3" json:"forbidden_command_line_args,omitempty"`
+}
+
+func (x *KernelPolicy) Reset() {
+	*x = KernelPolicy{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_attest_proto_msgTypes[18]
+		mi := &file_attest_proto_msgTypes[32]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *MachineState) String() string {
+func (x *KernelPolicy) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*MachineState) ProtoMessage() {}
+func (*KernelPolicy) ProtoMessage() {}
 
-func (x *MachineState) ProtoReflect() protoreflect.Message {
-	mi := &file_attest_proto_msgTypes[18]
+func (x *KernelPolicy) ProtoReflect() protoreflect.Message {
+	mi := &file_attest_proto_msgTypes[32]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1668,139 +2798,74 @@ func (x *MachineState) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use MachineState.ProtoReflect.Descriptor instead.
-func (*MachineState) Descriptor() ([]byte, []int) {
-	return file_attest_proto_rawDescGZIP(), []int{18}
-}
-
-func (x *MachineState) GetPlatform() *PlatformState {
-	if x != nil {
-		return x.Platform
-	}
-	return nil
-}
-
-func (x *MachineState) GetSecureBoot() *SecureBootState {
-	if x != nil {
-		return x.SecureBoot
-	}
-	return nil
-}
-
-func (x *MachineState) GetRawEvents() []*Event {
-	if x != nil {
-		return x.RawEvents
-	}
-	return nil
-}
-
-func (x *MachineState) GetHash() tpm.HashAlgo {
-	if x != nil {
-		return x.Hash
-	}
-	return tpm.HashAlgo(0)
-}
-
-func (x *MachineState) GetGrub() *GrubState {
-	if x != nil {
-		return x.Grub
-	}
-	return nil
+// Deprecated: Use KernelPolicy.ProtoReflect.Descriptor instead.
+func (*KernelPolicy) Descriptor() ([]byte, []int) {
+	return file_attest_proto_rawDescGZIP(), []int{32}
 }
 
-func (x *MachineState) GetLinuxKernel() *LinuxKernelState {
+func (x *KernelPolicy) GetAllowedCommandLines() []string {
 	if x != nil {
-		return x.LinuxKernel
+		return x.AllowedCommandLines
 	}
 	return nil
 }
 
-func (x *MachineState) GetCos() *AttestedCosState {
+func (x *KernelPolicy) GetRequiredCommandLineArgs() []string {
 	if x != nil {
-		return x.Cos
+		return x.RequiredCommandLineArgs
 	}
 	return nil
 }
 
-func (x *MachineState) GetEfi() *EfiState {
+func (x *KernelPolicy) GetForbiddenCommandLineArgs() []string {
 	if x != nil {
-		return x.Efi
-	}
-	return nil
-}
-
-func (m *MachineState) GetTeeAttestation() isMachineState_TeeAttestation {
-	if m != nil {
-		return m.TeeAttestation
-	}
-	return nil
-}
-
-func (x *MachineState) GetSevSnpAttestation() *sevsnp.Attestation {
-	if x, ok := x.GetTeeAttestation().(*MachineState_SevSnpAttestation); ok {
-		return x.SevSnpAttestation
-	}
-	return nil
-}
-
-func (x *MachineState) GetTdxAttestation() *tdx.QuoteV4 {
-	if x, ok := x.GetTeeAttestation().(*MachineState_TdxAttestation); ok {
-		return x.TdxAttestation
+		return x.ForbiddenCommandLineArgs
 	}
 	return nil
 }
 
-type isMachineState_TeeAttestation interface {
-	isMachineState_TeeAttestation()
-}
-
-type MachineState_SevSnpAttestation struct {
-	SevSnpAttestation *sevsnp.Attestation `protobuf:"bytes,9,opt,name=sev_snp_attestation,json=sevSnpAttestation,proto3,oneof"`
-}
-
-type MachineState_TdxAttestation struct {
-	TdxAttestation *tdx.QuoteV4 `protobuf:"bytes,10,opt,name=tdx_attestation,json=tdxAttestation,proto3,oneof"`
-}
-
-func (*MachineState_SevSnpAttestation) isMachineState_TeeAttestation() {}
-
-func (*MachineState_TdxAttestation) isMachineState_TeeAttestation() {}
-
-// A policy dictating which values of PlatformState to allow
-type PlatformPolicy struct {
+// A policy dictating which values of AttestedCosState to allow
+type ContainerPolicy struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// If PlatformState.firmware contains a scrtm_version_id, it must appear
-	// in this list. For use with a GCE VM, minimum_gce_firmware_version is
-	// often a better alternative.
-	AllowedScrtmVersionIds [][]byte `protobuf:"bytes,1,rep,name=allowed_scrtm_version_ids,json=allowedScrtmVersionIds,proto3" json:"allowed_scrtm_version_ids,omitempty"`
-	// If PlatformState.firmware contains a minimum_gce_firmware_version, it must
-	// be greater than or equal to this value. Currently, the max version is 1.
-	MinimumGceFirmwareVersion uint32 `protobuf:"varint,2,opt,name=minimum_gce_firmware_version,json=minimumGceFirmwareVersion,proto3" json:"minimum_gce_firmware_version,omitempty"`
-	// The PlatformState's technology must be at least as secure as
-	// the specified minimum_technology (i.e. AMD_SEV_ES > AMD_SEV > NONE).
-	MinimumTechnology GCEConfidentialTechnology `protobuf:"varint,3,opt,name=minimum_technology,json=minimumTechnology,proto3,enum=attest.GCEConfidentialTechnology" json:"minimum_technology,omitempty"`
-}
-
-func (x *PlatformPolicy) Reset() {
-	*x = PlatformPolicy{}
+	// If non-empty, ContainerState.image_digest must appear in this list.
+	AllowedImageDigests []string `protobuf:"bytes,1,rep,name=allowed_image_digests,json=allowedImageDigests,proto3" json:"allowed_image_digests,omitempty"`
+	// If non-empty, ContainerState.image_reference must appear in this list.
+	AllowedImageReferences []string `protobuf:"bytes,2,rep,name=allowed_image_references,json=allowedImageReferences,proto3" json:"allowed_image_references,omitempty"`
+	// If non-empty, ContainerState.restart_policy must appear in this list.
+	AllowedRestartPolicies []RestartPolicy `protobuf:"varint,3,rep,packed,name=allowed_restart_policies,json=allowedRestartPolicies,proto3,enum=attest.RestartPolicy" json:"allowed_restart_policies,omitempty"`
+	// If true, the operator must not have overridden any of the container's
+	// arguments or environment variables.
+	ForbidOverrides bool `protobuf:"varint,4,opt,name=forbid_overrides,json=forbidOverrides,proto3" json:"forbid_overrides,omitempty"`
+	// If set, the COS version must be greater than or equal to this value.
+	MinimumCosVersion *SemanticVersion `protobuf:"bytes,5,opt,name=minimum_cos_version,json=minimumCosVersion,proto3" json:"minimum_cos_version,omitempty"`
+	// If set, the launcher version must be greater than or equal to this value.
+	MinimumLauncherVersion *SemanticVersion `protobuf:"bytes,6,opt,name=minimum_launcher_version,json=minimumLauncherVersion,proto3" json:"minimum_launcher_version,omitempty"`
+	// If true, memory monitoring must be enabled.
+	RequireMemoryMonitoring bool `protobuf:"varint,7,opt,name=require_memory_monitoring,json=requireMemoryMonitoring,proto3" json:"require_memory_monitoring,omitempty"`
+	// If non-empty, GpuDeviceState.cc_mode must appear in this list.
+	AllowedGpuCcModes []GPUDeviceCCMode `protobuf:"varint,8,rep,packed,name=allowed_gpu_cc_modes,json=allowedGpuCcModes,proto3,enum=attest.GPUDeviceCCMode" json:"allowed_gpu_cc_modes,omitempty"`
+}
+
+func (x *ContainerPolicy) Reset() {
+	*x = ContainerPolicy{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_attest_proto_msgTypes[19]
+		mi := &file_attest_proto_msgTypes[33]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *PlatformPolicy) String() string {
+func (x *ContainerPolicy) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PlatformPolicy) ProtoMessage() {}
+func (*ContainerPolicy) ProtoMessage() {}
 
-func (x *PlatformPolicy) ProtoReflect() protoreflect.Message {
-	mi := &file_attest_proto_msgTypes[19]
+func (x *ContainerPolicy) ProtoReflect() protoreflect.Message {
+	mi := &file_attest_proto_msgTypes[33]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1811,125 +2876,105 @@ func (x *PlatformPolicy) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PlatformPolicy.ProtoReflect.Descriptor instead.
-func (*PlatformPolicy) Descriptor() ([]byte, []int) {
-	return file_attest_proto_rawDescGZIP(), []int{19}
+// Deprecated: Use ContainerPolicy.ProtoReflect.Descriptor instead.
+func (*ContainerPolicy) Descriptor() ([]byte, []int) {
+	return file_attest_proto_rawDescGZIP(), []int{33}
 }
 
-func (x *PlatformPolicy) GetAllowedScrtmVersionIds() [][]byte {
+func (x *ContainerPolicy) GetAllowedImageDigests() []string {
 	if x != nil {
-		return x.AllowedScrtmVersionIds
+		return x.AllowedImageDigests
 	}
 	return nil
 }
 
-func (x *PlatformPolicy) GetMinimumGceFirmwareVersion() uint32 {
+func (x *ContainerPolicy) GetAllowedImageReferences() []string {
 	if x != nil {
-		return x.MinimumGceFirmwareVersion
+		return x.AllowedImageReferences
 	}
-	return 0
+	return nil
 }
 
-func (x *PlatformPolicy) GetMinimumTechnology() GCEConfidentialTechnology {
+func (x *ContainerPolicy) GetAllowedRestartPolicies() []RestartPolicy {
 	if x != nil {
-		return x.MinimumTechnology
+		return x.AllowedRestartPolicies
 	}
-	return GCEConfidentialTechnology_NONE
-}
-
-// A policy about what parts of a RIM to compare against machine state as
-// reflected in a quote or (verified) event log. Reference measurements for
-// a component are expected to be addressable by the machine state's reported
-// digest, or otherwise presented as cached collateral with the attestation
-// itself. The method of delivery is vendor-specific.
-type RIMPolicy struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
-
-	// If true, the signed measurement must be available (by means that can be
-	// vendor-specific), and the target measurement must be among the listed
-	// signed measurements.  If false, then only error if there is a problem
-	// verifying the signed measurements when they are available.
-	RequireSigned bool `protobuf:"varint,1,opt,name=require_signed,json=requireSigned,proto3" json:"require_signed,omitempty"`
-	// x.509 certificates in ASN.1 DER format.
-	RootCerts [][]byte `protobuf:"bytes,2,rep,name=root_certs,json=rootCerts,proto3" json:"root_certs,omitempty"`
+	return nil
 }
 
-func (x *RIMPolicy) Reset() {
-	*x = RIMPolicy{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_attest_proto_msgTypes[20]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
+func (x *ContainerPolicy) GetForbidOverrides() bool {
+	if x != nil {
+		return x.ForbidOverrides
 	}
+	return false
 }
 
-func (x *RIMPolicy) String() string {
-	return protoimpl.X.MessageStringOf(x)
-}
-
-func (*RIMPolicy) ProtoMessage() {}
-
-func (x *RIMPolicy) ProtoReflect() protoreflect.Message {
-	mi := &file_attest_proto_msgTypes[20]
-	if protoimpl.UnsafeEnabled && x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+func (x *ContainerPolicy) GetMinimumCosVersion() *SemanticVersion {
+	if x != nil {
+		return x.MinimumCosVersion
 	}
-	return mi.MessageOf(x)
+	return nil
 }
 
-// Deprecated: Use RIMPolicy.ProtoReflect.Descriptor instead.
-func (*RIMPolicy) Descriptor() ([]byte, []int) {
-	return file_attest_proto_rawDescGZIP(), []int{20}
+func (x *ContainerPolicy) GetMinimumLauncherVersion() *SemanticVersion {
+	if x != nil {
+		return x.MinimumLauncherVersion
+	}
+	return nil
 }
 
-func (x *RIMPolicy) GetRequireSigned() bool {
+func (x *ContainerPolicy) GetRequireMemoryMonitoring() bool {
 	if x != nil {
-		return x.RequireSigned
+		return x.RequireMemoryMonitoring
 	}
 	return false
 }
 
-func (x *RIMPolicy) GetRootCerts() [][]byte {
+func (x *ContainerPolicy) GetAllowedGpuCcModes() []GPUDeviceCCMode {
 	if x != nil {
-		return x.RootCerts
+		return x.AllowedGpuCcModes
 	}
 	return nil
 }
 
-// Represent minimal decisions about attestation fields until necessary to
-// add for policy reasons.
-type SevSnpPolicy struct {
+// A policy dictating which TDX quotes to allow
+type TdxPolicy struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// The policy for checking the signed reference values for the UEFI at launch.
-	Uefi *RIMPolicy `protobuf:"bytes,1,opt,name=uefi,proto3" json:"uefi,omitempty"`
-}
-
-func (x *SevSnpPolicy) Reset() {
-	*x = SevSnpPolicy{}
+	// If non-empty, the quote's MRTD (the measurement of the initial TD
+	// contents) must appear in this list.
+	AllowedMrtds [][]byte `protobuf:"bytes,1,rep,name=allowed_mrtds,json=allowedMrtds,proto3" json:"allowed_mrtds,omitempty"`
+	// The expected values of the quote's RTMRs. The value at index i is
+	// compared against RTMR[i], and an empty value is not checked.
+	ExpectedRtmrs [][]byte `protobuf:"bytes,2,rep,name=expected_rtmrs,json=expectedRtmrs,proto3" json:"expected_rtmrs,omitempty"`
+	// If set, each component of the quote's TEE_TCB_SVN must be greater than or
+	// equal to the corresponding component of this 16-byte value.
+	MinimumTeeTcbSvn []byte `protobuf:"bytes,3,opt,name=minimum_tee_tcb_svn,json=minimumTeeTcbSvn,proto3" json:"minimum_tee_tcb_svn,omitempty"`
+	// If true, the TD must not have the DEBUG attribute set.
+	ForbidDebug bool `protobuf:"varint,4,opt,name=forbid_debug,json=forbidDebug,proto3" json:"forbid_debug,omitempty"`
+	// If non-empty, the quote's MRSIGNERSEAM must appear in this list.
+	AllowedMrSignerSeams [][]byte `protobuf:"bytes,5,rep,name=allowed_mr_signer_seams,json=allowedMrSignerSeams,proto3" json:"allowed_mr_signer_seams,omitempty"`
+}
+
+func (x *TdxPolicy) Reset() {
+	*x = TdxPolicy{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_attest_proto_msgTypes[21]
+		mi := &file_attest_proto_msgTypes[34]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *SevSnpPolicy) String() string {
+func (x *TdxPolicy) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SevSnpPolicy) ProtoMessage() {}
+func (*TdxPolicy) ProtoMessage() {}
 
-func (x *SevSnpPolicy) ProtoReflect() protoreflect.Message {
-	mi := &file_attest_proto_msgTypes[21]
+func (x *TdxPolicy) ProtoReflect() protoreflect.Message {
+	mi := &file_attest_proto_msgTypes[34]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1940,14 +2985,42 @@ func (x *SevSnpPolicy) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SevSnpPolicy.ProtoReflect.Descriptor instead.
-func (*SevSnpPolicy) Descriptor() ([]byte, []int) {
-	return file_attest_proto_rawDescGZIP(), []int{21}
+// Deprecated: Use TdxPolicy.ProtoReflect.Descriptor instead.
+func (*TdxPolicy) Descriptor() ([]byte, []int) {
+	return file_attest_proto_rawDescGZIP(), []int{34}
 }
 
-func (x *SevSnpPolicy) GetUefi() *RIMPolicy {
+func (x *TdxPolicy) GetAllowedMrtds() [][]byte {
 	if x != nil {
-		return x.Uefi
+		return x.AllowedMrtds
+	}
+	return nil
+}
+
+func (x *TdxPolicy) GetExpectedRtmrs() [][]byte {
+	if x != nil {
+		return x.ExpectedRtmrs
+	}
+	return nil
+}
+
+func (x *TdxPolicy) GetMinimumTeeTcbSvn() []byte {
+	if x != nil {
+		return x.MinimumTeeTcbSvn
+	}
+	return nil
+}
+
+func (x *TdxPolicy) GetForbidDebug() bool {
+	if x != nil {
+		return x.ForbidDebug
+	}
+	return false
+}
+
+func (x *TdxPolicy) GetAllowedMrSignerSeams() [][]byte {
+	if x != nil {
+		return x.AllowedMrSignerSeams
 	}
 	return nil
 }
@@ -1958,16 +3031,24 @@ type Policy struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Platform *PlatformPolicy `protobuf:"bytes,1,opt,name=platform,proto3" json:"platform,omitempty"`
+	Platform   *PlatformPolicy   `protobuf:"bytes,1,opt,name=platform,proto3" json:"platform,omitempty"`
+	SecureBoot *SecureBootPolicy `protobuf:"bytes,2,opt,name=secure_boot,json=secureBoot,proto3" json:"secure_boot,omitempty"`
 	// When the attestation is on SEV-SNP, this is the policy. Unset means no
 	// constraints.
-	SevSnp *SevSnpPolicy `protobuf:"bytes,3,opt,name=sev_snp,json=sevSnp,proto3" json:"sev_snp,omitempty"`
+	SevSnp     *SevSnpPolicy     `protobuf:"bytes,3,opt,name=sev_snp,json=sevSnp,proto3" json:"sev_snp,omitempty"`
+	Bootloader *BootloaderPolicy `protobuf:"bytes,4,opt,name=bootloader,proto3" json:"bootloader,omitempty"`
+	Kernel     *KernelPolicy     `protobuf:"bytes,5,opt,name=kernel,proto3" json:"kernel,omitempty"`
+	Container  *ContainerPolicy  `protobuf:"bytes,6,opt,name=container,proto3" json:"container,omitempty"`
+	// When the attestation is on TDX, this is the policy. Unset means no
+	// constraints.
+	Tdx  *TdxPolicy  `protobuf:"bytes,7,opt,name=tdx,proto3" json:"tdx,omitempty"`
+	Shim *ShimPolicy `protobuf:"bytes,8,opt,name=shim,proto3" json:"shim,omitempty"`
 }
 
 func (x *Policy) Reset() {
 	*x = Policy{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_attest_proto_msgTypes[22]
+		mi := &file_attest_proto_msgTypes[35]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1980,7 +3061,7 @@ func (x *Policy) String() string {
 func (*Policy) ProtoMessage() {}
 
 func (x *Policy) ProtoReflect() protoreflect.Message {
-	mi := &file_attest_proto_msgTypes[22]
+	mi := &file_attest_proto_msgTypes[35]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1993,7 +3074,7 @@ func (x *Policy) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Policy.ProtoReflect.Descriptor instead.
 func (*Policy) Descriptor() ([]byte, []int) {
-	return file_attest_proto_rawDescGZIP(), []int{22}
+	return file_attest_proto_rawDescGZIP(), []int{35}
 }
 
 func (x *Policy) GetPlatform() *PlatformPolicy {
@@ -2003,6 +3084,13 @@ func (x *Policy) GetPlatform() *PlatformPolicy {
 	return nil
 }
 
+func (x *Policy) GetSecureBoot() *SecureBootPolicy {
+	if x != nil {
+		return x.SecureBoot
+	}
+	return nil
+}
+
 func (x *Policy) GetSevSnp() *SevSnpPolicy {
 	if x != nil {
 		return x.SevSnp
@@ -2010,6 +3098,41 @@ func (x *Policy) GetSevSnp() *SevSnpPolicy {
 	return nil
 }
 
+func (x *Policy) GetBootloader() *BootloaderPolicy {
+	if x != nil {
+		return x.Bootloader
+	}
+	return nil
+}
+
+func (x *Policy) GetKernel() *KernelPolicy {
+	if x != nil {
+		return x.Kernel
+	}
+	return nil
+}
+
+func (x *Policy) GetContainer() *ContainerPolicy {
+	if x != nil {
+		return x.Container
+	}
+	return nil
+}
+
+func (x *Policy) GetTdx() *TdxPolicy {
+	if x != nil {
+		return x.Tdx
+	}
+	return nil
+}
+
+func (x *Policy) GetShim() *ShimPolicy {
+	if x != nil {
+		return x.Shim
+	}
+	return nil
+}
+
 var File_attest_proto protoreflect.FileDescriptor
 
 var file_attest_proto_rawDesc = []byte{
@@ -2028,7 +3151,7 @@ var file_attest_proto_rawDesc = []byte{
 	0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x69, 0x6e, 0x73,
 	0x74, 0x61, 0x6e, 0x63, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x69, 0x6e, 0x73,
 	0x74, 0x61, 0x6e, 0x63, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a,
-	0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x49, 0x64, 0x22, 0xae, 0x03, 0x0a, 0x0b, 0x41,
+	0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x49, 0x64, 0x22, 0xe0, 0x03, 0x0a, 0x0b, 0x41,
 	0x74, 0x74, 0x65, 0x73, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x15, 0x0a, 0x06, 0x61, 0x6b,
 	0x5f, 0x70, 0x75, 0x62, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x61, 0x6b, 0x50, 0x75,
 	0x62, 0x12, 0x22, 0x0a, 0x06, 0x71, 0x75, 0x6f, 0x74, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28,
@@ -2054,258 +3177,461 @@ var file_attest_proto_rawDesc = []byte{
 	0x37, 0x0a, 0x0f, 0x74, 0x64, 0x78, 0x5f, 0x61, 0x74, 0x74, 0x65, 0x73, 0x74, 0x61, 0x74, 0x69,
 	0x6f, 0x6e, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x74, 0x64, 0x78, 0x2e, 0x51,
 	0x75, 0x6f, 0x74, 0x65, 0x56, 0x34, 0x48, 0x00, 0x52, 0x0e, 0x74, 0x64, 0x78, 0x41, 0x74, 0x74,
-	0x65, 0x73, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x42, 0x11, 0x0a, 0x0f, 0x74, 0x65, 0x65, 0x5f,
-	0x61, 0x74, 0x74, 0x65, 0x73, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0xb9, 0x02, 0x0a, 0x15,
-	0x53, 0x65, 0x76, 0x53, 0x6e, 0x70, 0x53, 0x76, 0x73, 0x6d, 0x41, 0x74, 0x74, 0x65, 0x73, 0x74,
-	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x35, 0x0a, 0x0b, 0x61, 0x74, 0x74, 0x65, 0x73, 0x74, 0x61,
-	0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x61, 0x74, 0x74,
-	0x65, 0x73, 0x74, 0x2e, 0x41, 0x74, 0x74, 0x65, 0x73, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52,
-	0x0b, 0x61, 0x74, 0x74, 0x65, 0x73, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x43, 0x0a, 0x13,
-	0x73, 0x65, 0x76, 0x5f, 0x73, 0x6e, 0x70, 0x5f, 0x61, 0x74, 0x74, 0x65, 0x73, 0x74, 0x61, 0x74,
-	0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x73, 0x65, 0x76, 0x73,
-	0x6e, 0x70, 0x2e, 0x41, 0x74, 0x74, 0x65, 0x73, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x11,
-	0x73, 0x65, 0x76, 0x53, 0x6e, 0x70, 0x41, 0x74, 0x74, 0x65, 0x73, 0x74, 0x61, 0x74, 0x69, 0x6f,
-	0x6e, 0x12, 0x32, 0x0a, 0x15, 0x76, 0x74, 0x70, 0x6d, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63,
-	0x65, 0x5f, 0x6d, 0x61, 0x6e, 0x69, 0x66, 0x65, 0x73, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c,
-	0x52, 0x13, 0x76, 0x74, 0x70, 0x6d, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x4d, 0x61, 0x6e,
-	0x69, 0x66, 0x65, 0x73, 0x74, 0x12, 0x41, 0x0a, 0x1d, 0x76, 0x74, 0x70, 0x6d, 0x5f, 0x73, 0x65,
-	0x72, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x6d, 0x61, 0x6e, 0x69, 0x66, 0x65, 0x73, 0x74, 0x5f, 0x76,
-	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x1a, 0x76, 0x74,
-	0x70, 0x6d, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x4d, 0x61, 0x6e, 0x69, 0x66, 0x65, 0x73,
-	0x74, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x2d, 0x0a, 0x12, 0x6c, 0x61, 0x75, 0x6e,
-	0x63, 0x68, 0x5f, 0x65, 0x6e, 0x64, 0x6f, 0x72, 0x73, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x05,
-	0x20, 0x01, 0x28, 0x0c, 0x52, 0x11, 0x6c, 0x61, 0x75, 0x6e, 0x63, 0x68, 0x45, 0x6e, 0x64, 0x6f,
-	0x72, 0x73, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x22, 0xeb, 0x01, 0x0a, 0x0d, 0x50, 0x6c, 0x61, 0x74,
-	0x66, 0x6f, 0x72, 0x6d, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x2a, 0x0a, 0x10, 0x73, 0x63, 0x72,
-	0x74, 0x6d, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x0c, 0x48, 0x00, 0x52, 0x0e, 0x73, 0x63, 0x72, 0x74, 0x6d, 0x56, 0x65, 0x72, 0x73,
-	0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x21, 0x0a, 0x0b, 0x67, 0x63, 0x65, 0x5f, 0x76, 0x65, 0x72,
-	0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x00, 0x52, 0x0a, 0x67, 0x63,
-	0x65, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x41, 0x0a, 0x0a, 0x74, 0x65, 0x63, 0x68,
-	0x6e, 0x6f, 0x6c, 0x6f, 0x67, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x21, 0x2e, 0x61,
-	0x74, 0x74, 0x65, 0x73, 0x74, 0x2e, 0x47, 0x43, 0x45, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x64, 0x65,
-	0x6e, 0x74, 0x69, 0x61, 0x6c, 0x54, 0x65, 0x63, 0x68, 0x6e, 0x6f, 0x6c, 0x6f, 0x67, 0x79, 0x52,
-	0x0a, 0x74, 0x65, 0x63, 0x68, 0x6e, 0x6f, 0x6c, 0x6f, 0x67, 0x79, 0x12, 0x3c, 0x0a, 0x0d, 0x69,
-	0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x5f, 0x69, 0x6e, 0x66, 0x6f, 0x18, 0x04, 0x20, 0x01,
-	0x28, 0x0b, 0x32, 0x17, 0x2e, 0x61, 0x74, 0x74, 0x65, 0x73, 0x74, 0x2e, 0x47, 0x43, 0x45, 0x49,
-	0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x0c, 0x69, 0x6e, 0x73,
-	0x74, 0x61, 0x6e, 0x63, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x42, 0x0a, 0x0a, 0x08, 0x66, 0x69, 0x72,
-	0x6d, 0x77, 0x61, 0x72, 0x65, 0x22, 0x51, 0x0a, 0x08, 0x47, 0x72, 0x75, 0x62, 0x46, 0x69, 0x6c,
-	0x65, 0x12, 0x16, 0x0a, 0x06, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x0c, 0x52, 0x06, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x12, 0x2d, 0x0a, 0x12, 0x75, 0x6e, 0x74,
-	0x72, 0x75, 0x73, 0x74, 0x65, 0x64, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x11, 0x75, 0x6e, 0x74, 0x72, 0x75, 0x73, 0x74, 0x65, 0x64,
-	0x46, 0x69, 0x6c, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0x4f, 0x0a, 0x09, 0x47, 0x72, 0x75, 0x62,
-	0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x26, 0x0a, 0x05, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x18, 0x01,
-	0x20, 0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x61, 0x74, 0x74, 0x65, 0x73, 0x74, 0x2e, 0x47, 0x72,
-	0x75, 0x62, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x05, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x12, 0x1a, 0x0a,
-	0x08, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52,
-	0x08, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x73, 0x22, 0x35, 0x0a, 0x10, 0x4c, 0x69, 0x6e,
-	0x75, 0x78, 0x4b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x21, 0x0a,
-	0x0c, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x5f, 0x6c, 0x69, 0x6e, 0x65, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x0b, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x4c, 0x69, 0x6e, 0x65,
-	0x22, 0xa0, 0x01, 0x0a, 0x05, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x63,
-	0x72, 0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x70,
-	0x63, 0x72, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x25, 0x0a, 0x0e, 0x75, 0x6e, 0x74, 0x72, 0x75,
-	0x73, 0x74, 0x65, 0x64, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52,
-	0x0d, 0x75, 0x6e, 0x74, 0x72, 0x75, 0x73, 0x74, 0x65, 0x64, 0x54, 0x79, 0x70, 0x65, 0x12, 0x12,
-	0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x61,
-	0x74, 0x61, 0x12, 0x16, 0x0a, 0x06, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x04, 0x20, 0x01,
-	0x28, 0x0c, 0x52, 0x06, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x12, 0x27, 0x0a, 0x0f, 0x64, 0x69,
-	0x67, 0x65, 0x73, 0x74, 0x5f, 0x76, 0x65, 0x72, 0x69, 0x66, 0x69, 0x65, 0x64, 0x18, 0x05, 0x20,
-	0x01, 0x28, 0x08, 0x52, 0x0e, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x56, 0x65, 0x72, 0x69, 0x66,
-	0x69, 0x65, 0x64, 0x22, 0x72, 0x0a, 0x0b, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61,
-	0x74, 0x65, 0x12, 0x12, 0x0a, 0x03, 0x64, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x48,
-	0x00, 0x52, 0x03, 0x64, 0x65, 0x72, 0x12, 0x3d, 0x0a, 0x0a, 0x77, 0x65, 0x6c, 0x6c, 0x5f, 0x6b,
-	0x6e, 0x6f, 0x77, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1c, 0x2e, 0x61, 0x74, 0x74,
-	0x65, 0x73, 0x74, 0x2e, 0x57, 0x65, 0x6c, 0x6c, 0x4b, 0x6e, 0x6f, 0x77, 0x6e, 0x43, 0x65, 0x72,
-	0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x48, 0x00, 0x52, 0x09, 0x77, 0x65, 0x6c, 0x6c,
-	0x4b, 0x6e, 0x6f, 0x77, 0x6e, 0x42, 0x10, 0x0a, 0x0e, 0x72, 0x65, 0x70, 0x72, 0x65, 0x73, 0x65,
-	0x6e, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x4d, 0x0a, 0x08, 0x44, 0x61, 0x74, 0x61, 0x62,
-	0x61, 0x73, 0x65, 0x12, 0x29, 0x0a, 0x05, 0x63, 0x65, 0x72, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03,
-	0x28, 0x0b, 0x32, 0x13, 0x2e, 0x61, 0x74, 0x74, 0x65, 0x73, 0x74, 0x2e, 0x43, 0x65, 0x72, 0x74,
-	0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x52, 0x05, 0x63, 0x65, 0x72, 0x74, 0x73, 0x12, 0x16,
-	0x0a, 0x06, 0x68, 0x61, 0x73, 0x68, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x06,
-	0x68, 0x61, 0x73, 0x68, 0x65, 0x73, 0x22, 0xe7, 0x01, 0x0a, 0x0f, 0x53, 0x65, 0x63, 0x75, 0x72,
-	0x65, 0x42, 0x6f, 0x6f, 0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x65, 0x6e,
-	0x61, 0x62, 0x6c, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x65, 0x6e, 0x61,
-	0x62, 0x6c, 0x65, 0x64, 0x12, 0x20, 0x0a, 0x02, 0x64, 0x62, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
+	0x65, 0x73, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x30, 0x0a, 0x14, 0x69, 0x6d, 0x61, 0x5f,
+	0x6d, 0x65, 0x61, 0x73, 0x75, 0x72, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x6c, 0x69, 0x73, 0x74,
+	0x18, 0x0a, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x12, 0x69, 0x6d, 0x61, 0x4d, 0x65, 0x61, 0x73, 0x75,
+	0x72, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x4c, 0x69, 0x73, 0x74, 0x42, 0x11, 0x0a, 0x0f, 0x74, 0x65,
+	0x65, 0x5f, 0x61, 0x74, 0x74, 0x65, 0x73, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0xb9, 0x02,
+	0x0a, 0x15, 0x53, 0x65, 0x76, 0x53, 0x6e, 0x70, 0x53, 0x76, 0x73, 0x6d, 0x41, 0x74, 0x74, 0x65,
+	0x73, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x35, 0x0a, 0x0b, 0x61, 0x74, 0x74, 0x65, 0x73,
+	0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x61,
+	0x74, 0x74, 0x65, 0x73, 0x74, 0x2e, 0x41, 0x74, 0x74, 0x65, 0x73, 0x74, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x52, 0x0b, 0x61, 0x74, 0x74, 0x65, 0x73, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x43,
+	0x0a, 0x13, 0x73, 0x65, 0x76, 0x5f, 0x73, 0x6e, 0x70, 0x5f, 0x61, 0x74, 0x74, 0x65, 0x73, 0x74,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x73, 0x65,
+	0x76, 0x73, 0x6e, 0x70, 0x2e, 0x41, 0x74, 0x74, 0x65, 0x73, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x52, 0x11, 0x73, 0x65, 0x76, 0x53, 0x6e, 0x70, 0x41, 0x74, 0x74, 0x65, 0x73, 0x74, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x12, 0x32, 0x0a, 0x15, 0x76, 0x74, 0x70, 0x6d, 0x5f, 0x73, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x5f, 0x6d, 0x61, 0x6e, 0x69, 0x66, 0x65, 0x73, 0x74, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x13, 0x76, 0x74, 0x70, 0x6d, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x4d,
+	0x61, 0x6e, 0x69, 0x66, 0x65, 0x73, 0x74, 0x12, 0x41, 0x0a, 0x1d, 0x76, 0x74, 0x70, 0x6d, 0x5f,
+	0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x6d, 0x61, 0x6e, 0x69, 0x66, 0x65, 0x73, 0x74,
+	0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x1a,
+	0x76, 0x74, 0x70, 0x6d, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x4d, 0x61, 0x6e, 0x69, 0x66,
+	0x65, 0x73, 0x74, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x2d, 0x0a, 0x12, 0x6c, 0x61,
+	0x75, 0x6e, 0x63, 0x68, 0x5f, 0x65, 0x6e, 0x64, 0x6f, 0x72, 0x73, 0x65, 0x6d, 0x65, 0x6e, 0x74,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x11, 0x6c, 0x61, 0x75, 0x6e, 0x63, 0x68, 0x45, 0x6e,
+	0x64, 0x6f, 0x72, 0x73, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x22, 0xeb, 0x01, 0x0a, 0x0d, 0x50, 0x6c,
+	0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x2a, 0x0a, 0x10, 0x73,
+	0x63, 0x72, 0x74, 0x6d, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x00, 0x52, 0x0e, 0x73, 0x63, 0x72, 0x74, 0x6d, 0x56, 0x65,
+	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x21, 0x0a, 0x0b, 0x67, 0x63, 0x65, 0x5f, 0x76,
+	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x00, 0x52, 0x0a,
+	0x67, 0x63, 0x65, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x41, 0x0a, 0x0a, 0x74, 0x65,
+	0x63, 0x68, 0x6e, 0x6f, 0x6c, 0x6f, 0x67, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x21,
+	0x2e, 0x61, 0x74, 0x74, 0x65, 0x73, 0x74, 0x2e, 0x47, 0x43, 0x45, 0x43, 0x6f, 0x6e, 0x66, 0x69,
+	0x64, 0x65, 0x6e, 0x74, 0x69, 0x61, 0x6c, 0x54, 0x65, 0x63, 0x68, 0x6e, 0x6f, 0x6c, 0x6f, 0x67,
+	0x79, 0x52, 0x0a, 0x74, 0x65, 0x63, 0x68, 0x6e, 0x6f, 0x6c, 0x6f, 0x67, 0x79, 0x12, 0x3c, 0x0a,
+	0x0d, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x5f, 0x69, 0x6e, 0x66, 0x6f, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x61, 0x74, 0x74, 0x65, 0x73, 0x74, 0x2e, 0x47, 0x43,
+	0x45, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x0c, 0x69,
+	0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x42, 0x0a, 0x0a, 0x08, 0x66,
+	0x69, 0x72, 0x6d, 0x77, 0x61, 0x72, 0x65, 0x22, 0x51, 0x0a, 0x08, 0x47, 0x72, 0x75, 0x62, 0x46,
+	0x69, 0x6c, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x06, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x12, 0x2d, 0x0a, 0x12, 0x75,
+	0x6e, 0x74, 0x72, 0x75, 0x73, 0x74, 0x65, 0x64, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x6e, 0x61, 0x6d,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x11, 0x75, 0x6e, 0x74, 0x72, 0x75, 0x73, 0x74,
+	0x65, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0x4f, 0x0a, 0x09, 0x47, 0x72,
+	0x75, 0x62, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x26, 0x0a, 0x05, 0x66, 0x69, 0x6c, 0x65, 0x73,
+	0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x61, 0x74, 0x74, 0x65, 0x73, 0x74, 0x2e,
+	0x47, 0x72, 0x75, 0x62, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x05, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x12,
+	0x1a, 0x0a, 0x08, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x08, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x73, 0x22, 0x35, 0x0a, 0x10, 0x4c,
+	0x69, 0x6e, 0x75, 0x78, 0x4b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12,
+	0x21, 0x0a, 0x0c, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x5f, 0x6c, 0x69, 0x6e, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x4c, 0x69,
+	0x6e, 0x65, 0x22, 0x4e, 0x0a, 0x12, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x64, 0x4d, 0x65, 0x61,
+	0x73, 0x75, 0x72, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65, 0x73, 0x63,
+	0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64,
+	0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x64, 0x69,
+	0x67, 0x65, 0x73, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x64, 0x69, 0x67, 0x65,
+	0x73, 0x74, 0x22, 0xb4, 0x01, 0x0a, 0x10, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x64, 0x42, 0x6f,
+	0x6f, 0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x3d, 0x0a, 0x0c, 0x75, 0x6b, 0x69, 0x5f, 0x73,
+	0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e,
+	0x61, 0x74, 0x74, 0x65, 0x73, 0x74, 0x2e, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x64, 0x4d, 0x65,
+	0x61, 0x73, 0x75, 0x72, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x0b, 0x75, 0x6b, 0x69, 0x53, 0x65,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x25, 0x0a, 0x0e, 0x69, 0x6e, 0x69, 0x74, 0x72, 0x64,
+	0x5f, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x0d,
+	0x69, 0x6e, 0x69, 0x74, 0x72, 0x64, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x73, 0x12, 0x3a, 0x0a,
+	0x0a, 0x65, 0x78, 0x74, 0x65, 0x6e, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x1a, 0x2e, 0x61, 0x74, 0x74, 0x65, 0x73, 0x74, 0x2e, 0x53, 0x79, 0x73, 0x74, 0x65,
+	0x6d, 0x64, 0x4d, 0x65, 0x61, 0x73, 0x75, 0x72, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x0a, 0x65,
+	0x78, 0x74, 0x65, 0x6e, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0xa0, 0x01, 0x0a, 0x05, 0x45, 0x76,
+	0x65, 0x6e, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x63, 0x72, 0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x70, 0x63, 0x72, 0x49, 0x6e, 0x64, 0x65, 0x78,
+	0x12, 0x25, 0x0a, 0x0e, 0x75, 0x6e, 0x74, 0x72, 0x75, 0x73, 0x74, 0x65, 0x64, 0x5f, 0x74, 0x79,
+	0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0d, 0x75, 0x6e, 0x74, 0x72, 0x75, 0x73,
+	0x74, 0x65, 0x64, 0x54, 0x79, 0x70, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x12, 0x16, 0x0a, 0x06, 0x64,
+	0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x64, 0x69, 0x67,
+	0x65, 0x73, 0x74, 0x12, 0x27, 0x0a, 0x0f, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x5f, 0x76, 0x65,
+	0x72, 0x69, 0x66, 0x69, 0x65, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0e, 0x64, 0x69,
+	0x67, 0x65, 0x73, 0x74, 0x56, 0x65, 0x72, 0x69, 0x66, 0x69, 0x65, 0x64, 0x22, 0x72, 0x0a, 0x0b,
+	0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x12, 0x12, 0x0a, 0x03, 0x64,
+	0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x00, 0x52, 0x03, 0x64, 0x65, 0x72, 0x12,
+	0x3d, 0x0a, 0x0a, 0x77, 0x65, 0x6c, 0x6c, 0x5f, 0x6b, 0x6e, 0x6f, 0x77, 0x6e, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x1c, 0x2e, 0x61, 0x74, 0x74, 0x65, 0x73, 0x74, 0x2e, 0x57, 0x65, 0x6c,
+	0x6c, 0x4b, 0x6e, 0x6f, 0x77, 0x6e, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74,
+	0x65, 0x48, 0x00, 0x52, 0x09, 0x77, 0x65, 0x6c, 0x6c, 0x4b, 0x6e, 0x6f, 0x77, 0x6e, 0x42, 0x10,
+	0x0a, 0x0e, 0x72, 0x65, 0x70, 0x72, 0x65, 0x73, 0x65, 0x6e, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x22, 0x4d, 0x0a, 0x08, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x12, 0x29, 0x0a, 0x05,
+	0x63, 0x65, 0x72, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x61, 0x74,
+	0x74, 0x65, 0x73, 0x74, 0x2e, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65,
+	0x52, 0x05, 0x63, 0x65, 0x72, 0x74, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x68, 0x61, 0x73, 0x68, 0x65,
+	0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x06, 0x68, 0x61, 0x73, 0x68, 0x65, 0x73, 0x22,
+	0xe7, 0x01, 0x0a, 0x0f, 0x53, 0x65, 0x63, 0x75, 0x72, 0x65, 0x42, 0x6f, 0x6f, 0x74, 0x53, 0x74,
+	0x61, 0x74, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x12, 0x20, 0x0a,
+	0x02, 0x64, 0x62, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x61, 0x74, 0x74, 0x65,
+	0x73, 0x74, 0x2e, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x52, 0x02, 0x64, 0x62, 0x12,
+	0x22, 0x0a, 0x03, 0x64, 0x62, 0x78, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x61,
+	0x74, 0x74, 0x65, 0x73, 0x74, 0x2e, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x52, 0x03,
+	0x64, 0x62, 0x78, 0x12, 0x2e, 0x0a, 0x09, 0x61, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x69, 0x74, 0x79,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x61, 0x74, 0x74, 0x65, 0x73, 0x74, 0x2e,
+	0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x52, 0x09, 0x61, 0x75, 0x74, 0x68, 0x6f, 0x72,
+	0x69, 0x74, 0x79, 0x12, 0x20, 0x0a, 0x02, 0x70, 0x6b, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x10, 0x2e, 0x61, 0x74, 0x74, 0x65, 0x73, 0x74, 0x2e, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73,
+	0x65, 0x52, 0x02, 0x70, 0x6b, 0x12, 0x22, 0x0a, 0x03, 0x6b, 0x65, 0x6b, 0x18, 0x06, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x10, 0x2e, 0x61, 0x74, 0x74, 0x65, 0x73, 0x74, 0x2e, 0x44, 0x61, 0x74, 0x61,
+	0x62, 0x61, 0x73, 0x65, 0x52, 0x03, 0x6b, 0x65, 0x6b, 0x22, 0xcc, 0x02, 0x0a, 0x09, 0x53, 0x68,
+	0x69, 0x6d, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x62, 0x61, 0x74, 0x5f,
+	0x6c, 0x65, 0x76, 0x65, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x62, 0x61,
+	0x74, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x12, 0x3b, 0x0a, 0x10, 0x76, 0x65, 0x6e, 0x64, 0x6f, 0x72,
+	0x5f, 0x61, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x69, 0x74, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
 	0x32, 0x10, 0x2e, 0x61, 0x74, 0x74, 0x65, 0x73, 0x74, 0x2e, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61,
-	0x73, 0x65, 0x52, 0x02, 0x64, 0x62, 0x12, 0x22, 0x0a, 0x03, 0x64, 0x62, 0x78, 0x18, 0x03, 0x20,
+	0x73, 0x65, 0x52, 0x0f, 0x76, 0x65, 0x6e, 0x64, 0x6f, 0x72, 0x41, 0x75, 0x74, 0x68, 0x6f, 0x72,
+	0x69, 0x74, 0x79, 0x12, 0x35, 0x0a, 0x0d, 0x6d, 0x6f, 0x6b, 0x5f, 0x61, 0x75, 0x74, 0x68, 0x6f,
+	0x72, 0x69, 0x74, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x61, 0x74, 0x74,
+	0x65, 0x73, 0x74, 0x2e, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x52, 0x0c, 0x6d, 0x6f,
+	0x6b, 0x41, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x69, 0x74, 0x79, 0x12, 0x26, 0x0a, 0x0f, 0x6d, 0x6f,
+	0x6b, 0x5f, 0x6c, 0x69, 0x73, 0x74, 0x5f, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x0d, 0x6d, 0x6f, 0x6b, 0x4c, 0x69, 0x73, 0x74, 0x44, 0x69, 0x67, 0x65,
+	0x73, 0x74, 0x12, 0x29, 0x0a, 0x11, 0x6d, 0x6f, 0x6b, 0x5f, 0x6c, 0x69, 0x73, 0x74, 0x5f, 0x78,
+	0x5f, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0e, 0x6d,
+	0x6f, 0x6b, 0x4c, 0x69, 0x73, 0x74, 0x58, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x12, 0x28, 0x0a,
+	0x10, 0x6d, 0x6f, 0x6b, 0x5f, 0x6c, 0x69, 0x73, 0x74, 0x5f, 0x74, 0x72, 0x75, 0x73, 0x74, 0x65,
+	0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0e, 0x6d, 0x6f, 0x6b, 0x4c, 0x69, 0x73, 0x74,
+	0x54, 0x72, 0x75, 0x73, 0x74, 0x65, 0x64, 0x12, 0x2f, 0x0a, 0x13, 0x76, 0x61, 0x6c, 0x69, 0x64,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x64, 0x69, 0x73, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x18, 0x07,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x12, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x44, 0x69, 0x73, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x22, 0xbe, 0x01, 0x0a, 0x0e, 0x49, 0x6d, 0x61,
+	0x4d, 0x65, 0x61, 0x73, 0x75, 0x72, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x70,
+	0x63, 0x72, 0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08,
+	0x70, 0x63, 0x72, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x1a, 0x0a, 0x08, 0x74, 0x65, 0x6d, 0x70,
+	0x6c, 0x61, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x74, 0x65, 0x6d, 0x70,
+	0x6c, 0x61, 0x74, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x16, 0x0a, 0x06, 0x64, 0x69, 0x67, 0x65,
+	0x73, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74,
+	0x12, 0x29, 0x0a, 0x10, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x5f, 0x61, 0x6c, 0x67, 0x6f, 0x72,
+	0x69, 0x74, 0x68, 0x6d, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x64, 0x69, 0x67, 0x65,
+	0x73, 0x74, 0x41, 0x6c, 0x67, 0x6f, 0x72, 0x69, 0x74, 0x68, 0x6d, 0x12, 0x1c, 0x0a, 0x09, 0x76,
+	0x69, 0x6f, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09,
+	0x76, 0x69, 0x6f, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x46, 0x0a, 0x08, 0x49, 0x6d, 0x61,
+	0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x3a, 0x0a, 0x0c, 0x6d, 0x65, 0x61, 0x73, 0x75, 0x72, 0x65,
+	0x6d, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x61, 0x74,
+	0x74, 0x65, 0x73, 0x74, 0x2e, 0x49, 0x6d, 0x61, 0x4d, 0x65, 0x61, 0x73, 0x75, 0x72, 0x65, 0x6d,
+	0x65, 0x6e, 0x74, 0x52, 0x0c, 0x6d, 0x65, 0x61, 0x73, 0x75, 0x72, 0x65, 0x6d, 0x65, 0x6e, 0x74,
+	0x73, 0x22, 0x93, 0x04, 0x0a, 0x0e, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x53,
+	0x74, 0x61, 0x74, 0x65, 0x12, 0x27, 0x0a, 0x0f, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x5f, 0x72, 0x65,
+	0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x69,
+	0x6d, 0x61, 0x67, 0x65, 0x52, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x12, 0x21, 0x0a,
+	0x0c, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x5f, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0b, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74,
+	0x12, 0x3c, 0x0a, 0x0e, 0x72, 0x65, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x70, 0x6f, 0x6c, 0x69,
+	0x63, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x15, 0x2e, 0x61, 0x74, 0x74, 0x65, 0x73,
+	0x74, 0x2e, 0x52, 0x65, 0x73, 0x74, 0x61, 0x72, 0x74, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x52,
+	0x0d, 0x72, 0x65, 0x73, 0x74, 0x61, 0x72, 0x74, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x19,
+	0x0a, 0x08, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x07, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x49, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x61, 0x72, 0x67,
+	0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x61, 0x72, 0x67, 0x73, 0x12, 0x3e, 0x0a,
+	0x08, 0x65, 0x6e, 0x76, 0x5f, 0x76, 0x61, 0x72, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x23, 0x2e, 0x61, 0x74, 0x74, 0x65, 0x73, 0x74, 0x2e, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e,
+	0x65, 0x72, 0x53, 0x74, 0x61, 0x74, 0x65, 0x2e, 0x45, 0x6e, 0x76, 0x56, 0x61, 0x72, 0x73, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x52, 0x07, 0x65, 0x6e, 0x76, 0x56, 0x61, 0x72, 0x73, 0x12, 0x27, 0x0a,
+	0x0f, 0x6f, 0x76, 0x65, 0x72, 0x72, 0x69, 0x64, 0x64, 0x65, 0x6e, 0x5f, 0x61, 0x72, 0x67, 0x73,
+	0x18, 0x07, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0e, 0x6f, 0x76, 0x65, 0x72, 0x72, 0x69, 0x64, 0x64,
+	0x65, 0x6e, 0x41, 0x72, 0x67, 0x73, 0x12, 0x5d, 0x0a, 0x13, 0x6f, 0x76, 0x65, 0x72, 0x72, 0x69,
+	0x64, 0x64, 0x65, 0x6e, 0x5f, 0x65, 0x6e, 0x76, 0x5f, 0x76, 0x61, 0x72, 0x73, 0x18, 0x08, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x2d, 0x2e, 0x61, 0x74, 0x74, 0x65, 0x73, 0x74, 0x2e, 0x43, 0x6f, 0x6e,
+	0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x53, 0x74, 0x61, 0x74, 0x65, 0x2e, 0x4f, 0x76, 0x65, 0x72,
+	0x72, 0x69, 0x64, 0x64, 0x65, 0x6e, 0x45, 0x6e, 0x76, 0x56, 0x61, 0x72, 0x73, 0x45, 0x6e, 0x74,
+	0x72, 0x79, 0x52, 0x11, 0x6f, 0x76, 0x65, 0x72, 0x72, 0x69, 0x64, 0x64, 0x65, 0x6e, 0x45, 0x6e,
+	0x76, 0x56, 0x61, 0x72, 0x73, 0x1a, 0x3a, 0x0a, 0x0c, 0x45, 0x6e, 0x76, 0x56, 0x61, 0x72, 0x73,
+	0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38,
+	0x01, 0x1a, 0x44, 0x0a, 0x16, 0x4f, 0x76, 0x65, 0x72, 0x72, 0x69, 0x64, 0x64, 0x65, 0x6e, 0x45,
+	0x6e, 0x76, 0x56, 0x61, 0x72, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b,
+	0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a,
+	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x53, 0x0a, 0x0f, 0x53, 0x65, 0x6d, 0x61, 0x6e,
+	0x74, 0x69, 0x63, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x6d, 0x61,
+	0x6a, 0x6f, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x6d, 0x61, 0x6a, 0x6f, 0x72,
+	0x12, 0x14, 0x0a, 0x05, 0x6d, 0x69, 0x6e, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x05, 0x6d, 0x69, 0x6e, 0x6f, 0x72, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x61, 0x74, 0x63, 0x68, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x70, 0x61, 0x74, 0x63, 0x68, 0x22, 0x56, 0x0a, 0x15,
+	0x48, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x4d, 0x6f, 0x6e, 0x69, 0x74, 0x6f, 0x72, 0x69, 0x6e, 0x67,
+	0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x2a, 0x0a, 0x0e, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x5f,
+	0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x48, 0x00, 0x52,
+	0x0d, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x45, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x88, 0x01,
+	0x01, 0x42, 0x11, 0x0a, 0x0f, 0x5f, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x5f, 0x65, 0x6e, 0x61,
+	0x62, 0x6c, 0x65, 0x64, 0x22, 0x42, 0x0a, 0x0e, 0x47, 0x70, 0x75, 0x44, 0x65, 0x76, 0x69, 0x63,
+	0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x30, 0x0a, 0x07, 0x63, 0x63, 0x5f, 0x6d, 0x6f, 0x64,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x17, 0x2e, 0x61, 0x74, 0x74, 0x65, 0x73, 0x74,
+	0x2e, 0x47, 0x50, 0x55, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x43, 0x43, 0x4d, 0x6f, 0x64, 0x65,
+	0x52, 0x06, 0x63, 0x63, 0x4d, 0x6f, 0x64, 0x65, 0x22, 0x3d, 0x0a, 0x0d, 0x57, 0x6f, 0x72, 0x6b,
+	0x6c, 0x6f, 0x61, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x18, 0x0a,
+	0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07,
+	0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x22, 0x45, 0x0a, 0x0e, 0x49, 0x6d, 0x70, 0x6f, 0x72,
+	0x74, 0x65, 0x64, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1f, 0x0a,
+	0x0b, 0x62, 0x6c, 0x6f, 0x62, 0x5f, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x0a, 0x62, 0x6c, 0x6f, 0x62, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x22, 0xfc,
+	0x03, 0x0a, 0x10, 0x41, 0x74, 0x74, 0x65, 0x73, 0x74, 0x65, 0x64, 0x43, 0x6f, 0x73, 0x53, 0x74,
+	0x61, 0x74, 0x65, 0x12, 0x34, 0x0a, 0x09, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x61, 0x74, 0x74, 0x65, 0x73, 0x74, 0x2e,
+	0x43, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x09,
+	0x63, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x12, 0x38, 0x0a, 0x0b, 0x63, 0x6f, 0x73,
+	0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17,
+	0x2e, 0x61, 0x74, 0x74, 0x65, 0x73, 0x74, 0x2e, 0x53, 0x65, 0x6d, 0x61, 0x6e, 0x74, 0x69, 0x63,
+	0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x0a, 0x63, 0x6f, 0x73, 0x56, 0x65, 0x72, 0x73,
+	0x69, 0x6f, 0x6e, 0x12, 0x42, 0x0a, 0x10, 0x6c, 0x61, 0x75, 0x6e, 0x63, 0x68, 0x65, 0x72, 0x5f,
+	0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e,
+	0x61, 0x74, 0x74, 0x65, 0x73, 0x74, 0x2e, 0x53, 0x65, 0x6d, 0x61, 0x6e, 0x74, 0x69, 0x63, 0x56,
+	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x0f, 0x6c, 0x61, 0x75, 0x6e, 0x63, 0x68, 0x65, 0x72,
+	0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x4a, 0x0a, 0x11, 0x68, 0x65, 0x61, 0x6c, 0x74,
+	0x68, 0x5f, 0x6d, 0x6f, 0x6e, 0x69, 0x74, 0x6f, 0x72, 0x69, 0x6e, 0x67, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x61, 0x74, 0x74, 0x65, 0x73, 0x74, 0x2e, 0x48, 0x65, 0x61, 0x6c,
+	0x74, 0x68, 0x4d, 0x6f, 0x6e, 0x69, 0x74, 0x6f, 0x72, 0x69, 0x6e, 0x67, 0x53, 0x74, 0x61, 0x74,
+	0x65, 0x52, 0x10, 0x68, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x4d, 0x6f, 0x6e, 0x69, 0x74, 0x6f, 0x72,
+	0x69, 0x6e, 0x67, 0x12, 0x40, 0x0a, 0x10, 0x67, 0x70, 0x75, 0x5f, 0x64, 0x65, 0x76, 0x69, 0x63,
+	0x65, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e,
+	0x61, 0x74, 0x74, 0x65, 0x73, 0x74, 0x2e, 0x47, 0x70, 0x75, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65,
+	0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x0e, 0x67, 0x70, 0x75, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65,
+	0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x3e, 0x0a, 0x0f, 0x77, 0x6f, 0x72, 0x6b, 0x6c, 0x6f, 0x61,
+	0x64, 0x5f, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15,
+	0x2e, 0x61, 0x74, 0x74, 0x65, 0x73, 0x74, 0x2e, 0x57, 0x6f, 0x72, 0x6b, 0x6c, 0x6f, 0x61, 0x64,
+	0x45, 0x76, 0x65, 0x6e, 0x74, 0x52, 0x0e, 0x77, 0x6f, 0x72, 0x6b, 0x6c, 0x6f, 0x61, 0x64, 0x45,
+	0x76, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x34, 0x0a, 0x16, 0x77, 0x6f, 0x72, 0x6b, 0x6c, 0x6f, 0x61,
+	0x64, 0x5f, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x5f, 0x6c, 0x6f, 0x63, 0x6b, 0x65, 0x64, 0x18,
+	0x07, 0x20, 0x01, 0x28, 0x08, 0x52, 0x14, 0x77, 0x6f, 0x72, 0x6b, 0x6c, 0x6f, 0x61, 0x64, 0x45,
+	0x76, 0x65, 0x6e, 0x74, 0x73, 0x4c, 0x6f, 0x63, 0x6b, 0x65, 0x64, 0x12, 0x30, 0x0a, 0x07, 0x73,
+	0x65, 0x63, 0x72, 0x65, 0x74, 0x73, 0x18, 0x08, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x61,
+	0x74, 0x74, 0x65, 0x73, 0x74, 0x2e, 0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x65, 0x64, 0x53, 0x65,
+	0x63, 0x72, 0x65, 0x74, 0x52, 0x07, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x73, 0x22, 0x20, 0x0a,
+	0x06, 0x45, 0x66, 0x69, 0x41, 0x70, 0x70, 0x12, 0x16, 0x0a, 0x06, 0x64, 0x69, 0x67, 0x65, 0x73,
+	0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x22,
+	0x2e, 0x0a, 0x08, 0x45, 0x66, 0x69, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x22, 0x0a, 0x04, 0x61,
+	0x70, 0x70, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x61, 0x74, 0x74, 0x65,
+	0x73, 0x74, 0x2e, 0x45, 0x66, 0x69, 0x41, 0x70, 0x70, 0x52, 0x04, 0x61, 0x70, 0x70, 0x73, 0x22,
+	0x9b, 0x05, 0x0a, 0x0c, 0x4d, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65,
+	0x12, 0x31, 0x0a, 0x08, 0x70, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x15, 0x2e, 0x61, 0x74, 0x74, 0x65, 0x73, 0x74, 0x2e, 0x50, 0x6c, 0x61, 0x74,
+	0x66, 0x6f, 0x72, 0x6d, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x08, 0x70, 0x6c, 0x61, 0x74, 0x66,
+	0x6f, 0x72, 0x6d, 0x12, 0x38, 0x0a, 0x0b, 0x73, 0x65, 0x63, 0x75, 0x72, 0x65, 0x5f, 0x62, 0x6f,
+	0x6f, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x61, 0x74, 0x74, 0x65, 0x73,
+	0x74, 0x2e, 0x53, 0x65, 0x63, 0x75, 0x72, 0x65, 0x42, 0x6f, 0x6f, 0x74, 0x53, 0x74, 0x61, 0x74,
+	0x65, 0x52, 0x0a, 0x73, 0x65, 0x63, 0x75, 0x72, 0x65, 0x42, 0x6f, 0x6f, 0x74, 0x12, 0x2c, 0x0a,
+	0x0a, 0x72, 0x61, 0x77, 0x5f, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x0d, 0x2e, 0x61, 0x74, 0x74, 0x65, 0x73, 0x74, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74,
+	0x52, 0x09, 0x72, 0x61, 0x77, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x21, 0x0a, 0x04, 0x68,
+	0x61, 0x73, 0x68, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0d, 0x2e, 0x74, 0x70, 0x6d, 0x2e,
+	0x48, 0x61, 0x73, 0x68, 0x41, 0x6c, 0x67, 0x6f, 0x52, 0x04, 0x68, 0x61, 0x73, 0x68, 0x12, 0x25,
+	0x0a, 0x04, 0x67, 0x72, 0x75, 0x62, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x61,
+	0x74, 0x74, 0x65, 0x73, 0x74, 0x2e, 0x47, 0x72, 0x75, 0x62, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52,
+	0x04, 0x67, 0x72, 0x75, 0x62, 0x12, 0x3b, 0x0a, 0x0c, 0x6c, 0x69, 0x6e, 0x75, 0x78, 0x5f, 0x6b,
+	0x65, 0x72, 0x6e, 0x65, 0x6c, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x61, 0x74,
+	0x74, 0x65, 0x73, 0x74, 0x2e, 0x4c, 0x69, 0x6e, 0x75, 0x78, 0x4b, 0x65, 0x72, 0x6e, 0x65, 0x6c,
+	0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x0b, 0x6c, 0x69, 0x6e, 0x75, 0x78, 0x4b, 0x65, 0x72, 0x6e,
+	0x65, 0x6c, 0x12, 0x2a, 0x0a, 0x03, 0x63, 0x6f, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x18, 0x2e, 0x61, 0x74, 0x74, 0x65, 0x73, 0x74, 0x2e, 0x41, 0x74, 0x74, 0x65, 0x73, 0x74, 0x65,
+	0x64, 0x43, 0x6f, 0x73, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x03, 0x63, 0x6f, 0x73, 0x12, 0x22,
+	0x0a, 0x03, 0x65, 0x66, 0x69, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x61, 0x74,
+	0x74, 0x65, 0x73, 0x74, 0x2e, 0x45, 0x66, 0x69, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x03, 0x65,
+	0x66, 0x69, 0x12, 0x45, 0x0a, 0x13, 0x73, 0x65, 0x76, 0x5f, 0x73, 0x6e, 0x70, 0x5f, 0x61, 0x74,
+	0x74, 0x65, 0x73, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x13, 0x2e, 0x73, 0x65, 0x76, 0x73, 0x6e, 0x70, 0x2e, 0x41, 0x74, 0x74, 0x65, 0x73, 0x74, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x48, 0x00, 0x52, 0x11, 0x73, 0x65, 0x76, 0x53, 0x6e, 0x70, 0x41, 0x74,
+	0x74, 0x65, 0x73, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x37, 0x0a, 0x0f, 0x74, 0x64, 0x78,
+	0x5f, 0x61, 0x74, 0x74, 0x65, 0x73, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x0a, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x74, 0x64, 0x78, 0x2e, 0x51, 0x75, 0x6f, 0x74, 0x65, 0x56, 0x34,
+	0x48, 0x00, 0x52, 0x0e, 0x74, 0x64, 0x78, 0x41, 0x74, 0x74, 0x65, 0x73, 0x74, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x12, 0x3b, 0x0a, 0x0c, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x64, 0x5f, 0x62, 0x6f,
+	0x6f, 0x74, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x61, 0x74, 0x74, 0x65, 0x73,
+	0x74, 0x2e, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x64, 0x42, 0x6f, 0x6f, 0x74, 0x53, 0x74, 0x61,
+	0x74, 0x65, 0x52, 0x0b, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x64, 0x42, 0x6f, 0x6f, 0x74, 0x12,
+	0x25, 0x0a, 0x04, 0x73, 0x68, 0x69, 0x6d, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e,
+	0x61, 0x74, 0x74, 0x65, 0x73, 0x74, 0x2e, 0x53, 0x68, 0x69, 0x6d, 0x53, 0x74, 0x61, 0x74, 0x65,
+	0x52, 0x04, 0x73, 0x68, 0x69, 0x6d, 0x12, 0x22, 0x0a, 0x03, 0x69, 0x6d, 0x61, 0x18, 0x0d, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x61, 0x74, 0x74, 0x65, 0x73, 0x74, 0x2e, 0x49, 0x6d, 0x61,
+	0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x03, 0x69, 0x6d, 0x61, 0x42, 0x11, 0x0a, 0x0f, 0x74, 0x65,
+	0x65, 0x5f, 0x61, 0x74, 0x74, 0x65, 0x73, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0xde, 0x01,
+	0x0a, 0x0e, 0x50, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79,
+	0x12, 0x39, 0x0a, 0x19, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x5f, 0x73, 0x63, 0x72, 0x74,
+	0x6d, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x73, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x0c, 0x52, 0x16, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x53, 0x63, 0x72, 0x74,
+	0x6d, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x73, 0x12, 0x3f, 0x0a, 0x1c, 0x6d,
+	0x69, 0x6e, 0x69, 0x6d, 0x75, 0x6d, 0x5f, 0x67, 0x63, 0x65, 0x5f, 0x66, 0x69, 0x72, 0x6d, 0x77,
+	0x61, 0x72, 0x65, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x19, 0x6d, 0x69, 0x6e, 0x69, 0x6d, 0x75, 0x6d, 0x47, 0x63, 0x65, 0x46, 0x69, 0x72,
+	0x6d, 0x77, 0x61, 0x72, 0x65, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x50, 0x0a, 0x12,
+	0x6d, 0x69, 0x6e, 0x69, 0x6d, 0x75, 0x6d, 0x5f, 0x74, 0x65, 0x63, 0x68, 0x6e, 0x6f, 0x6c, 0x6f,
+	0x67, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x21, 0x2e, 0x61, 0x74, 0x74, 0x65, 0x73,
+	0x74, 0x2e, 0x47, 0x43, 0x45, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x61,
+	0x6c, 0x54, 0x65, 0x63, 0x68, 0x6e, 0x6f, 0x6c, 0x6f, 0x67, 0x79, 0x52, 0x11, 0x6d, 0x69, 0x6e,
+	0x69, 0x6d, 0x75, 0x6d, 0x54, 0x65, 0x63, 0x68, 0x6e, 0x6f, 0x6c, 0x6f, 0x67, 0x79, 0x22, 0x51,
+	0x0a, 0x09, 0x52, 0x49, 0x4d, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x25, 0x0a, 0x0e, 0x72,
+	0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x5f, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x0d, 0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x53, 0x69, 0x67, 0x6e,
+	0x65, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x72, 0x6f, 0x6f, 0x74, 0x5f, 0x63, 0x65, 0x72, 0x74, 0x73,
+	0x18, 0x02, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x09, 0x72, 0x6f, 0x6f, 0x74, 0x43, 0x65, 0x72, 0x74,
+	0x73, 0x22, 0x35, 0x0a, 0x0c, 0x53, 0x65, 0x76, 0x53, 0x6e, 0x70, 0x50, 0x6f, 0x6c, 0x69, 0x63,
+	0x79, 0x12, 0x25, 0x0a, 0x04, 0x75, 0x65, 0x66, 0x69, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x11, 0x2e, 0x61, 0x74, 0x74, 0x65, 0x73, 0x74, 0x2e, 0x52, 0x49, 0x4d, 0x50, 0x6f, 0x6c, 0x69,
+	0x63, 0x79, 0x52, 0x04, 0x75, 0x65, 0x66, 0x69, 0x22, 0x95, 0x02, 0x0a, 0x10, 0x53, 0x65, 0x63,
+	0x75, 0x72, 0x65, 0x42, 0x6f, 0x6f, 0x74, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x27, 0x0a,
+	0x0f, 0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x5f, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0e, 0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x45,
+	0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x12, 0x33, 0x0a, 0x0c, 0x72, 0x65, 0x71, 0x75, 0x69, 0x72,
+	0x65, 0x64, 0x5f, 0x64, 0x62, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x61,
+	0x74, 0x74, 0x65, 0x73, 0x74, 0x2e, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x52, 0x0b,
+	0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x44, 0x62, 0x78, 0x12, 0x33, 0x0a, 0x0c, 0x66,
+	0x6f, 0x72, 0x62, 0x69, 0x64, 0x64, 0x65, 0x6e, 0x5f, 0x64, 0x62, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x10, 0x2e, 0x61, 0x74, 0x74, 0x65, 0x73, 0x74, 0x2e, 0x44, 0x61, 0x74, 0x61, 0x62,
+	0x61, 0x73, 0x65, 0x52, 0x0b, 0x66, 0x6f, 0x72, 0x62, 0x69, 0x64, 0x64, 0x65, 0x6e, 0x44, 0x62,
+	0x12, 0x3d, 0x0a, 0x11, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x5f, 0x61, 0x75, 0x74, 0x68,
+	0x6f, 0x72, 0x69, 0x74, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x61, 0x74,
+	0x74, 0x65, 0x73, 0x74, 0x2e, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x52, 0x10, 0x61,
+	0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x41, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x69, 0x74, 0x79, 0x12,
+	0x2f, 0x0a, 0x0a, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x5f, 0x70, 0x6b, 0x18, 0x05, 0x20,
 	0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x61, 0x74, 0x74, 0x65, 0x73, 0x74, 0x2e, 0x44, 0x61, 0x74,
-	0x61, 0x62, 0x61, 0x73, 0x65, 0x52, 0x03, 0x64, 0x62, 0x78, 0x12, 0x2e, 0x0a, 0x09, 0x61, 0x75,
-	0x74, 0x68, 0x6f, 0x72, 0x69, 0x74, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e,
-	0x61, 0x74, 0x74, 0x65, 0x73, 0x74, 0x2e, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x52,
-	0x09, 0x61, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x69, 0x74, 0x79, 0x12, 0x20, 0x0a, 0x02, 0x70, 0x6b,
-	0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x61, 0x74, 0x74, 0x65, 0x73, 0x74, 0x2e,
-	0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x52, 0x02, 0x70, 0x6b, 0x12, 0x22, 0x0a, 0x03,
-	0x6b, 0x65, 0x6b, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x61, 0x74, 0x74, 0x65,
-	0x73, 0x74, 0x2e, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x52, 0x03, 0x6b, 0x65, 0x6b,
-	0x22, 0x93, 0x04, 0x0a, 0x0e, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x53, 0x74,
-	0x61, 0x74, 0x65, 0x12, 0x27, 0x0a, 0x0f, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x5f, 0x72, 0x65, 0x66,
-	0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x69, 0x6d,
-	0x61, 0x67, 0x65, 0x52, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x12, 0x21, 0x0a, 0x0c,
-	0x69, 0x6d, 0x61, 0x67, 0x65, 0x5f, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x0b, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x12,
-	0x3c, 0x0a, 0x0e, 0x72, 0x65, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x70, 0x6f, 0x6c, 0x69, 0x63,
-	0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x15, 0x2e, 0x61, 0x74, 0x74, 0x65, 0x73, 0x74,
-	0x2e, 0x52, 0x65, 0x73, 0x74, 0x61, 0x72, 0x74, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x52, 0x0d,
-	0x72, 0x65, 0x73, 0x74, 0x61, 0x72, 0x74, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x19, 0x0a,
-	0x08, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x07, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x49, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x61, 0x72, 0x67, 0x73,
-	0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x61, 0x72, 0x67, 0x73, 0x12, 0x3e, 0x0a, 0x08,
-	0x65, 0x6e, 0x76, 0x5f, 0x76, 0x61, 0x72, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x23,
-	0x2e, 0x61, 0x74, 0x74, 0x65, 0x73, 0x74, 0x2e, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65,
-	0x72, 0x53, 0x74, 0x61, 0x74, 0x65, 0x2e, 0x45, 0x6e, 0x76, 0x56, 0x61, 0x72, 0x73, 0x45, 0x6e,
-	0x74, 0x72, 0x79, 0x52, 0x07, 0x65, 0x6e, 0x76, 0x56, 0x61, 0x72, 0x73, 0x12, 0x27, 0x0a, 0x0f,
-	0x6f, 0x76, 0x65, 0x72, 0x72, 0x69, 0x64, 0x64, 0x65, 0x6e, 0x5f, 0x61, 0x72, 0x67, 0x73, 0x18,
-	0x07, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0e, 0x6f, 0x76, 0x65, 0x72, 0x72, 0x69, 0x64, 0x64, 0x65,
-	0x6e, 0x41, 0x72, 0x67, 0x73, 0x12, 0x5d, 0x0a, 0x13, 0x6f, 0x76, 0x65, 0x72, 0x72, 0x69, 0x64,
-	0x64, 0x65, 0x6e, 0x5f, 0x65, 0x6e, 0x76, 0x5f, 0x76, 0x61, 0x72, 0x73, 0x18, 0x08, 0x20, 0x03,
-	0x28, 0x0b, 0x32, 0x2d, 0x2e, 0x61, 0x74, 0x74, 0x65, 0x73, 0x74, 0x2e, 0x43, 0x6f, 0x6e, 0x74,
-	0x61, 0x69, 0x6e, 0x65, 0x72, 0x53, 0x74, 0x61, 0x74, 0x65, 0x2e, 0x4f, 0x76, 0x65, 0x72, 0x72,
-	0x69, 0x64, 0x64, 0x65, 0x6e, 0x45, 0x6e, 0x76, 0x56, 0x61, 0x72, 0x73, 0x45, 0x6e, 0x74, 0x72,
-	0x79, 0x52, 0x11, 0x6f, 0x76, 0x65, 0x72, 0x72, 0x69, 0x64, 0x64, 0x65, 0x6e, 0x45, 0x6e, 0x76,
-	0x56, 0x61, 0x72, 0x73, 0x1a, 0x3a, 0x0a, 0x0c, 0x45, 0x6e, 0x76, 0x56, 0x61, 0x72, 0x73, 0x45,
-	0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01,
-	0x1a, 0x44, 0x0a, 0x16, 0x4f, 0x76, 0x65, 0x72, 0x72, 0x69, 0x64, 0x64, 0x65, 0x6e, 0x45, 0x6e,
-	0x76, 0x56, 0x61, 0x72, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65,
-	0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05,
-	0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c,
-	0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x53, 0x0a, 0x0f, 0x53, 0x65, 0x6d, 0x61, 0x6e, 0x74,
-	0x69, 0x63, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x6d, 0x61, 0x6a,
-	0x6f, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x6d, 0x61, 0x6a, 0x6f, 0x72, 0x12,
-	0x14, 0x0a, 0x05, 0x6d, 0x69, 0x6e, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05,
-	0x6d, 0x69, 0x6e, 0x6f, 0x72, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x61, 0x74, 0x63, 0x68, 0x18, 0x03,
-	0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x70, 0x61, 0x74, 0x63, 0x68, 0x22, 0x56, 0x0a, 0x15, 0x48,
-	0x65, 0x61, 0x6c, 0x74, 0x68, 0x4d, 0x6f, 0x6e, 0x69, 0x74, 0x6f, 0x72, 0x69, 0x6e, 0x67, 0x53,
-	0x74, 0x61, 0x74, 0x65, 0x12, 0x2a, 0x0a, 0x0e, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x5f, 0x65,
-	0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x48, 0x00, 0x52, 0x0d,
-	0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x45, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x88, 0x01, 0x01,
-	0x42, 0x11, 0x0a, 0x0f, 0x5f, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x5f, 0x65, 0x6e, 0x61, 0x62,
-	0x6c, 0x65, 0x64, 0x22, 0x42, 0x0a, 0x0e, 0x47, 0x70, 0x75, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65,
-	0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x30, 0x0a, 0x07, 0x63, 0x63, 0x5f, 0x6d, 0x6f, 0x64, 0x65,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x17, 0x2e, 0x61, 0x74, 0x74, 0x65, 0x73, 0x74, 0x2e,
-	0x47, 0x50, 0x55, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x43, 0x43, 0x4d, 0x6f, 0x64, 0x65, 0x52,
-	0x06, 0x63, 0x63, 0x4d, 0x6f, 0x64, 0x65, 0x22, 0xd4, 0x02, 0x0a, 0x10, 0x41, 0x74, 0x74, 0x65,
-	0x73, 0x74, 0x65, 0x64, 0x43, 0x6f, 0x73, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x34, 0x0a, 0x09,
-	0x63, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
-	0x16, 0x2e, 0x61, 0x74, 0x74, 0x65, 0x73, 0x74, 0x2e, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e,
-	0x65, 0x72, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x09, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e,
-	0x65, 0x72, 0x12, 0x38, 0x0a, 0x0b, 0x63, 0x6f, 0x73, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f,
-	0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x61, 0x74, 0x74, 0x65, 0x73, 0x74,
-	0x2e, 0x53, 0x65, 0x6d, 0x61, 0x6e, 0x74, 0x69, 0x63, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
-	0x52, 0x0a, 0x63, 0x6f, 0x73, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x42, 0x0a, 0x10,
-	0x6c, 0x61, 0x75, 0x6e, 0x63, 0x68, 0x65, 0x72, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
-	0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x61, 0x74, 0x74, 0x65, 0x73, 0x74, 0x2e,
-	0x53, 0x65, 0x6d, 0x61, 0x6e, 0x74, 0x69, 0x63, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x52,
-	0x0f, 0x6c, 0x61, 0x75, 0x6e, 0x63, 0x68, 0x65, 0x72, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
-	0x12, 0x4a, 0x0a, 0x11, 0x68, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x5f, 0x6d, 0x6f, 0x6e, 0x69, 0x74,
-	0x6f, 0x72, 0x69, 0x6e, 0x67, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x61, 0x74,
-	0x74, 0x65, 0x73, 0x74, 0x2e, 0x48, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x4d, 0x6f, 0x6e, 0x69, 0x74,
-	0x6f, 0x72, 0x69, 0x6e, 0x67, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x10, 0x68, 0x65, 0x61, 0x6c,
-	0x74, 0x68, 0x4d, 0x6f, 0x6e, 0x69, 0x74, 0x6f, 0x72, 0x69, 0x6e, 0x67, 0x12, 0x40, 0x0a, 0x10,
-	0x67, 0x70, 0x75, 0x5f, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x65,
-	0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x61, 0x74, 0x74, 0x65, 0x73, 0x74, 0x2e,
-	0x47, 0x70, 0x75, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x0e,
-	0x67, 0x70, 0x75, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x22, 0x20,
-	0x0a, 0x06, 0x45, 0x66, 0x69, 0x41, 0x70, 0x70, 0x12, 0x16, 0x0a, 0x06, 0x64, 0x69, 0x67, 0x65,
-	0x73, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74,
-	0x22, 0x2e, 0x0a, 0x08, 0x45, 0x66, 0x69, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x22, 0x0a, 0x04,
-	0x61, 0x70, 0x70, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x61, 0x74, 0x74,
-	0x65, 0x73, 0x74, 0x2e, 0x45, 0x66, 0x69, 0x41, 0x70, 0x70, 0x52, 0x04, 0x61, 0x70, 0x70, 0x73,
-	0x22, 0x93, 0x04, 0x0a, 0x0c, 0x4d, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x65, 0x53, 0x74, 0x61, 0x74,
-	0x65, 0x12, 0x31, 0x0a, 0x08, 0x70, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x61, 0x74, 0x74, 0x65, 0x73, 0x74, 0x2e, 0x50, 0x6c, 0x61,
-	0x74, 0x66, 0x6f, 0x72, 0x6d, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x08, 0x70, 0x6c, 0x61, 0x74,
-	0x66, 0x6f, 0x72, 0x6d, 0x12, 0x38, 0x0a, 0x0b, 0x73, 0x65, 0x63, 0x75, 0x72, 0x65, 0x5f, 0x62,
-	0x6f, 0x6f, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x61, 0x74, 0x74, 0x65,
-	0x73, 0x74, 0x2e, 0x53, 0x65, 0x63, 0x75, 0x72, 0x65, 0x42, 0x6f, 0x6f, 0x74, 0x53, 0x74, 0x61,
-	0x74, 0x65, 0x52, 0x0a, 0x73, 0x65, 0x63, 0x75, 0x72, 0x65, 0x42, 0x6f, 0x6f, 0x74, 0x12, 0x2c,
-	0x0a, 0x0a, 0x72, 0x61, 0x77, 0x5f, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x03, 0x20, 0x03,
-	0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x61, 0x74, 0x74, 0x65, 0x73, 0x74, 0x2e, 0x45, 0x76, 0x65, 0x6e,
-	0x74, 0x52, 0x09, 0x72, 0x61, 0x77, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x21, 0x0a, 0x04,
-	0x68, 0x61, 0x73, 0x68, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0d, 0x2e, 0x74, 0x70, 0x6d,
-	0x2e, 0x48, 0x61, 0x73, 0x68, 0x41, 0x6c, 0x67, 0x6f, 0x52, 0x04, 0x68, 0x61, 0x73, 0x68, 0x12,
-	0x25, 0x0a, 0x04, 0x67, 0x72, 0x75, 0x62, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e,
-	0x61, 0x74, 0x74, 0x65, 0x73, 0x74, 0x2e, 0x47, 0x72, 0x75, 0x62, 0x53, 0x74, 0x61, 0x74, 0x65,
-	0x52, 0x04, 0x67, 0x72, 0x75, 0x62, 0x12, 0x3b, 0x0a, 0x0c, 0x6c, 0x69, 0x6e, 0x75, 0x78, 0x5f,
-	0x6b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x61,
-	0x74, 0x74, 0x65, 0x73, 0x74, 0x2e, 0x4c, 0x69, 0x6e, 0x75, 0x78, 0x4b, 0x65, 0x72, 0x6e, 0x65,
-	0x6c, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x0b, 0x6c, 0x69, 0x6e, 0x75, 0x78, 0x4b, 0x65, 0x72,
-	0x6e, 0x65, 0x6c, 0x12, 0x2a, 0x0a, 0x03, 0x63, 0x6f, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x18, 0x2e, 0x61, 0x74, 0x74, 0x65, 0x73, 0x74, 0x2e, 0x41, 0x74, 0x74, 0x65, 0x73, 0x74,
-	0x65, 0x64, 0x43, 0x6f, 0x73, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x03, 0x63, 0x6f, 0x73, 0x12,
-	0x22, 0x0a, 0x03, 0x65, 0x66, 0x69, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x61,
-	0x74, 0x74, 0x65, 0x73, 0x74, 0x2e, 0x45, 0x66, 0x69, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x03,
-	0x65, 0x66, 0x69, 0x12, 0x45, 0x0a, 0x13, 0x73, 0x65, 0x76, 0x5f, 0x73, 0x6e, 0x70, 0x5f, 0x61,
-	0x74, 0x74, 0x65, 0x73, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x13, 0x2e, 0x73, 0x65, 0x76, 0x73, 0x6e, 0x70, 0x2e, 0x41, 0x74, 0x74, 0x65, 0x73, 0x74,
-	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x48, 0x00, 0x52, 0x11, 0x73, 0x65, 0x76, 0x53, 0x6e, 0x70, 0x41,
-	0x74, 0x74, 0x65, 0x73, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x37, 0x0a, 0x0f, 0x74, 0x64,
-	0x78, 0x5f, 0x61, 0x74, 0x74, 0x65, 0x73, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x0a, 0x20,
-	0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x74, 0x64, 0x78, 0x2e, 0x51, 0x75, 0x6f, 0x74, 0x65, 0x56,
-	0x34, 0x48, 0x00, 0x52, 0x0e, 0x74, 0x64, 0x78, 0x41, 0x74, 0x74, 0x65, 0x73, 0x74, 0x61, 0x74,
-	0x69, 0x6f, 0x6e, 0x42, 0x11, 0x0a, 0x0f, 0x74, 0x65, 0x65, 0x5f, 0x61, 0x74, 0x74, 0x65, 0x73,
-	0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0xde, 0x01, 0x0a, 0x0e, 0x50, 0x6c, 0x61, 0x74, 0x66,
-	0x6f, 0x72, 0x6d, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x39, 0x0a, 0x19, 0x61, 0x6c, 0x6c,
-	0x6f, 0x77, 0x65, 0x64, 0x5f, 0x73, 0x63, 0x72, 0x74, 0x6d, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69,
-	0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x16, 0x61, 0x6c,
-	0x6c, 0x6f, 0x77, 0x65, 0x64, 0x53, 0x63, 0x72, 0x74, 0x6d, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f,
-	0x6e, 0x49, 0x64, 0x73, 0x12, 0x3f, 0x0a, 0x1c, 0x6d, 0x69, 0x6e, 0x69, 0x6d, 0x75, 0x6d, 0x5f,
-	0x67, 0x63, 0x65, 0x5f, 0x66, 0x69, 0x72, 0x6d, 0x77, 0x61, 0x72, 0x65, 0x5f, 0x76, 0x65, 0x72,
-	0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x19, 0x6d, 0x69, 0x6e, 0x69,
-	0x6d, 0x75, 0x6d, 0x47, 0x63, 0x65, 0x46, 0x69, 0x72, 0x6d, 0x77, 0x61, 0x72, 0x65, 0x56, 0x65,
-	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x50, 0x0a, 0x12, 0x6d, 0x69, 0x6e, 0x69, 0x6d, 0x75, 0x6d,
-	0x5f, 0x74, 0x65, 0x63, 0x68, 0x6e, 0x6f, 0x6c, 0x6f, 0x67, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28,
-	0x0e, 0x32, 0x21, 0x2e, 0x61, 0x74, 0x74, 0x65, 0x73, 0x74, 0x2e, 0x47, 0x43, 0x45, 0x43, 0x6f,
-	0x6e, 0x66, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x61, 0x6c, 0x54, 0x65, 0x63, 0x68, 0x6e, 0x6f,
-	0x6c, 0x6f, 0x67, 0x79, 0x52, 0x11, 0x6d, 0x69, 0x6e, 0x69, 0x6d, 0x75, 0x6d, 0x54, 0x65, 0x63,
-	0x68, 0x6e, 0x6f, 0x6c, 0x6f, 0x67, 0x79, 0x22, 0x51, 0x0a, 0x09, 0x52, 0x49, 0x4d, 0x50, 0x6f,
-	0x6c, 0x69, 0x63, 0x79, 0x12, 0x25, 0x0a, 0x0e, 0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x5f,
-	0x73, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0d, 0x72, 0x65,
-	0x71, 0x75, 0x69, 0x72, 0x65, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x72,
-	0x6f, 0x6f, 0x74, 0x5f, 0x63, 0x65, 0x72, 0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0c, 0x52,
-	0x09, 0x72, 0x6f, 0x6f, 0x74, 0x43, 0x65, 0x72, 0x74, 0x73, 0x22, 0x35, 0x0a, 0x0c, 0x53, 0x65,
-	0x76, 0x53, 0x6e, 0x70, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x25, 0x0a, 0x04, 0x75, 0x65,
-	0x66, 0x69, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x61, 0x74, 0x74, 0x65, 0x73,
-	0x74, 0x2e, 0x52, 0x49, 0x4d, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x52, 0x04, 0x75, 0x65, 0x66,
-	0x69, 0x22, 0x6b, 0x0a, 0x06, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x32, 0x0a, 0x08, 0x70,
-	0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e,
-	0x61, 0x74, 0x74, 0x65, 0x73, 0x74, 0x2e, 0x50, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x50,
-	0x6f, 0x6c, 0x69, 0x63, 0x79, 0x52, 0x08, 0x70, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x12,
-	0x2d, 0x0a, 0x07, 0x73, 0x65, 0x76, 0x5f, 0x73, 0x6e, 0x70, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x14, 0x2e, 0x61, 0x74, 0x74, 0x65, 0x73, 0x74, 0x2e, 0x53, 0x65, 0x76, 0x53, 0x6e, 0x70,
-	0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x52, 0x06, 0x73, 0x65, 0x76, 0x53, 0x6e, 0x70, 0x2a, 0x62,
-	0x0a, 0x19, 0x47, 0x43, 0x45, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x61,
-	0x6c, 0x54, 0x65, 0x63, 0x68, 0x6e, 0x6f, 0x6c, 0x6f, 0x67, 0x79, 0x12, 0x08, 0x0a, 0x04, 0x4e,
-	0x4f, 0x4e, 0x45, 0x10, 0x00, 0x12, 0x0b, 0x0a, 0x07, 0x41, 0x4d, 0x44, 0x5f, 0x53, 0x45, 0x56,
-	0x10, 0x01, 0x12, 0x0e, 0x0a, 0x0a, 0x41, 0x4d, 0x44, 0x5f, 0x53, 0x45, 0x56, 0x5f, 0x45, 0x53,
-	0x10, 0x02, 0x12, 0x0d, 0x0a, 0x09, 0x49, 0x4e, 0x54, 0x45, 0x4c, 0x5f, 0x54, 0x44, 0x58, 0x10,
-	0x03, 0x12, 0x0f, 0x0a, 0x0b, 0x41, 0x4d, 0x44, 0x5f, 0x53, 0x45, 0x56, 0x5f, 0x53, 0x4e, 0x50,
-	0x10, 0x04, 0x2a, 0x96, 0x01, 0x0a, 0x14, 0x57, 0x65, 0x6c, 0x6c, 0x4b, 0x6e, 0x6f, 0x77, 0x6e,
-	0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x12, 0x0b, 0x0a, 0x07, 0x55,
-	0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x00, 0x12, 0x1c, 0x0a, 0x18, 0x4d, 0x53, 0x5f, 0x57,
-	0x49, 0x4e, 0x44, 0x4f, 0x57, 0x53, 0x5f, 0x50, 0x52, 0x4f, 0x44, 0x5f, 0x50, 0x43, 0x41, 0x5f,
-	0x32, 0x30, 0x31, 0x31, 0x10, 0x01, 0x12, 0x1f, 0x0a, 0x1b, 0x4d, 0x53, 0x5f, 0x54, 0x48, 0x49,
-	0x52, 0x44, 0x5f, 0x50, 0x41, 0x52, 0x54, 0x59, 0x5f, 0x55, 0x45, 0x46, 0x49, 0x5f, 0x43, 0x41,
-	0x5f, 0x32, 0x30, 0x31, 0x31, 0x10, 0x02, 0x12, 0x1e, 0x0a, 0x1a, 0x4d, 0x53, 0x5f, 0x54, 0x48,
-	0x49, 0x52, 0x44, 0x5f, 0x50, 0x41, 0x52, 0x54, 0x59, 0x5f, 0x4b, 0x45, 0x4b, 0x5f, 0x43, 0x41,
-	0x5f, 0x32, 0x30, 0x31, 0x31, 0x10, 0x03, 0x12, 0x12, 0x0a, 0x0e, 0x47, 0x43, 0x45, 0x5f, 0x44,
-	0x45, 0x46, 0x41, 0x55, 0x4c, 0x54, 0x5f, 0x50, 0x4b, 0x10, 0x04, 0x2a, 0x35, 0x0a, 0x0d, 0x52,
-	0x65, 0x73, 0x74, 0x61, 0x72, 0x74, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x0a, 0x0a, 0x06,
-	0x41, 0x6c, 0x77, 0x61, 0x79, 0x73, 0x10, 0x00, 0x12, 0x0d, 0x0a, 0x09, 0x4f, 0x6e, 0x46, 0x61,
-	0x69, 0x6c, 0x75, 0x72, 0x65, 0x10, 0x01, 0x12, 0x09, 0x0a, 0x05, 0x4e, 0x65, 0x76, 0x65, 0x72,
-	0x10, 0x02, 0x2a, 0x3b, 0x0a, 0x0f, 0x47, 0x50, 0x55, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x43,
-	0x43, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x09, 0x0a, 0x05, 0x55, 0x4e, 0x53, 0x45, 0x54, 0x10, 0x00,
-	0x12, 0x06, 0x0a, 0x02, 0x4f, 0x4e, 0x10, 0x01, 0x12, 0x07, 0x0a, 0x03, 0x4f, 0x46, 0x46, 0x10,
-	0x02, 0x12, 0x0c, 0x0a, 0x08, 0x44, 0x45, 0x56, 0x54, 0x4f, 0x4f, 0x4c, 0x53, 0x10, 0x03, 0x42,
-	0x2d, 0x5a, 0x2b, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x67, 0x6f,
-	0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x67, 0x6f, 0x2d, 0x74, 0x70, 0x6d, 0x2d, 0x74, 0x6f, 0x6f, 0x6c,
-	0x73, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x61, 0x74, 0x74, 0x65, 0x73, 0x74, 0x62, 0x06,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x61, 0x62, 0x61, 0x73, 0x65, 0x52, 0x09, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x50, 0x6b,
+	0x22, 0xdd, 0x01, 0x0a, 0x0a, 0x53, 0x68, 0x69, 0x6d, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12,
+	0x2d, 0x0a, 0x12, 0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x5f, 0x76, 0x61, 0x6c, 0x69, 0x64,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x11, 0x72, 0x65, 0x71,
+	0x75, 0x69, 0x72, 0x65, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x30,
+	0x0a, 0x14, 0x66, 0x6f, 0x72, 0x62, 0x69, 0x64, 0x5f, 0x6d, 0x6f, 0x6b, 0x5f, 0x61, 0x75, 0x74,
+	0x68, 0x6f, 0x72, 0x69, 0x74, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x12, 0x66, 0x6f,
+	0x72, 0x62, 0x69, 0x64, 0x4d, 0x6f, 0x6b, 0x41, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x69, 0x74, 0x79,
+	0x12, 0x35, 0x0a, 0x17, 0x66, 0x6f, 0x72, 0x62, 0x69, 0x64, 0x5f, 0x6d, 0x6f, 0x6b, 0x5f, 0x6c,
+	0x69, 0x73, 0x74, 0x5f, 0x74, 0x72, 0x75, 0x73, 0x74, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x14, 0x66, 0x6f, 0x72, 0x62, 0x69, 0x64, 0x4d, 0x6f, 0x6b, 0x4c, 0x69, 0x73, 0x74,
+	0x54, 0x72, 0x75, 0x73, 0x74, 0x65, 0x64, 0x12, 0x37, 0x0a, 0x18, 0x61, 0x6c, 0x6c, 0x6f, 0x77,
+	0x65, 0x64, 0x5f, 0x6d, 0x6f, 0x6b, 0x5f, 0x6c, 0x69, 0x73, 0x74, 0x5f, 0x64, 0x69, 0x67, 0x65,
+	0x73, 0x74, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x15, 0x61, 0x6c, 0x6c, 0x6f, 0x77,
+	0x65, 0x64, 0x4d, 0x6f, 0x6b, 0x4c, 0x69, 0x73, 0x74, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x73,
+	0x22, 0x84, 0x01, 0x0a, 0x10, 0x42, 0x6f, 0x6f, 0x74, 0x6c, 0x6f, 0x61, 0x64, 0x65, 0x72, 0x50,
+	0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x35, 0x0a, 0x17, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64,
+	0x5f, 0x65, 0x66, 0x69, 0x5f, 0x61, 0x70, 0x70, 0x5f, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x73,
+	0x18, 0x01, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x14, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x45,
+	0x66, 0x69, 0x41, 0x70, 0x70, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x73, 0x12, 0x39, 0x0a, 0x19,
+	0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x5f, 0x67, 0x72, 0x75, 0x62, 0x5f, 0x66, 0x69, 0x6c,
+	0x65, 0x5f, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0c, 0x52,
+	0x16, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x47, 0x72, 0x75, 0x62, 0x46, 0x69, 0x6c, 0x65,
+	0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x73, 0x22, 0xbe, 0x01, 0x0a, 0x0c, 0x4b, 0x65, 0x72, 0x6e,
+	0x65, 0x6c, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x32, 0x0a, 0x15, 0x61, 0x6c, 0x6c, 0x6f,
+	0x77, 0x65, 0x64, 0x5f, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x5f, 0x6c, 0x69, 0x6e, 0x65,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x13, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64,
+	0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x4c, 0x69, 0x6e, 0x65, 0x73, 0x12, 0x3b, 0x0a, 0x1a,
+	0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x5f, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64,
+	0x5f, 0x6c, 0x69, 0x6e, 0x65, 0x5f, 0x61, 0x72, 0x67, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x17, 0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e,
+	0x64, 0x4c, 0x69, 0x6e, 0x65, 0x41, 0x72, 0x67, 0x73, 0x12, 0x3d, 0x0a, 0x1b, 0x66, 0x6f, 0x72,
+	0x62, 0x69, 0x64, 0x64, 0x65, 0x6e, 0x5f, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x5f, 0x6c,
+	0x69, 0x6e, 0x65, 0x5f, 0x61, 0x72, 0x67, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x18,
+	0x66, 0x6f, 0x72, 0x62, 0x69, 0x64, 0x64, 0x65, 0x6e, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64,
+	0x4c, 0x69, 0x6e, 0x65, 0x41, 0x72, 0x67, 0x73, 0x22, 0x9d, 0x04, 0x0a, 0x0f, 0x43, 0x6f, 0x6e,
+	0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x32, 0x0a, 0x15,
+	0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x5f, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x5f, 0x64, 0x69,
+	0x67, 0x65, 0x73, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x13, 0x61, 0x6c, 0x6c,
+	0x6f, 0x77, 0x65, 0x64, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x73,
+	0x12, 0x38, 0x0a, 0x18, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x5f, 0x69, 0x6d, 0x61, 0x67,
+	0x65, 0x5f, 0x72, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03,
+	0x28, 0x09, 0x52, 0x16, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x49, 0x6d, 0x61, 0x67, 0x65,
+	0x52, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x73, 0x12, 0x4f, 0x0a, 0x18, 0x61, 0x6c,
+	0x6c, 0x6f, 0x77, 0x65, 0x64, 0x5f, 0x72, 0x65, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x70, 0x6f,
+	0x6c, 0x69, 0x63, 0x69, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0e, 0x32, 0x15, 0x2e, 0x61,
+	0x74, 0x74, 0x65, 0x73, 0x74, 0x2e, 0x52, 0x65, 0x73, 0x74, 0x61, 0x72, 0x74, 0x50, 0x6f, 0x6c,
+	0x69, 0x63, 0x79, 0x52, 0x16, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x52, 0x65, 0x73, 0x74,
+	0x61, 0x72, 0x74, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x69, 0x65, 0x73, 0x12, 0x29, 0x0a, 0x10, 0x66,
+	0x6f, 0x72, 0x62, 0x69, 0x64, 0x5f, 0x6f, 0x76, 0x65, 0x72, 0x72, 0x69, 0x64, 0x65, 0x73, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0f, 0x66, 0x6f, 0x72, 0x62, 0x69, 0x64, 0x4f, 0x76, 0x65,
+	0x72, 0x72, 0x69, 0x64, 0x65, 0x73, 0x12, 0x47, 0x0a, 0x13, 0x6d, 0x69, 0x6e, 0x69, 0x6d, 0x75,
+	0x6d, 0x5f, 0x63, 0x6f, 0x73, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x61, 0x74, 0x74, 0x65, 0x73, 0x74, 0x2e, 0x53, 0x65, 0x6d,
+	0x61, 0x6e, 0x74, 0x69, 0x63, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x11, 0x6d, 0x69,
+	0x6e, 0x69, 0x6d, 0x75, 0x6d, 0x43, 0x6f, 0x73, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12,
+	0x51, 0x0a, 0x18, 0x6d, 0x69, 0x6e, 0x69, 0x6d, 0x75, 0x6d, 0x5f, 0x6c, 0x61, 0x75, 0x6e, 0x63,
+	0x68, 0x65, 0x72, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x17, 0x2e, 0x61, 0x74, 0x74, 0x65, 0x73, 0x74, 0x2e, 0x53, 0x65, 0x6d, 0x61, 0x6e,
+	0x74, 0x69, 0x63, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x16, 0x6d, 0x69, 0x6e, 0x69,
+	0x6d, 0x75, 0x6d, 0x4c, 0x61, 0x75, 0x6e, 0x63, 0x68, 0x65, 0x72, 0x56, 0x65, 0x72, 0x73, 0x69,
+	0x6f, 0x6e, 0x12, 0x3a, 0x0a, 0x19, 0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x5f, 0x6d, 0x65,
+	0x6d, 0x6f, 0x72, 0x79, 0x5f, 0x6d, 0x6f, 0x6e, 0x69, 0x74, 0x6f, 0x72, 0x69, 0x6e, 0x67, 0x18,
+	0x07, 0x20, 0x01, 0x28, 0x08, 0x52, 0x17, 0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x4d, 0x65,
+	0x6d, 0x6f, 0x72, 0x79, 0x4d, 0x6f, 0x6e, 0x69, 0x74, 0x6f, 0x72, 0x69, 0x6e, 0x67, 0x12, 0x48,
+	0x0a, 0x14, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x5f, 0x67, 0x70, 0x75, 0x5f, 0x63, 0x63,
+	0x5f, 0x6d, 0x6f, 0x64, 0x65, 0x73, 0x18, 0x08, 0x20, 0x03, 0x28, 0x0e, 0x32, 0x17, 0x2e, 0x61,
+	0x74, 0x74, 0x65, 0x73, 0x74, 0x2e, 0x47, 0x50, 0x55, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x43,
+	0x43, 0x4d, 0x6f, 0x64, 0x65, 0x52, 0x11, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x47, 0x70,
+	0x75, 0x43, 0x63, 0x4d, 0x6f, 0x64, 0x65, 0x73, 0x22, 0xe0, 0x01, 0x0a, 0x09, 0x54, 0x64, 0x78,
+	0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x23, 0x0a, 0x0d, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x65,
+	0x64, 0x5f, 0x6d, 0x72, 0x74, 0x64, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x0c, 0x61,
+	0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x4d, 0x72, 0x74, 0x64, 0x73, 0x12, 0x25, 0x0a, 0x0e, 0x65,
+	0x78, 0x70, 0x65, 0x63, 0x74, 0x65, 0x64, 0x5f, 0x72, 0x74, 0x6d, 0x72, 0x73, 0x18, 0x02, 0x20,
+	0x03, 0x28, 0x0c, 0x52, 0x0d, 0x65, 0x78, 0x70, 0x65, 0x63, 0x74, 0x65, 0x64, 0x52, 0x74, 0x6d,
+	0x72, 0x73, 0x12, 0x2d, 0x0a, 0x13, 0x6d, 0x69, 0x6e, 0x69, 0x6d, 0x75, 0x6d, 0x5f, 0x74, 0x65,
+	0x65, 0x5f, 0x74, 0x63, 0x62, 0x5f, 0x73, 0x76, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52,
+	0x10, 0x6d, 0x69, 0x6e, 0x69, 0x6d, 0x75, 0x6d, 0x54, 0x65, 0x65, 0x54, 0x63, 0x62, 0x53, 0x76,
+	0x6e, 0x12, 0x21, 0x0a, 0x0c, 0x66, 0x6f, 0x72, 0x62, 0x69, 0x64, 0x5f, 0x64, 0x65, 0x62, 0x75,
+	0x67, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0b, 0x66, 0x6f, 0x72, 0x62, 0x69, 0x64, 0x44,
+	0x65, 0x62, 0x75, 0x67, 0x12, 0x35, 0x0a, 0x17, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x5f,
+	0x6d, 0x72, 0x5f, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x5f, 0x73, 0x65, 0x61, 0x6d, 0x73, 0x18,
+	0x05, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x14, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x4d, 0x72,
+	0x53, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x53, 0x65, 0x61, 0x6d, 0x73, 0x22, 0x92, 0x03, 0x0a, 0x06,
+	0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x32, 0x0a, 0x08, 0x70, 0x6c, 0x61, 0x74, 0x66, 0x6f,
+	0x72, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x61, 0x74, 0x74, 0x65, 0x73,
+	0x74, 0x2e, 0x50, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79,
+	0x52, 0x08, 0x70, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x12, 0x39, 0x0a, 0x0b, 0x73, 0x65,
+	0x63, 0x75, 0x72, 0x65, 0x5f, 0x62, 0x6f, 0x6f, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x18, 0x2e, 0x61, 0x74, 0x74, 0x65, 0x73, 0x74, 0x2e, 0x53, 0x65, 0x63, 0x75, 0x72, 0x65, 0x42,
+	0x6f, 0x6f, 0x74, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x52, 0x0a, 0x73, 0x65, 0x63, 0x75, 0x72,
+	0x65, 0x42, 0x6f, 0x6f, 0x74, 0x12, 0x2d, 0x0a, 0x07, 0x73, 0x65, 0x76, 0x5f, 0x73, 0x6e, 0x70,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x61, 0x74, 0x74, 0x65, 0x73, 0x74, 0x2e,
+	0x53, 0x65, 0x76, 0x53, 0x6e, 0x70, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x52, 0x06, 0x73, 0x65,
+	0x76, 0x53, 0x6e, 0x70, 0x12, 0x38, 0x0a, 0x0a, 0x62, 0x6f, 0x6f, 0x74, 0x6c, 0x6f, 0x61, 0x64,
+	0x65, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x61, 0x74, 0x74, 0x65, 0x73,
+	0x74, 0x2e, 0x42, 0x6f, 0x6f, 0x74, 0x6c, 0x6f, 0x61, 0x64, 0x65, 0x72, 0x50, 0x6f, 0x6c, 0x69,
+	0x63, 0x79, 0x52, 0x0a, 0x62, 0x6f, 0x6f, 0x74, 0x6c, 0x6f, 0x61, 0x64, 0x65, 0x72, 0x12, 0x2c,
+	0x0a, 0x06, 0x6b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14,
+	0x2e, 0x61, 0x74, 0x74, 0x65, 0x73, 0x74, 0x2e, 0x4b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x50, 0x6f,
+	0x6c, 0x69, 0x63, 0x79, 0x52, 0x06, 0x6b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x12, 0x35, 0x0a, 0x09,
+	0x63, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x17, 0x2e, 0x61, 0x74, 0x74, 0x65, 0x73, 0x74, 0x2e, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e,
+	0x65, 0x72, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x52, 0x09, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x69,
+	0x6e, 0x65, 0x72, 0x12, 0x23, 0x0a, 0x03, 0x74, 0x64, 0x78, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x11, 0x2e, 0x61, 0x74, 0x74, 0x65, 0x73, 0x74, 0x2e, 0x54, 0x64, 0x78, 0x50, 0x6f, 0x6c,
+	0x69, 0x63, 0x79, 0x52, 0x03, 0x74, 0x64, 0x78, 0x12, 0x26, 0x0a, 0x04, 0x73, 0x68, 0x69, 0x6d,
+	0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x61, 0x74, 0x74, 0x65, 0x73, 0x74, 0x2e,
+	0x53, 0x68, 0x69, 0x6d, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x52, 0x04, 0x73, 0x68, 0x69, 0x6d,
+	0x2a, 0x62, 0x0a, 0x19, 0x47, 0x43, 0x45, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x64, 0x65, 0x6e, 0x74,
+	0x69, 0x61, 0x6c, 0x54, 0x65, 0x63, 0x68, 0x6e, 0x6f, 0x6c, 0x6f, 0x67, 0x79, 0x12, 0x08, 0x0a,
+	0x04, 0x4e, 0x4f, 0x4e, 0x45, 0x10, 0x00, 0x12, 0x0b, 0x0a, 0x07, 0x41, 0x4d, 0x44, 0x5f, 0x53,
+	0x45, 0x56, 0x10, 0x01, 0x12, 0x0e, 0x0a, 0x0a, 0x41, 0x4d, 0x44, 0x5f, 0x53, 0x45, 0x56, 0x5f,
+	0x45, 0x53, 0x10, 0x02, 0x12, 0x0d, 0x0a, 0x09, 0x49, 0x4e, 0x54, 0x45, 0x4c, 0x5f, 0x54, 0x44,
+	0x58, 0x10, 0x03, 0x12, 0x0f, 0x0a, 0x0b, 0x41, 0x4d, 0x44, 0x5f, 0x53, 0x45, 0x56, 0x5f, 0x53,
+	0x4e, 0x50, 0x10, 0x04, 0x2a, 0x96, 0x01, 0x0a, 0x14, 0x57, 0x65, 0x6c, 0x6c, 0x4b, 0x6e, 0x6f,
+	0x77, 0x6e, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x12, 0x0b, 0x0a,
+	0x07, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x00, 0x12, 0x1c, 0x0a, 0x18, 0x4d, 0x53,
+	0x5f, 0x57, 0x49, 0x4e, 0x44, 0x4f, 0x57, 0x53, 0x5f, 0x50, 0x52, 0x4f, 0x44, 0x5f, 0x50, 0x43,
+	0x41, 0x5f, 0x32, 0x30, 0x31, 0x31, 0x10, 0x01, 0x12, 0x1f, 0x0a, 0x1b, 0x4d, 0x53, 0x5f, 0x54,
+	0x48, 0x49, 0x52, 0x44, 0x5f, 0x50, 0x41, 0x52, 0x54, 0x59, 0x5f, 0x55, 0x45, 0x46, 0x49, 0x5f,
+	0x43, 0x41, 0x5f, 0x32, 0x30, 0x31, 0x31, 0x10, 0x02, 0x12, 0x1e, 0x0a, 0x1a, 0x4d, 0x53, 0x5f,
+	0x54, 0x48, 0x49, 0x52, 0x44, 0x5f, 0x50, 0x41, 0x52, 0x54, 0x59, 0x5f, 0x4b, 0x45, 0x4b, 0x5f,
+	0x43, 0x41, 0x5f, 0x32, 0x30, 0x31, 0x31, 0x10, 0x03, 0x12, 0x12, 0x0a, 0x0e, 0x47, 0x43, 0x45,
+	0x5f, 0x44, 0x45, 0x46, 0x41, 0x55, 0x4c, 0x54, 0x5f, 0x50, 0x4b, 0x10, 0x04, 0x2a, 0x35, 0x0a,
+	0x0d, 0x52, 0x65, 0x73, 0x74, 0x61, 0x72, 0x74, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x0a,
+	0x0a, 0x06, 0x41, 0x6c, 0x77, 0x61, 0x79, 0x73, 0x10, 0x00, 0x12, 0x0d, 0x0a, 0x09, 0x4f, 0x6e,
+	0x46, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x10, 0x01, 0x12, 0x09, 0x0a, 0x05, 0x4e, 0x65, 0x76,
+	0x65, 0x72, 0x10, 0x02, 0x2a, 0x3b, 0x0a, 0x0f, 0x47, 0x50, 0x55, 0x44, 0x65, 0x76, 0x69, 0x63,
+	0x65, 0x43, 0x43, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x09, 0x0a, 0x05, 0x55, 0x4e, 0x53, 0x45, 0x54,
+	0x10, 0x00, 0x12, 0x06, 0x0a, 0x02, 0x4f, 0x4e, 0x10, 0x01, 0x12, 0x07, 0x0a, 0x03, 0x4f, 0x46,
+	0x46, 0x10, 0x02, 0x12, 0x0c, 0x0a, 0x08, 0x44, 0x45, 0x56, 0x54, 0x4f, 0x4f, 0x4c, 0x53, 0x10,
+	0x03, 0x42, 0x2d, 0x5a, 0x2b, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x67, 0x6f, 0x2d, 0x74, 0x70, 0x6d, 0x2d, 0x74, 0x6f,
+	0x6f, 0x6c, 0x73, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x61, 0x74, 0x74, 0x65, 0x73, 0x74,
+	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -2321,7 +3647,7 @@ func file_attest_proto_rawDescGZIP() []byte {
 }
 
 var file_attest_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
-var file_attest_proto_msgTypes = make([]protoimpl.MessageInfo, 25)
+var file_attest_proto_msgTypes = make([]protoimpl.MessageInfo, 38)
 var file_attest_proto_goTypes = []interface{}{
 	(GCEConfidentialTechnology)(0), // 0: attest.GCEConfidentialTechnology
 	(WellKnownCertificate)(0),      // 1: attest.WellKnownCertificate
@@ -2334,75 +3660,112 @@ var file_attest_proto_goTypes = []interface{}{
 	(*GrubFile)(nil),               // 8: attest.GrubFile
 	(*GrubState)(nil),              // 9: attest.GrubState
 	(*LinuxKernelState)(nil),       // 10: attest.LinuxKernelState
-	(*Event)(nil),                  // 11: attest.Event
-	(*Certificate)(nil),            // 12: attest.Certificate
-	(*Database)(nil),               // 13: attest.Database
-	(*SecureBootState)(nil),        // 14: attest.SecureBootState
-	(*ContainerState)(nil),         // 15: attest.ContainerState
-	(*SemanticVersion)(nil),        // 16: attest.SemanticVersion
-	(*HealthMonitoringState)(nil),  // 17: attest.HealthMonitoringState
-	(*GpuDeviceState)(nil),         // 18: attest.GpuDeviceState
-	(*AttestedCosState)(nil),       // 19: attest.AttestedCosState
-	(*EfiApp)(nil),                 // 20: attest.EfiApp
-	(*EfiState)(nil),               // 21: attest.EfiState
-	(*MachineState)(nil),           // 22: attest.MachineState
-	(*PlatformPolicy)(nil),         // 23: attest.PlatformPolicy
-	(*RIMPolicy)(nil),              // 24: attest.RIMPolicy
-	(*SevSnpPolicy)(nil),           // 25: attest.SevSnpPolicy
-	(*Policy)(nil),                 // 26: attest.Policy
-	nil,                            // 27: attest.ContainerState.EnvVarsEntry
-	nil,                            // 28: attest.ContainerState.OverriddenEnvVarsEntry
-	(*tpm.Quote)(nil),              // 29: tpm.Quote
-	(*sevsnp.Attestation)(nil),     // 30: sevsnp.Attestation
-	(*tdx.QuoteV4)(nil),            // 31: tdx.QuoteV4
-	(tpm.HashAlgo)(0),              // 32: tpm.HashAlgo
+	(*SystemdMeasurement)(nil),     // 11: attest.SystemdMeasurement
+	(*SystemdBootState)(nil),       // 12: attest.SystemdBootState
+	(*Event)(nil),                  // 13: attest.Event
+	(*Certificate)(nil),            // 14: attest.Certificate
+	(*Database)(nil),               // 15: attest.Database
+	(*SecureBootState)(nil),        // 16: attest.SecureBootState
+	(*ShimState)(nil),              // 17: attest.ShimState
+	(*ImaMeasurement)(nil),         // 18: attest.ImaMeasurement
+	(*ImaState)(nil),               // 19: attest.ImaState
+	(*ContainerState)(nil),         // 20: attest.ContainerState
+	(*SemanticVersion)(nil),        // 21: attest.SemanticVersion
+	(*HealthMonitoringState)(nil),  // 22: attest.HealthMonitoringState
+	(*GpuDeviceState)(nil),         // 23: attest.GpuDeviceState
+	(*WorkloadEvent)(nil),          // 24: attest.WorkloadEvent
+	(*ImportedSecret)(nil),         // 25: attest.ImportedSecret
+	(*AttestedCosState)(nil),       // 26: attest.AttestedCosState
+	(*EfiApp)(nil),                 // 27: attest.EfiApp
+	(*EfiState)(nil),               // 28: attest.EfiState
+	(*MachineState)(nil),           // 29: attest.MachineState
+	(*PlatformPolicy)(nil),         // 30: attest.PlatformPolicy
+	(*RIMPolicy)(nil),              // 31: attest.RIMPolicy
+	(*SevSnpPolicy)(nil),           // 32: attest.SevSnpPolicy
+	(*SecureBootPolicy)(nil),       // 33: attest.SecureBootPolicy
+	(*ShimPolicy)(nil),             // 34: attest.ShimPolicy
+	(*BootloaderPolicy)(nil),       // 35: attest.BootloaderPolicy
+	(*KernelPolicy)(nil),           // 36: attest.KernelPolicy
+	(*ContainerPolicy)(nil),        // 37: attest.ContainerPolicy
+	(*TdxPolicy)(nil),              // 38: attest.TdxPolicy
+	(*Policy)(nil),                 // 39: attest.Policy
+	nil,                            // 40: attest.ContainerState.EnvVarsEntry
+	nil,                            // 41: attest.ContainerState.OverriddenEnvVarsEntry
+	(*tpm.Quote)(nil),              // 42: tpm.Quote
+	(*sevsnp.Attestation)(nil),     // 43: sevsnp.Attestation
+	(*tdx.QuoteV4)(nil),            // 44: tdx.QuoteV4
+	(tpm.HashAlgo)(0),              // 45: tpm.HashAlgo
 }
 var file_attest_proto_depIdxs = []int32{
-	29, // 0: attest.Attestation.quotes:type_name -> tpm.Quote
+	42, // 0: attest.Attestation.quotes:type_name -> tpm.Quote
 	4,  // 1: attest.Attestation.instance_info:type_name -> attest.GCEInstanceInfo
-	30, // 2: attest.Attestation.sev_snp_attestation:type_name -> sevsnp.Attestation
-	31, // 3: attest.Attestation.tdx_attestation:type_name -> tdx.QuoteV4
+	43, // 2: attest.Attestation.sev_snp_attestation:type_name -> sevsnp.Attestation
+	44, // 3: attest.Attestation.tdx_attestation:type_name -> tdx.QuoteV4
 	5,  // 4: attest.SevSnpSvsmAttestation.attestation:type_name -> attest.Attestation
-	30, // 5: attest.SevSnpSvsmAttestation.sev_snp_attestation:type_name -> sevsnp.Attestation
+	43, // 5: attest.SevSnpSvsmAttestation.sev_snp_attestation:type_name -> sevsnp.Attestation
 	0,  // 6: attest.PlatformState.technology:type_name -> attest.GCEConfidentialTechnology
 	4,  // 7: attest.PlatformState.instance_info:type_name -> attest.GCEInstanceInfo
 	8,  // 8: attest.GrubState.files:type_name -> attest.GrubFile
-	1,  // 9: attest.Certificate.well_known:type_name -> attest.WellKnownCertificate
-	12, // 10: attest.Database.certs:type_name -> attest.Certificate
-	13, // 11: attest.SecureBootState.db:type_name -> attest.Database
-	13, // 12: attest.SecureBootState.dbx:type_name -> attest.Database
-	13, // 13: attest.SecureBootState.authority:type_name -> attest.Database
-	13, // 14: attest.SecureBootState.pk:type_name -> attest.Database
-	13, // 15: attest.SecureBootState.kek:type_name -> attest.Database
-	2,  // 16: attest.ContainerState.restart_policy:type_name -> attest.RestartPolicy
-	27, // 17: attest.ContainerState.env_vars:type_name -> attest.ContainerState.EnvVarsEntry
-	28, // 18: attest.ContainerState.overridden_env_vars:type_name -> attest.ContainerState.OverriddenEnvVarsEntry
-	3,  // 19: attest.GpuDeviceState.cc_mode:type_name -> attest.GPUDeviceCCMode
-	15, // 20: attest.AttestedCosState.container:type_name -> attest.ContainerState
-	16, // 21: attest.AttestedCosState.cos_version:type_name -> attest.SemanticVersion
-	16, // 22: attest.AttestedCosState.launcher_version:type_name -> attest.SemanticVersion
-	17, // 23: attest.AttestedCosState.health_monitoring:type_name -> attest.HealthMonitoringState
-	18, // 24: attest.AttestedCosState.gpu_device_state:type_name -> attest.GpuDeviceState
-	20, // 25: attest.EfiState.apps:type_name -> attest.EfiApp
-	7,  // 26: attest.MachineState.platform:type_name -> attest.PlatformState
-	14, // 27: attest.MachineState.secure_boot:type_name -> attest.SecureBootState
-	11, // 28: attest.MachineState.raw_events:type_name -> attest.Event
-	32, // 29: attest.MachineState.hash:type_name -> tpm.HashAlgo
-	9,  // 30: attest.MachineState.grub:type_name -> attest.GrubState
-	10, // 31: attest.MachineState.linux_kernel:type_name -> attest.LinuxKernelState
-	19, // 32: attest.MachineState.cos:type_name -> attest.AttestedCosState
-	21, // 33: attest.MachineState.efi:type_name -> attest.EfiState
-	30, // 34: attest.MachineState.sev_snp_attestation:type_name -> sevsnp.Attestation
-	31, // 35: attest.MachineState.tdx_attestation:type_name -> tdx.QuoteV4
-	0,  // 36: attest.PlatformPolicy.minimum_technology:type_name -> attest.GCEConfidentialTechnology
-	24, // 37: attest.SevSnpPolicy.uefi:type_name -> attest.RIMPolicy
-	23, // 38: attest.Policy.platform:type_name -> attest.PlatformPolicy
-	25, // 39: attest.Policy.sev_snp:type_name -> attest.SevSnpPolicy
-	40, // [40:40] is the sub-list for method output_type
-	40, // [40:40] is the sub-list for method input_type
-	40, // [40:40] is the sub-list for extension type_name
-	40, // [40:40] is the sub-list for extension extendee
-	0,  // [0:40] is the sub-list for field type_name
+	11, // 9: attest.SystemdBootState.uki_sections:type_name -> attest.SystemdMeasurement
+	11, // 10: attest.SystemdBootState.extensions:type_name -> attest.SystemdMeasurement
+	1,  // 11: attest.Certificate.well_known:type_name -> attest.WellKnownCertificate
+	14, // 12: attest.Database.certs:type_name -> attest.Certificate
+	15, // 13: attest.SecureBootState.db:type_name -> attest.Database
+	15, // 14: attest.SecureBootState.dbx:type_name -> attest.Database
+	15, // 15: attest.SecureBootState.authority:type_name -> attest.Database
+	15, // 16: attest.SecureBootState.pk:type_name -> attest.Database
+	15, // 17: attest.SecureBootState.kek:type_name -> attest.Database
+	15, // 18: attest.ShimState.vendor_authority:type_name -> attest.Database
+	15, // 19: attest.ShimState.mok_authority:type_name -> attest.Database
+	18, // 20: attest.ImaState.measurements:type_name -> attest.ImaMeasurement
+	2,  // 21: attest.ContainerState.restart_policy:type_name -> attest.RestartPolicy
+	40, // 22: attest.ContainerState.env_vars:type_name -> attest.ContainerState.EnvVarsEntry
+	41, // 23: attest.ContainerState.overridden_env_vars:type_name -> attest.ContainerState.OverriddenEnvVarsEntry
+	3,  // 24: attest.GpuDeviceState.cc_mode:type_name -> attest.GPUDeviceCCMode
+	20, // 25: attest.AttestedCosState.container:type_name -> attest.ContainerState
+	21, // 26: attest.AttestedCosState.cos_version:type_name -> attest.SemanticVersion
+	21, // 27: attest.AttestedCosState.launcher_version:type_name -> attest.SemanticVersion
+	22, // 28: attest.AttestedCosState.health_monitoring:type_name -> attest.HealthMonitoringState
+	23, // 29: attest.AttestedCosState.gpu_device_state:type_name -> attest.GpuDeviceState
+	24, // 30: attest.AttestedCosState.workload_events:type_name -> attest.WorkloadEvent
+	25, // 31: attest.AttestedCosState.secrets:type_name -> attest.ImportedSecret
+	27, // 32: attest.EfiState.apps:type_name -> attest.EfiApp
+	7,  // 33: attest.MachineState.platform:type_name -> attest.PlatformState
+	16, // 34: attest.MachineState.secure_boot:type_name -> attest.SecureBootState
+	13, // 35: attest.MachineState.raw_events:type_name -> attest.Event
+	45, // 36: attest.MachineState.hash:type_name -> tpm.HashAlgo
+	9,  // 37: attest.MachineState.grub:type_name -> attest.GrubState
+	10, // 38: attest.MachineState.linux_kernel:type_name -> attest.LinuxKernelState
+	26, // 39: attest.MachineState.cos:type_name -> attest.AttestedCosState
+	28, // 40: attest.MachineState.efi:type_name -> attest.EfiState
+	43, // 41: attest.MachineState.sev_snp_attestation:type_name -> sevsnp.Attestation
+	44, // 42: attest.MachineState.tdx_attestation:type_name -> tdx.QuoteV4
+	12, // 43: attest.MachineState.systemd_boot:type_name -> attest.SystemdBootState
+	17, // 44: attest.MachineState.shim:type_name -> attest.ShimState
+	19, // 45: attest.MachineState.ima:type_name -> attest.ImaState
+	0,  // 46: attest.PlatformPolicy.minimum_technology:type_name -> attest.GCEConfidentialTechnology
+	31, // 47: attest.SevSnpPolicy.uefi:type_name -> attest.RIMPolicy
+	15, // 48: attest.SecureBootPolicy.required_dbx:type_name -> attest.Database
+	15, // 49: attest.SecureBootPolicy.forbidden_db:type_name -> attest.Database
+	15, // 50: attest.SecureBootPolicy.allowed_authority:type_name -> attest.Database
+	15, // 51: attest.SecureBootPolicy.allowed_pk:type_name -> attest.Database
+	2,  // 52: attest.ContainerPolicy.allowed_restart_policies:type_name -> attest.RestartPolicy
+	21, // 53: attest.ContainerPolicy.minimum_cos_version:type_name -> attest.SemanticVersion
+	21, // 54: attest.ContainerPolicy.minimum_launcher_version:type_name -> attest.SemanticVersion
+	3,  // 55: attest.ContainerPolicy.allowed_gpu_cc_modes:type_name -> attest.GPUDeviceCCMode
+	30, // 56: attest.Policy.platform:type_name -> attest.PlatformPolicy
+	33, // 57: attest.Policy.secure_boot:type_name -> attest.SecureBootPolicy
+	32, // 58: attest.Policy.sev_snp:type_name -> attest.SevSnpPolicy
+	35, // 59: attest.Policy.bootloader:type_name -> attest.BootloaderPolicy
+	36, // 60: attest.Policy.kernel:type_name -> attest.KernelPolicy
+	37, // 61: attest.Policy.container:type_name -> attest.ContainerPolicy
+	38, // 62: attest.Policy.tdx:type_name -> attest.TdxPolicy
+	34, // 63: attest.Policy.shim:type_name -> attest.ShimPolicy
+	64, // [64:64] is the sub-list for method output_type
+	64, // [64:64] is the sub-list for method input_type
+	64, // [64:64] is the sub-list for extension type_name
+	64, // [64:64] is the sub-list for extension extendee
+	0,  // [0:64] is the sub-list for field type_name
 }
 
 func init() { file_attest_proto_init() }
@@ -2496,7 +3859,7 @@ func file_attest_proto_init() {
 			}
 		}
 		file_attest_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Event); i {
+			switch v := v.(*SystemdMeasurement); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2508,7 +3871,7 @@ func file_attest_proto_init() {
 			}
 		}
 		file_attest_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Certificate); i {
+			switch v := v.(*SystemdBootState); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2520,7 +3883,7 @@ func file_attest_proto_init() {
 			}
 		}
 		file_attest_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Database); i {
+			switch v := v.(*Event); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2532,7 +3895,7 @@ func file_attest_proto_init() {
 			}
 		}
 		file_attest_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SecureBootState); i {
+			switch v := v.(*Certificate); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2544,7 +3907,7 @@ func file_attest_proto_init() {
 			}
 		}
 		file_attest_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ContainerState); i {
+			switch v := v.(*Database); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2556,7 +3919,7 @@ func file_attest_proto_init() {
 			}
 		}
 		file_attest_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SemanticVersion); i {
+			switch v := v.(*SecureBootState); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2568,7 +3931,7 @@ func file_attest_proto_init() {
 			}
 		}
 		file_attest_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*HealthMonitoringState); i {
+			switch v := v.(*ShimState); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2580,7 +3943,7 @@ func file_attest_proto_init() {
 			}
 		}
 		file_attest_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GpuDeviceState); i {
+			switch v := v.(*ImaMeasurement); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2592,7 +3955,7 @@ func file_attest_proto_init() {
 			}
 		}
 		file_attest_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AttestedCosState); i {
+			switch v := v.(*ImaState); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2604,7 +3967,7 @@ func file_attest_proto_init() {
 			}
 		}
 		file_attest_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*EfiApp); i {
+			switch v := v.(*ContainerState); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2616,7 +3979,7 @@ func file_attest_proto_init() {
 			}
 		}
 		file_attest_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*EfiState); i {
+			switch v := v.(*SemanticVersion); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2628,7 +3991,7 @@ func file_attest_proto_init() {
 			}
 		}
 		file_attest_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*MachineState); i {
+			switch v := v.(*HealthMonitoringState); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2640,7 +4003,7 @@ func file_attest_proto_init() {
 			}
 		}
 		file_attest_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PlatformPolicy); i {
+			switch v := v.(*GpuDeviceState); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2652,7 +4015,7 @@ func file_attest_proto_init() {
 			}
 		}
 		file_attest_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RIMPolicy); i {
+			switch v := v.(*WorkloadEvent); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2664,7 +4027,7 @@ func file_attest_proto_init() {
 			}
 		}
 		file_attest_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SevSnpPolicy); i {
+			switch v := v.(*ImportedSecret); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2676,6 +4039,162 @@ func file_attest_proto_init() {
 			}
 		}
 		file_attest_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AttestedCosState); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_attest_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EfiApp); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_attest_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EfiState); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_attest_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MachineState); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_attest_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PlatformPolicy); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_attest_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RIMPolicy); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_attest_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SevSnpPolicy); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_attest_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SecureBootPolicy); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_attest_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ShimPolicy); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_attest_proto_msgTypes[31].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BootloaderPolicy); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_attest_proto_msgTypes[32].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*KernelPolicy); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_attest_proto_msgTypes[33].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ContainerPolicy); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_attest_proto_msgTypes[34].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TdxPolicy); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_attest_proto_msgTypes[35].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*Policy); i {
 			case 0:
 				return &v.state
@@ -2696,12 +4215,12 @@ func file_attest_proto_init() {
 		(*PlatformState_ScrtmVersionId)(nil),
 		(*PlatformState_GceVersion)(nil),
 	}
-	file_attest_proto_msgTypes[8].OneofWrappers = []interface{}{
+	file_attest_proto_msgTypes[10].OneofWrappers = []interface{}{
 		(*Certificate_Der)(nil),
 		(*Certificate_WellKnown)(nil),
 	}
-	file_attest_proto_msgTypes[13].OneofWrappers = []interface{}{}
-	file_attest_proto_msgTypes[18].OneofWrappers = []interface{}{
+	file_attest_proto_msgTypes[18].OneofWrappers = []interface{}{}
+	file_attest_proto_msgTypes[25].OneofWrappers = []interface{}{
 		(*MachineState_SevSnpAttestation)(nil),
 		(*MachineState_TdxAttestation)(nil),
 	}
@@ -2711,7 +4230,7 @@ func file_attest_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_attest_proto_rawDesc,
 			NumEnums:      4,
-			NumMessages:   25,
+			NumMessages:   38,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -0,0 +1,33 @@
+package server
+
+import (
+	"crypto"
+	"fmt"
+
+	"github.com/google/go-tpm-tools/internal"
+	tpb "github.com/google/go-tpm-tools/proto/tpm"
+	"github.com/google/go-tpm/legacy/tpm2"
+)
+
+// certifiedKeyAttributes are the attributes a certified key must have: it must
+// have been generated by the TPM, must not leave it, and must be able to sign.
+const certifiedKeyAttributes = tpm2.FlagFixedTPM | tpm2.FlagFixedParent | tpm2.FlagSensitiveDataOrigin | tpm2.FlagSign
+
+// VerifyCertifiedKey checks that a CertifiedBlob (see client.Key.Certify) is
+// a certification by the trusted AK with the expected extraData, and returns
+// the public key of the certified object. The certified object must be an
+// unrestricted, sign-only key generated by the TPM that can't be duplicated,
+// so that its signatures come from the same TPM as the AK's, and it can't be
+// used to sign TPM-generated data such as quotes. The caller must have already
+// established trust in the AK (for example, with VerifyAttestation).
+func VerifyCertifiedKey(blob *tpb.CertifiedBlob, trustedAK crypto.PublicKey, extraData []byte) (crypto.PublicKey, error) {
+	pubArea, err := internal.VerifyCertification(blob, trustedAK, extraData)
+	if err != nil {
+		return nil, err
+	}
+	if attrs := pubArea.Attributes; attrs&certifiedKeyAttributes != certifiedKeyAttributes ||
+		attrs&(tpm2.FlagRestricted|tpm2.FlagDecrypt) != 0 {
+		return nil, fmt.Errorf("certified key has attributes %#x, expected an unrestricted sign-only key that is fixedTPM, fixedParent and sensitiveDataOrigin", uint32(attrs))
+	}
+	return pubArea.Key()
+}
@@ -0,0 +1,55 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/google/go-tpm-tools/client"
+	"github.com/google/go-tpm-tools/internal/test"
+	"github.com/google/go-tpm/legacy/tpm2"
+)
+
+func TestVerifyCertifiedKey(t *testing.T) {
+	rwc := test.GetTPM(t)
+	defer client.CheckedClose(t, rwc)
+
+	ak, err := client.AttestationKeyECC(rwc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ak.Close()
+
+	signingTemplate := client.AKTemplateECC()
+	signingTemplate.Attributes &= ^tpm2.FlagRestricted
+	duplicableTemplate := signingTemplate
+	duplicableTemplate.Attributes &= ^(tpm2.FlagFixedTPM | tpm2.FlagFixedParent)
+
+	tests := []struct {
+		name     string
+		template tpm2.Public
+		wantErr  bool
+	}{
+		{"UnrestrictedSigning", signingTemplate, false},
+		{"Restricted", client.AKTemplateECC(), true},
+		{"Decrypt", client.SRKTemplateECC(), true},
+		{"Duplicable", duplicableTemplate, true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			object, err := client.NewKey(rwc, tpm2.HandleEndorsement, tc.template)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer object.Close()
+
+			extraData := []byte("certification nonce")
+			blob, err := ak.Certify(object, extraData)
+			if err != nil {
+				t.Fatalf("Certify() failed: %v", err)
+			}
+			_, err = VerifyCertifiedKey(blob, ak.PublicKey(), extraData)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("VerifyCertifiedKey() got err %v, want error: %v", err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -3,15 +3,20 @@ package server
 import (
 	"bytes"
 	"crypto"
+	"crypto/sha256"
 	"crypto/x509"
+	"encoding/binary"
 	"encoding/hex"
 	"errors"
 	"fmt"
 	"hash"
+	"slices"
+	"unicode/utf16"
 
 	"github.com/google/go-attestation/attest"
 	"github.com/google/go-eventlog/register"
 	"github.com/google/go-tpm-tools/cel"
+	"github.com/google/go-tpm-tools/internal/eventlog"
 	pb "github.com/google/go-tpm-tools/proto/attest"
 	tpmpb "github.com/google/go-tpm-tools/proto/tpm"
 	"github.com/google/go-tpm/legacy/tpm2"
@@ -44,20 +49,28 @@ var (
 // trusted. Users can establish trust in PCR values by either calling
 // client.ReadPCRs() themselves or by verifying the values via a PCR quote.
 func parsePCClientEventLog(rawEventLog []byte, pcrs *tpmpb.PCRs, opts VerifyOpts) (*pb.MachineState, error) {
-	var errors []error
 	events, err := parseReplayHelper(rawEventLog, pcrs)
 	if err != nil {
 		return nil, createGroupedError("", []error{err})
 	}
-	// error is already checked in convertToAttestPcrs
-	cryptoHash, _ := tpm2.Algorithm(pcrs.GetHash()).Hash()
+	return getPCClientMachineState(pcrs.GetHash(), events, opts)
+}
+
+// getPCClientMachineState returns the MachineState for events that have
+// already been replayed against the PCRs of the given bank. Like
+// parsePCClientEventLog, it may return a partial MachineState along with a
+// GroupedError.
+func getPCClientMachineState(bank tpmpb.HashAlgo, events []attest.Event, opts VerifyOpts) (*pb.MachineState, error) {
+	var errors []error
+	// error is already checked when replaying the events
+	cryptoHash, _ := tpm2.Algorithm(bank).Hash()
 
 	rawEvents := convertToPbEvents(cryptoHash, events)
 	platform, err := getPlatformState(cryptoHash, rawEvents)
 	if err != nil {
 		errors = append(errors, err)
 	}
-	sbState, err := getSecureBootState(events)
+	sbState, err := getSecureBootState(cryptoHash, events)
 	if err != nil {
 		errors = append(errors, err)
 	}
@@ -65,10 +78,16 @@ func parsePCClientEventLog(rawEventLog []byte, pcrs *tpmpb.PCRs, opts VerifyOpts
 	if err != nil {
 		errors = append(errors, err)
 	}
+	shimState, err := getShimState(cryptoHash, rawEvents)
+	if err != nil {
+		errors = append(errors, err)
+	}
 
 	var grub *pb.GrubState
+	var systemdBoot *pb.SystemdBootState
 	var kernel *pb.LinuxKernelState
-	if opts.Loader == GRUB {
+	switch opts.Loader {
+	case GRUB:
 		grub, err = getGrubState(cryptoHash, rawEvents)
 		if err != nil {
 			errors = append(errors, err)
@@ -77,6 +96,16 @@ func parsePCClientEventLog(rawEventLog []byte, pcrs *tpmpb.PCRs, opts VerifyOpts
 		if err != nil {
 			errors = append(errors, err)
 		}
+	case SystemdBoot:
+		var cmdlines []string
+		systemdBoot, cmdlines, err = getSystemdBootState(cryptoHash, rawEvents)
+		if err != nil {
+			errors = append(errors, err)
+		}
+		kernel, err = getLinuxKernelStateFromSystemdBoot(systemdBoot, cmdlines)
+		if err != nil {
+			errors = append(errors, err)
+		}
 	}
 
 	return &pb.MachineState{
@@ -84,9 +113,11 @@ func parsePCClientEventLog(rawEventLog []byte, pcrs *tpmpb.PCRs, opts VerifyOpts
 		SecureBoot:  sbState,
 		Efi:         efiState,
 		RawEvents:   rawEvents,
-		Hash:        pcrs.GetHash(),
+		Hash:        bank,
 		Grub:        grub,
 		LinuxKernel: kernel,
+		SystemdBoot: systemdBoot,
+		Shim:        shimState,
 	}, createGroupedError("failed to fully parse MachineState:", errors)
 }
 
@@ -174,10 +205,19 @@ func getVerifiedCosState(coscel cel.CEL, registerType uint8) (*pb.AttestedCosSta
 			return nil, err
 		}
 
+		if cosState.WorkloadEventsLocked {
+			return nil, fmt.Errorf("found COS Event Type %v after WorkloadSeparator event", cosTlv.EventType)
+		}
+		// Only the workload's events can follow the LaunchSeparator, and they
+		// can only follow it.
 		// TODO: Add support for post-separator container data
-		if seenSeparator {
+		isWorkloadEvent := cosTlv.EventType == cel.WorkloadEventType || cosTlv.EventType == cel.WorkloadSeparatorType
+		if seenSeparator && !isWorkloadEvent {
 			return nil, fmt.Errorf("found COS Event Type %v after LaunchSeparator event", cosTlv.EventType)
 		}
+		if !seenSeparator && isWorkloadEvent {
+			return nil, fmt.Errorf("found COS Event Type %v before LaunchSeparator event", cosTlv.EventType)
+		}
 
 		switch cosTlv.EventType {
 		case cel.ImageRefType:
@@ -238,6 +278,20 @@ func getVerifiedCosState(coscel cel.CEL, registerType uint8) (*pb.AttestedCosSta
 				return nil, fmt.Errorf("unknown GPU device CC mode in COS eventlog: %s", string(cosTlv.EventContent))
 			}
 			cosState.GpuDeviceState.CcMode = pb.GPUDeviceCCMode(ccMode)
+		case cel.WorkloadEventType:
+			name, content, err := cel.ParseWorkloadEvent(cosTlv.EventContent)
+			if err != nil {
+				return nil, err
+			}
+			cosState.WorkloadEvents = append(cosState.WorkloadEvents, &pb.WorkloadEvent{Name: name, Content: content})
+		case cel.WorkloadSeparatorType:
+			cosState.WorkloadEventsLocked = true
+		case cel.SecretType:
+			name, blobDigest, err := cel.ParseSecret(cosTlv.EventContent)
+			if err != nil {
+				return nil, err
+			}
+			cosState.Secrets = append(cosState.Secrets, &pb.ImportedSecret{Name: name, BlobDigest: blobDigest})
 
 		default:
 			return nil, fmt.Errorf("found unknown COS Event Type %v", cosTlv.EventType)
@@ -262,6 +316,7 @@ func getSeparatorInfo(hash crypto.Hash) *separatorInfo {
 	sepData := [][]byte{{0, 0, 0, 0}, {0xff, 0xff, 0xff, 0xff}}
 	sepDigests := make([][]byte, 0, len(sepData))
 	for _, value := range sepData {
+		hasher.Reset()
 		hasher.Write(value)
 		sepDigests = append(sepDigests, hasher.Sum(nil))
 	}
@@ -355,6 +410,13 @@ func parseReplayHelper(rawEventLog []byte, pcrs *tpmpb.PCRs) ([]attest.Event, er
 		return nil, nil
 	}
 
+	// go-attestation can only replay the SHA-1 and SHA-256 banks.
+	switch tpm2.Algorithm(pcrs.GetHash()) {
+	case tpm2.AlgSHA1, tpm2.AlgSHA256:
+	default:
+		return replayCryptoAgileEventLog(rawEventLog, pcrs)
+	}
+
 	attestPcrs, err := convertToAttestPcrs(pcrs)
 	if err != nil {
 		return nil, fmt.Errorf("received bad PCR proto: %v", err)
@@ -370,6 +432,72 @@ func parseReplayHelper(rawEventLog []byte, pcrs *tpmpb.PCRs) ([]attest.Event, er
 	return events, nil
 }
 
+// replayCryptoAgileEventLog replays a crypto-agile event log against the PCRs
+// of any bank with digests in the log, such as the SHA-384 and SHA-512 banks.
+// As with attest.EventLog.Verify, it returns the events extended into the
+// provided PCRs (in log order), and fails if any of those PCRs do not replay.
+func replayCryptoAgileEventLog(rawEventLog []byte, pcrs *tpmpb.PCRs) ([]attest.Event, error) {
+	alg := tpm2.Algorithm(pcrs.GetHash())
+	hash, err := alg.Hash()
+	if err != nil {
+		return nil, fmt.Errorf("received bad PCR proto: %v", err)
+	}
+	log, err := eventlog.Parse(rawEventLog)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse event log: %v", err)
+	}
+	if !slices.Contains(log.Algs, alg) {
+		return nil, fmt.Errorf("failed to replay event log: log has no %v digests", pcrs.GetHash())
+	}
+
+	replayed := make(map[uint32][]byte)
+	var events []attest.Event
+	for i, event := range log.Events {
+		if _, ok := pcrs.GetPcrs()[event.Index]; !ok {
+			continue
+		}
+		digest, ok := event.Digests[alg]
+		if !ok {
+			return nil, fmt.Errorf("failed to replay event log: event #%d has no %v digest", i, pcrs.GetHash())
+		}
+		value, ok := replayed[event.Index]
+		if !ok {
+			value = make([]byte, hash.Size())
+		}
+		if event.Type == NoAction {
+			// NoAction events aren't extended and so aren't returned, but the
+			// StartupLocality event sets the initial value of PCR0.
+			if locality, isLocality := event.StartupLocality(); isLocality && !ok {
+				value[len(value)-1] = locality
+				replayed[event.Index] = value
+			}
+			continue
+		}
+		hasher := hash.New()
+		hasher.Write(value)
+		hasher.Write(digest)
+		replayed[event.Index] = hasher.Sum(nil)
+		events = append(events, attest.Event{
+			Index:  int(event.Index),
+			Type:   attest.EventType(event.Type),
+			Data:   event.Data,
+			Digest: digest,
+		})
+	}
+
+	var mismatched []uint32
+	for index, value := range replayed {
+		if !bytes.Equal(value, pcrs.GetPcrs()[index]) {
+			mismatched = append(mismatched, index)
+		}
+	}
+	if len(mismatched) != 0 {
+		slices.Sort(mismatched)
+		return nil, fmt.Errorf("failed to replay event log: %v PCRs %v do not match the log", pcrs.GetHash(), mismatched)
+	}
+	return events, nil
+}
+
 func convertToAttestPcrs(pcrProto *tpmpb.PCRs) ([]attest.PCR, error) {
 	hash := tpm2.Algorithm(pcrProto.GetHash())
 	cryptoHash, err := hash.Hash()
@@ -440,7 +568,10 @@ func matchWellKnown(cert x509.Certificate) (pb.WellKnownCertificate, error) {
 	return pb.WellKnownCertificate_UNKNOWN, errors.New("failed to find matching well known certificate")
 }
 
-func getSecureBootState(attestEvents []attest.Event) (*pb.SecureBootState, error) {
+func getSecureBootState(hash crypto.Hash, attestEvents []attest.Event) (*pb.SecureBootState, error) {
+	if hash != crypto.SHA1 && hash != crypto.SHA256 {
+		attestEvents = toSHA256Events(hash, attestEvents)
+	}
 	attestSbState, err := attest.ParseSecurebootState(attestEvents)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse SecureBootState: %v", err)
@@ -458,6 +589,194 @@ func getSecureBootState(attestEvents []attest.Event) (*pb.SecureBootState, error
 	}, nil
 }
 
+// toSHA256Events replaces the digests of events from a bank go-attestation
+// cannot check (such as SHA-384) with SHA-256 digests, so they can be passed
+// to attest.ParseSecurebootState. A digest of the event data is replaced with
+// the SHA-256 digest of the same data. As with go-attestation, a digest of the
+// data without its last byte (logged by older versions of shim) is also
+// accepted. Any other digest is replaced with a zero digest, so events that do
+// not verify still fail to verify.
+func toSHA256Events(hash crypto.Hash, attestEvents []attest.Event) []attest.Event {
+	converted := make([]attest.Event, len(attestEvents))
+	for i, event := range attestEvents {
+		converted[i] = event
+		converted[i].Digest = make([]byte, sha256.Size)
+		candidates := [][]byte{event.Data}
+		if len(event.Data) > 0 {
+			candidates = append(candidates, event.Data[:len(event.Data)-1])
+		}
+		for _, data := range candidates {
+			hasher := hash.New()
+			hasher.Write(data)
+			if bytes.Equal(hasher.Sum(nil), event.Digest) {
+				digest := sha256.Sum256(data)
+				converted[i].Digest = digest[:]
+				break
+			}
+		}
+	}
+	return converted
+}
+
+// getShimState parses the MOK variables measured by shim into PCR14 and the
+// shim variables and authorities measured into PCR7, as described in
+// https://github.com/rhboot/shim/blob/main/README.tpm. It returns nil if the
+// event log contains no shim measurements.
+func getShimState(hash crypto.Hash, events []*pb.Event) (*pb.ShimState, error) {
+	state := &pb.ShimState{}
+	var vendorCerts, mokCerts []x509.Certificate
+	var vendorHashes, mokHashes [][]byte
+	seenShim := false
+	seenMOKVariables := make(map[string]bool)
+	// MokListTrusted and MokSBState are single byte variables set to 1 when
+	// enabled. The MOK lists are signature lists, so can't have this digest.
+	hasher := hash.New()
+	hasher.Write([]byte{1})
+	enabledDigest := hasher.Sum(nil)
+	enabledMOKFlags := 0
+	for idx, event := range events {
+		index := event.GetPcrIndex()
+		if index == 14 {
+			if event.GetUntrustedType() != IPL {
+				return nil, fmt.Errorf("invalid event type for PCR%d, expected EV_IPL", index)
+			}
+			seenShim = true
+			// The data is the variable name, but the digest is of the variable
+			// contents, which are not in the event log. As the name isn't
+			// covered by the digest, the flags are counted regardless of the
+			// name they are logged with.
+			if bytes.Equal(event.GetDigest(), enabledDigest) {
+				enabledMOKFlags++
+			}
+			name := string(bytes.TrimSuffix(event.GetData(), []byte{'\x00'}))
+			if seenMOKVariables[name] {
+				return nil, fmt.Errorf("MOK variable %q measured into PCR%d more than once", name, index)
+			}
+			seenMOKVariables[name] = true
+			switch name {
+			case "MokList":
+				state.MokListDigest = event.GetDigest()
+			case "MokListX":
+				state.MokListXDigest = event.GetDigest()
+			case "MokListTrusted", "MokSBState":
+			default:
+				return nil, fmt.Errorf("unknown MOK variable %q measured into PCR%d", name, index)
+			}
+			continue
+		}
+		if index != 7 || event.GetUntrustedType() != EFIVariableAuthority {
+			continue
+		}
+
+		// Authorities measured by the firmware are parsed elsewhere, so only
+		// fail on malformed events that use the shim GUID. Only shim measures
+		// vendor_db, but it uses the Secure Boot db GUID.
+		data := event.GetData()
+		isShimGUID := len(data) >= 16 && bytes.Equal(data[:16], shimLockGUID)
+		_, name, value, err := parseUEFIVariableData(data)
+		if !isShimGUID && (err != nil || name != "vendor_db") {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid authority event #%d: %v", idx, err)
+		}
+		if !event.GetDigestVerified() && !paddedDigestVerified(hash, data, 32+2*len(utf16.Encode([]rune(name)))+len(value), event.GetDigest()) {
+			return nil, fmt.Errorf("invalid digest for shim variable %q", name)
+		}
+		seenShim = true
+		switch name {
+		case "SbatLevel":
+			state.SbatLevel = string(value)
+		case "MokListTrusted":
+			state.MokListTrusted = bytes.Equal(value, []byte{1})
+		case "MokSBState":
+			state.ValidationDisabled = bytes.Equal(value, []byte{1})
+		case "Shim":
+			// The built-in vendor certificate is measured as a raw DER.
+			cert, err := x509.ParseCertificate(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid shim vendor certificate: %v", err)
+			}
+			vendorCerts = append(vendorCerts, *cert)
+		case "vendor_db":
+			if vendorCerts, vendorHashes, err = appendSignatureData(vendorCerts, vendorHashes, value); err != nil {
+				return nil, fmt.Errorf("invalid shim vendor_db authority: %v", err)
+			}
+		case "MokList", "MokListRT":
+			if mokCerts, mokHashes, err = appendSignatureData(mokCerts, mokHashes, value); err != nil {
+				return nil, fmt.Errorf("invalid MOK authority: %v", err)
+			}
+		default:
+			return nil, fmt.Errorf("unknown shim variable %q measured into PCR%d", name, index)
+		}
+	}
+	if !seenShim {
+		return nil, nil
+	}
+	// MokSBState is measured into PCR7 when set, but shim only measures
+	// MokListTrusted into PCR14, so it is trusted if any enabled flag in PCR14
+	// isn't accounted for by MokSBState.
+	disabledFlags := 0
+	if state.ValidationDisabled {
+		disabledFlags = 1
+	}
+	if enabledMOKFlags > disabledFlags {
+		state.MokListTrusted = true
+	}
+	state.VendorAuthority = convertToPbDatabase(vendorCerts, vendorHashes)
+	state.MokAuthority = convertToPbDatabase(mokCerts, mokHashes)
+	return state, nil
+}
+
+// parseUEFIVariableData parses the UEFI_VARIABLE_DATA structure used by
+// EV_EFI_VARIABLE_* events, returning the variable's vendor GUID, name and
+// contents. Some firmware pads the structure, so trailing bytes are ignored.
+func parseUEFIVariableData(data []byte) ([]byte, string, []byte, error) {
+	const headerSize = 32
+	if len(data) < headerSize {
+		return nil, "", nil, fmt.Errorf("variable data is %d bytes, expected at least %d", len(data), headerSize)
+	}
+	nameLength := binary.LittleEndian.Uint64(data[16:24])
+	dataLength := binary.LittleEndian.Uint64(data[24:32])
+	rest := uint64(len(data) - headerSize)
+	if nameLength > rest/2 || dataLength > rest-2*nameLength {
+		return nil, "", nil, fmt.Errorf("variable data is %d bytes, but header specifies a %d character name and %d bytes of data", rest, nameLength, dataLength)
+	}
+	nameEnd := headerSize + 2*nameLength
+	units := make([]uint16, nameLength)
+	for i := range units {
+		units[i] = binary.LittleEndian.Uint16(data[headerSize+2*i:])
+	}
+	return data[:16], string(utf16.Decode(units)), data[nameEnd : nameEnd+dataLength], nil
+}
+
+// paddedDigestVerified reports whether the digest is of the data truncated to
+// some length of at least size. Older versions of shim pad the
+// UEFI_VARIABLE_DATA they log, but only measure part of the padding.
+func paddedDigestVerified(hash crypto.Hash, data []byte, size int, digest []byte) bool {
+	for end := size; end < len(data); end++ {
+		hasher := hash.New()
+		hasher.Write(data[:end])
+		if bytes.Equal(hasher.Sum(nil), digest) {
+			return true
+		}
+	}
+	return false
+}
+
+// appendSignatureData parses a single EFI_SIGNATURE_DATA, as measured by shim
+// when verifying an image, and appends its certificate or hash.
+func appendSignatureData(certs []x509.Certificate, hashes [][]byte, data []byte) ([]x509.Certificate, [][]byte, error) {
+	// Skip the SignatureOwner GUID.
+	if len(data) <= 16 {
+		return nil, nil, fmt.Errorf("signature data is %d bytes, expected more than 16", len(data))
+	}
+	if cert, err := x509.ParseCertificate(data[16:]); err == nil {
+		return append(certs, *cert), hashes, nil
+	}
+	return certs, append(hashes, data[16:]), nil
+}
+
 func getGrubState(hash crypto.Hash, events []*pb.Event) (*pb.GrubState, error) {
 	var files []*pb.GrubFile
 	var commands []string
@@ -514,6 +833,121 @@ func getGrubState(hash crypto.Hash, events []*pb.Event) (*pb.GrubState, error) {
 	return &pb.GrubState{Files: files, Commands: commands}, nil
 }
 
+// getSystemdBootState parses the measurements made by systemd-boot,
+// systemd-stub and the Linux EFI stub, as described in
+// https://systemd.io/TPM2_PCR_MEASUREMENTS/. It also returns the kernel command
+// lines passed via LoadOptions and measured into PCR8 or PCR12.
+func getSystemdBootState(hash crypto.Hash, events []*pb.Event) (*pb.SystemdBootState, []string, error) {
+	state := &pb.SystemdBootState{}
+	var cmdlines []string
+	// systemd-stub measures the name of each UKI section, then its contents.
+	var pendingSection string
+	hasher := hash.New()
+	for idx, event := range events {
+		index := event.GetPcrIndex()
+		if index != 8 && index != 9 && index != 11 && index != 12 {
+			continue
+		}
+
+		if index == 9 {
+			// PCR9 is only used by the Linux EFI stub to measure the initrd and
+			// the LoadOptions.
+			if event.GetUntrustedType() != EventTag {
+				return nil, nil, fmt.Errorf("invalid event type for PCR%d, expected EV_EVENT_TAG", index)
+			}
+			id, err := getTaggedEventID(event.GetData())
+			if err != nil {
+				return nil, nil, fmt.Errorf("invalid tagged event #%d: %v", idx, err)
+			}
+			switch id {
+			case linuxInitrdEventTagID:
+				state.InitrdDigests = append(state.InitrdDigests, event.GetDigest())
+			case linuxLoadOptionsEventTagID:
+				// The command line is parsed from the systemd measurements.
+			default:
+				return nil, nil, fmt.Errorf("unknown tagged event ID %#x for PCR%d", id, index)
+			}
+			continue
+		}
+
+		if event.GetUntrustedType() != IPL {
+			return nil, nil, fmt.Errorf("invalid event type for PCR%d, expected EV_IPL", index)
+		}
+		description, err := decodeUTF16String(event.GetData())
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid systemd event #%d: %v", idx, err)
+		}
+		switch index {
+		case 8, 12:
+			// The LoadOptions are measured with themselves as the description.
+			// PCR8 is only used by older versions of systemd.
+			if event.GetDigestVerified() {
+				cmdlines = append(cmdlines, description)
+			} else if index == 12 {
+				state.Extensions = append(state.Extensions, &pb.SystemdMeasurement{
+					Description: description,
+					Digest:      event.GetDigest(),
+				})
+			} else {
+				return nil, nil, fmt.Errorf("unverified kernel command line digest for PCR%d", index)
+			}
+		case 11:
+			if pendingSection == "" {
+				// The section name is measured as a NUL-terminated ASCII string.
+				if err := verifyDataDigest(hasher, append([]byte(description), '\x00'), event.GetDigest()); err != nil {
+					return nil, nil, fmt.Errorf("invalid UKI section name event #%d: %v", idx, err)
+				}
+				pendingSection = description
+				continue
+			}
+			if description != pendingSection {
+				return nil, nil, fmt.Errorf("UKI section %q measured after the name of section %q", description, pendingSection)
+			}
+			state.UkiSections = append(state.UkiSections, &pb.SystemdMeasurement{
+				Description: description,
+				Digest:      event.GetDigest(),
+			})
+			pendingSection = ""
+		}
+	}
+	if pendingSection != "" {
+		return nil, nil, fmt.Errorf("UKI section %q has no measured contents", pendingSection)
+	}
+	if len(state.GetUkiSections()) == 0 && len(state.GetInitrdDigests()) == 0 &&
+		len(state.GetExtensions()) == 0 && len(cmdlines) == 0 {
+		return nil, nil, errors.New("no systemd-boot measurements found")
+	}
+	return state, cmdlines, nil
+}
+
+// getTaggedEventID returns the taggedEventID of a TCG_PCClientTaggedEvent.
+func getTaggedEventID(data []byte) (uint32, error) {
+	if len(data) < 8 {
+		return 0, fmt.Errorf("tagged event is %d bytes, expected at least 8", len(data))
+	}
+	size := binary.LittleEndian.Uint32(data[4:8])
+	if uint64(size) != uint64(len(data)-8) {
+		return 0, fmt.Errorf("tagged event data is %d bytes, but header specifies %d", len(data)-8, size)
+	}
+	return binary.LittleEndian.Uint32(data[:4]), nil
+}
+
+// decodeUTF16String decodes a little-endian, NUL-terminated UTF-16 string, as
+// used by systemd for event descriptions.
+func decodeUTF16String(data []byte) (string, error) {
+	if len(data) < 2 || len(data)%2 != 0 {
+		return "", fmt.Errorf("UTF-16 string has invalid length %d", len(data))
+	}
+	units := make([]uint16, len(data)/2)
+	for i := range units {
+		units[i] = binary.LittleEndian.Uint16(data[2*i:])
+	}
+	if units[len(units)-1] != 0 {
+		return "", errors.New("UTF-16 string is not NUL-terminated")
+	}
+	return string(utf16.Decode(units[:len(units)-1])), nil
+}
+
 // verifyNullTerminatedRawData checks the digest of the data.
 // Returns nil if digest match the hash of the data or the data without the last bytes (\x00).
 // The caller needs to make sure len(data) is at least 1, and data is ended with '\x00',
@@ -667,6 +1101,27 @@ func getLinuxKernelStateFromGRUB(grub *pb.GrubState) (*pb.LinuxKernelState, erro
 	return &pb.LinuxKernelState{CommandLine: cmdline}, nil
 }
 
+// getLinuxKernelStateFromSystemdBoot returns the kernel command line passed
+// via LoadOptions. If the UKI embeds a .cmdline section, systemd-stub may
+// ignore the LoadOptions, so the command line is only known by the digest of
+// that section, and no LinuxKernelState is returned.
+func getLinuxKernelStateFromSystemdBoot(state *pb.SystemdBootState, cmdlines []string) (*pb.LinuxKernelState, error) {
+	for _, section := range state.GetUkiSections() {
+		if section.GetDescription() == ".cmdline" {
+			return nil, nil
+		}
+	}
+	if len(cmdlines) == 0 {
+		return nil, nil
+	}
+	for _, cmdline := range cmdlines[1:] {
+		if cmdline != cmdlines[0] {
+			return nil, fmt.Errorf("found different kernel command lines %q and %q", cmdlines[0], cmdline)
+		}
+	}
+	return &pb.LinuxKernelState{CommandLine: cmdlines[0]}, nil
+}
+
 func getGrubKernelCmdlineSuffix(grubCmd []byte) int {
 	for _, prefix := range [][]byte{oldGrubKernelCmdlinePrefix, newGrubKernelCmdlinePrefix} {
 		if bytes.HasPrefix(grubCmd, prefix) {
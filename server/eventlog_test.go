@@ -4,11 +4,15 @@ import (
 	"bytes"
 	"crypto"
 	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
 	"encoding/hex"
 	"fmt"
 	"strings"
 	"testing"
 
+	"github.com/google/go-attestation/attest"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-configfs-tsm/configfs/fakertmr"
 	configfstsmrtmr "github.com/google/go-configfs-tsm/rtmr"
@@ -68,6 +72,21 @@ var Rhel8GCE = eventLog{
 			9:  decodeHex("d43b2f61eb18b4791812ff5f20ab20e4ef621ba683370bedf5dbdf518b3a8078"),
 			14: decodeHex("d8f57ebcc1a23cc46832696e1a657f720e1be8f5b405bb7204682114e363b455"),
 		},
+	}, {
+		Hash: pb.HashAlgo_SHA384,
+		Pcrs: map[uint32][]byte{
+			0:  decodeHex("8be2d39fecef6e883d467379c57847437cfa03a6f7f7f78dcb2a05a479db4b4749ececedd105b760bc8313abccf1dfb6"),
+			1:  decodeHex("fe3dc5d3f48a1b682e9ec3a2ea4d4e82b76868e216c886872ed05421c28522f63ef26de16e262585a9f3a8eaea3f933b"),
+			2:  decodeHex("518923b0f955d08da077c96aaba522b9decede61c599cea6c41889cfbea4ae4d50529d96fe4d1afdafb65e7f95bf23c4"),
+			3:  decodeHex("518923b0f955d08da077c96aaba522b9decede61c599cea6c41889cfbea4ae4d50529d96fe4d1afdafb65e7f95bf23c4"),
+			4:  decodeHex("62622ff1f3ed4c7ec59650f78caa80499f54d4bf273560cee780c9411cab9ee0f040299b22599c5f797d0c8b0f0342c4"),
+			5:  decodeHex("f653a0a6625b3eb12f56a075fb07c9f3f9c9c0d33abd770663f98e2b13ab0f8f971557133702d2faa9e19355ca5fff77"),
+			6:  decodeHex("518923b0f955d08da077c96aaba522b9decede61c599cea6c41889cfbea4ae4d50529d96fe4d1afdafb65e7f95bf23c4"),
+			7:  decodeHex("c045321e7b0361a932c779319f590c798b1e9dcada13b9b5df8afae1012240babd3e42d5a1e83f5bb6e9f8463a0f21f8"),
+			8:  decodeHex("6b789d88cf56779b2fcc641958f5d10ea0a53d0944abe16a9c727bc08a876ec7c002b831fb394f60242e2866c8155bc2"),
+			9:  decodeHex("7a9bdaf00517a432127aa65d50c354db7c915f41b68194a1331907705c005c4b406876f37689d5387f4766b8f6c133db"),
+			14: decodeHex("57fd21f31d9e28c4fbee7bafaaaa94bfb0c5b289dbb749fc15ab3503f1cc0ca3c2b23ac479a42bc70ae306eadac6693a"),
+		},
 	}},
 	ExpectedEFIAppDigests: map[pb.HashAlgo][]string{
 		pb.HashAlgo_SHA1: {
@@ -120,6 +139,20 @@ var UbuntuAmdSevGCE = eventLog{
 			8: decodeHex("c324da9d0c54252c37af697cdd58b066f2bb0f4a69752d27623bc738d02e9486"),
 			9: decodeHex("2d334f1eeb9a16dabaccaa746ff1c0dce2e9aeb3f3a4a314e5e1e61b01e940d0"),
 		},
+	}, {
+		Hash: pb.HashAlgo_SHA384,
+		Pcrs: map[uint32][]byte{
+			0: decodeHex("46ce251b0b5b3da7917c5eb7a72e6e88f8f830445b149937921b095c1fd628db691963861c1153aba9c7097ff1c747f9"),
+			1: decodeHex("752f2d334ec6b7ccb07831ec08b8d66704026d20bac5cf57be195a696674d3fe33c32dadd84f53889ee1b8c7bd4bc0c4"),
+			2: decodeHex("518923b0f955d08da077c96aaba522b9decede61c599cea6c41889cfbea4ae4d50529d96fe4d1afdafb65e7f95bf23c4"),
+			3: decodeHex("518923b0f955d08da077c96aaba522b9decede61c599cea6c41889cfbea4ae4d50529d96fe4d1afdafb65e7f95bf23c4"),
+			4: decodeHex("d66b8d853c961702887e74a4dfa1bfaf14a520dec2737bc94b73cbff76aec7f9ff1e5a481e43093037292af200ccf3c9"),
+			5: decodeHex("b5d31a3edbbeb651fcf3c340574ecec7cb2793e11643a88fa692b5ae641b0a584fac5ff98bd0fc31eeff04e70a96be4e"),
+			6: decodeHex("518923b0f955d08da077c96aaba522b9decede61c599cea6c41889cfbea4ae4d50529d96fe4d1afdafb65e7f95bf23c4"),
+			7: decodeHex("6a1f1604c59dd839da479155e65694233709956c175e2d8c49858ecd832dbc1741290734fe7228cf98b23e1c760c52fd"),
+			8: decodeHex("74ea8e26bc86d7f8caf28aaa72d1637a65d551f779d273f1d1946ce8ee2d27796dc227beb53d10176b5b3a034832be95"),
+			9: decodeHex("82006dc77dab60a35abdd1ce2946f8c64d750e690b333d3b84429611380c4deec63cffdedc6769693ff8c50572ad529e"),
+		},
 	}},
 	ExpectedEFIAppDigests: map[pb.HashAlgo][]string{
 		pb.HashAlgo_SHA1: {
@@ -170,6 +203,21 @@ var Ubuntu2104NoDbxGCE = eventLog{
 			9:  decodeHex("9f27883322aaaf043662c27542d9685790c687ea554e4e2ae30f0e099a2e4889"),
 			14: decodeHex("8351c65483c5419079e8c96758dd2130bee075d71fea226f68ec4eb5bfc71983"),
 		},
+	}, {
+		Hash: pb.HashAlgo_SHA384,
+		Pcrs: map[uint32][]byte{
+			0:  decodeHex("8be2d39fecef6e883d467379c57847437cfa03a6f7f7f78dcb2a05a479db4b4749ececedd105b760bc8313abccf1dfb6"),
+			1:  decodeHex("382f8b0c004009344620c720690011386c383af66e38437f6f44854426a8a7a1d8eb8c9ffcc5c61b9b39729446c34042"),
+			2:  decodeHex("518923b0f955d08da077c96aaba522b9decede61c599cea6c41889cfbea4ae4d50529d96fe4d1afdafb65e7f95bf23c4"),
+			3:  decodeHex("518923b0f955d08da077c96aaba522b9decede61c599cea6c41889cfbea4ae4d50529d96fe4d1afdafb65e7f95bf23c4"),
+			4:  decodeHex("6bb9f97fa6a24844a6976c6196dcf766574c2062923d2ccbb9e04a365f36a986c798342cb9720d919b0f6a72a1aaab3e"),
+			5:  decodeHex("6c1b5fbc7598002e1c48171baf44ffc24c001ba16d25356fb2c06fe8bc3aa73ca78bb658fc4eb5952d5862ee7097ea86"),
+			6:  decodeHex("518923b0f955d08da077c96aaba522b9decede61c599cea6c41889cfbea4ae4d50529d96fe4d1afdafb65e7f95bf23c4"),
+			7:  decodeHex("79ca6795f9f8cb4f8653f64370dcdcc845e2d7be213424c1295bb4626ec436436bcca9decd0bd989b7218ea24af40313"),
+			8:  decodeHex("edf46c2b7278fb9a7e9f0f9ef4bfdcafe156ff687ce039069b9cb9c11cae76d72ad881212ef748cf868138516d22edae"),
+			9:  decodeHex("b22f00a43ff104a75b333718cb822311654d33d42154b70c57a90a42c9674fff79e8ca016c2656aa7c92be41ebc57a64"),
+			14: decodeHex("b8b567350264af771620c027a7b166896385885029f5e5b2feb9a0c62b7ffdfc276b702373b26b3aa589ab675ee8654d"),
+		},
 	}},
 	ExpectedEFIAppDigests: map[pb.HashAlgo][]string{
 		pb.HashAlgo_SHA1: {
@@ -220,6 +268,21 @@ var Ubuntu2104NoSecureBootGCE = eventLog{
 			9:  decodeHex("adb87be3efd96cc3a2f66b8aa7564f9727563ef494a95d571a3f38ff4afb25dd"),
 			14: decodeHex("8351c65483c5419079e8c96758dd2130bee075d71fea226f68ec4eb5bfc71983"),
 		},
+	}, {
+		Hash: pb.HashAlgo_SHA384,
+		Pcrs: map[uint32][]byte{
+			0:  decodeHex("8be2d39fecef6e883d467379c57847437cfa03a6f7f7f78dcb2a05a479db4b4749ececedd105b760bc8313abccf1dfb6"),
+			1:  decodeHex("6b088ab036df8ef6e5ecbc719f37836ce616360d74c36b9cd23b9545ec0795e66776856c53a08f89720c77832c4b1ff2"),
+			2:  decodeHex("518923b0f955d08da077c96aaba522b9decede61c599cea6c41889cfbea4ae4d50529d96fe4d1afdafb65e7f95bf23c4"),
+			3:  decodeHex("518923b0f955d08da077c96aaba522b9decede61c599cea6c41889cfbea4ae4d50529d96fe4d1afdafb65e7f95bf23c4"),
+			4:  decodeHex("3ebf3c452bc17e7eb3fdfd04a0f4f6fc9b67032cdc9442ec31480555ba6b0e16d40801d07fa8809804e337d420eb4e74"),
+			5:  decodeHex("ea0b89e9481c7ab394490a49c77a35a80cc8300f38dc1c7b07071dd97eb4a9f5055f8778bd6b33139f6422e12f4fba62"),
+			6:  decodeHex("518923b0f955d08da077c96aaba522b9decede61c599cea6c41889cfbea4ae4d50529d96fe4d1afdafb65e7f95bf23c4"),
+			7:  decodeHex("ad480f162711e25255a35cfa46f700820f39f8411fcf1b10787d35a33970a9207cdf544eeb760512c083c8f1a6c0cad0"),
+			8:  decodeHex("96317e24c0f3c783bc90ecb0e4e0e47cffc1e239d99c181d892dc6bc32e6b32f8b538d4492816bcd46e96909e02d8455"),
+			9:  decodeHex("fc8578079fa8425b2e84059be723073bb28c49d0fe47587727a64256dc6ef79493cb94557a849c909370422a71544700"),
+			14: decodeHex("b8b567350264af771620c027a7b166896385885029f5e5b2feb9a0c62b7ffdfc276b702373b26b3aa589ab675ee8654d"),
+		},
 	}},
 	ExpectedEFIAppDigests: map[pb.HashAlgo][]string{
 		pb.HashAlgo_SHA1: {
@@ -270,6 +333,21 @@ var Ubuntu2404AmdSevSnp = eventLog{
 			9:  decodeHex("ce08798b283c7a0ddc5e9ad1d602304b945b741fc60c20e254eafa0f4782512b"),
 			14: decodeHex("306f9d8b94f17d93dc6e7cf8f5c79d652eb4c6c4d13de2dddc24af416e13ecaf"),
 		},
+	}, {
+		Hash: pb.HashAlgo_SHA384,
+		Pcrs: map[uint32][]byte{
+			0:  decodeHex("99df1a2dd3bb13aeb3eb4067e3081d58ec884ff31f15cd1e1998ec192abf43acb3406bd0a9a8c26f3e930ed6da80de66"),
+			1:  decodeHex("44504ddb84af6373c3bdc9e6c650d874a44f8ec562d3db1e7c1ec18e225a258a368ec0cdb241f1d537483c66e2db1485"),
+			2:  decodeHex("518923b0f955d08da077c96aaba522b9decede61c599cea6c41889cfbea4ae4d50529d96fe4d1afdafb65e7f95bf23c4"),
+			3:  decodeHex("518923b0f955d08da077c96aaba522b9decede61c599cea6c41889cfbea4ae4d50529d96fe4d1afdafb65e7f95bf23c4"),
+			4:  decodeHex("49eedb642cab80ea07518840eb497c5e296f6eee07721ff347b61572647ccd5534f0a1d054516e2c928daddf1fa9b863"),
+			5:  decodeHex("c502281d1dae76ffb3e2a89154a3820a8124a552bce4e644b887b2f224a841136346af5f9f309a7ea4c5f39191818627"),
+			6:  decodeHex("518923b0f955d08da077c96aaba522b9decede61c599cea6c41889cfbea4ae4d50529d96fe4d1afdafb65e7f95bf23c4"),
+			7:  decodeHex("1ad429a007b9187143e057983ae5ea5b44e534f7147c6b3c1baa06fcc435071db2164f04eea2e83098b7a1faf311209a"),
+			8:  decodeHex("34bf8e6139061cdf1b2d9f8e0c125ceaf1a94497e387646bad8bb2dbf147dfb1362f34c84ab5f88f22c62302c751b6c5"),
+			9:  decodeHex("6c1ed7e6ebcad3ed7836b2489c3f811a5efd92f900a61eb5699c24f2845f63de457cc56cbb8b9f8ecee4b43eded7f1d4"),
+			14: decodeHex("937437d07298010015f4598395c9f8dc202ef36e0be3897bba89874bf612b5da092beadfe37f79714a60193819e384ad"),
+		},
 	}},
 	ExpectedEFIAppDigests: map[pb.HashAlgo][]string{
 		pb.HashAlgo_SHA1: {
@@ -408,6 +486,20 @@ var COS85AmdSev = eventLog{
 			8: decodeHex("9e9b6511ae6ad443aae4c7bf998ffffbcd271c874f1efab9d692f129eb6e6c18"),
 			9: decodeHex("f4f2d92d6d54f6c41f2706fd98091317642e0680a7902c72893d41e3464a93b7"),
 		},
+	}, {
+		Hash: pb.HashAlgo_SHA384,
+		Pcrs: map[uint32][]byte{
+			0: decodeHex("46ce251b0b5b3da7917c5eb7a72e6e88f8f830445b149937921b095c1fd628db691963861c1153aba9c7097ff1c747f9"),
+			1: decodeHex("844d7108d3a3b5de969355e20cb4d6b7ca14d287f0dbb81883ed0d1f6372a61715c69d5c6ad02e881297ae5c063273a1"),
+			2: decodeHex("518923b0f955d08da077c96aaba522b9decede61c599cea6c41889cfbea4ae4d50529d96fe4d1afdafb65e7f95bf23c4"),
+			3: decodeHex("518923b0f955d08da077c96aaba522b9decede61c599cea6c41889cfbea4ae4d50529d96fe4d1afdafb65e7f95bf23c4"),
+			4: decodeHex("7f3a5eb37191b396b74e0b941bc90d0775e9ea0af7b47048c19c40f58d81e8a0c95686c48ebcafb76600d3cc89bdad23"),
+			5: decodeHex("ee5a2c21cb2842555710d9d949dc97766bd5080e8731bde2b0ec9c12793820a00d7a5a333dfd49b8d2eb11549093c994"),
+			6: decodeHex("518923b0f955d08da077c96aaba522b9decede61c599cea6c41889cfbea4ae4d50529d96fe4d1afdafb65e7f95bf23c4"),
+			7: decodeHex("1d89e5eb741702ee7c2eb73aa84fdcfa5fa279ad5c1fc428b694575198afb6ddf83c6ce1a06b2c563efbf840a2cead63"),
+			8: decodeHex("f97c3aaae273a2758fc1595ac99c77425f896520914a3482158970dfb49f9e1106f9ce6b2465ec2865e976c8ab81c6e4"),
+			9: decodeHex("27f4fb46966f14370e1c8ba832226bc4b1bc26b128d4bdeadc3274fbf77a65f3e5fe4dfe1700ceabfe4c4ccccf71e926"),
+		},
 	}},
 	ExpectedEFIAppDigests: map[pb.HashAlgo][]string{
 		pb.HashAlgo_SHA1: {
@@ -458,6 +550,20 @@ var COS93AmdSev = eventLog{
 			8: decodeHex("ba18b7028111f1f193967cad3c23b5050f73061c0f119182ac0f42efd6a9159e"),
 			9: decodeHex("0b1e4f9ca7bc8535c4c33f0025969d7abea008aa51dcd7f7c2d1068470e4bce4"),
 		},
+	}, {
+		Hash: pb.HashAlgo_SHA384,
+		Pcrs: map[uint32][]byte{
+			0: decodeHex("46ce251b0b5b3da7917c5eb7a72e6e88f8f830445b149937921b095c1fd628db691963861c1153aba9c7097ff1c747f9"),
+			1: decodeHex("844d7108d3a3b5de969355e20cb4d6b7ca14d287f0dbb81883ed0d1f6372a61715c69d5c6ad02e881297ae5c063273a1"),
+			2: decodeHex("518923b0f955d08da077c96aaba522b9decede61c599cea6c41889cfbea4ae4d50529d96fe4d1afdafb65e7f95bf23c4"),
+			3: decodeHex("518923b0f955d08da077c96aaba522b9decede61c599cea6c41889cfbea4ae4d50529d96fe4d1afdafb65e7f95bf23c4"),
+			4: decodeHex("b215320ec051d386c57c2de651f62577eeb718ae0cf49014fcc2e26ffd035bbf42a07187ebd4f1d4e1212e6b2fb781b0"),
+			5: decodeHex("51b5dd2ecf75e503ea0857830a0d8012097f8c5e0a8a585a67e69f6eff207d657db34a5942f154c6a61ebdd984807922"),
+			6: decodeHex("518923b0f955d08da077c96aaba522b9decede61c599cea6c41889cfbea4ae4d50529d96fe4d1afdafb65e7f95bf23c4"),
+			7: decodeHex("1d89e5eb741702ee7c2eb73aa84fdcfa5fa279ad5c1fc428b694575198afb6ddf83c6ce1a06b2c563efbf840a2cead63"),
+			8: decodeHex("15bcc2128d4a8c272c3e859a2a5c37c7cf75881090af683cb10f75b765fb9b27a72db3ec642985fcfccbc6f50f773751"),
+			9: decodeHex("157cf1cc99aa9c72188c098ab47154bdb3acde807ce0b9b1952bb01ac7b867a0023ffcc0f4794139b99ec01119eab26a"),
+		},
 	}},
 	ExpectedEFIAppDigests: map[pb.HashAlgo][]string{
 		pb.HashAlgo_SHA1: {
@@ -510,6 +616,21 @@ var COS101AmdSev = eventLog{
 			9:  decodeHex("b5ad662e5eb9165825ee39ad66e851a67a193e0b87b27858f25ac58afa72ac57"),
 			14: decodeHex("d0d95459205afae879514db7b85630f5d6b8272ed8c731bf92933dbc9fe99969"),
 		},
+	}, {
+		Hash: pb.HashAlgo_SHA384,
+		Pcrs: map[uint32][]byte{
+			0:  decodeHex("46ce251b0b5b3da7917c5eb7a72e6e88f8f830445b149937921b095c1fd628db691963861c1153aba9c7097ff1c747f9"),
+			1:  decodeHex("844d7108d3a3b5de969355e20cb4d6b7ca14d287f0dbb81883ed0d1f6372a61715c69d5c6ad02e881297ae5c063273a1"),
+			2:  decodeHex("518923b0f955d08da077c96aaba522b9decede61c599cea6c41889cfbea4ae4d50529d96fe4d1afdafb65e7f95bf23c4"),
+			3:  decodeHex("518923b0f955d08da077c96aaba522b9decede61c599cea6c41889cfbea4ae4d50529d96fe4d1afdafb65e7f95bf23c4"),
+			4:  decodeHex("2255116d3bfce3a07c4fbbc8d26101641153b76cc5fda6d7506ad77c179fb86c85ae7c50bef750b8246280adc7dc0f44"),
+			5:  decodeHex("998c8b21bed34d401d6135adbf9508f202ac6886686652b3aeac2f9a04c98c6ce3255f1f0cd090a6e1710c2f5529bdf3"),
+			6:  decodeHex("518923b0f955d08da077c96aaba522b9decede61c599cea6c41889cfbea4ae4d50529d96fe4d1afdafb65e7f95bf23c4"),
+			7:  decodeHex("c56a163bc5efa890d2d88dae43bcba7b5a6dde104777817fde63ab09eba05da3d6018abf8620b372d118d55d17c147c3"),
+			8:  decodeHex("4677b70de1e5b5ee91db3e257a379b85db09048dfbebf871b84ff0606dda99e133e1009ce244989627c06017540284e4"),
+			9:  decodeHex("4e69f1ea521b24a53f3b7c17955d19ef2cb9660eb7d56473de08f36c52352e63cd0a5e2de82fb2784c3e8d85eaaef652"),
+			14: decodeHex("633a5b853f6277ef2294f2ca9435144cab242f22195a019a6020710e109dac7c7f27813c7557227d4ee8f395509081ec"),
+		},
 	}},
 	ExpectedEFIAppDigests: map[pb.HashAlgo][]string{
 		pb.HashAlgo_SHA1: {
@@ -601,6 +722,139 @@ var CGKE251000 = eventLog{
 	}},
 }
 
+// Synthetic Agile Event Log derived from Ubuntu2404AmdSevSnp, with the shim and
+// GRUB measurements replaced by those of systemd-boot booting a Type #1 entry.
+var Ubuntu2404SystemdBoot = eventLog{
+	RawLog: test.Ubuntu2404SystemdBootEventLog,
+	Banks: []*pb.PCRs{{
+		Hash: pb.HashAlgo_SHA1,
+		Pcrs: map[uint32][]byte{
+			0:  decodeHex("8124f09f069c7d2d9acf5ce4eab928a7103a0bb2"),
+			1:  decodeHex("f00d6bbdea9ba55996f237a7f95f2b328a44e3f2"),
+			2:  decodeHex("b2a83b0ebf2f8374299a5b2bdfc31ea955ad7236"),
+			3:  decodeHex("b2a83b0ebf2f8374299a5b2bdfc31ea955ad7236"),
+			4:  decodeHex("329bf533e68e9f9eda0dd15efc7ff6b9f296b0f4"),
+			5:  decodeHex("f65b39c7aec83294f796c1ea4acc987f80914efe"),
+			6:  decodeHex("b2a83b0ebf2f8374299a5b2bdfc31ea955ad7236"),
+			7:  decodeHex("b847708fb98f01677789181a3db82ac155ef43f2"),
+			8:  decodeHex("dbfd845cac1fb4302ec41039ed42ee74192f990c"),
+			9:  decodeHex("ce8b37d35bfe7aae5cc33d64b480e45efb28d00f"),
+			12: decodeHex("dbfd845cac1fb4302ec41039ed42ee74192f990c"),
+		},
+	}, {
+		Hash: pb.HashAlgo_SHA256,
+		Pcrs: map[uint32][]byte{
+			0:  decodeHex("50597a27846e91d025eef597abbc89f72bff9af849094db97b0684d8bc4c515e"),
+			1:  decodeHex("57344e1cc8c6619413df33013a7cd67915459f967395af41db21c1fa7ca9c307"),
+			2:  decodeHex("3d458cfe55cc03ea1f443f1562beec8df51c75e14a9fcf9a7234a13f198e7969"),
+			3:  decodeHex("3d458cfe55cc03ea1f443f1562beec8df51c75e14a9fcf9a7234a13f198e7969"),
+			4:  decodeHex("6a1c33f59cf375feee93b1b543587742009e71f46f449e8155b587b57c767b65"),
+			5:  decodeHex("0b0e1903aeb1bff649b82dba2cdcf5c4ffb75027e54f151ab00b3b989f16a300"),
+			6:  decodeHex("3d458cfe55cc03ea1f443f1562beec8df51c75e14a9fcf9a7234a13f198e7969"),
+			7:  decodeHex("07ab797aecd611a958afaf227d5d2fa253fc2591c69cf57c0c3f285d7a5146f0"),
+			8:  decodeHex("22f192e4e1507f0fb542f466352793f0b07971e09ef4f9196c92d5c5e3571983"),
+			9:  decodeHex("2f3b907c318abf6235a77d5ff3787af9465f9d07314a696de2cc901752b2eb87"),
+			12: decodeHex("22f192e4e1507f0fb542f466352793f0b07971e09ef4f9196c92d5c5e3571983"),
+		},
+	}, {
+		Hash: pb.HashAlgo_SHA384,
+		Pcrs: map[uint32][]byte{
+			0:  decodeHex("99df1a2dd3bb13aeb3eb4067e3081d58ec884ff31f15cd1e1998ec192abf43acb3406bd0a9a8c26f3e930ed6da80de66"),
+			1:  decodeHex("44504ddb84af6373c3bdc9e6c650d874a44f8ec562d3db1e7c1ec18e225a258a368ec0cdb241f1d537483c66e2db1485"),
+			2:  decodeHex("518923b0f955d08da077c96aaba522b9decede61c599cea6c41889cfbea4ae4d50529d96fe4d1afdafb65e7f95bf23c4"),
+			3:  decodeHex("518923b0f955d08da077c96aaba522b9decede61c599cea6c41889cfbea4ae4d50529d96fe4d1afdafb65e7f95bf23c4"),
+			4:  decodeHex("09af925e020fe9983a3d38876d20412dbf82311bdc2eef65f6a016665bc3bef1c48e9cdc6a457004a3f9dec0f2464a07"),
+			5:  decodeHex("c502281d1dae76ffb3e2a89154a3820a8124a552bce4e644b887b2f224a841136346af5f9f309a7ea4c5f39191818627"),
+			6:  decodeHex("518923b0f955d08da077c96aaba522b9decede61c599cea6c41889cfbea4ae4d50529d96fe4d1afdafb65e7f95bf23c4"),
+			7:  decodeHex("4f549a70961aadeb63cac4a878511d7dd914162d1074af0046694a6d69d4be0521370366fa25501136a162db2dd239df"),
+			8:  decodeHex("452b59d6fde29d9907365615d743f3010646b696380e1e0e3c312205ea1ea6b6d4705b75ff1d88b7f0f9862ddadf8d6a"),
+			9:  decodeHex("e9faa55985ac5d5f734421bf7863f0b74a86cf993f424eb0717d003e04ea6e977e3daadfbce8589456f85b34e2dcb15c"),
+			12: decodeHex("452b59d6fde29d9907365615d743f3010646b696380e1e0e3c312205ea1ea6b6d4705b75ff1d88b7f0f9862ddadf8d6a"),
+		},
+	}},
+	ExpectedEFIAppDigests: map[pb.HashAlgo][]string{
+		pb.HashAlgo_SHA1: {
+			"d5055e3a502e43aa48e67bc3d288db7af1081e29",
+			"e471c2078a1adec4a67e6342930534c95a675b53",
+		},
+		pb.HashAlgo_SHA256: {
+			"d1dfda82465f6f662ea5aa26eb776c6ad23000d9d8fa7a3ce1f0a6c6986f4f0e",
+			"1b4e126c0a4dc52204a288cabb3aac2054323f8861664d305a4a4e581adf578d",
+		},
+		pb.HashAlgo_SHA384: {
+			"cd7ee1fc171184c86d9d3d9c540dbd8769bee6b36d3eea0a6a0cc6eda553b150961574d4cc6c5d547cf36d0445a28110",
+			"31e7fd5b81a1d776460b6b773371c4a29e7be84ed614558847a1e968304e2265158b4353e82776c34f9de4a9a3ef6f55",
+		},
+	},
+}
+
+// Synthetic Agile Event Log derived from Ubuntu2404AmdSevSnp, with the shim and
+// GRUB measurements replaced by those of systemd-boot booting a Unified Kernel
+// Image with an embedded .cmdline.
+var Ubuntu2404SystemdBootUKI = eventLog{
+	RawLog: test.Ubuntu2404SystemdBootUKIEventLog,
+	Banks: []*pb.PCRs{{
+		Hash: pb.HashAlgo_SHA1,
+		Pcrs: map[uint32][]byte{
+			0:  decodeHex("8124f09f069c7d2d9acf5ce4eab928a7103a0bb2"),
+			1:  decodeHex("f00d6bbdea9ba55996f237a7f95f2b328a44e3f2"),
+			2:  decodeHex("b2a83b0ebf2f8374299a5b2bdfc31ea955ad7236"),
+			3:  decodeHex("b2a83b0ebf2f8374299a5b2bdfc31ea955ad7236"),
+			4:  decodeHex("09d9ebb89455cd4822c1f6b1eb0e74951bdad54b"),
+			5:  decodeHex("f65b39c7aec83294f796c1ea4acc987f80914efe"),
+			6:  decodeHex("b2a83b0ebf2f8374299a5b2bdfc31ea955ad7236"),
+			7:  decodeHex("b847708fb98f01677789181a3db82ac155ef43f2"),
+			9:  decodeHex("c28314b1028456ec88bfed35439e61cc5803c2b9"),
+			11: decodeHex("1fb5215ed1cee892aced7c8c18ab904d74a37f5f"),
+			12: decodeHex("01063486742d4294697d29129c03853b4cf1c996"),
+		},
+	}, {
+		Hash: pb.HashAlgo_SHA256,
+		Pcrs: map[uint32][]byte{
+			0:  decodeHex("50597a27846e91d025eef597abbc89f72bff9af849094db97b0684d8bc4c515e"),
+			1:  decodeHex("57344e1cc8c6619413df33013a7cd67915459f967395af41db21c1fa7ca9c307"),
+			2:  decodeHex("3d458cfe55cc03ea1f443f1562beec8df51c75e14a9fcf9a7234a13f198e7969"),
+			3:  decodeHex("3d458cfe55cc03ea1f443f1562beec8df51c75e14a9fcf9a7234a13f198e7969"),
+			4:  decodeHex("515f04085345064fcf09c232ea162c8b72fefc4cd7d4954b3b302ab1aa107757"),
+			5:  decodeHex("0b0e1903aeb1bff649b82dba2cdcf5c4ffb75027e54f151ab00b3b989f16a300"),
+			6:  decodeHex("3d458cfe55cc03ea1f443f1562beec8df51c75e14a9fcf9a7234a13f198e7969"),
+			7:  decodeHex("07ab797aecd611a958afaf227d5d2fa253fc2591c69cf57c0c3f285d7a5146f0"),
+			9:  decodeHex("987f8e6452fd65cf3aa9720356716e5e611800f42157f95255b65e398a416061"),
+			11: decodeHex("6d70e074e03ad64623186fc305fc6dfe52aa1f34c08ebe8e4c8d4efd9e8a4f07"),
+			12: decodeHex("467cad771752ae3324f0ef079bae4416994bec4d5a3274ed510442b1b3731b31"),
+		},
+	}, {
+		Hash: pb.HashAlgo_SHA384,
+		Pcrs: map[uint32][]byte{
+			0:  decodeHex("99df1a2dd3bb13aeb3eb4067e3081d58ec884ff31f15cd1e1998ec192abf43acb3406bd0a9a8c26f3e930ed6da80de66"),
+			1:  decodeHex("44504ddb84af6373c3bdc9e6c650d874a44f8ec562d3db1e7c1ec18e225a258a368ec0cdb241f1d537483c66e2db1485"),
+			2:  decodeHex("518923b0f955d08da077c96aaba522b9decede61c599cea6c41889cfbea4ae4d50529d96fe4d1afdafb65e7f95bf23c4"),
+			3:  decodeHex("518923b0f955d08da077c96aaba522b9decede61c599cea6c41889cfbea4ae4d50529d96fe4d1afdafb65e7f95bf23c4"),
+			4:  decodeHex("14836736f010ce94b654e338eb7409c788cab3d1757aff5627f0199b5026d8a6b58656f68196d83e3bc248b7f7d81e7d"),
+			5:  decodeHex("c502281d1dae76ffb3e2a89154a3820a8124a552bce4e644b887b2f224a841136346af5f9f309a7ea4c5f39191818627"),
+			6:  decodeHex("518923b0f955d08da077c96aaba522b9decede61c599cea6c41889cfbea4ae4d50529d96fe4d1afdafb65e7f95bf23c4"),
+			7:  decodeHex("4f549a70961aadeb63cac4a878511d7dd914162d1074af0046694a6d69d4be0521370366fa25501136a162db2dd239df"),
+			9:  decodeHex("4ffd5c255d90a3ea42b2300037d792468b3b69f1f816afb5ba44d634dc851bca9e77ad92f7c69ff3861e804cfdeda7d2"),
+			11: decodeHex("9ac536b06551019d1f9dd54191b13ee44568256ca25e26eafb63bd4526364dbc53ee1216c0ad1ac9596d361460e5accb"),
+			12: decodeHex("28db9d28b953badc35ed56fe8fcb4360f3a5833468abf7afc663891143bd21d049ffde8dd4b0a95b6cdeb696166e9783"),
+		},
+	}},
+	ExpectedEFIAppDigests: map[pb.HashAlgo][]string{
+		pb.HashAlgo_SHA1: {
+			"d5055e3a502e43aa48e67bc3d288db7af1081e29",
+			"a0e4c6bd9c7131ad497811165c788a2878eb2014",
+		},
+		pb.HashAlgo_SHA256: {
+			"d1dfda82465f6f662ea5aa26eb776c6ad23000d9d8fa7a3ce1f0a6c6986f4f0e",
+			"989fc117383cd1eee7e269c72de634a514cb561e7721121bdb1891185c0b1927",
+		},
+		pb.HashAlgo_SHA384: {
+			"cd7ee1fc171184c86d9d3d9c540dbd8769bee6b36d3eea0a6a0cc6eda553b150961574d4cc6c5d547cf36d0445a28110",
+			"ab15ce6a21d6c426bdb5e94b2910cf2246a9e31c1806b1cdfdf255c4fdbace686bef0d561efb919521afea2a2b8c4376",
+		},
+	},
+}
+
 func TestParseEventLogs(t *testing.T) {
 	sbatErrorStr := "asn1: structure error: tags don't match (16 vs {class:0 tag:24 length:10 isCompound:true})"
 	logs := []struct {
@@ -628,6 +882,8 @@ func TestParseEventLogs(t *testing.T) {
 		{GdcHost, "GdcHost", VerifyOpts{Loader: GRUB, AllowEFIAppBeforeCallingEvent: true}, []string{"invalid SCRTM version event for PCR0"}},
 		{SP800155EventLog, "SP800155EventLog", VerifyOpts{Loader: GRUB}, nil},
 		{CGKE251000, "CGKE251000", VerifyOpts{Loader: GRUB}, nil},
+		{Ubuntu2404SystemdBoot, "Ubuntu2404SystemdBoot", VerifyOpts{Loader: SystemdBoot}, nil},
+		{Ubuntu2404SystemdBootUKI, "Ubuntu2404SystemdBootUKI", VerifyOpts{Loader: SystemdBoot}, nil},
 	}
 
 	for _, log := range logs {
@@ -1102,6 +1358,130 @@ func TestParsingCELEventLog(t *testing.T) {
 	}
 }
 
+func TestParsingCELWorkloadEvents(t *testing.T) {
+	test.SkipForRealTPM(t)
+	imageRef := cel.CosTlv{EventType: cel.ImageRefType, EventContent: []byte("docker.io/bazel/experimental/test:latest")}
+	launchSeparator := cel.CosTlv{EventType: cel.LaunchSeparatorType}
+	model := cel.CosTlv{EventType: cel.WorkloadEventType, EventContent: []byte("model=sha256:781d8dfdd92118436bd914442c8339e6")}
+	config := cel.CosTlv{EventType: cel.WorkloadEventType, EventContent: []byte("config=")}
+	workloadSeparator := cel.CosTlv{EventType: cel.WorkloadSeparatorType}
+
+	tests := []struct {
+		name       string
+		events     []cel.CosTlv
+		wantEvents []*attestpb.WorkloadEvent
+		wantLocked bool
+		wantErr    bool
+	}{
+		{"NoWorkloadEvents", []cel.CosTlv{imageRef, launchSeparator}, nil, false, false},
+		{"WorkloadEvents", []cel.CosTlv{imageRef, launchSeparator, model, config},
+			[]*attestpb.WorkloadEvent{{Name: "model", Content: []byte("sha256:781d8dfdd92118436bd914442c8339e6")}, {Name: "config", Content: []byte{}}}, false, false},
+		{"LockedWorkloadEvents", []cel.CosTlv{imageRef, launchSeparator, model, workloadSeparator},
+			[]*attestpb.WorkloadEvent{{Name: "model", Content: []byte("sha256:781d8dfdd92118436bd914442c8339e6")}}, true, false},
+		{"WorkloadEventBeforeLaunchSeparator", []cel.CosTlv{imageRef, model, launchSeparator}, nil, false, true},
+		{"WorkloadSeparatorBeforeLaunchSeparator", []cel.CosTlv{imageRef, workloadSeparator}, nil, false, true},
+		{"WorkloadEventAfterWorkloadSeparator", []cel.CosTlv{imageRef, launchSeparator, workloadSeparator, model}, nil, false, true},
+		{"LauncherEventAfterLaunchSeparator", []cel.CosTlv{launchSeparator, model, imageRef}, nil, false, true},
+		{"MalformedWorkloadEvent", []cel.CosTlv{launchSeparator, {EventType: cel.WorkloadEventType, EventContent: []byte("model")}}, nil, false, true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tpm := test.GetTPM(t)
+			defer client.CheckedClose(t, tpm)
+
+			coscel := &cel.CEL{}
+			for _, event := range tc.events {
+				if err := coscel.AppendEventPCR(tpm, cel.CosEventPCR, event); err != nil {
+					t.Fatal(err)
+				}
+			}
+			var buf bytes.Buffer
+			if err := coscel.EncodeCEL(&buf); err != nil {
+				t.Fatal(err)
+			}
+			banks, err := client.ReadAllPCRs(tpm)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, bank := range banks {
+				acosState, err := ParseCosCELPCR(buf.Bytes(), convertToPCRBank(t, bank))
+				if tc.wantErr {
+					if err == nil {
+						t.Errorf("expected error from ParseCosCELPCR(), but get nil")
+					}
+					continue
+				}
+				if err != nil {
+					t.Fatalf("expecting no error from ParseCosCELPCR(), but get %v", err)
+				}
+				if diff := cmp.Diff(tc.wantEvents, acosState.GetWorkloadEvents(), protocmp.Transform()); diff != "" {
+					t.Errorf("unexpected workload events difference:\n%v", diff)
+				}
+				if acosState.GetWorkloadEventsLocked() != tc.wantLocked {
+					t.Errorf("got WorkloadEventsLocked %v, want %v", acosState.GetWorkloadEventsLocked(), tc.wantLocked)
+				}
+			}
+		})
+	}
+}
+
+func TestParsingCELSecrets(t *testing.T) {
+	test.SkipForRealTPM(t)
+	imageRef := cel.CosTlv{EventType: cel.ImageRefType, EventContent: []byte("docker.io/bazel/experimental/test:latest")}
+	launchSeparator := cel.CosTlv{EventType: cel.LaunchSeparatorType}
+	digest := sha256.Sum256([]byte("blob"))
+	secret := cel.CosTlv{EventType: cel.SecretType, EventContent: append([]byte("api-key="), digest[:]...)}
+
+	tests := []struct {
+		name        string
+		events      []cel.CosTlv
+		wantSecrets []*attestpb.ImportedSecret
+		wantErr     bool
+	}{
+		{"NoSecrets", []cel.CosTlv{imageRef, launchSeparator}, nil, false},
+		{"Secret", []cel.CosTlv{imageRef, secret, launchSeparator},
+			[]*attestpb.ImportedSecret{{Name: "api-key", BlobDigest: digest[:]}}, false},
+		{"SecretAfterLaunchSeparator", []cel.CosTlv{imageRef, launchSeparator, secret}, nil, true},
+		{"MalformedSecret", []cel.CosTlv{{EventType: cel.SecretType, EventContent: []byte("api-key=")}, launchSeparator}, nil, true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tpm := test.GetTPM(t)
+			defer client.CheckedClose(t, tpm)
+
+			coscel := &cel.CEL{}
+			for _, event := range tc.events {
+				if err := coscel.AppendEventPCR(tpm, cel.CosEventPCR, event); err != nil {
+					t.Fatal(err)
+				}
+			}
+			var buf bytes.Buffer
+			if err := coscel.EncodeCEL(&buf); err != nil {
+				t.Fatal(err)
+			}
+			banks, err := client.ReadAllPCRs(tpm)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, bank := range banks {
+				acosState, err := ParseCosCELPCR(buf.Bytes(), convertToPCRBank(t, bank))
+				if tc.wantErr {
+					if err == nil {
+						t.Errorf("expected error from ParseCosCELPCR(), but get nil")
+					}
+					continue
+				}
+				if err != nil {
+					t.Fatalf("expecting no error from ParseCosCELPCR(), but get %v", err)
+				}
+				if diff := cmp.Diff(tc.wantSecrets, acosState.GetSecrets(), protocmp.Transform()); diff != "" {
+					t.Errorf("unexpected secrets difference:\n%v", diff)
+				}
+			}
+		})
+	}
+}
+
 func generateNonCosCelEvent(hashAlgoList []crypto.Hash) (cel.Record, error) {
 	randRecord := cel.Record{}
 	randRecord.RecNum = 0
@@ -1161,6 +1541,311 @@ func TestParseLinuxKernelState(t *testing.T) {
 	}
 }
 
+func TestParseSystemdBootState(t *testing.T) {
+	sha256Hex := func(data string) string {
+		digest := sha256.Sum256([]byte(data))
+		return hex.EncodeToString(digest[:])
+	}
+	initrd := "synthetic initrd for 6.8.0-1010-gcp"
+
+	t.Run("Type1Entry", func(t *testing.T) {
+		msState, err := parsePCClientEventLog(Ubuntu2404SystemdBoot.RawLog, Ubuntu2404SystemdBoot.Banks[1], VerifyOpts{Loader: SystemdBoot})
+		if err != nil {
+			t.Fatalf("failed to parse and replay log: %v", err)
+		}
+		if got := msState.GetLinuxKernel().GetCommandLine(); got != test.Ubuntu2404SystemdBootCmdline {
+			t.Errorf("kernel command line got %q, want %q", got, test.Ubuntu2404SystemdBootCmdline)
+		}
+		sdState := msState.GetSystemdBoot()
+		if len(sdState.GetUkiSections()) != 0 {
+			t.Errorf("got %d UKI sections, want none", len(sdState.GetUkiSections()))
+		}
+		if len(sdState.GetInitrdDigests()) != 1 || hex.EncodeToString(sdState.GetInitrdDigests()[0]) != sha256Hex(initrd) {
+			t.Errorf("initrd digests got %x, want [%s]", sdState.GetInitrdDigests(), sha256Hex(initrd))
+		}
+		if msState.GetGrub() != nil {
+			t.Errorf("got GrubState %v, want nil", msState.GetGrub())
+		}
+	})
+
+	t.Run("UKI", func(t *testing.T) {
+		msState, err := parsePCClientEventLog(Ubuntu2404SystemdBootUKI.RawLog, Ubuntu2404SystemdBootUKI.Banks[1], VerifyOpts{Loader: SystemdBoot})
+		if err != nil {
+			t.Fatalf("failed to parse and replay log: %v", err)
+		}
+		// The UKI embeds a .cmdline, so the command line is only known by digest.
+		if msState.GetLinuxKernel() != nil {
+			t.Errorf("got LinuxKernelState %v, want nil", msState.GetLinuxKernel())
+		}
+		want := []*attestpb.SystemdMeasurement{
+			{Description: ".linux", Digest: decodeHex(sha256Hex("synthetic Linux kernel image 6.8.0-1010-gcp"))},
+			{Description: ".osrel", Digest: decodeHex(sha256Hex("PRETTY_NAME=\"Ubuntu 24.04 LTS\"\nNAME=\"Ubuntu\"\nVERSION_ID=\"24.04\"\nID=ubuntu\n"))},
+			{Description: ".cmdline", Digest: decodeHex(sha256Hex("root=PARTUUID=8270f3c9-b4e4-4345-80ee-5a62db7ebf3f ro console=ttyS0,115200 panic=-1"))},
+			{Description: ".initrd", Digest: decodeHex(sha256Hex(initrd))},
+			{Description: ".uname", Digest: decodeHex(sha256Hex("6.8.0-1010-gcp"))},
+			{Description: ".sbat", Digest: decodeHex(sha256Hex("sbat,1,SBAT Version,sbat,1,https://github.com/rhboot/shim/blob/main/SBAT.md\n"))},
+		}
+		sdState := msState.GetSystemdBoot()
+		if diff := cmp.Diff(want, sdState.GetUkiSections(), protocmp.Transform()); diff != "" {
+			t.Errorf("unexpected UKI sections (-want +got):\n%s", diff)
+		}
+		if len(sdState.GetInitrdDigests()) != 1 || hex.EncodeToString(sdState.GetInitrdDigests()[0]) != sha256Hex(initrd) {
+			t.Errorf("initrd digests got %x, want [%s]", sdState.GetInitrdDigests(), sha256Hex(initrd))
+		}
+		if len(sdState.GetExtensions()) != 1 || sdState.GetExtensions()[0].GetDescription() != "Credentials initrd" {
+			t.Errorf("extensions got %v, want a single credentials initrd", sdState.GetExtensions())
+		}
+	})
+
+	t.Run("GRUBLoader", func(t *testing.T) {
+		_, err := parsePCClientEventLog(Ubuntu2404SystemdBoot.RawLog, Ubuntu2404SystemdBoot.Banks[1], VerifyOpts{Loader: GRUB})
+		if err == nil {
+			t.Error("parsing a systemd-boot log with the GRUB loader succeeded, want error")
+		}
+	})
+}
+
+func TestSystemdBootStateTampered(t *testing.T) {
+	hash := crypto.SHA256
+	utf16z := func(s string) []byte {
+		var out []byte
+		for _, r := range s + "\x00" {
+			out = append(out, byte(r), 0)
+		}
+		return out
+	}
+	digest := func(data []byte) []byte {
+		d := sha256.Sum256(data)
+		return d[:]
+	}
+	nameEvent := func(name string) *attestpb.Event {
+		return &attestpb.Event{PcrIndex: 11, UntrustedType: IPL, Data: utf16z(name), Digest: digest(append([]byte(name), 0))}
+	}
+	contentEvent := func(name string) *attestpb.Event {
+		return &attestpb.Event{PcrIndex: 11, UntrustedType: IPL, Data: utf16z(name), Digest: digest([]byte("contents"))}
+	}
+	cmdlineEvent := func(pcr uint32, cmdline string) *attestpb.Event {
+		data := utf16z(cmdline)
+		return &attestpb.Event{PcrIndex: pcr, UntrustedType: IPL, Data: data, Digest: digest(data), DigestVerified: true}
+	}
+
+	tests := []struct {
+		name    string
+		events  []*attestpb.Event
+		wantErr string
+	}{
+		{"Valid", []*attestpb.Event{nameEvent(".linux"), contentEvent(".linux")}, ""},
+		{"RenamedSection", []*attestpb.Event{
+			{PcrIndex: 11, UntrustedType: IPL, Data: utf16z(".cmdline"), Digest: digest([]byte(".linux\x00"))},
+			contentEvent(".cmdline"),
+		}, "invalid UKI section name event"},
+		{"MismatchedContents", []*attestpb.Event{nameEvent(".linux"), contentEvent(".cmdline")}, "measured after the name of section"},
+		{"MissingContents", []*attestpb.Event{nameEvent(".linux")}, "has no measured contents"},
+		{"WrongType", []*attestpb.Event{{PcrIndex: 11, UntrustedType: EventTag, Data: utf16z(".linux")}}, "expected EV_IPL"},
+		{"DifferentCmdlines", []*attestpb.Event{cmdlineEvent(8, "ro"), cmdlineEvent(12, "rw")}, "different kernel command lines"},
+		{"NoMeasurements", nil, "no systemd-boot measurements"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			sdState, cmdlines, err := getSystemdBootState(hash, tc.events)
+			if err == nil {
+				_, err = getLinuxKernelStateFromSystemdBoot(sdState, cmdlines)
+			}
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Errorf("getSystemdBootState() got err %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("getSystemdBootState() got err %v, want error containing %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseShimState(t *testing.T) {
+	tests := []struct {
+		name string
+		log  eventLog
+		opts VerifyOpts
+		// wantShim is false for event logs from machines that did not boot using shim.
+		wantShim       bool
+		sbatLevel      string
+		mokListTrusted bool
+		vendorCerts    int
+		// The SHA-256 digest of MokList.
+		mokListDigest string
+	}{
+		{"Rhel8GCE", Rhel8GCE, VerifyOpts{Loader: GRUB}, true, "", false, 1,
+			"69bbddbe5a4480b7ab2e5632638b978bba978e66d04b677b3fd4ad2e5c7e1c5b"},
+		{"Ubuntu2404AmdSevSnp", Ubuntu2404AmdSevSnp, VerifyOpts{Loader: GRUB}, true, "sbat,1,2021030218\n", true, 0,
+			"2f196b05a0564764cca674175ecd97898e74ed3891c7c63ce6f17dc82603164a"},
+		{"CGKE251000", CGKE251000, VerifyOpts{Loader: GRUB}, true, "sbat,1,2021030218\n", true, 0,
+			"8d8a3aae50d5d25838c95c034aadce7b548c9a952eb7925e366eda537c59c3b0"},
+		{"UbuntuAmdSevGCE", UbuntuAmdSevGCE, VerifyOpts{Loader: GRUB}, false, "", false, 0, ""},
+		{"Ubuntu2404SystemdBoot", Ubuntu2404SystemdBoot, VerifyOpts{Loader: SystemdBoot}, false, "", false, 0, ""},
+	}
+	for _, tc := range tests {
+		for _, bank := range tc.log.Banks {
+			hashName := pb.HashAlgo_name[int32(bank.Hash)]
+			t.Run(fmt.Sprintf("%s-%s", tc.name, hashName), func(t *testing.T) {
+				msState, err := parsePCClientEventLog(tc.log.RawLog, bank, tc.opts)
+				if err != nil {
+					t.Fatalf("failed to parse and replay log: %v", err)
+				}
+				shim := msState.GetShim()
+				if !tc.wantShim {
+					if shim != nil {
+						t.Errorf("got ShimState %v, want nil", shim)
+					}
+					return
+				}
+				if shim == nil {
+					t.Fatal("got nil ShimState")
+				}
+				if shim.GetSbatLevel() != tc.sbatLevel {
+					t.Errorf("SBAT level got %q, want %q", shim.GetSbatLevel(), tc.sbatLevel)
+				}
+				if shim.GetMokListTrusted() != tc.mokListTrusted {
+					t.Errorf("MokListTrusted got %v, want %v", shim.GetMokListTrusted(), tc.mokListTrusted)
+				}
+				if shim.GetValidationDisabled() {
+					t.Error("got shim validation disabled, want enabled")
+				}
+				if got := len(shim.GetVendorAuthority().GetCerts()); got != tc.vendorCerts {
+					t.Errorf("got %d vendor authority certs, want %d", got, tc.vendorCerts)
+				}
+				if len(shim.GetMokAuthority().GetCerts()) != 0 || len(shim.GetMokAuthority().GetHashes()) != 0 {
+					t.Errorf("got MOK authority %v, want none", shim.GetMokAuthority())
+				}
+				if bank.Hash == pb.HashAlgo_SHA256 && hex.EncodeToString(shim.GetMokListDigest()) != tc.mokListDigest {
+					t.Errorf("MokList digest got %x, want %s", shim.GetMokListDigest(), tc.mokListDigest)
+				}
+			})
+		}
+	}
+}
+
+func TestShimStateEvents(t *testing.T) {
+	hash := crypto.SHA256
+	digest := func(data []byte) []byte {
+		d := sha256.Sum256(data)
+		return d[:]
+	}
+	variableData := func(guid []byte, name string, value []byte) []byte {
+		data := append([]byte{}, guid...)
+		data = binary.LittleEndian.AppendUint64(data, uint64(len(name)))
+		data = binary.LittleEndian.AppendUint64(data, uint64(len(value)))
+		for _, r := range name {
+			data = append(data, byte(r), 0)
+		}
+		return append(data, value...)
+	}
+	authorityEvent := func(name string, value []byte) *attestpb.Event {
+		data := variableData(shimLockGUID, name, value)
+		return &attestpb.Event{PcrIndex: 7, UntrustedType: EFIVariableAuthority, Data: data, Digest: digest(data), DigestVerified: true}
+	}
+	mokEvent := func(name string, value []byte) *attestpb.Event {
+		return &attestpb.Event{PcrIndex: 14, UntrustedType: IPL, Data: append([]byte(name), 0), Digest: digest(value)}
+	}
+	// An EFI_SIGNATURE_DATA containing a SHA-256 image hash.
+	mokHash := digest([]byte("image"))
+	mokSignature := append(make([]byte, 16), mokHash...)
+
+	t.Run("MOKs", func(t *testing.T) {
+		shim, err := getShimState(hash, []*attestpb.Event{
+			mokEvent("MokList", []byte("list")),
+			mokEvent("MokSBState", []byte{1}),
+			authorityEvent("MokSBState", []byte{1}),
+			authorityEvent("MokListRT", mokSignature),
+		})
+		if err != nil {
+			t.Fatalf("getShimState() failed: %v", err)
+		}
+		if !shim.GetValidationDisabled() {
+			t.Error("got shim validation enabled, want disabled")
+		}
+		if hashes := shim.GetMokAuthority().GetHashes(); len(hashes) != 1 || !bytes.Equal(hashes[0], mokHash) {
+			t.Errorf("got MOK authority hashes %x, want [%x]", hashes, mokHash)
+		}
+		if !bytes.Equal(shim.GetMokListDigest(), digest([]byte("list"))) {
+			t.Errorf("got MokList digest %x, want %x", shim.GetMokListDigest(), digest([]byte("list")))
+		}
+	})
+
+	t.Run("FlagsFromAuthorityEvents", func(t *testing.T) {
+		// The PCR14 event names aren't covered by their digests, so relabeling
+		// the MokListTrusted event doesn't hide it, or disable validation.
+		shim, err := getShimState(hash, []*attestpb.Event{
+			mokEvent("MokList", []byte("list")),
+			mokEvent("MokSBState", []byte{1}),
+		})
+		if err != nil {
+			t.Fatalf("getShimState() failed: %v", err)
+		}
+		if shim.GetValidationDisabled() || !shim.GetMokListTrusted() {
+			t.Errorf("got ShimState %v, want validation enabled and MOK list trusted", shim)
+		}
+
+		shim, err = getShimState(hash, []*attestpb.Event{
+			mokEvent("MokSBState", []byte{1}),
+			authorityEvent("MokSBState", []byte{1}),
+		})
+		if err != nil {
+			t.Fatalf("getShimState() failed: %v", err)
+		}
+		if !shim.GetValidationDisabled() || shim.GetMokListTrusted() {
+			t.Errorf("got ShimState %v, want validation disabled and MOK list untrusted", shim)
+		}
+	})
+
+	t.Run("TrailingBytes", func(t *testing.T) {
+		// Firmware authorities may be padded or malformed, and are skipped.
+		dbGUID := make([]byte, 16)
+		padded := append(variableData(dbGUID, "db", make([]byte, 16)), 0, 0, 0)
+		malformed := variableData(dbGUID, "db", nil)[:24]
+		sbat := []byte("sbat,1,2021030218\n")
+		// Older versions of shim only measure part of the padding.
+		shimPadded := append(variableData(shimLockGUID, "SbatLevel", sbat), 0, 0)
+		shim, err := getShimState(hash, []*attestpb.Event{
+			{PcrIndex: 7, UntrustedType: EFIVariableAuthority, Data: padded, Digest: digest(padded), DigestVerified: true},
+			{PcrIndex: 7, UntrustedType: EFIVariableAuthority, Data: malformed, Digest: digest(malformed), DigestVerified: true},
+			{PcrIndex: 7, UntrustedType: EFIVariableAuthority, Data: shimPadded, Digest: digest(shimPadded[:len(shimPadded)-1])},
+		})
+		if err != nil {
+			t.Fatalf("getShimState() failed: %v", err)
+		}
+		if shim.GetSbatLevel() != string(sbat) {
+			t.Errorf("SBAT level got %q, want %q", shim.GetSbatLevel(), sbat)
+		}
+	})
+
+	unverified := authorityEvent("SbatLevel", []byte("sbat,1,2021030218\n"))
+	unverified.DigestVerified = false
+	tests := []struct {
+		name    string
+		events  []*attestpb.Event
+		wantErr string
+	}{
+		{"UnverifiedDigest", []*attestpb.Event{unverified}, "invalid digest for shim variable"},
+		{"UnknownMOKVariable", []*attestpb.Event{mokEvent("MokPolicy", []byte{1})}, "unknown MOK variable"},
+		{"DuplicateMOKVariable", []*attestpb.Event{mokEvent("MokList", nil), mokEvent("MokList", nil)}, "more than once"},
+		{"UnknownShimVariable", []*attestpb.Event{authorityEvent("Unknown", nil)}, "unknown shim variable"},
+		{"WrongType", []*attestpb.Event{{PcrIndex: 14, UntrustedType: EventTag, Data: []byte("MokList\x00")}}, "expected EV_IPL"},
+		{"TruncatedSignature", []*attestpb.Event{authorityEvent("MokListRT", make([]byte, 16))}, "invalid MOK authority"},
+		{"TruncatedVariable", []*attestpb.Event{{PcrIndex: 7, UntrustedType: EFIVariableAuthority, Data: shimLockGUID}}, "invalid authority event"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := getShimState(hash, tc.events)
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("getShimState() got err %v, want error containing %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
 func TestNullTerminatedDataDigest(t *testing.T) {
 	rawdata := []byte("123456")
 	rawdataNullTerminated := []byte("123456\x00")
@@ -1271,6 +1956,8 @@ func TestParseEfiState(t *testing.T) {
 		{COS85AmdSev, "COS85AmdSev"},
 		{COS93AmdSev, "COS93AmdSev"},
 		{COS101AmdSev, "COS101AmdSev"},
+		{Ubuntu2404SystemdBoot, "Ubuntu2404SystemdBoot"},
+		{Ubuntu2404SystemdBootUKI, "Ubuntu2404SystemdBootUKI"},
 	}
 	for _, log := range logs {
 		for _, bank := range log.Banks {
@@ -1367,3 +2054,23 @@ func decodeHex(hexStr string) []byte {
 	}
 	return bytes
 }
+
+func TestToSHA256Events(t *testing.T) {
+	data := []byte("event data")
+	sha384Digest := func(data []byte) []byte {
+		d := sha512.Sum384(data)
+		return d[:]
+	}
+	want := sha256.Sum256(data)
+	wantTruncated := sha256.Sum256(data[:len(data)-1])
+	events := toSHA256Events(crypto.SHA384, []attest.Event{
+		{Data: data, Digest: sha384Digest(data)},
+		{Data: data, Digest: sha384Digest(data[:len(data)-1])},
+		{Data: data, Digest: sha384Digest([]byte("other data"))},
+	})
+	for i, want := range [][]byte{want[:], wantTruncated[:], make([]byte, sha256.Size)} {
+		if !bytes.Equal(events[i].Digest, want) {
+			t.Errorf("event #%d got digest %x, want %x", i, events[i].Digest, want)
+		}
+	}
+}
@@ -0,0 +1,300 @@
+package server
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	pb "github.com/google/go-tpm-tools/proto/attest"
+	tpmpb "github.com/google/go-tpm-tools/proto/tpm"
+	"github.com/google/go-tpm/legacy/tpm2"
+)
+
+// imaLegacyTemplate is the original IMA template, which uses a SHA-1 or MD5
+// file digest and a fixed-size file name buffer when computing its template
+// hash.
+const imaLegacyTemplate = "ima"
+
+// imaLegacyNameSize is the size of the file name buffer hashed by the legacy
+// "ima" template (IMA_EVENT_NAME_LEN_MAX + 1).
+const imaLegacyNameSize = 256
+
+// imaNGTemplates are the IMA templates whose first two fields are "d-ng" (the
+// algorithm-prefixed file digest) and "n-ng" (the NUL-terminated file name).
+// See https://www.kernel.org/doc/html/latest/security/IMA-templates.html.
+var imaNGTemplates = map[string]bool{
+	"ima-ng":     true,
+	"ima-sig":    true,
+	"ima-buf":    true,
+	"ima-modsig": true,
+}
+
+// imaEntry is a single entry of an IMA runtime measurement list.
+type imaEntry struct {
+	pcr uint32
+	// The SHA-1 template hash from the list. It is only used to detect
+	// violations, as the template hash for each PCR bank is recomputed.
+	templateHash []byte
+	template     string
+	// hashedData is the data the kernel hashes to compute the template hash.
+	hashedData  []byte
+	measurement *pb.ImaMeasurement
+}
+
+// parseIMAMeasurementList parses a Linux IMA runtime measurement list and
+// replays it against the provided PCR values. It returns the measurements that
+// were made before the PCRs were read (usually when they were quoted), as the
+// list may have grown before it was collected.
+//
+// Both the binary and ASCII formats of the list are supported, though only the
+// "ima" and "ima-ng" templates can be parsed from the ASCII format. The binary
+// format must be little-endian and use SHA-1 template hashes, as with the
+// default binary_runtime_measurements file.
+func parseIMAMeasurementList(rawList []byte, pcrs *tpmpb.PCRs) (*pb.ImaState, error) {
+	var entries []imaEntry
+	var err error
+	// The binary format starts with a little-endian PCR index, which is never
+	// an ASCII digit.
+	if len(rawList) > 0 && rawList[0] >= '0' && rawList[0] <= '9' {
+		entries, err = parseIMAASCIIList(rawList)
+	} else {
+		entries, err = parseIMABinaryList(rawList)
+	}
+	if err != nil {
+		return nil, err
+	}
+	count, err := replayIMAEntries(entries, pcrs)
+	if err != nil {
+		return nil, err
+	}
+	state := &pb.ImaState{}
+	for _, entry := range entries[:count] {
+		state.Measurements = append(state.Measurements, entry.measurement)
+	}
+	return state, nil
+}
+
+// replayIMAEntries returns the number of entries, counted from the start of the
+// list, that replay to the provided PCR values. At least one entry must replay,
+// as IMA measures the boot aggregate before any other measurement, so a
+// non-empty list can't match PCRs in their reset state.
+func replayIMAEntries(entries []imaEntry, pcrs *tpmpb.PCRs) (int, error) {
+	hash, err := tpm2.Algorithm(pcrs.GetHash()).Hash()
+	if err != nil {
+		return 0, err
+	}
+	replayed := make(map[uint32][]byte)
+	for _, entry := range entries {
+		if _, ok := pcrs.GetPcrs()[entry.pcr]; !ok {
+			return 0, fmt.Errorf("IMA measurement list extends PCR%d, which is not in the PCR bank", entry.pcr)
+		}
+		replayed[entry.pcr] = make([]byte, hash.Size())
+	}
+	matches := func() bool {
+		for index, value := range replayed {
+			if !bytes.Equal(value, pcrs.GetPcrs()[index]) {
+				return false
+			}
+		}
+		return true
+	}
+
+	for i, entry := range entries {
+		if i > 0 && matches() {
+			return i, nil
+		}
+		digest := bytes.Repeat([]byte{0xff}, hash.Size())
+		if !entry.measurement.GetViolation() {
+			hasher := hash.New()
+			hasher.Write(entry.hashedData)
+			digest = hasher.Sum(nil)
+		}
+		hasher := hash.New()
+		hasher.Write(replayed[entry.pcr])
+		hasher.Write(digest)
+		replayed[entry.pcr] = hasher.Sum(nil)
+	}
+	if matches() {
+		return len(entries), nil
+	}
+	return 0, fmt.Errorf("IMA measurement list does not replay to the %v PCR values", pcrs.GetHash())
+}
+
+func parseIMABinaryList(rawList []byte) ([]imaEntry, error) {
+	var entries []imaEntry
+	buf := bytes.NewBuffer(rawList)
+	for buf.Len() > 0 {
+		var header struct {
+			PCR          uint32
+			TemplateHash [sha1.Size]byte
+			NameLength   uint32
+		}
+		if err := binary.Read(buf, binary.LittleEndian, &header); err != nil {
+			return nil, fmt.Errorf("failed to read IMA entry #%d header: %v", len(entries), err)
+		}
+		template := buf.Next(int(header.NameLength))
+		if len(template) != int(header.NameLength) {
+			return nil, fmt.Errorf("IMA entry #%d template name is truncated", len(entries))
+		}
+		entry := imaEntry{
+			pcr:          header.PCR,
+			templateHash: header.TemplateHash[:],
+			template:     string(template),
+		}
+
+		var err error
+		if entry.template == imaLegacyTemplate {
+			// The legacy template has no template data length, and is
+			// followed by the file digest and the length-prefixed file name.
+			digest := buf.Next(sha1.Size)
+			var path []byte
+			if path, err = readIMAField(buf); err == nil {
+				err = entry.setLegacyData(digest, string(path))
+			}
+		} else {
+			var templateData []byte
+			if templateData, err = readIMAField(buf); err == nil {
+				err = entry.setTemplateData(templateData)
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid IMA entry #%d: %v", len(entries), err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func parseIMAASCIIList(rawList []byte) ([]imaEntry, error) {
+	var entries []imaEntry
+	for i, line := range strings.Split(strings.TrimSuffix(string(rawList), "\n"), "\n") {
+		// The file name is last, and may contain spaces.
+		fields := strings.SplitN(line, " ", 5)
+		if len(fields) != 5 {
+			return nil, fmt.Errorf("IMA entry #%d has %d fields, expected 5", i, len(fields))
+		}
+		pcr, err := strconv.ParseUint(fields[0], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid IMA entry #%d PCR: %v", i, err)
+		}
+		templateHash, err := hex.DecodeString(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid IMA entry #%d template hash: %v", i, err)
+		}
+		entry := imaEntry{pcr: uint32(pcr), templateHash: templateHash, template: fields[2]}
+
+		switch entry.template {
+		case imaLegacyTemplate:
+			var digest []byte
+			if digest, err = hex.DecodeString(fields[3]); err == nil {
+				err = entry.setLegacyData(digest, fields[4])
+			}
+		case "ima-ng":
+			algorithm, digestHex, ok := strings.Cut(fields[3], ":")
+			if !ok {
+				err = fmt.Errorf("file digest %q has no algorithm", fields[3])
+				break
+			}
+			var digest []byte
+			if digest, err = hex.DecodeString(digestHex); err != nil {
+				break
+			}
+			templateData := appendIMAField(nil, append([]byte(algorithm+":\x00"), digest...))
+			templateData = appendIMAField(templateData, append([]byte(fields[4]), '\x00'))
+			err = entry.setTemplateData(templateData)
+		default:
+			err = fmt.Errorf("template %q is only supported in the binary format", entry.template)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid IMA entry #%d: %v", i, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// setLegacyData sets the data of an entry using the legacy "ima" template.
+func (e *imaEntry) setLegacyData(digest []byte, path string) error {
+	if len(digest) != sha1.Size {
+		return fmt.Errorf("file digest is %d bytes, expected %d", len(digest), sha1.Size)
+	}
+	if len(path) >= imaLegacyNameSize {
+		return fmt.Errorf("file name is %d bytes, expected less than %d", len(path), imaLegacyNameSize)
+	}
+	name := make([]byte, imaLegacyNameSize)
+	copy(name, path)
+	e.hashedData = append(append([]byte{}, digest...), name...)
+	e.measurement = &pb.ImaMeasurement{
+		PcrIndex:        e.pcr,
+		Template:        e.template,
+		Path:            path,
+		Digest:          digest,
+		DigestAlgorithm: "sha1",
+		Violation:       e.isViolation(),
+	}
+	return nil
+}
+
+// setTemplateData sets the data of an entry using a template whose data is a
+// series of length-prefixed fields.
+func (e *imaEntry) setTemplateData(templateData []byte) error {
+	e.hashedData = templateData
+	e.measurement = &pb.ImaMeasurement{
+		PcrIndex:  e.pcr,
+		Template:  e.template,
+		Violation: e.isViolation(),
+	}
+	if !imaNGTemplates[e.template] {
+		// The fields of custom templates are unknown, so only the template
+		// data can be replayed.
+		return nil
+	}
+
+	buf := bytes.NewBuffer(templateData)
+	digestField, err := readIMAField(buf)
+	if err != nil {
+		return fmt.Errorf("invalid d-ng field: %v", err)
+	}
+	nameField, err := readIMAField(buf)
+	if err != nil {
+		return fmt.Errorf("invalid n-ng field: %v", err)
+	}
+	algorithm, digest, ok := bytes.Cut(digestField, []byte(":\x00"))
+	if !ok {
+		return errors.New("d-ng field has no algorithm")
+	}
+	e.measurement.Path = string(bytes.TrimSuffix(nameField, []byte{'\x00'}))
+	e.measurement.Digest = digest
+	e.measurement.DigestAlgorithm = string(algorithm)
+	return nil
+}
+
+// isViolation reports whether the entry records a violation, which IMA
+// indicates with a zero template hash.
+func (e *imaEntry) isViolation() bool {
+	return bytes.Equal(e.templateHash, make([]byte, len(e.templateHash)))
+}
+
+// readIMAField reads a field prefixed by its little-endian uint32 length.
+func readIMAField(buf *bytes.Buffer) ([]byte, error) {
+	var length uint32
+	if err := binary.Read(buf, binary.LittleEndian, &length); err != nil {
+		return nil, err
+	}
+	field := buf.Next(int(length))
+	if len(field) != int(length) {
+		return nil, fmt.Errorf("field is %d bytes, but its length is %d", len(field), length)
+	}
+	return field, nil
+}
+
+// appendIMAField appends a field prefixed by its little-endian uint32 length.
+func appendIMAField(data []byte, field []byte) []byte {
+	data = binary.LittleEndian.AppendUint32(data, uint32(len(field)))
+	return append(data, field...)
+}
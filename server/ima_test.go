@@ -0,0 +1,290 @@
+package server
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-tpm-tools/client"
+	"github.com/google/go-tpm-tools/internal/test"
+	attestpb "github.com/google/go-tpm-tools/proto/attest"
+	pb "github.com/google/go-tpm-tools/proto/tpm"
+	"github.com/google/go-tpm/legacy/tpm2"
+	"github.com/google/go-tpm/tpmutil"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+const imaTestPCR = 10
+
+type testIMAEntry struct {
+	template  string
+	path      string
+	content   string
+	violation bool
+}
+
+func (e testIMAEntry) fileDigest() []byte {
+	if e.violation {
+		return make([]byte, sha256.Size)
+	}
+	if e.template == imaLegacyTemplate {
+		digest := sha1.Sum([]byte(e.content))
+		return digest[:]
+	}
+	digest := sha256.Sum256([]byte(e.content))
+	return digest[:]
+}
+
+// templateData returns the template data as it appears in the binary list.
+func (e testIMAEntry) templateData() []byte {
+	if e.template == imaLegacyTemplate {
+		return appendIMAField(e.fileDigest(), []byte(e.path))
+	}
+	data := appendIMAField(nil, append([]byte("sha256:\x00"), e.fileDigest()...))
+	data = appendIMAField(data, append([]byte(e.path), 0))
+	if e.template == "ima-sig" {
+		data = appendIMAField(data, nil)
+	}
+	return data
+}
+
+// templateHash returns the hash extended into the PCR for the given algorithm.
+func (e testIMAEntry) templateHash(hash crypto.Hash) []byte {
+	if e.violation {
+		return bytes.Repeat([]byte{0xff}, hash.Size())
+	}
+	hasher := hash.New()
+	if e.template == imaLegacyTemplate {
+		name := make([]byte, imaLegacyNameSize)
+		copy(name, e.path)
+		hasher.Write(e.fileDigest())
+		hasher.Write(name)
+	} else {
+		hasher.Write(e.templateData())
+	}
+	return hasher.Sum(nil)
+}
+
+func (e testIMAEntry) listTemplateHash() []byte {
+	if e.violation {
+		return make([]byte, sha1.Size)
+	}
+	return e.templateHash(crypto.SHA1)
+}
+
+func (e testIMAEntry) measurement() *attestpb.ImaMeasurement {
+	algorithm := "sha256"
+	if e.template == imaLegacyTemplate {
+		algorithm = "sha1"
+	}
+	return &attestpb.ImaMeasurement{
+		PcrIndex:        imaTestPCR,
+		Template:        e.template,
+		Path:            e.path,
+		Digest:          e.fileDigest(),
+		DigestAlgorithm: algorithm,
+		Violation:       e.violation,
+	}
+}
+
+func binaryIMAList(entries []testIMAEntry) []byte {
+	var list []byte
+	for _, e := range entries {
+		list = binary.LittleEndian.AppendUint32(list, imaTestPCR)
+		list = append(list, e.listTemplateHash()...)
+		list = appendIMAField(list, []byte(e.template))
+		if e.template == imaLegacyTemplate {
+			list = append(list, e.templateData()...)
+		} else {
+			list = appendIMAField(list, e.templateData())
+		}
+	}
+	return list
+}
+
+func asciiIMAList(entries []testIMAEntry) []byte {
+	var list strings.Builder
+	for _, e := range entries {
+		digest := fmt.Sprintf("sha256:%x", e.fileDigest())
+		if e.template == imaLegacyTemplate {
+			digest = fmt.Sprintf("%x", e.fileDigest())
+		}
+		fmt.Fprintf(&list, "%d %x %s %s %s\n", imaTestPCR, e.listTemplateHash(), e.template, digest, e.path)
+	}
+	return []byte(list.String())
+}
+
+func imaPCRs(hash crypto.Hash, entries []testIMAEntry) *pb.PCRs {
+	alg, _ := tpm2.HashToAlgorithm(hash)
+	pcr := make([]byte, hash.Size())
+	for _, e := range entries {
+		hasher := hash.New()
+		hasher.Write(pcr)
+		hasher.Write(e.templateHash(hash))
+		pcr = hasher.Sum(nil)
+	}
+	return &pb.PCRs{Hash: pb.HashAlgo(alg), Pcrs: map[uint32][]byte{imaTestPCR: pcr}}
+}
+
+var testIMAEntries = []testIMAEntry{
+	{template: "ima-ng", path: "boot_aggregate", content: "pcrs"},
+	{template: "ima-ng", path: "/usr/bin/bash", content: "bash"},
+	{template: "ima-ng", path: "/etc/my config", content: "config"},
+	{template: "ima-ng", path: "/var/log/syslog", violation: true},
+	{template: "ima", path: "/usr/lib/libc.so.6", content: "libc"},
+}
+
+func TestParseIMAMeasurementList(t *testing.T) {
+	withSig := append(append([]testIMAEntry{}, testIMAEntries...),
+		testIMAEntry{template: "ima-sig", path: "/usr/bin/ls", content: "ls"})
+	formats := []struct {
+		name    string
+		entries []testIMAEntry
+		list    func([]testIMAEntry) []byte
+	}{
+		{"Binary", testIMAEntries, binaryIMAList},
+		{"ASCII", testIMAEntries, asciiIMAList},
+		{"BinaryIMASig", withSig, binaryIMAList},
+	}
+	for _, format := range formats {
+		var want []*attestpb.ImaMeasurement
+		for _, e := range format.entries {
+			want = append(want, e.measurement())
+		}
+		for _, hash := range []crypto.Hash{crypto.SHA1, crypto.SHA256} {
+			t.Run(fmt.Sprintf("%s-%v", format.name, hash), func(t *testing.T) {
+				state, err := parseIMAMeasurementList(format.list(format.entries), imaPCRs(hash, format.entries))
+				if err != nil {
+					t.Fatalf("parseIMAMeasurementList() failed: %v", err)
+				}
+				if diff := cmp.Diff(want, state.GetMeasurements(), protocmp.Transform()); diff != "" {
+					t.Errorf("unexpected IMA measurements (-want +got):\n%s", diff)
+				}
+			})
+		}
+	}
+}
+
+func TestParseIMAMeasurementListNewerThanPCRs(t *testing.T) {
+	// Entries measured after the PCRs were quoted are dropped.
+	state, err := parseIMAMeasurementList(binaryIMAList(testIMAEntries), imaPCRs(crypto.SHA256, testIMAEntries[:2]))
+	if err != nil {
+		t.Fatalf("parseIMAMeasurementList() failed: %v", err)
+	}
+	if got := len(state.GetMeasurements()); got != 2 {
+		t.Errorf("got %d measurements, want 2", got)
+	}
+}
+
+func TestParseIMAMeasurementListFail(t *testing.T) {
+	tampered := append([]testIMAEntry{}, testIMAEntries...)
+	tampered[1].content = "evil bash"
+	pcrs := imaPCRs(crypto.SHA256, testIMAEntries)
+	tests := []struct {
+		name    string
+		list    []byte
+		pcrs    *pb.PCRs
+		wantErr string
+	}{
+		{"TamperedDigest", binaryIMAList(tampered), pcrs, "does not replay"},
+		{"TamperedASCII", asciiIMAList(tampered), pcrs, "does not replay"},
+		{"MissingEntries", binaryIMAList(testIMAEntries[:2]), pcrs, "does not replay"},
+		{"ResetPCR", binaryIMAList(testIMAEntries), imaPCRs(crypto.SHA256, nil), "does not replay"},
+		{"Truncated", binaryIMAList(testIMAEntries)[:100], pcrs, "invalid IMA entry"},
+		{"MissingPCR", binaryIMAList(testIMAEntries), &pb.PCRs{Hash: pb.HashAlgo_SHA256}, "not in the PCR bank"},
+		{"UnsupportedASCIITemplate", []byte("10 00 ima-sig sha256:00 /bin/ls 00\n"), pcrs, "only supported in the binary format"},
+		{"MalformedASCII", []byte("10 00 ima-ng\n"), pcrs, "expected 5"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := parseIMAMeasurementList(tc.list, tc.pcrs)
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("parseIMAMeasurementList() got err %v, want error containing %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestVerifyAttestationWithIMA(t *testing.T) {
+	rwc := test.GetTPM(t)
+	defer client.CheckedClose(t, rwc)
+
+	for _, hash := range []crypto.Hash{crypto.SHA1, crypto.SHA256} {
+		alg, err := tpm2.HashToAlgorithm(hash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, e := range testIMAEntries {
+			if err := tpm2.PCRExtend(rwc, tpmutil.Handle(imaTestPCR), alg, e.templateHash(hash), ""); err != nil {
+				t.Fatalf("failed to extend PCR%d: %v", imaTestPCR, err)
+			}
+		}
+	}
+	ak, err := client.AttestationKeyRSA(rwc)
+	if err != nil {
+		t.Fatalf("failed to generate AK: %v", err)
+	}
+	defer ak.Close()
+
+	nonce := []byte("super secret nonce")
+	attestation, err := ak.Attest(client.AttestOpts{Nonce: nonce, IMAMeasurementList: binaryIMAList(testIMAEntries)})
+	if err != nil {
+		t.Fatalf("failed to attest: %v", err)
+	}
+	state, err := VerifyAttestation(attestation, VerifyOpts{
+		Nonce:      nonce,
+		TrustedAKs: []crypto.PublicKey{ak.PublicKey()},
+		Loader:     GRUB,
+	})
+	if err != nil {
+		t.Fatalf("failed to verify: %v", err)
+	}
+	if got := len(state.GetIma().GetMeasurements()); got != len(testIMAEntries) {
+		t.Errorf("got %d IMA measurements, want %d", got, len(testIMAEntries))
+	}
+
+	attestation.ImaMeasurementList = binaryIMAList(testIMAEntries[1:])
+	if _, err := VerifyAttestation(attestation, VerifyOpts{
+		Nonce:      nonce,
+		TrustedAKs: []crypto.PublicKey{ak.PublicKey()},
+	}); err == nil {
+		t.Error("verification succeeded with an IMA measurement list missing entries, expected failure")
+	}
+}
+
+func TestVerifyAttestationWithIMAUsesEventLogBank(t *testing.T) {
+	rwc := test.GetTPM(t)
+	defer client.CheckedClose(t, rwc)
+
+	// Only the SHA-384 bank matches the list, but the event log verifies
+	// against the preferred SHA-256 bank, so the list must be rejected.
+	for _, e := range testIMAEntries {
+		if err := tpm2.PCRExtend(rwc, tpmutil.Handle(imaTestPCR), tpm2.AlgSHA384, e.templateHash(crypto.SHA384), ""); err != nil {
+			t.Fatalf("failed to extend PCR%d: %v", imaTestPCR, err)
+		}
+	}
+	ak, err := client.AttestationKeyRSA(rwc)
+	if err != nil {
+		t.Fatalf("failed to generate AK: %v", err)
+	}
+	defer ak.Close()
+
+	nonce := []byte("super secret nonce")
+	attestation, err := ak.Attest(client.AttestOpts{Nonce: nonce, IMAMeasurementList: binaryIMAList(testIMAEntries)})
+	if err != nil {
+		t.Fatalf("failed to attest: %v", err)
+	}
+	if _, err := VerifyAttestation(attestation, VerifyOpts{
+		Nonce:      nonce,
+		TrustedAKs: []crypto.PublicKey{ak.PublicKey()},
+		Loader:     GRUB,
+	}); err == nil || !strings.Contains(err.Error(), "IMA measurement list") {
+		t.Errorf("VerifyAttestation() got err %v, want IMA measurement list error", err)
+	}
+}
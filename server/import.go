@@ -36,6 +36,26 @@ func CreateImportBlob(ekPub crypto.PublicKey, sensitive []byte, pcrs *pb.PCRs) (
 	return createImportBlobHelper(ek, public, private, pcrs)
 }
 
+// CreateImportBlobWithPolicy is like CreateImportBlob, but requires the
+// provided client.Policy to be satisfied for Import to succeed. The returned
+// ImportBlob must be imported using the client Key.ImportWithPolicy() method.
+func CreateImportBlobWithPolicy(ekPub crypto.PublicKey, sensitive []byte, policy *client.Policy) (*pb.ImportBlob, error) {
+	ek, err := CreateEKPublicAreaFromKey(ekPub)
+	if err != nil {
+		return nil, err
+	}
+	digest, err := policy.Digest()
+	if err != nil {
+		return nil, fmt.Errorf("invalid policy: %w", err)
+	}
+	private := createPrivate(sensitive)
+	public := createPublic(private)
+	public.AuthPolicy = digest
+	public.Attributes |= tpm2.FlagAdminWithPolicy
+
+	return encryptImportBlob(ek, public, private)
+}
+
 // CreateSigningKeyImportBlob uses the provided public EK to encrypt the signing
 // key into import blob format. The returned import blob can be used to import
 // the signing key into the TPM associated with the provided EK without exposing
@@ -57,7 +77,15 @@ func CreateSigningKeyImportBlob(ekPub crypto.PublicKey, signingKey crypto.Privat
 
 func createImportBlobHelper(ek, public tpm2.Public, private tpm2.Private, pcrs *pb.PCRs) (*pb.ImportBlob, error) {
 	setPublicAuth(&public, pcrs)
+	blob, err := encryptImportBlob(ek, public, private)
+	if err != nil {
+		return nil, err
+	}
+	blob.Pcrs = pcrs
+	return blob, nil
+}
 
+func encryptImportBlob(ek, public tpm2.Public, private tpm2.Private) (*pb.ImportBlob, error) {
 	var seed, encryptedSeed []byte
 	var err error
 	switch ek.Type {
@@ -87,7 +115,6 @@ func createImportBlobHelper(ek, public tpm2.Public, private tpm2.Private, pcrs *
 		Duplicate:     duplicate,
 		EncryptedSeed: encryptedSeed,
 		PublicArea:    pubEncoded,
-		Pcrs:          pcrs,
 	}, nil
 }
 
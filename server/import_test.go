@@ -184,6 +184,107 @@ func TestImportPCRs(t *testing.T) {
 	}
 }
 
+func TestImportWithPolicy(t *testing.T) {
+	rwc := test.GetTPM(t)
+	defer client.CheckedClose(t, rwc)
+
+	ek, err := client.EndorsementKeyECC(rwc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ek.Close()
+	pcr0, err := tpm2.ReadPCR(rwc, 0, tpm2.AlgSHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	badPCR := append([]byte(nil), pcr0...)
+	badPCR[0]++
+	goodPCRs := &pb.PCRs{Hash: pb.HashAlgo_SHA256, Pcrs: map[uint32][]byte{0: pcr0}}
+	badPCRs := &pb.PCRs{Hash: pb.HashAlgo_SHA256, Pcrs: map[uint32][]byte{0: badPCR}}
+	subtests := []struct {
+		name          string
+		policy        *client.Policy
+		expectSuccess bool
+	}{
+		{"Good-PCR", client.NewPolicy().PolicyPCR(goodPCRs).PolicyLocality(0), true},
+		{"Bad-PCR", client.NewPolicy().PolicyPCR(badPCRs).PolicyLocality(0), false},
+		{"OR", client.NewPolicy().PolicyOR(
+			client.NewPolicy().PolicyPCR(badPCRs),
+			client.NewPolicy().PolicyPCR(goodPCRs),
+		), true},
+		{"Expired", client.NewPolicy().PolicyPCR(goodPCRs).PolicyClockBefore(0), false},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.name, func(t *testing.T) {
+			secret := []byte("super secret code")
+			blob, err := CreateImportBlobWithPolicy(ek.PublicKey(), secret, subtest.policy)
+			if err != nil {
+				t.Fatalf("creating import blob failed: %v", err)
+			}
+			if _, err := ek.Import(blob); err == nil {
+				t.Error("Import succeeded without the policy, expected failure")
+			}
+			output, err := ek.ImportWithPolicy(blob, subtest.policy)
+			if subtest.expectSuccess {
+				if err != nil {
+					t.Fatalf("import failed: %v", err)
+				}
+				if !bytes.Equal(output, secret) {
+					t.Errorf("got %X, expected %X", output, secret)
+				}
+			} else if err == nil {
+				t.Error("expected ImportWithPolicy to fail but it did not")
+			}
+		})
+	}
+}
+
+func TestImportWithSessionEncryption(t *testing.T) {
+	rwc := test.GetTPM(t)
+	defer client.CheckedClose(t, rwc)
+
+	ek, err := client.EndorsementKeyRSA(rwc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ek.Close()
+	if err := ek.SetSessionEncryption(ek); err != nil {
+		t.Fatalf("SetSessionEncryption() failed: %v", err)
+	}
+	pcrs, err := client.ReadPCRs(rwc, tpm2.PCRSelection{Hash: tpm2.AlgSHA256, PCRs: []int{0, 7}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	secret := []byte("super secret code")
+
+	for _, blobPCRs := range []*pb.PCRs{nil, pcrs} {
+		blob, err := CreateImportBlob(ek.PublicKey(), secret, blobPCRs)
+		if err != nil {
+			t.Fatalf("creating import blob failed: %v", err)
+		}
+		output, err := ek.Import(blob)
+		if err != nil {
+			t.Fatalf("import failed: %v", err)
+		}
+		if !bytes.Equal(output, secret) {
+			t.Errorf("got %X, expected %X", output, secret)
+		}
+	}
+
+	policy := client.NewPolicy().PolicyPCR(pcrs).PolicyLocality(0)
+	blob, err := CreateImportBlobWithPolicy(ek.PublicKey(), secret, policy)
+	if err != nil {
+		t.Fatalf("creating import blob failed: %v", err)
+	}
+	output, err := ek.ImportWithPolicy(blob, policy)
+	if err != nil {
+		t.Fatalf("import failed: %v", err)
+	}
+	if !bytes.Equal(output, secret) {
+		t.Errorf("got %X, expected %X", output, secret)
+	}
+}
+
 func TestSigningKeyImport(t *testing.T) {
 	rwc := test.GetTPM(t)
 	defer client.CheckedClose(t, rwc)
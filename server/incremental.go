@@ -0,0 +1,341 @@
+package server
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/google/go-attestation/attest"
+	"github.com/google/go-tpm-tools/cel"
+	"github.com/google/go-tpm-tools/internal"
+	"github.com/google/go-tpm-tools/internal/eventlog"
+	pb "github.com/google/go-tpm-tools/proto/attest"
+	tpmpb "github.com/google/go-tpm-tools/proto/tpm"
+	"github.com/google/go-tpm/legacy/tpm2"
+	"google.golang.org/protobuf/proto"
+)
+
+// IncrementalVerifier verifies the attestations of long-lived machines that
+// attest repeatedly, without parsing and replaying their entire event logs
+// each time.
+//
+// The first attestation verified for an AK must contain the full TCG and
+// canonical event logs, and is verified as with VerifyAttestation. The COS
+// state from the canonical event log (replayed against the same PCR bank) is
+// also added to the MachineState.
+//
+// Later attestations for the same AK must only contain the events appended to
+// each log since the last attestation verified for that AK: raw
+// TCG_PCR_EVENT2 structures for the TCG event log, and CEL records (numbered
+// after the previously verified records) for the canonical event log. These
+// events are replayed starting from the previously verified PCR values, and
+// must match a quote over the same PCR bank as the first attestation. Every
+// PCR extended by either log must match the quote, even if no events were
+// appended to it.
+//
+// Once a machine reboots, its PCRs are reset and its attestations no longer
+// verify. Call Forget to verify its next attestation from the full logs.
+//
+// IMA measurement lists are not supported. An IncrementalVerifier is safe for
+// concurrent use.
+type IncrementalVerifier struct {
+	opts VerifyOpts
+
+	mu     sync.Mutex
+	states map[string]*incrementalState
+}
+
+// incrementalState is the verified state of a single AK.
+type incrementalState struct {
+	mu sync.Mutex
+	// The PCR bank of the verified quotes.
+	bank tpmpb.HashAlgo
+	// The replayed values of the PCRs extended by the verified events.
+	verified map[uint32][]byte
+	// Used to parse appended TCG events. It is nil if the full TCG event log
+	// was not in the crypto-agile format.
+	tcgLog *eventlog.Log
+	// The verified TCG events of the PCR bank.
+	events []attest.Event
+	// The verified canonical event log records.
+	cel cel.CEL
+	// The MachineState parsed from the TCG event log.
+	tcgState *pb.MachineState
+	// The COS state parsed from the canonical event log, if any.
+	cosState *pb.AttestedCosState
+}
+
+// NewIncrementalVerifier returns an IncrementalVerifier that verifies
+// attestations using opts. The Nonce in opts is ignored, as each attestation
+// has its own nonce.
+func NewIncrementalVerifier(opts VerifyOpts) (*IncrementalVerifier, error) {
+	if err := validateOpts(opts); err != nil {
+		return nil, fmt.Errorf("bad options: %w", err)
+	}
+	return &IncrementalVerifier{opts: opts, states: make(map[string]*incrementalState)}, nil
+}
+
+// Verify verifies an attestation generated with the provided nonce, and
+// returns the machine's MachineState. If an attestation fails to verify, the
+// state kept for its AK is not changed.
+func (v *IncrementalVerifier) Verify(attestation *pb.Attestation, nonce []byte) (*pb.MachineState, error) {
+	if len(attestation.GetImaMeasurementList()) != 0 {
+		return nil, errors.New("IMA measurement lists are not supported by IncrementalVerifier")
+	}
+	opts := v.opts
+	opts.Nonce = nonce
+
+	machineState, akPubKey, err := validateAK(attestation, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse and validate AK: %w", err)
+	}
+	key, err := x509.MarshalPKIXPublicKey(akPubKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal AK public key: %w", err)
+	}
+
+	v.mu.Lock()
+	state, ok := v.states[string(key)]
+	if !ok {
+		state = &incrementalState{}
+		v.states[string(key)] = state
+	}
+	v.mu.Unlock()
+
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	if state.tcgState == nil {
+		err = state.init(attestation, akPubKey, opts)
+	} else {
+		err = state.update(attestation, akPubKey, opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	proto.Merge(machineState, state.tcgState)
+	if state.cosState != nil {
+		machineState.Cos = proto.Clone(state.cosState).(*pb.AttestedCosState)
+	}
+	return machineState, nil
+}
+
+// Forget discards the state kept for an AK, so the next attestation verified
+// for it must contain the full event logs.
+func (v *IncrementalVerifier) Forget(akPub crypto.PublicKey) error {
+	key, err := x509.MarshalPKIXPublicKey(akPub)
+	if err != nil {
+		return fmt.Errorf("failed to marshal AK public key: %w", err)
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	delete(v.states, string(key))
+	return nil
+}
+
+// init verifies an attestation containing the full event logs.
+func (s *incrementalState) init(attestation *pb.Attestation, akPubKey crypto.PublicKey, opts VerifyOpts) error {
+	quote, tcgState, err := verifyQuotesAndParse(attestation, akPubKey, opts)
+	if err != nil {
+		return err
+	}
+	pcrs := quote.GetPcrs()
+	verified := make(map[uint32][]byte)
+	events := make([]attest.Event, 0, len(tcgState.GetRawEvents()))
+	for _, event := range tcgState.GetRawEvents() {
+		verified[event.GetPcrIndex()] = pcrs.GetPcrs()[event.GetPcrIndex()]
+		events = append(events, attest.Event{
+			Index:  int(event.GetPcrIndex()),
+			Type:   attest.EventType(event.GetUntrustedType()),
+			Data:   event.GetData(),
+			Digest: event.GetDigest(),
+		})
+	}
+	// Legacy event logs cannot have events appended, as their events have a
+	// different format.
+	tcgLog, err := eventlog.Parse(attestation.GetEventLog())
+	if err == nil {
+		tcgLog.Events = nil
+	}
+
+	records, cosState, err := appendCELRecords(cel.CEL{}, attestation.GetCanonicalEventLog(), pcrs, verified)
+	if err != nil {
+		return err
+	}
+	if err := checkReplayed(verified, pcrs); err != nil {
+		return err
+	}
+
+	s.bank = pcrs.GetHash()
+	s.verified = verified
+	s.tcgLog = tcgLog
+	s.events = events
+	s.cel = records
+	s.tcgState = tcgState
+	s.cosState = cosState
+	return nil
+}
+
+// update verifies an attestation containing only appended events.
+func (s *incrementalState) update(attestation *pb.Attestation, akPubKey crypto.PublicKey, opts VerifyOpts) error {
+	var quote *tpmpb.Quote
+	for _, q := range attestation.GetQuotes() {
+		if q.GetPcrs().GetHash() == s.bank {
+			quote = q
+			break
+		}
+	}
+	if quote == nil {
+		return fmt.Errorf("attestation does not contain a %v quote, which previous attestations were verified with", s.bank)
+	}
+	if err := internal.VerifyQuote(quote, akPubKey, opts.Nonce); err != nil {
+		return fmt.Errorf("failed to verify quote: %w", err)
+	}
+	pcrs := quote.GetPcrs()
+	replayed := maps.Clone(s.verified)
+
+	events := s.events
+	appended, err := s.replayTCGEvents(attestation.GetEventLog(), pcrs, replayed)
+	if err != nil {
+		return err
+	}
+	tcgState := s.tcgState
+	if len(appended) != 0 {
+		events = append(slices.Clip(events), appended...)
+		if tcgState, err = getPCClientMachineState(s.bank, events, opts); err != nil {
+			return fmt.Errorf("failed to parse machine state from TCG event log: %w", err)
+		}
+	}
+
+	records, cosState := s.cel, s.cosState
+	if len(attestation.GetCanonicalEventLog()) != 0 {
+		if records, cosState, err = appendCELRecords(s.cel, attestation.GetCanonicalEventLog(), pcrs, replayed); err != nil {
+			return err
+		}
+	}
+	if err := checkReplayed(replayed, pcrs); err != nil {
+		return err
+	}
+
+	s.verified = replayed
+	s.events = events
+	s.cel = records
+	s.tcgState = tcgState
+	s.cosState = cosState
+	return nil
+}
+
+// replayTCGEvents parses appended TCG events and extends them into the replayed
+// PCR values. It returns the events extended into the quoted PCRs.
+func (s *incrementalState) replayTCGEvents(rawEvents []byte, pcrs *tpmpb.PCRs, replayed map[uint32][]byte) ([]attest.Event, error) {
+	if len(rawEvents) == 0 {
+		return nil, nil
+	}
+	if s.tcgLog == nil {
+		return nil, errors.New("cannot append events to a TCG event log that is not in the crypto-agile format")
+	}
+	parsed, err := s.tcgLog.ParseEvents(rawEvents)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse appended TCG events: %w", err)
+	}
+	alg := tpm2.Algorithm(s.bank)
+	var events []attest.Event
+	for i, event := range parsed {
+		if _, ok := pcrs.GetPcrs()[event.Index]; !ok {
+			continue
+		}
+		digest, ok := event.Digests[alg]
+		if !ok {
+			return nil, fmt.Errorf("appended TCG event #%d has no %v digest", i, s.bank)
+		}
+		if event.Type != eventlog.NoAction {
+			if err := extendReplayed(replayed, s.bank, event.Index, digest); err != nil {
+				return nil, err
+			}
+		}
+		events = append(events, attest.Event{
+			Index:  int(event.Index),
+			Type:   attest.EventType(event.Type),
+			Data:   event.Data,
+			Digest: digest,
+		})
+	}
+	return events, nil
+}
+
+// appendCELRecords decodes canonical event log records, extends them into the
+// replayed PCR values, and appends them to the verified records. It returns
+// the COS state of all the records, or nil if there are none.
+func appendCELRecords(verified cel.CEL, rawRecords []byte, pcrs *tpmpb.PCRs, replayed map[uint32][]byte) (cel.CEL, *pb.AttestedCosState, error) {
+	if len(verified.Records) == 0 && len(rawRecords) == 0 {
+		return verified, nil, nil
+	}
+	appended, err := cel.DecodeToCEL(bytes.NewBuffer(rawRecords))
+	if err != nil {
+		return cel.CEL{}, nil, fmt.Errorf("failed to decode canonical event log: %w", err)
+	}
+	hash, err := tpm2.Algorithm(pcrs.GetHash()).Hash()
+	if err != nil {
+		return cel.CEL{}, nil, err
+	}
+	for i, record := range appended.Records {
+		if want := uint64(len(verified.Records) + i); record.RecNum != want {
+			return cel.CEL{}, nil, fmt.Errorf("canonical event log record number is %d, expected %d", record.RecNum, want)
+		}
+		if record.IndexType != cel.PCRTypeValue {
+			return cel.CEL{}, nil, fmt.Errorf("canonical event log record #%d is not for a PCR", record.RecNum)
+		}
+		digest, ok := record.Digests[hash]
+		if !ok {
+			return cel.CEL{}, nil, fmt.Errorf("canonical event log record #%d has no %v digest", record.RecNum, pcrs.GetHash())
+		}
+		if err := extendReplayed(replayed, pcrs.GetHash(), uint32(record.Index), digest); err != nil {
+			return cel.CEL{}, nil, err
+		}
+	}
+
+	records := cel.CEL{Records: append(slices.Clip(verified.Records), appended.Records...)}
+	cosState, err := getVerifiedCosState(records, cel.PCRTypeValue)
+	if err != nil {
+		return cel.CEL{}, nil, err
+	}
+	return records, cosState, nil
+}
+
+// extendReplayed extends a digest into the replayed value of a PCR. PCRs
+// without a replayed value start from zero.
+func extendReplayed(replayed map[uint32][]byte, bank tpmpb.HashAlgo, index uint32, digest []byte) error {
+	hash, err := tpm2.Algorithm(bank).Hash()
+	if err != nil {
+		return err
+	}
+	value, ok := replayed[index]
+	if !ok {
+		value = make([]byte, hash.Size())
+	}
+	hasher := hash.New()
+	hasher.Write(value)
+	hasher.Write(digest)
+	replayed[index] = hasher.Sum(nil)
+	return nil
+}
+
+// checkReplayed checks that the replayed PCR values match the quoted values.
+func checkReplayed(replayed map[uint32][]byte, pcrs *tpmpb.PCRs) error {
+	var mismatched []uint32
+	for index, value := range replayed {
+		if !bytes.Equal(value, pcrs.GetPcrs()[index]) {
+			mismatched = append(mismatched, index)
+		}
+	}
+	if len(mismatched) != 0 {
+		slices.Sort(mismatched)
+		return fmt.Errorf("event logs do not replay to the quoted %v PCRs %v", pcrs.GetHash(), mismatched)
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package server
+
+import (
+	"bytes"
+	"crypto"
+	"encoding/binary"
+	"testing"
+
+	"github.com/google/go-tpm-tools/cel"
+	"github.com/google/go-tpm-tools/client"
+	"github.com/google/go-tpm-tools/internal/test"
+	attestpb "github.com/google/go-tpm-tools/proto/attest"
+	"github.com/google/go-tpm/legacy/tpm2"
+	"github.com/google/go-tpm/tpmutil"
+)
+
+func TestIncrementalVerifier(t *testing.T) {
+	test.SkipForRealTPM(t)
+	rwc := test.GetTPM(t)
+	defer client.CheckedClose(t, rwc)
+
+	ak, err := client.AttestationKeyRSA(rwc)
+	if err != nil {
+		t.Fatalf("failed to generate AK: %v", err)
+	}
+	defer ak.Close()
+	verifier, err := NewIncrementalVerifier(VerifyOpts{
+		TrustedAKs: []crypto.PublicKey{ak.PublicKey()},
+		Loader:     GRUB,
+	})
+	if err != nil {
+		t.Fatalf("NewIncrementalVerifier() failed: %v", err)
+	}
+
+	coscel := &cel.CEL{}
+	appendCOSEvent := func(eventType cel.CosType, content string) {
+		event := cel.CosTlv{EventType: eventType, EventContent: []byte(content)}
+		if err := coscel.AppendEventPCR(rwc, cel.CosEventPCR, event); err != nil {
+			t.Fatalf("failed to append COS event: %v", err)
+		}
+	}
+	// The digests of the test event log are extended into PCR16 as well.
+	appendTCGEvent := func(data string) []byte {
+		event := binary.LittleEndian.AppendUint32(nil, uint32(test.DebugPCR))
+		event = binary.LittleEndian.AppendUint32(event, EventTag)
+		algs := []tpm2.Algorithm{tpm2.AlgSHA1, tpm2.AlgSHA256, tpm2.AlgSHA384}
+		event = binary.LittleEndian.AppendUint32(event, uint32(len(algs)))
+		for _, alg := range algs {
+			hash, err := alg.Hash()
+			if err != nil {
+				t.Fatal(err)
+			}
+			hasher := hash.New()
+			hasher.Write([]byte(data))
+			digest := hasher.Sum(nil)
+			if err := tpm2.PCRExtend(rwc, tpmutil.Handle(test.DebugPCR), alg, digest, ""); err != nil {
+				t.Fatalf("failed to extend PCR%d: %v", test.DebugPCR, err)
+			}
+			event = binary.LittleEndian.AppendUint16(event, uint16(alg))
+			event = append(event, digest...)
+		}
+		event = binary.LittleEndian.AppendUint32(event, uint32(len(data)))
+		return append(event, data...)
+	}
+	attestAndVerify := func(nonce string, tcgEvents []byte, records []cel.Record) (*attestpb.MachineState, error) {
+		var buf bytes.Buffer
+		appended := cel.CEL{Records: records}
+		if err := appended.EncodeCEL(&buf); err != nil {
+			t.Fatal(err)
+		}
+		attestation, err := ak.Attest(client.AttestOpts{
+			Nonce:             []byte(nonce),
+			TCGEventLog:       tcgEvents,
+			CanonicalEventLog: buf.Bytes(),
+		})
+		if err != nil {
+			t.Fatalf("failed to attest: %v", err)
+		}
+		return verifier.Verify(attestation, []byte(nonce))
+	}
+
+	// The first attestation has the full logs.
+	appendCOSEvent(cel.ImageRefType, "docker.io/library/hello-world:latest")
+	state, err := attestAndVerify("nonce 1", nil, coscel.Records)
+	if err != nil {
+		t.Fatalf("failed to verify full logs: %v", err)
+	}
+	if got := state.GetCos().GetContainer().GetImageReference(); got != "docker.io/library/hello-world:latest" {
+		t.Errorf("got image reference %q, want docker.io/library/hello-world:latest", got)
+	}
+	numEvents := len(state.GetRawEvents())
+	if len(state.GetGrub().GetFiles()) == 0 {
+		t.Error("MachineState is missing GRUB state")
+	}
+
+	// Later attestations only have the appended events.
+	verifiedRecords := len(coscel.Records)
+	appendCOSEvent(cel.EnvVarType, "foo=bar")
+	tcgEvents := appendTCGEvent("incremental test event")
+	state, err = attestAndVerify("nonce 2", tcgEvents, coscel.Records[verifiedRecords:])
+	if err != nil {
+		t.Fatalf("failed to verify appended events: %v", err)
+	}
+	if got := state.GetCos().GetContainer().GetEnvVars()["foo"]; got != "bar" {
+		t.Errorf("got environment variable foo=%q, want foo=bar", got)
+	}
+	if got := state.GetCos().GetContainer().GetImageReference(); got != "docker.io/library/hello-world:latest" {
+		t.Errorf("got image reference %q after appending events, want docker.io/library/hello-world:latest", got)
+	}
+	if got := len(state.GetRawEvents()); got != numEvents+1 {
+		t.Errorf("got %d TCG events, want %d", got, numEvents+1)
+	}
+	if len(state.GetGrub().GetFiles()) == 0 {
+		t.Error("MachineState is missing GRUB state after appending events")
+	}
+
+	if _, err := attestAndVerify("nonce 3", []byte{}, nil); err != nil {
+		t.Errorf("failed to verify with no appended events: %v", err)
+	}
+	if _, err := attestAndVerify("nonce 4", []byte{}, coscel.Records[verifiedRecords:]); err == nil {
+		t.Error("verification succeeded with already verified records, expected failure")
+	}
+
+	// Extending a PCR without logging it causes every later attestation to
+	// fail, even with the full logs.
+	if err := tpm2.PCRExtend(rwc, tpmutil.Handle(cel.CosEventPCR), tpm2.AlgSHA256, make([]byte, crypto.SHA256.Size()), ""); err != nil {
+		t.Fatalf("failed to extend PCR%d: %v", cel.CosEventPCR, err)
+	}
+	if _, err := attestAndVerify("nonce 5", []byte{}, nil); err == nil {
+		t.Error("verification succeeded after an unlogged PCR extension, expected failure")
+	}
+	if err := verifier.Forget(ak.PublicKey()); err != nil {
+		t.Fatalf("Forget() failed: %v", err)
+	}
+	if _, err := attestAndVerify("nonce 6", nil, coscel.Records); err == nil {
+		t.Error("verification of full logs succeeded after an unlogged PCR extension, expected failure")
+	}
+}
+
+func TestIncrementalVerifierRejectsIMA(t *testing.T) {
+	verifier, err := NewIncrementalVerifier(VerifyOpts{TrustedAKs: []crypto.PublicKey{nil}})
+	if err != nil {
+		t.Fatalf("NewIncrementalVerifier() failed: %v", err)
+	}
+	attestation := &attestpb.Attestation{ImaMeasurementList: []byte("10 00 ima-ng sha256:00 /bin/ls\n")}
+	if _, err := verifier.Verify(attestation, []byte("nonce")); err == nil {
+		t.Error("Verify() succeeded with an IMA measurement list, expected failure")
+	}
+}
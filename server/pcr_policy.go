@@ -0,0 +1,58 @@
+package server
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/google/go-tpm-tools/client"
+	"github.com/google/go-tpm-tools/internal"
+	pb "github.com/google/go-tpm-tools/proto/tpm"
+	"github.com/google/go-tpm/legacy/tpm2"
+)
+
+// SignPCRPolicy approves a set of PCR values for unsealing data sealed with
+// client.SealOpts.PolicySigner set to the signer's public key. The returned
+// policy is passed to the client Key.Unseal() method in
+// client.UnsealOpts.SignedPolicy.
+// When PCR values change (such as after a firmware or kernel upgrade), a new
+// policy can be signed for the new values instead of resealing the data.
+// The signer must be an RSA (PKCS #1 v1.5) or ECDSA key, and signs a SHA256
+// digest of the PCR policy.
+func SignPCRPolicy(signer crypto.Signer, pcrs *pb.PCRs) (*pb.SignedPCRPolicy, error) {
+	if len(pcrs.GetPcrs()) == 0 {
+		return nil, errors.New("PCR policy has no PCRs")
+	}
+	approvedPolicy := internal.PCRSessionAuth(pcrs, client.SessionHashAlg)
+	digest := internal.AuthorizedPolicyDigest(approvedPolicy, client.SessionHashAlg)
+	rawSig, err := signer.Sign(rand.Reader, digest, client.SessionHashAlg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign PCR policy: %w", err)
+	}
+
+	var sig tpm2.Signature
+	switch signer.Public().(type) {
+	case *rsa.PublicKey:
+		sig.Alg = tpm2.AlgRSASSA
+		sig.RSA = &tpm2.SignatureRSA{HashAlg: client.SessionHashAlgTpm, Signature: rawSig}
+	case *ecdsa.PublicKey:
+		var ecdsaSig struct{ R, S *big.Int }
+		if _, err := asn1.Unmarshal(rawSig, &ecdsaSig); err != nil {
+			return nil, fmt.Errorf("failed to parse ECDSA signature: %w", err)
+		}
+		sig.Alg = tpm2.AlgECDSA
+		sig.ECC = &tpm2.SignatureECC{HashAlg: client.SessionHashAlgTpm, R: ecdsaSig.R, S: ecdsaSig.S}
+	default:
+		return nil, fmt.Errorf("unsupported policy signer key type: %T", signer.Public())
+	}
+	encodedSig, err := sig.Encode()
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode PCR policy signature: %w", err)
+	}
+	return &pb.SignedPCRPolicy{Pcrs: pcrs, RawSig: encodedSig}, nil
+}
@@ -0,0 +1,132 @@
+package server
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+
+	"github.com/google/go-tpm-tools/client"
+	"github.com/google/go-tpm-tools/internal/test"
+	"github.com/google/go-tpm/legacy/tpm2"
+	"github.com/google/go-tpm/tpmutil"
+)
+
+func TestSealWithSignedPCRPolicy(t *testing.T) {
+	rwc := test.GetTPM(t)
+	defer client.CheckedClose(t, rwc)
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	eccKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signers := []struct {
+		name   string
+		signer crypto.Signer
+	}{
+		{"RSA", rsaKey},
+		{"ECC", eccKey},
+	}
+	sel := tpm2.PCRSelection{Hash: tpm2.AlgSHA256, PCRs: []int{7, test.DebugPCR}}
+	for _, s := range signers {
+		t.Run(s.name, func(t *testing.T) {
+			srk, err := client.StorageRootKeyRSA(rwc)
+			if err != nil {
+				t.Fatalf("failed to create SRK: %v", err)
+			}
+			defer srk.Close()
+
+			secret := []byte("test")
+			sealed, err := srk.Seal(secret, client.SealOpts{PolicySigner: s.signer.Public()})
+			if err != nil {
+				t.Fatalf("failed to seal: %v", err)
+			}
+			unseal := func(signer crypto.Signer) ([]byte, error) {
+				pcrs, err := client.ReadPCRs(rwc, sel)
+				if err != nil {
+					t.Fatalf("failed to read PCRs: %v", err)
+				}
+				policy, err := SignPCRPolicy(signer, pcrs)
+				if err != nil {
+					t.Fatalf("failed to sign PCR policy: %v", err)
+				}
+				return srk.Unseal(sealed, client.UnsealOpts{SignedPolicy: policy})
+			}
+
+			unsealed, err := unseal(s.signer)
+			if err != nil {
+				t.Fatalf("failed to unseal: %v", err)
+			}
+			if !bytes.Equal(unsealed, secret) {
+				t.Errorf("unsealed (%v) not equal to secret (%v)", unsealed, secret)
+			}
+			if _, err := unseal(otherKey); err == nil {
+				t.Error("unsealing with another signer's policy succeeded, expected failure")
+			}
+
+			// A policy for the old PCR values no longer unseals the data,
+			// but a policy for the new values does.
+			pcrs, err := client.ReadPCRs(rwc, sel)
+			if err != nil {
+				t.Fatalf("failed to read PCRs: %v", err)
+			}
+			oldPolicy, err := SignPCRPolicy(s.signer, pcrs)
+			if err != nil {
+				t.Fatalf("failed to sign PCR policy: %v", err)
+			}
+			extension := bytes.Repeat([]byte{0xAA}, crypto.SHA256.Size())
+			if err := tpm2.PCRExtend(rwc, tpmutil.Handle(test.DebugPCR), tpm2.AlgSHA256, extension, ""); err != nil {
+				t.Fatalf("failed to extend PCR: %v", err)
+			}
+			if _, err := srk.Unseal(sealed, client.UnsealOpts{SignedPolicy: oldPolicy}); err == nil {
+				t.Error("unsealing with a policy for old PCR values succeeded, expected failure")
+			}
+			if unsealed, err = unseal(s.signer); err != nil {
+				t.Fatalf("failed to unseal with a policy for new PCR values: %v", err)
+			}
+			if !bytes.Equal(unsealed, secret) {
+				t.Errorf("unsealed (%v) not equal to secret (%v)", unsealed, secret)
+			}
+
+			if _, err := srk.Unseal(sealed, client.UnsealOpts{}); err == nil {
+				t.Error("unsealing without a signed policy succeeded, expected failure")
+			}
+		})
+	}
+}
+
+func TestSealWithPolicySignerFail(t *testing.T) {
+	rwc := test.GetTPM(t)
+	defer client.CheckedClose(t, rwc)
+	srk, err := client.StorageRootKeyRSA(rwc)
+	if err != nil {
+		t.Fatalf("failed to create SRK: %v", err)
+	}
+	defer srk.Close()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	opts := client.SealOpts{
+		Current:      tpm2.PCRSelection{Hash: tpm2.AlgSHA256, PCRs: []int{7}},
+		PolicySigner: key.Public(),
+	}
+	if _, err := srk.Seal([]byte("test"), opts); err == nil {
+		t.Error("sealing with both Current and PolicySigner succeeded, expected failure")
+	}
+	if _, err := SignPCRPolicy(key, nil); err == nil {
+		t.Error("signing a policy with no PCRs succeeded, expected failure")
+	}
+}
@@ -2,23 +2,67 @@ package server
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
+	epb "github.com/google/gce-tcb-verifier/proto/endorsement"
+	tcbv "github.com/google/gce-tcb-verifier/verify"
+	"github.com/google/go-sev-guest/proto/sevsnp"
 	"github.com/google/go-sev-guest/verify/trust"
+	"github.com/google/go-tdx-guest/proto/tdx"
 	pb "github.com/google/go-tpm-tools/proto/attest"
+	"google.golang.org/protobuf/proto"
 )
 
+const (
+	// teeTcbSvnSize is the size of the TEE_TCB_SVN field of a TDX quote.
+	teeTcbSvnSize = 16
+	// tdAttributesDebugBit is the TUD.DEBUG bit in the first byte of a TDX
+	// quote's TD_ATTRIBUTES.
+	tdAttributesDebugBit = 0x01
+)
+
+// sevSnpUefiEndorsementURL is the location of the signed UEFI reference
+// measurements for a hex-encoded SEV-SNP launch measurement.
+var sevSnpUefiEndorsementURL = "https://storage.googleapis.com/gce_tcb_integrity/ovmf_x64_csm/sevsnp/%s.binarypb"
+
 // EvaluatePolicy succeeds if the provided MachineState complies with the
 // provided policy. If the state does not pass the policy, the returned error
 // will describe in what way the state failed. See the Policy documentation for
 // more information about the specifics of different policies.
+//
+// All parts of the policy are evaluated, so the returned error will be a
+// *GroupedError listing every violated rule, rather than just the first.
+//
+// EvaluatePolicy uses DefaultPolicyOptions, so it may download reference
+// materials when the policy requires them. Use EvaluatePolicyWithOptions to
+// control this behavior.
 func EvaluatePolicy(state *pb.MachineState, policy *pb.Policy) error {
-	if err := evaluatePlatformPolicy(state.GetPlatform(), policy.GetPlatform()); err != nil {
-		return err
+	return EvaluatePolicyWithOptions(state, policy, DefaultPolicyOptions())
+}
+
+// EvaluatePolicyWithOptions is like EvaluatePolicy, but allows customizing how
+// reference materials are fetched and the time used when checking time-based
+// constraints. A nil opts is equivalent to DefaultPolicyOptions().
+func EvaluatePolicyWithOptions(state *pb.MachineState, policy *pb.Policy, opts *PolicyOptions) error {
+	if opts == nil {
+		opts = DefaultPolicyOptions()
 	}
-	return nil
+	var errs []error
+	errs = append(errs, evaluatePlatformPolicy(state.GetPlatform(), policy.GetPlatform())...)
+	errs = append(errs, evaluateSecureBootPolicy(state.GetSecureBoot(), policy.GetSecureBoot())...)
+	errs = append(errs, evaluateBootloaderPolicy(state, policy.GetBootloader())...)
+	errs = append(errs, evaluateShimPolicy(state.GetShim(), policy.GetShim())...)
+	errs = append(errs, evaluateKernelPolicy(state.GetLinuxKernel(), policy.GetKernel())...)
+	errs = append(errs, evaluateContainerPolicy(state.GetCos(), policy.GetContainer())...)
+	errs = append(errs, evaluateSevSnpPolicy(state.GetSevSnpAttestation(), policy.GetSevSnp(), opts)...)
+	errs = append(errs, evaluateTdxPolicy(state.GetTdxAttestation(), policy.GetTdx())...)
+	return createGroupedError("MachineState does not comply with policy:", errs)
 }
 
 // PolicyOptions provides extra options for evaluating policy.
@@ -37,25 +81,26 @@ func DefaultPolicyOptions() *PolicyOptions {
 	}
 }
 
-func evaluatePlatformPolicy(state *pb.PlatformState, policy *pb.PlatformPolicy) error {
+func evaluatePlatformPolicy(state *pb.PlatformState, policy *pb.PlatformPolicy) []error {
+	var errs []error
 	allowedVersions := policy.GetAllowedScrtmVersionIds()
 	if len(allowedVersions) > 0 {
 		if err := hasAllowedVersion(state, allowedVersions); err != nil {
-			return err
+			errs = append(errs, err)
 		}
 	}
 
 	minGceVersion := policy.GetMinimumGceFirmwareVersion()
 	gceVersion := state.GetGceVersion()
 	if minGceVersion > gceVersion {
-		return fmt.Errorf("expected GCE Version %d or later, got %d", minGceVersion, gceVersion)
+		errs = append(errs, fmt.Errorf("expected GCE Version %d or later, got %d", minGceVersion, gceVersion))
 	}
 	minTech := policy.GetMinimumTechnology()
 	tech := state.GetTechnology()
 	if minTech > tech {
-		return fmt.Errorf("expected a GCE Confidential Technology of %d or later, got %d", minTech, tech)
+		errs = append(errs, fmt.Errorf("expected a GCE Confidential Technology of %d or later, got %d", minTech, tech))
 	}
-	return nil
+	return errs
 }
 
 func hasAllowedVersion(state *pb.PlatformState, allowedVersions [][]byte) error {
@@ -77,3 +122,417 @@ func hasAllowedVersion(state *pb.PlatformState, allowedVersions [][]byte) error
 	}
 	return fmt.Errorf("provided SCRTM version (%x) not allowed", version)
 }
+
+func evaluateSecureBootPolicy(state *pb.SecureBootState, policy *pb.SecureBootPolicy) []error {
+	if !policy.GetRequireEnabled() &&
+		len(policy.GetRequiredDbx().GetCerts()) == 0 &&
+		len(policy.GetRequiredDbx().GetHashes()) == 0 &&
+		len(policy.GetForbiddenDb().GetCerts()) == 0 &&
+		len(policy.GetForbiddenDb().GetHashes()) == 0 &&
+		len(policy.GetAllowedAuthority().GetCerts()) == 0 &&
+		len(policy.GetAllowedPk().GetCerts()) == 0 {
+		return nil
+	}
+	if state == nil {
+		return []error{errors.New("policy restricts Secure Boot, but MachineState has no SecureBootState")}
+	}
+
+	var errs []error
+	if policy.GetRequireEnabled() && !state.GetEnabled() {
+		errs = append(errs, errors.New("expected Secure Boot to be enabled"))
+	}
+
+	dbx := state.GetDbx()
+	for _, cert := range policy.GetRequiredDbx().GetCerts() {
+		if !containsCert(dbx.GetCerts(), cert) {
+			errs = append(errs, fmt.Errorf("required dbx certificate (%s) is not revoked", describeCert(cert)))
+		}
+	}
+	for _, hash := range policy.GetRequiredDbx().GetHashes() {
+		if !contains(dbx.GetHashes(), hash) {
+			errs = append(errs, fmt.Errorf("required dbx hash (%x) is not revoked", hash))
+		}
+	}
+
+	db := state.GetDb()
+	for _, cert := range policy.GetForbiddenDb().GetCerts() {
+		if containsCert(db.GetCerts(), cert) {
+			errs = append(errs, fmt.Errorf("forbidden db certificate (%s) is trusted", describeCert(cert)))
+		}
+	}
+	for _, hash := range policy.GetForbiddenDb().GetHashes() {
+		if contains(db.GetHashes(), hash) {
+			errs = append(errs, fmt.Errorf("forbidden db hash (%x) is trusted", hash))
+		}
+	}
+
+	if allowed := policy.GetAllowedAuthority().GetCerts(); len(allowed) > 0 {
+		for _, cert := range state.GetAuthority().GetCerts() {
+			if !containsCert(allowed, cert) {
+				errs = append(errs, fmt.Errorf("authority certificate (%s) not allowed", describeCert(cert)))
+			}
+		}
+	}
+	if allowed := policy.GetAllowedPk().GetCerts(); len(allowed) > 0 {
+		for _, cert := range state.GetPk().GetCerts() {
+			if !containsCert(allowed, cert) {
+				errs = append(errs, fmt.Errorf("platform key certificate (%s) not allowed", describeCert(cert)))
+			}
+		}
+	}
+	return errs
+}
+
+func evaluateBootloaderPolicy(state *pb.MachineState, policy *pb.BootloaderPolicy) []error {
+	var errs []error
+	if allowed := policy.GetAllowedEfiAppDigests(); len(allowed) > 0 {
+		if state.GetEfi() == nil {
+			errs = append(errs, errors.New("policy restricts EFI applications, but MachineState has no EfiState"))
+		}
+		for _, app := range state.GetEfi().GetApps() {
+			if !contains(allowed, app.GetDigest()) {
+				errs = append(errs, fmt.Errorf("EFI application digest (%x) not allowed", app.GetDigest()))
+			}
+		}
+	}
+	if allowed := policy.GetAllowedGrubFileDigests(); len(allowed) > 0 {
+		if state.GetGrub() == nil {
+			errs = append(errs, errors.New("policy restricts GRUB files, but MachineState has no GrubState"))
+		}
+		for _, file := range state.GetGrub().GetFiles() {
+			if !contains(allowed, file.GetDigest()) {
+				errs = append(errs, fmt.Errorf("GRUB file %q digest (%x) not allowed", file.GetUntrustedFilename(), file.GetDigest()))
+			}
+		}
+	}
+	return errs
+}
+
+func evaluateShimPolicy(state *pb.ShimState, policy *pb.ShimPolicy) []error {
+	var errs []error
+	if state == nil && (policy.GetRequireValidation() || policy.GetForbidMokListTrusted()) {
+		errs = append(errs, errors.New("policy constrains shim validation, but MachineState has no ShimState"))
+	}
+	if policy.GetRequireValidation() && state.GetValidationDisabled() {
+		errs = append(errs, errors.New("expected shim validation to be enabled"))
+	}
+	if policy.GetForbidMokAuthority() {
+		for _, cert := range state.GetMokAuthority().GetCerts() {
+			errs = append(errs, fmt.Errorf("MOK authority certificate (%s) not allowed", describeCert(cert)))
+		}
+		for _, hash := range state.GetMokAuthority().GetHashes() {
+			errs = append(errs, fmt.Errorf("MOK authority hash (%x) not allowed", hash))
+		}
+	}
+	if policy.GetForbidMokListTrusted() && state.GetMokListTrusted() {
+		errs = append(errs, errors.New("expected the MOK list to not be trusted by the kernel"))
+	}
+	if allowed := policy.GetAllowedMokListDigests(); len(allowed) > 0 {
+		if state == nil {
+			errs = append(errs, errors.New("policy restricts the MOK list, but MachineState has no ShimState"))
+		} else if !contains(allowed, state.GetMokListDigest()) {
+			errs = append(errs, fmt.Errorf("MOK list digest (%x) not allowed", state.GetMokListDigest()))
+		}
+	}
+	return errs
+}
+
+func evaluateKernelPolicy(state *pb.LinuxKernelState, policy *pb.KernelPolicy) []error {
+	if len(policy.GetAllowedCommandLines()) == 0 &&
+		len(policy.GetRequiredCommandLineArgs()) == 0 &&
+		len(policy.GetForbiddenCommandLineArgs()) == 0 {
+		return nil
+	}
+	if state == nil {
+		return []error{errors.New("policy restricts the kernel command line, but MachineState has no LinuxKernelState")}
+	}
+
+	var errs []error
+	cmdline := state.GetCommandLine()
+	if allowed := policy.GetAllowedCommandLines(); len(allowed) > 0 && !containsString(allowed, cmdline) {
+		errs = append(errs, fmt.Errorf("kernel command line (%q) not allowed", cmdline))
+	}
+	args := strings.Fields(cmdline)
+	for _, required := range policy.GetRequiredCommandLineArgs() {
+		if !containsString(args, required) {
+			errs = append(errs, fmt.Errorf("kernel command line is missing required argument %q", required))
+		}
+	}
+	for _, forbidden := range policy.GetForbiddenCommandLineArgs() {
+		for _, arg := range args {
+			if arg == forbidden || (!strings.Contains(forbidden, "=") && strings.HasPrefix(arg, forbidden+"=")) {
+				errs = append(errs, fmt.Errorf("kernel command line contains forbidden argument %q", arg))
+			}
+		}
+	}
+	return errs
+}
+
+func evaluateContainerPolicy(state *pb.AttestedCosState, policy *pb.ContainerPolicy) []error {
+	if policy == nil {
+		return nil
+	}
+	if state == nil {
+		return []error{errors.New("policy restricts the container, but MachineState has no AttestedCosState")}
+	}
+
+	var errs []error
+	container := state.GetContainer()
+	if allowed := policy.GetAllowedImageDigests(); len(allowed) > 0 && !containsString(allowed, container.GetImageDigest()) {
+		errs = append(errs, fmt.Errorf("container image digest (%q) not allowed", container.GetImageDigest()))
+	}
+	if allowed := policy.GetAllowedImageReferences(); len(allowed) > 0 && !containsString(allowed, container.GetImageReference()) {
+		errs = append(errs, fmt.Errorf("container image reference (%q) not allowed", container.GetImageReference()))
+	}
+	if allowed := policy.GetAllowedRestartPolicies(); len(allowed) > 0 {
+		found := false
+		for _, restartPolicy := range allowed {
+			if restartPolicy == container.GetRestartPolicy() {
+				found = true
+				break
+			}
+		}
+		if !found {
+			errs = append(errs, fmt.Errorf("container restart policy %v not allowed", container.GetRestartPolicy()))
+		}
+	}
+	if policy.GetForbidOverrides() {
+		if len(container.GetOverriddenArgs()) > 0 {
+			errs = append(errs, fmt.Errorf("container arguments were overridden: %q", container.GetOverriddenArgs()))
+		}
+		if len(container.GetOverriddenEnvVars()) > 0 {
+			errs = append(errs, fmt.Errorf("%d container environment variables were overridden", len(container.GetOverriddenEnvVars())))
+		}
+	}
+	if minVersion := policy.GetMinimumCosVersion(); minVersion != nil && compareSemanticVersions(state.GetCosVersion(), minVersion) < 0 {
+		errs = append(errs, fmt.Errorf("expected COS version %s or later, got %s",
+			formatSemanticVersion(minVersion), formatSemanticVersion(state.GetCosVersion())))
+	}
+	if minVersion := policy.GetMinimumLauncherVersion(); minVersion != nil && compareSemanticVersions(state.GetLauncherVersion(), minVersion) < 0 {
+		errs = append(errs, fmt.Errorf("expected launcher version %s or later, got %s",
+			formatSemanticVersion(minVersion), formatSemanticVersion(state.GetLauncherVersion())))
+	}
+	if policy.GetRequireMemoryMonitoring() && !state.GetHealthMonitoring().GetMemoryEnabled() {
+		errs = append(errs, errors.New("expected memory monitoring to be enabled"))
+	}
+	if allowed := policy.GetAllowedGpuCcModes(); len(allowed) > 0 {
+		ccMode := state.GetGpuDeviceState().GetCcMode()
+		found := false
+		for _, mode := range allowed {
+			if mode == ccMode {
+				found = true
+				break
+			}
+		}
+		if !found {
+			errs = append(errs, fmt.Errorf("GPU CC mode %v not allowed", ccMode))
+		}
+	}
+	return errs
+}
+
+func evaluateSevSnpPolicy(attestation *sevsnp.Attestation, policy *pb.SevSnpPolicy, opts *PolicyOptions) []error {
+	uefiPolicy := policy.GetUefi()
+	if uefiPolicy == nil {
+		return nil
+	}
+	if attestation == nil {
+		return []error{errors.New("policy constrains SEV-SNP, but MachineState has no SEV-SNP attestation")}
+	}
+	if err := evaluateUefiRIMPolicy(attestation.GetReport().GetMeasurement(), uefiPolicy, opts); err != nil {
+		return []error{err}
+	}
+	return nil
+}
+
+// evaluateUefiRIMPolicy checks an SEV-SNP launch measurement against the
+// signed UEFI reference measurements published for it. The reference
+// measurements are fetched using opts.Getter and must chain to one of the
+// policy's root certificates.
+func evaluateUefiRIMPolicy(measurement []byte, policy *pb.RIMPolicy, opts *PolicyOptions) error {
+	roots, err := parseRIMRootCerts(policy.GetRootCerts())
+	if err != nil {
+		return err
+	}
+	getter := opts.Getter
+	if getter == nil {
+		getter = trust.DefaultHTTPSGetter()
+	}
+	url := fmt.Sprintf(sevSnpUefiEndorsementURL, hex.EncodeToString(measurement))
+	rawEndorsement, err := getter.Get(url)
+	if err != nil {
+		if policy.GetRequireSigned() {
+			return fmt.Errorf("failed to get signed UEFI reference measurements from %s: %w", url, err)
+		}
+		// The signed measurements are unavailable, but not required.
+		return nil
+	}
+
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	if err := tcbv.Endorsement(rawEndorsement, &tcbv.Options{RootsOfTrust: roots, Now: now}); err != nil {
+		return fmt.Errorf("failed to verify UEFI launch endorsement: %w", err)
+	}
+	endorsement := &epb.VMLaunchEndorsement{}
+	if err := proto.Unmarshal(rawEndorsement, endorsement); err != nil {
+		return fmt.Errorf("failed to unmarshal UEFI launch endorsement: %w", err)
+	}
+	golden := &epb.VMGoldenMeasurement{}
+	if err := proto.Unmarshal(endorsement.GetSerializedUefiGolden(), golden); err != nil {
+		return fmt.Errorf("failed to unmarshal UEFI golden measurement: %w", err)
+	}
+	for _, signed := range golden.GetSevSnp().GetMeasurements() {
+		if bytes.Equal(signed, measurement) {
+			return nil
+		}
+	}
+	return fmt.Errorf("SEV-SNP launch measurement (%x) is not among the signed UEFI reference measurements", measurement)
+}
+
+func parseRIMRootCerts(rawCerts [][]byte) (*x509.CertPool, error) {
+	if len(rawCerts) == 0 {
+		return nil, errors.New("RIMPolicy must contain at least one root certificate")
+	}
+	certs, err := parseCerts(rawCerts)
+	if err != nil {
+		return nil, fmt.Errorf("RIMPolicy root certificates: %w", err)
+	}
+	return makePool(certs), nil
+}
+
+func evaluateTdxPolicy(quote *tdx.QuoteV4, policy *pb.TdxPolicy) []error {
+	if policy == nil {
+		return nil
+	}
+	if quote == nil {
+		return []error{errors.New("policy constrains TDX, but MachineState has no TDX attestation")}
+	}
+
+	var errs []error
+	body := quote.GetTdQuoteBody()
+	if allowed := policy.GetAllowedMrtds(); len(allowed) > 0 && !contains(allowed, body.GetMrTd()) {
+		errs = append(errs, fmt.Errorf("MRTD (%x) not allowed", body.GetMrTd()))
+	}
+	rtmrs := body.GetRtmrs()
+	for i, expected := range policy.GetExpectedRtmrs() {
+		if len(expected) == 0 {
+			continue
+		}
+		if i >= len(rtmrs) {
+			errs = append(errs, fmt.Errorf("expected RTMR[%d] to be %x, but the quote has no RTMR[%d]", i, expected, i))
+			continue
+		}
+		if !bytes.Equal(rtmrs[i], expected) {
+			errs = append(errs, fmt.Errorf("expected RTMR[%d] to be %x, got %x", i, expected, rtmrs[i]))
+		}
+	}
+	if minSvn := policy.GetMinimumTeeTcbSvn(); len(minSvn) > 0 {
+		if err := checkTeeTcbSvn(body.GetTeeTcbSvn(), minSvn); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if policy.GetForbidDebug() {
+		attributes := body.GetTdAttributes()
+		if len(attributes) == 0 || attributes[0]&tdAttributesDebugBit != 0 {
+			errs = append(errs, fmt.Errorf("TD attributes (%x) have the DEBUG bit set", attributes))
+		}
+	}
+	if allowed := policy.GetAllowedMrSignerSeams(); len(allowed) > 0 && !contains(allowed, body.GetMrSignerSeam()) {
+		errs = append(errs, fmt.Errorf("MRSIGNERSEAM (%x) not allowed", body.GetMrSignerSeam()))
+	}
+	return errs
+}
+
+// checkTeeTcbSvn checks that each component of the TEE_TCB_SVN is at least the
+// corresponding component of minSvn.
+func checkTeeTcbSvn(svn []byte, minSvn []byte) error {
+	if len(minSvn) != teeTcbSvnSize {
+		return fmt.Errorf("policy minimum TEE_TCB_SVN must be %d bytes, got %d", teeTcbSvnSize, len(minSvn))
+	}
+	if len(svn) != teeTcbSvnSize {
+		return fmt.Errorf("quote TEE_TCB_SVN must be %d bytes, got %d", teeTcbSvnSize, len(svn))
+	}
+	for i := range minSvn {
+		if svn[i] < minSvn[i] {
+			return fmt.Errorf("expected TEE_TCB_SVN %x or later, got %x", minSvn, svn)
+		}
+	}
+	return nil
+}
+
+// certificateKey returns a comparable key for a Certificate. DER-encoded
+// certificates matching a WellKnownCertificate map to the same key as the
+// corresponding enum value.
+func certificateKey(cert *pb.Certificate) string {
+	switch rep := cert.GetRepresentation().(type) {
+	case *pb.Certificate_WellKnown:
+		return rep.WellKnown.String()
+	case *pb.Certificate_Der:
+		if wellKnown, err := matchWellKnown(x509.Certificate{Raw: rep.Der}); err == nil {
+			return wellKnown.String()
+		}
+		return string(rep.Der)
+	}
+	return ""
+}
+
+func containsCert(set []*pb.Certificate, cert *pb.Certificate) bool {
+	key := certificateKey(cert)
+	for _, setItem := range set {
+		if certificateKey(setItem) == key {
+			return true
+		}
+	}
+	return false
+}
+
+// describeCert returns a short, human-readable description of a Certificate
+// for use in error messages.
+func describeCert(cert *pb.Certificate) string {
+	switch rep := cert.GetRepresentation().(type) {
+	case *pb.Certificate_WellKnown:
+		return rep.WellKnown.String()
+	case *pb.Certificate_Der:
+		if wellKnown, err := matchWellKnown(x509.Certificate{Raw: rep.Der}); err == nil {
+			return wellKnown.String()
+		}
+		if parsed, err := x509.ParseCertificate(rep.Der); err == nil {
+			return parsed.Subject.String()
+		}
+		return fmt.Sprintf("unparsable certificate with SHA-256 digest %x", sha256.Sum256(rep.Der))
+	}
+	return "empty certificate"
+}
+
+func containsString(set []string, value string) bool {
+	for _, setItem := range set {
+		if setItem == value {
+			return true
+		}
+	}
+	return false
+}
+
+// compareSemanticVersions returns -1, 0, or 1 if a is less than, equal to,
+// or greater than b, respectively.
+func compareSemanticVersions(a, b *pb.SemanticVersion) int {
+	for _, pair := range [][2]uint32{
+		{a.GetMajor(), b.GetMajor()},
+		{a.GetMinor(), b.GetMinor()},
+		{a.GetPatch(), b.GetPatch()},
+	} {
+		if pair[0] < pair[1] {
+			return -1
+		}
+		if pair[0] > pair[1] {
+			return 1
+		}
+	}
+	return 0
+}
+
+func formatSemanticVersion(v *pb.SemanticVersion) string {
+	return fmt.Sprintf("%d.%d.%d", v.GetMajor(), v.GetMinor(), v.GetPatch())
+}
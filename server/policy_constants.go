@@ -24,12 +24,25 @@ const (
 	NonhostInfo                uint32 = 0x00000011
 	EFIBootServicesApplication uint32 = 0x80000003
 	EFIAction                  uint32 = 0x80000007
+	EFIVariableAuthority       uint32 = 0x800000E0
 )
 
 // EventTagLoadedImageHex used with type "EV_EVENT_TAG".
 // This corresponds to a TLV struct of type LOAD_OPTIONS_EVENT_TAG_ID (0x8F3B22ED, reversed endian), length 0x1a (26), value `LOADED_IMAGE::LoadOptions\n`.
 const EventTagLoadedImageHex = "ed223b8f1a0000004c4f414445445f494d4147453a3a4c6f61644f7074696f6e7300"
 
+// Tagged event IDs used by the Linux EFI stub for "EV_EVENT_TAG" events in PCR9.
+// See drivers/firmware/efi/libstub/efi-stub-helper.c in the Linux kernel.
+const (
+	linuxInitrdEventTagID      uint32 = 0x8F3B22EC
+	linuxLoadOptionsEventTagID uint32 = 0x8F3B22ED
+)
+
+// shimLockGUID is the vendor GUID (605dab50-e046-4300-abb6-3dd810dd8b23) of the
+// variables measured by shim, in its little-endian EFI_GUID encoding.
+var shimLockGUID = []byte{0x50, 0xab, 0x5d, 0x60, 0x46, 0xe0, 0x00, 0x43,
+	0xab, 0xb6, 0x3d, 0xd8, 0x10, 0xdd, 0x8b, 0x23}
+
 // Constant events used with type "EV_EFI_ACTION".
 // Taken from TCG PC Client Platform Firmware Profile Specification,
 // Table 17 EV_EFI_ACTION Strings.
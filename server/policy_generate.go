@@ -0,0 +1,338 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+
+	pb "github.com/google/go-tpm-tools/proto/attest"
+)
+
+// MergeStrategy determines how values from multiple golden MachineStates are
+// combined into a policy's allow-lists.
+type MergeStrategy int
+
+const (
+	// MergeUnion allows any value seen in at least one golden MachineState, so
+	// every golden MachineState complies with the generated policy.
+	MergeUnion MergeStrategy = iota
+	// MergeIntersection only allows values seen in every golden MachineState.
+	// This is useful for detecting drift in a fleet that should be uniform.
+	MergeIntersection
+)
+
+// PolicyGenerationOptions controls which parts of a MachineState are pinned by
+// PolicyFromMachineState. The zero value pins everything and merges using
+// MergeUnion.
+type PolicyGenerationOptions struct {
+	// SkipFirmware leaves the firmware version and confidential technology
+	// unconstrained.
+	SkipFirmware bool
+	// SkipSecureBoot leaves the Secure Boot state and databases, and the shim
+	// MOK state, unconstrained.
+	SkipSecureBoot bool
+	// SkipBootloader leaves the EFI application and GRUB file digests
+	// unconstrained.
+	SkipBootloader bool
+	// SkipKernel leaves the kernel command line unconstrained.
+	SkipKernel bool
+	// SkipContainer leaves the container image unconstrained.
+	SkipContainer bool
+	// Merge determines how allow-lists from multiple golden states are
+	// combined.
+	Merge MergeStrategy
+}
+
+// PolicyFromMachineState generates a minimal Policy from one or more trusted
+// ("golden") MachineStates, such as those from a newly rolled out image. The
+// policy pins:
+//   - the SCRTM/GCE firmware version and the confidential technology
+//   - whether Secure Boot is enabled, the revoked dbx entries, and the trusted
+//     PK and authority certificates
+//   - the shim MOK list, and whether shim validation and MOK authorities are
+//     allowed
+//   - the digests of the EFI applications and GRUB files
+//   - the kernel command line
+//   - the container image digest
+//
+// Parts of the state that are not present in every golden MachineState are
+// left unconstrained. Minimum versions are taken from the oldest golden state,
+// and dbx entries are only required if every golden state revokes them.
+//
+// The generated policy should be reviewed before use. A nil opts is equivalent
+// to the zero PolicyGenerationOptions.
+func PolicyFromMachineState(states []*pb.MachineState, opts *PolicyGenerationOptions) (*pb.Policy, error) {
+	if len(states) == 0 {
+		return nil, errors.New("at least one golden MachineState is required")
+	}
+	if opts == nil {
+		opts = &PolicyGenerationOptions{}
+	}
+	if opts.Merge != MergeUnion && opts.Merge != MergeIntersection {
+		return nil, fmt.Errorf("unknown merge strategy %d", opts.Merge)
+	}
+
+	policy := &pb.Policy{}
+	var err error
+	if !opts.SkipFirmware {
+		if policy.Platform, err = generatePlatformPolicy(states, opts.Merge); err != nil {
+			return nil, err
+		}
+	}
+	if !opts.SkipSecureBoot {
+		if policy.SecureBoot, err = generateSecureBootPolicy(states, opts.Merge); err != nil {
+			return nil, err
+		}
+		if policy.Shim, err = generateShimPolicy(states, opts.Merge); err != nil {
+			return nil, err
+		}
+	}
+	if !opts.SkipBootloader {
+		if policy.Bootloader, err = generateBootloaderPolicy(states, opts.Merge); err != nil {
+			return nil, err
+		}
+	}
+	if !opts.SkipKernel {
+		if policy.Kernel, err = generateKernelPolicy(states, opts.Merge); err != nil {
+			return nil, err
+		}
+	}
+	if !opts.SkipContainer {
+		if policy.Container, err = generateContainerPolicy(states, opts.Merge); err != nil {
+			return nil, err
+		}
+	}
+	return policy, nil
+}
+
+func generatePlatformPolicy(states []*pb.MachineState, merge MergeStrategy) (*pb.PlatformPolicy, error) {
+	policy := &pb.PlatformPolicy{
+		MinimumGceFirmwareVersion: states[0].GetPlatform().GetGceVersion(),
+		MinimumTechnology:         states[0].GetPlatform().GetTechnology(),
+	}
+	var versions [][][]byte
+	pinVersions := true
+	for _, state := range states {
+		platform := state.GetPlatform()
+		if platform.GetGceVersion() < policy.MinimumGceFirmwareVersion {
+			policy.MinimumGceFirmwareVersion = platform.GetGceVersion()
+		}
+		if platform.GetTechnology() < policy.MinimumTechnology {
+			policy.MinimumTechnology = platform.GetTechnology()
+		}
+		version := scrtmVersion(platform)
+		// Pinning versions would reject a golden state without one.
+		pinVersions = pinVersions && version != nil
+		versions = append(versions, [][]byte{version})
+	}
+	if !pinVersions {
+		return policy, nil
+	}
+	allowed, err := mergeValues(versions, merge, bytesKey, "SCRTM versions")
+	if err != nil {
+		return nil, err
+	}
+	policy.AllowedScrtmVersionIds = allowed
+	return policy, nil
+}
+
+// scrtmVersion returns the SCRTM version of the platform, converting GCE
+// firmware versions as hasAllowedVersion does. It returns nil if the platform
+// has no firmware version.
+func scrtmVersion(platform *pb.PlatformState) []byte {
+	switch firmware := platform.GetFirmware().(type) {
+	case *pb.PlatformState_ScrtmVersionId:
+		return firmware.ScrtmVersionId
+	case *pb.PlatformState_GceVersion:
+		return ConvertGCEFirmwareVersionToSCRTMVersion(firmware.GceVersion)
+	}
+	return nil
+}
+
+func generateSecureBootPolicy(states []*pb.MachineState, merge MergeStrategy) (*pb.SecureBootPolicy, error) {
+	policy := &pb.SecureBootPolicy{RequireEnabled: true}
+	var dbxCerts, pkCerts, authorityCerts [][]*pb.Certificate
+	var dbxHashes [][][]byte
+	for _, state := range states {
+		sb := state.GetSecureBoot()
+		if sb == nil {
+			return nil, nil
+		}
+		policy.RequireEnabled = policy.RequireEnabled && sb.GetEnabled()
+		dbxCerts = append(dbxCerts, sb.GetDbx().GetCerts())
+		dbxHashes = append(dbxHashes, sb.GetDbx().GetHashes())
+		pkCerts = append(pkCerts, sb.GetPk().GetCerts())
+		authorityCerts = append(authorityCerts, sb.GetAuthority().GetCerts())
+	}
+
+	// Regardless of the merge strategy, only require the revocations that
+	// every golden state already has.
+	certs, _ := mergeValues(dbxCerts, MergeIntersection, certificateKey, "")
+	hashes, _ := mergeValues(dbxHashes, MergeIntersection, bytesKey, "")
+	if len(certs) > 0 || len(hashes) > 0 {
+		policy.RequiredDbx = &pb.Database{Certs: certs, Hashes: hashes}
+	}
+
+	pk, err := mergeValues(pkCerts, merge, certificateKey, "platform key certificates")
+	if err != nil {
+		return nil, err
+	}
+	if len(pk) > 0 {
+		policy.AllowedPk = &pb.Database{Certs: pk}
+	}
+	authority, err := mergeValues(authorityCerts, merge, certificateKey, "authority certificates")
+	if err != nil {
+		return nil, err
+	}
+	if len(authority) > 0 {
+		policy.AllowedAuthority = &pb.Database{Certs: authority}
+	}
+	return policy, nil
+}
+
+func generateShimPolicy(states []*pb.MachineState, merge MergeStrategy) (*pb.ShimPolicy, error) {
+	policy := &pb.ShimPolicy{RequireValidation: true, ForbidMokAuthority: true}
+	var digests [][][]byte
+	for _, state := range states {
+		shim := state.GetShim()
+		if shim == nil {
+			return nil, nil
+		}
+		policy.RequireValidation = policy.RequireValidation && !shim.GetValidationDisabled()
+		mokAuthority := shim.GetMokAuthority()
+		policy.ForbidMokAuthority = policy.ForbidMokAuthority &&
+			len(mokAuthority.GetCerts()) == 0 && len(mokAuthority.GetHashes()) == 0
+		digests = append(digests, [][]byte{shim.GetMokListDigest()})
+	}
+	allowed, err := mergeValues(digests, merge, bytesKey, "MOK list digests")
+	if err != nil {
+		return nil, err
+	}
+	policy.AllowedMokListDigests = allowed
+	return policy, nil
+}
+
+func generateBootloaderPolicy(states []*pb.MachineState, merge MergeStrategy) (*pb.BootloaderPolicy, error) {
+	policy := &pb.BootloaderPolicy{}
+	var appDigests, grubDigests [][][]byte
+	for _, state := range states {
+		if efi := state.GetEfi(); efi != nil {
+			digests := [][]byte{}
+			for _, app := range efi.GetApps() {
+				digests = append(digests, app.GetDigest())
+			}
+			appDigests = append(appDigests, digests)
+		} else {
+			appDigests = append(appDigests, nil)
+		}
+		if grub := state.GetGrub(); grub != nil {
+			digests := [][]byte{}
+			for _, file := range grub.GetFiles() {
+				digests = append(digests, file.GetDigest())
+			}
+			grubDigests = append(grubDigests, digests)
+		} else {
+			grubDigests = append(grubDigests, nil)
+		}
+	}
+
+	var err error
+	if allPresent(appDigests) {
+		if policy.AllowedEfiAppDigests, err = mergeValues(appDigests, merge, bytesKey, "EFI application digests"); err != nil {
+			return nil, err
+		}
+	}
+	if allPresent(grubDigests) {
+		if policy.AllowedGrubFileDigests, err = mergeValues(grubDigests, merge, bytesKey, "GRUB file digests"); err != nil {
+			return nil, err
+		}
+	}
+	if len(policy.AllowedEfiAppDigests) == 0 && len(policy.AllowedGrubFileDigests) == 0 {
+		return nil, nil
+	}
+	return policy, nil
+}
+
+func generateKernelPolicy(states []*pb.MachineState, merge MergeStrategy) (*pb.KernelPolicy, error) {
+	var cmdlines [][]string
+	for _, state := range states {
+		kernel := state.GetLinuxKernel()
+		if kernel == nil {
+			return nil, nil
+		}
+		cmdlines = append(cmdlines, []string{kernel.GetCommandLine()})
+	}
+	allowed, err := mergeValues(cmdlines, merge, stringKey, "kernel command lines")
+	if err != nil {
+		return nil, err
+	}
+	return &pb.KernelPolicy{AllowedCommandLines: allowed}, nil
+}
+
+func generateContainerPolicy(states []*pb.MachineState, merge MergeStrategy) (*pb.ContainerPolicy, error) {
+	var digests [][]string
+	for _, state := range states {
+		cos := state.GetCos()
+		if cos == nil {
+			return nil, nil
+		}
+		digests = append(digests, []string{cos.GetContainer().GetImageDigest()})
+	}
+	allowed, err := mergeValues(digests, merge, stringKey, "container image digests")
+	if err != nil {
+		return nil, err
+	}
+	return &pb.ContainerPolicy{AllowedImageDigests: allowed}, nil
+}
+
+// allPresent reports whether every golden state contributed a (possibly empty)
+// set of values.
+func allPresent[T any](sets [][]T) bool {
+	for _, set := range sets {
+		if set == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// mergeValues combines the values from each golden state according to the
+// merge strategy, removing duplicates and preserving the order in which values
+// are first seen. If an intersection removes every value, an error mentioning
+// the description is returned, as the resulting allow-list would reject all
+// of the golden states.
+func mergeValues[T any](sets [][]T, merge MergeStrategy, key func(T) string, description string) ([]T, error) {
+	counts := make(map[string]int)
+	var merged []T
+	for _, set := range sets {
+		seen := make(map[string]bool)
+		for _, value := range set {
+			k := key(value)
+			if seen[k] {
+				continue
+			}
+			seen[k] = true
+			if counts[k] == 0 {
+				merged = append(merged, value)
+			}
+			counts[k]++
+		}
+	}
+	if merge == MergeUnion {
+		return merged, nil
+	}
+
+	var common []T
+	for _, value := range merged {
+		if counts[key(value)] == len(sets) {
+			common = append(common, value)
+		}
+	}
+	if len(common) == 0 && len(merged) > 0 {
+		return nil, fmt.Errorf("golden MachineStates have no %s in common", description)
+	}
+	return common, nil
+}
+
+func bytesKey(b []byte) string { return string(b) }
+
+func stringKey(s string) string { return s }
@@ -0,0 +1,167 @@
+package server
+
+import (
+	"testing"
+
+	pb "github.com/google/go-tpm-tools/proto/attest"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestPolicyFromMachineStatePasses(t *testing.T) {
+	tests := []struct {
+		name          string
+		log           eventLog
+		bank          int
+		opts          VerifyOpts
+		knownFailures []string
+	}{
+		{"RHEL8-GRUB", Rhel8GCE, 1, VerifyOpts{Loader: GRUB}, nil},
+		{"Ubuntu1804AmdSev-CryptoAgile", UbuntuAmdSevGCE, 0, VerifyOpts{Loader: UnsupportedLoader}, nil},
+		{"ArchLinuxWorkstation", ArchLinuxWorkstation, 0, VerifyOpts{Loader: UnsupportedLoader, AllowEFIAppBeforeCallingEvent: true}, archLinuxKnownParsingFailures},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			machineState, err := parsePCClientEventLog(test.log.RawLog, test.log.Banks[test.bank], test.opts)
+			if err != nil {
+				gErr, ok := err.(*GroupedError)
+				if !ok || !gErr.containsKnownSubstrings(test.knownFailures) {
+					t.Fatalf("failed to get machine state: %v", err)
+				}
+			}
+			policy, err := PolicyFromMachineState([]*pb.MachineState{machineState}, nil)
+			if err != nil {
+				t.Fatalf("PolicyFromMachineState() failed: %v", err)
+			}
+			if err := EvaluatePolicy(machineState, policy); err != nil {
+				t.Errorf("golden state does not comply with generated policy: %v", err)
+			}
+		})
+	}
+}
+
+func TestPolicyFromMachineStatePinsState(t *testing.T) {
+	machineState, err := parsePCClientEventLog(Rhel8GCE.RawLog, Rhel8GCE.Banks[1], VerifyOpts{Loader: GRUB})
+	if err != nil {
+		t.Fatalf("failed to get machine state: %v", err)
+	}
+	policy, err := PolicyFromMachineState([]*pb.MachineState{machineState}, nil)
+	if err != nil {
+		t.Fatalf("PolicyFromMachineState() failed: %v", err)
+	}
+	if got, want := len(policy.GetBootloader().GetAllowedGrubFileDigests()), len(machineState.GetGrub().GetFiles()); got == 0 || got > want {
+		t.Errorf("policy allows %d GRUB file digests, want between 1 and %d", got, want)
+	}
+	if got := policy.GetKernel().GetAllowedCommandLines(); len(got) != 1 || got[0] != machineState.GetLinuxKernel().GetCommandLine() {
+		t.Errorf("policy allows kernel command lines %q, want only %q", got, machineState.GetLinuxKernel().GetCommandLine())
+	}
+	if shim := policy.GetShim(); !shim.GetRequireValidation() || !shim.GetForbidMokAuthority() || len(shim.GetAllowedMokListDigests()) != 1 {
+		t.Errorf("policy does not pin the shim MOK state: %v", shim)
+	}
+
+	modified := proto.Clone(machineState).(*pb.MachineState)
+	modified.GetLinuxKernel().CommandLine += " nokaslr"
+	modified.GetGrub().GetFiles()[0].Digest = []byte{0x00}
+	err = EvaluatePolicy(modified, policy)
+	if err == nil {
+		t.Fatal("modified state complies with generated policy, expected failure")
+	}
+	if gErr := err.(*GroupedError); len(gErr.Errors) != 2 {
+		t.Errorf("EvaluatePolicy() got %d errors, want 2: %v", len(gErr.Errors), gErr)
+	}
+}
+
+func TestPolicyFromMachineStateMerge(t *testing.T) {
+	makeState := func(gceVersion uint32, cmdline string, grubDigests ...[]byte) *pb.MachineState {
+		grub := &pb.GrubState{}
+		for _, digest := range grubDigests {
+			grub.Files = append(grub.Files, &pb.GrubFile{Digest: digest})
+		}
+		return &pb.MachineState{
+			Platform: &pb.PlatformState{
+				Firmware:   &pb.PlatformState_GceVersion{GceVersion: gceVersion},
+				Technology: pb.GCEConfidentialTechnology_AMD_SEV,
+			},
+			Grub:        grub,
+			LinuxKernel: &pb.LinuxKernelState{CommandLine: cmdline},
+			Cos: &pb.AttestedCosState{
+				Container: &pb.ContainerState{ImageDigest: "sha256:0123"},
+			},
+		}
+	}
+	oldState := makeState(3, "console=ttyS0", []byte{0x01}, []byte{0x02})
+	newState := makeState(5, "console=ttyS0", []byte{0x01}, []byte{0x03})
+	golden := []*pb.MachineState{oldState, newState}
+
+	t.Run("Union", func(t *testing.T) {
+		policy, err := PolicyFromMachineState(golden, &PolicyGenerationOptions{Merge: MergeUnion})
+		if err != nil {
+			t.Fatalf("PolicyFromMachineState() failed: %v", err)
+		}
+		if got := policy.GetPlatform().GetMinimumGceFirmwareVersion(); got != 3 {
+			t.Errorf("MinimumGceFirmwareVersion = %d, want 3", got)
+		}
+		if got := len(policy.GetPlatform().GetAllowedScrtmVersionIds()); got != 2 {
+			t.Errorf("policy allows %d SCRTM versions, want 2", got)
+		}
+		if got := len(policy.GetBootloader().GetAllowedGrubFileDigests()); got != 3 {
+			t.Errorf("policy allows %d GRUB file digests, want 3", got)
+		}
+		for _, state := range golden {
+			if err := EvaluatePolicy(state, policy); err != nil {
+				t.Errorf("golden state does not comply with generated policy: %v", err)
+			}
+		}
+	})
+	t.Run("Intersection", func(t *testing.T) {
+		policy, err := PolicyFromMachineState([]*pb.MachineState{oldState, oldState}, &PolicyGenerationOptions{Merge: MergeIntersection})
+		if err != nil {
+			t.Fatalf("PolicyFromMachineState() failed: %v", err)
+		}
+		if err := EvaluatePolicy(newState, policy); err == nil {
+			t.Error("drifted state complies with generated policy, expected failure")
+		}
+
+		policy, err = PolicyFromMachineState(golden, &PolicyGenerationOptions{Merge: MergeIntersection, SkipFirmware: true})
+		if err != nil {
+			t.Fatalf("PolicyFromMachineState() failed: %v", err)
+		}
+		if got := policy.GetBootloader().GetAllowedGrubFileDigests(); len(got) != 1 || got[0][0] != 0x01 {
+			t.Errorf("policy allows GRUB file digests %x, want only 01", got)
+		}
+		if _, err := PolicyFromMachineState(golden, &PolicyGenerationOptions{Merge: MergeIntersection}); err == nil {
+			t.Error("PolicyFromMachineState() succeeded with no common SCRTM versions, expected error")
+		}
+	})
+	t.Run("Skip", func(t *testing.T) {
+		policy, err := PolicyFromMachineState(golden, &PolicyGenerationOptions{
+			SkipFirmware:   true,
+			SkipSecureBoot: true,
+			SkipBootloader: true,
+			SkipKernel:     true,
+		})
+		if err != nil {
+			t.Fatalf("PolicyFromMachineState() failed: %v", err)
+		}
+		want := &pb.Policy{Container: &pb.ContainerPolicy{AllowedImageDigests: []string{"sha256:0123"}}}
+		if !proto.Equal(policy, want) {
+			t.Errorf("PolicyFromMachineState() = %v, want %v", policy, want)
+		}
+	})
+	t.Run("MissingState", func(t *testing.T) {
+		partial := proto.Clone(newState).(*pb.MachineState)
+		partial.LinuxKernel = nil
+		policy, err := PolicyFromMachineState([]*pb.MachineState{oldState, partial}, nil)
+		if err != nil {
+			t.Fatalf("PolicyFromMachineState() failed: %v", err)
+		}
+		if policy.GetKernel() != nil {
+			t.Errorf("policy constrains the kernel (%v), but not every golden state has one", policy.GetKernel())
+		}
+	})
+}
+
+func TestPolicyFromMachineStateNoStates(t *testing.T) {
+	if _, err := PolicyFromMachineState(nil, nil); err == nil {
+		t.Error("PolicyFromMachineState(nil) succeeded, expected error")
+	}
+}
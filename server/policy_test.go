@@ -1,9 +1,26 @@
 package server
 
 import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
 	"testing"
+	"time"
 
+	epb "github.com/google/gce-tcb-verifier/proto/endorsement"
+	"github.com/google/go-sev-guest/proto/sevsnp"
+	"github.com/google/go-tdx-guest/proto/tdx"
 	pb "github.com/google/go-tpm-tools/proto/attest"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 var defaultGcePolicy = pb.Policy{
@@ -152,3 +169,526 @@ func TestEvaluatePolicyFailure(t *testing.T) {
 		})
 	}
 }
+
+func TestEvaluateSecureBootPolicy(t *testing.T) {
+	machineState, err := parsePCClientEventLog(UbuntuAmdSevGCE.RawLog, UbuntuAmdSevGCE.Banks[0], VerifyOpts{Loader: UnsupportedLoader})
+	if err != nil {
+		t.Fatalf("failed to get machine state: %v", err)
+	}
+	winProdPCA := &pb.Certificate{Representation: &pb.Certificate_WellKnown{
+		WellKnown: pb.WellKnownCertificate_MS_WINDOWS_PROD_PCA_2011}}
+	winProdPCADer := &pb.Certificate{Representation: &pb.Certificate_Der{
+		Der: WindowsProductionPCA2011Cert}}
+	dbxHashes := machineState.GetSecureBoot().GetDbx().GetHashes()
+	if len(dbxHashes) == 0 {
+		t.Fatal("expected dbx hashes in the event log")
+	}
+
+	tests := []struct {
+		name       string
+		policy     *pb.SecureBootPolicy
+		wantErrors int
+	}{
+		{"Empty", &pb.SecureBootPolicy{}, 0},
+		{"RequiredDbxHashPresent", &pb.SecureBootPolicy{
+			RequiredDbx: &pb.Database{Hashes: dbxHashes},
+		}, 0},
+		{"RequiredDbxHashMissing", &pb.SecureBootPolicy{
+			RequiredDbx: &pb.Database{Hashes: [][]byte{{0x01}, {0x02}}},
+		}, 2},
+		{"ForbiddenDbWellKnown", &pb.SecureBootPolicy{
+			ForbiddenDb: &pb.Database{Certs: []*pb.Certificate{winProdPCA}},
+		}, 1},
+		{"ForbiddenDbDer", &pb.SecureBootPolicy{
+			ForbiddenDb: &pb.Database{Certs: []*pb.Certificate{winProdPCADer}},
+		}, 1},
+		{"AllowedPkMismatch", &pb.SecureBootPolicy{
+			AllowedPk: &pb.Database{Certs: []*pb.Certificate{winProdPCA}},
+		}, len(machineState.GetSecureBoot().GetPk().GetCerts())},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			errs := evaluateSecureBootPolicy(machineState.GetSecureBoot(), test.policy)
+			if len(errs) != test.wantErrors {
+				t.Errorf("evaluateSecureBootPolicy() got %d errors, want %d: %v", len(errs), test.wantErrors, errs)
+			}
+		})
+	}
+}
+
+func TestEvaluateSecureBootPolicyDisabled(t *testing.T) {
+	state := &pb.MachineState{SecureBoot: &pb.SecureBootState{Enabled: false}}
+	policy := &pb.Policy{SecureBoot: &pb.SecureBootPolicy{RequireEnabled: true}}
+	if err := EvaluatePolicy(state, policy); err == nil {
+		t.Error("expected policy failure for disabled Secure Boot; got success")
+	}
+}
+
+func TestEvaluateSecureBootPolicyNoState(t *testing.T) {
+	winProdPCA := &pb.Certificate{Representation: &pb.Certificate_WellKnown{
+		WellKnown: pb.WellKnownCertificate_MS_WINDOWS_PROD_PCA_2011}}
+	tests := []struct {
+		name       string
+		policy     *pb.SecureBootPolicy
+		wantErrors int
+	}{
+		{"Empty", &pb.SecureBootPolicy{}, 0},
+		{"ForbiddenDb", &pb.SecureBootPolicy{
+			ForbiddenDb: &pb.Database{Certs: []*pb.Certificate{winProdPCA}},
+		}, 1},
+		{"AllowedAuthority", &pb.SecureBootPolicy{
+			AllowedAuthority: &pb.Database{Certs: []*pb.Certificate{winProdPCA}},
+		}, 1},
+		{"AllowedPk", &pb.SecureBootPolicy{
+			AllowedPk: &pb.Database{Certs: []*pb.Certificate{winProdPCA}},
+		}, 1},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			errs := evaluateSecureBootPolicy(nil, test.policy)
+			if len(errs) != test.wantErrors {
+				t.Errorf("evaluateSecureBootPolicy() got %d errors, want %d: %v", len(errs), test.wantErrors, errs)
+			}
+		})
+	}
+}
+
+func TestEvaluateBootloaderPolicy(t *testing.T) {
+	machineState, err := parsePCClientEventLog(Rhel8GCE.RawLog, Rhel8GCE.Banks[1], VerifyOpts{Loader: GRUB})
+	if err != nil {
+		t.Fatalf("failed to get machine state: %v", err)
+	}
+	var efiDigests, grubDigests [][]byte
+	for _, app := range machineState.GetEfi().GetApps() {
+		efiDigests = append(efiDigests, app.GetDigest())
+	}
+	for _, file := range machineState.GetGrub().GetFiles() {
+		grubDigests = append(grubDigests, file.GetDigest())
+	}
+
+	tests := []struct {
+		name       string
+		state      *pb.MachineState
+		policy     *pb.BootloaderPolicy
+		wantErrors int
+	}{
+		{"AllAllowed", machineState, &pb.BootloaderPolicy{
+			AllowedEfiAppDigests:   efiDigests,
+			AllowedGrubFileDigests: grubDigests,
+		}, 0},
+		{"EfiAppMissing", machineState, &pb.BootloaderPolicy{
+			AllowedEfiAppDigests: efiDigests[1:],
+		}, 1},
+		{"GrubFilesNotAllowed", machineState, &pb.BootloaderPolicy{
+			AllowedGrubFileDigests: [][]byte{{0x00}},
+		}, len(grubDigests)},
+		{"NoGrubState", &pb.MachineState{}, &pb.BootloaderPolicy{
+			AllowedGrubFileDigests: grubDigests,
+		}, 1},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			errs := evaluateBootloaderPolicy(test.state, test.policy)
+			if len(errs) != test.wantErrors {
+				t.Errorf("evaluateBootloaderPolicy() got %d errors, want %d: %v", len(errs), test.wantErrors, errs)
+			}
+		})
+	}
+}
+
+func TestEvaluateShimPolicy(t *testing.T) {
+	machineState, err := parsePCClientEventLog(Ubuntu2404AmdSevSnp.RawLog, Ubuntu2404AmdSevSnp.Banks[1], VerifyOpts{Loader: GRUB})
+	if err != nil {
+		t.Fatalf("failed to get machine state: %v", err)
+	}
+	state := machineState.GetShim()
+	moks := &pb.ShimState{
+		MokAuthority:       &pb.Database{Certs: []*pb.Certificate{{Representation: &pb.Certificate_Der{Der: []byte{0x30}}}}, Hashes: [][]byte{{0x01}}},
+		MokListDigest:      []byte{0x02},
+		ValidationDisabled: true,
+	}
+
+	tests := []struct {
+		name       string
+		state      *pb.ShimState
+		policy     *pb.ShimPolicy
+		wantErrors int
+	}{
+		{"Empty", moks, &pb.ShimPolicy{}, 0},
+		{"AllAllowed", state, &pb.ShimPolicy{
+			RequireValidation:     true,
+			ForbidMokAuthority:    true,
+			AllowedMokListDigests: [][]byte{state.GetMokListDigest()},
+		}, 0},
+		{"MokListTrusted", state, &pb.ShimPolicy{ForbidMokListTrusted: true}, 1},
+		{"ValidationDisabled", moks, &pb.ShimPolicy{RequireValidation: true}, 1},
+		{"MokAuthority", moks, &pb.ShimPolicy{ForbidMokAuthority: true}, 2},
+		{"MokListNotAllowed", moks, &pb.ShimPolicy{
+			AllowedMokListDigests: [][]byte{state.GetMokListDigest()},
+		}, 1},
+		{"NoShimState", nil, &pb.ShimPolicy{
+			RequireValidation:     true,
+			ForbidMokAuthority:    true,
+			AllowedMokListDigests: [][]byte{state.GetMokListDigest()},
+		}, 2},
+		{"NoShimStateMokListTrusted", nil, &pb.ShimPolicy{ForbidMokListTrusted: true}, 1},
+		{"NoShimStateMokAuthority", nil, &pb.ShimPolicy{ForbidMokAuthority: true}, 0},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			errs := evaluateShimPolicy(test.state, test.policy)
+			if len(errs) != test.wantErrors {
+				t.Errorf("evaluateShimPolicy() got %d errors, want %d: %v", len(errs), test.wantErrors, errs)
+			}
+		})
+	}
+}
+
+func TestEvaluateKernelPolicy(t *testing.T) {
+	state := &pb.LinuxKernelState{CommandLine: "/vmlinuz root=/dev/sda1 ro console=ttyS0 init=/bin/sh"}
+	tests := []struct {
+		name       string
+		state      *pb.LinuxKernelState
+		policy     *pb.KernelPolicy
+		wantErrors int
+	}{
+		{"Empty", state, &pb.KernelPolicy{}, 0},
+		{"AllowedCommandLine", state, &pb.KernelPolicy{
+			AllowedCommandLines: []string{state.GetCommandLine()},
+		}, 0},
+		{"CommandLineNotAllowed", state, &pb.KernelPolicy{
+			AllowedCommandLines: []string{"/vmlinuz ro"},
+		}, 1},
+		{"RequiredArgs", state, &pb.KernelPolicy{
+			RequiredCommandLineArgs: []string{"ro", "console=ttyS0", "lockdown=integrity", "root"},
+		}, 2},
+		{"ForbiddenArgs", state, &pb.KernelPolicy{
+			ForbiddenCommandLineArgs: []string{"init", "console=tty0", "rw"},
+		}, 1},
+		{"ForbiddenArgWithValue", state, &pb.KernelPolicy{
+			ForbiddenCommandLineArgs: []string{"init=/bin/sh"},
+		}, 1},
+		{"NoKernelState", nil, &pb.KernelPolicy{
+			RequiredCommandLineArgs: []string{"ro"},
+		}, 1},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			errs := evaluateKernelPolicy(test.state, test.policy)
+			if len(errs) != test.wantErrors {
+				t.Errorf("evaluateKernelPolicy() got %d errors, want %d: %v", len(errs), test.wantErrors, errs)
+			}
+		})
+	}
+}
+
+func TestEvaluateContainerPolicy(t *testing.T) {
+	memoryEnabled := true
+	state := &pb.AttestedCosState{
+		Container: &pb.ContainerState{
+			ImageReference:    "docker.io/library/hello-world:latest",
+			ImageDigest:       "sha256:781d8dfdd92118436bd914442c8339e653b83f6bf3c1a7a98efcfb7c4fed7483",
+			RestartPolicy:     pb.RestartPolicy_Never,
+			OverriddenArgs:    []string{"--debug"},
+			OverriddenEnvVars: map[string]string{},
+		},
+		CosVersion:       &pb.SemanticVersion{Major: 101, Minor: 2, Patch: 3},
+		LauncherVersion:  &pb.SemanticVersion{Major: 0, Minor: 4, Patch: 0},
+		HealthMonitoring: &pb.HealthMonitoringState{MemoryEnabled: &memoryEnabled},
+		GpuDeviceState:   &pb.GpuDeviceState{CcMode: pb.GPUDeviceCCMode_ON},
+	}
+	tests := []struct {
+		name       string
+		state      *pb.AttestedCosState
+		policy     *pb.ContainerPolicy
+		wantErrors int
+	}{
+		{"Empty", state, &pb.ContainerPolicy{}, 0},
+		{"AllowedImage", state, &pb.ContainerPolicy{
+			AllowedImageDigests:    []string{state.GetContainer().GetImageDigest()},
+			AllowedImageReferences: []string{state.GetContainer().GetImageReference()},
+		}, 0},
+		{"ImageNotAllowed", state, &pb.ContainerPolicy{
+			AllowedImageDigests:    []string{"sha256:0000"},
+			AllowedImageReferences: []string{"docker.io/library/busybox:latest"},
+		}, 2},
+		{"RestartPolicy", state, &pb.ContainerPolicy{
+			AllowedRestartPolicies: []pb.RestartPolicy{pb.RestartPolicy_Always, pb.RestartPolicy_OnFailure},
+		}, 1},
+		{"ForbidOverrides", state, &pb.ContainerPolicy{ForbidOverrides: true}, 1},
+		{"MinimumVersionsMet", state, &pb.ContainerPolicy{
+			MinimumCosVersion:      &pb.SemanticVersion{Major: 101, Minor: 2},
+			MinimumLauncherVersion: &pb.SemanticVersion{Minor: 4},
+		}, 0},
+		{"MinimumVersionsNotMet", state, &pb.ContainerPolicy{
+			MinimumCosVersion:      &pb.SemanticVersion{Major: 101, Minor: 2, Patch: 4},
+			MinimumLauncherVersion: &pb.SemanticVersion{Major: 1},
+		}, 2},
+		{"MemoryMonitoring", state, &pb.ContainerPolicy{RequireMemoryMonitoring: true}, 0},
+		{"GpuCCMode", state, &pb.ContainerPolicy{
+			AllowedGpuCcModes: []pb.GPUDeviceCCMode{pb.GPUDeviceCCMode_DEVTOOLS},
+		}, 1},
+		{"NoCosState", nil, &pb.ContainerPolicy{}, 1},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			errs := evaluateContainerPolicy(test.state, test.policy)
+			if len(errs) != test.wantErrors {
+				t.Errorf("evaluateContainerPolicy() got %d errors, want %d: %v", len(errs), test.wantErrors, errs)
+			}
+		})
+	}
+}
+
+func TestEvaluatePolicyReportsAllViolations(t *testing.T) {
+	state := &pb.MachineState{
+		Platform: &pb.PlatformState{
+			Firmware: &pb.PlatformState_GceVersion{GceVersion: 1},
+		},
+		SecureBoot:  &pb.SecureBootState{Enabled: false},
+		LinuxKernel: &pb.LinuxKernelState{CommandLine: "ro init=/bin/sh"},
+	}
+	policy := &pb.Policy{
+		Platform: &pb.PlatformPolicy{
+			MinimumGceFirmwareVersion: 2,
+			MinimumTechnology:         pb.GCEConfidentialTechnology_AMD_SEV,
+		},
+		SecureBoot: &pb.SecureBootPolicy{RequireEnabled: true},
+		Kernel:     &pb.KernelPolicy{ForbiddenCommandLineArgs: []string{"init"}},
+		Container:  &pb.ContainerPolicy{},
+	}
+	err := EvaluatePolicy(state, policy)
+	if err == nil {
+		t.Fatal("expected policy failure; got success")
+	}
+	gErr, ok := err.(*GroupedError)
+	if !ok {
+		t.Fatalf("EvaluatePolicy should return a GroupedError, got %T", err)
+	}
+	if !gErr.containsKnownSubstrings([]string{
+		"expected GCE Version",
+		"expected a GCE Confidential Technology",
+		"expected Secure Boot to be enabled",
+		"forbidden argument",
+		"no AttestedCosState",
+	}) {
+		t.Errorf("unexpected policy violations: %v", err)
+	}
+}
+
+// fakeGetter serves canned responses in place of trust.HTTPSGetter.
+type fakeGetter struct {
+	responses map[string][]byte
+	requested []string
+}
+
+func (g *fakeGetter) Get(url string) ([]byte, error) {
+	g.requested = append(g.requested, url)
+	if resp, ok := g.responses[url]; ok {
+		return resp, nil
+	}
+	return nil, fmt.Errorf("404 not found: %s", url)
+}
+
+func TestEvaluateSevSnpPolicy(t *testing.T) {
+	measurement := make([]byte, 48)
+	measurement[0] = 0xab
+	url := fmt.Sprintf(sevSnpUefiEndorsementURL, fmt.Sprintf("%x", measurement))
+	state := &pb.MachineState{
+		TeeAttestation: &pb.MachineState_SevSnpAttestation{
+			SevSnpAttestation: &sevsnp.Attestation{
+				Report: &sevsnp.Report{Measurement: measurement},
+			},
+		},
+	}
+	rootCerts := [][]byte{MicrosoftUEFICA2011Cert}
+	testRoot, signed, missing := signedUefiEndorsements(t, measurement)
+	testRootCerts := [][]byte{testRoot}
+
+	tests := []struct {
+		name      string
+		state     *pb.MachineState
+		uefi      *pb.RIMPolicy
+		responses map[string][]byte
+		wantErr   string
+	}{
+		{"NoUefiPolicy", state, nil, nil, ""},
+		{"NoAttestation", &pb.MachineState{}, &pb.RIMPolicy{RootCerts: rootCerts}, nil, "no SEV-SNP attestation"},
+		{"NoRootCerts", state, &pb.RIMPolicy{}, nil, "at least one root certificate"},
+		{"BadRootCerts", state, &pb.RIMPolicy{RootCerts: [][]byte{{0x30, 0x00}}}, nil, "failed to parse cert"},
+		{"UnavailableNotRequired", state, &pb.RIMPolicy{RootCerts: rootCerts}, nil, ""},
+		{"UnavailableRequired", state, &pb.RIMPolicy{RequireSigned: true, RootCerts: rootCerts}, nil, "failed to get signed UEFI reference measurements"},
+		{"BadEndorsement", state, &pb.RIMPolicy{RootCerts: rootCerts},
+			map[string][]byte{url: []byte("not an endorsement")}, "failed to verify UEFI launch endorsement"},
+		{"UntrustedEndorsement", state, &pb.RIMPolicy{RootCerts: rootCerts},
+			map[string][]byte{url: signed}, "failed to verify UEFI launch endorsement"},
+		{"SignedEndorsement", state, &pb.RIMPolicy{RequireSigned: true, RootCerts: testRootCerts},
+			map[string][]byte{url: signed}, ""},
+		{"SignedEndorsementMissingMeasurement", state, &pb.RIMPolicy{RootCerts: testRootCerts},
+			map[string][]byte{url: missing}, "is not among the signed UEFI reference measurements"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			getter := &fakeGetter{responses: test.responses}
+			policy := &pb.Policy{SevSnp: &pb.SevSnpPolicy{Uefi: test.uefi}}
+			err := EvaluatePolicyWithOptions(test.state, policy, &PolicyOptions{Getter: getter, Now: time.Now()})
+			if test.wantErr == "" {
+				if err != nil {
+					t.Errorf("EvaluatePolicyWithOptions() failed: %v", err)
+				}
+				return
+			}
+			var gErr *GroupedError
+			if !errors.As(err, &gErr) || !gErr.containsOnlySubstring(test.wantErr) {
+				t.Errorf("EvaluatePolicyWithOptions() = %v, want error containing %q", err, test.wantErr)
+			}
+		})
+	}
+}
+
+// signedUefiEndorsements returns a test root certificate, and two UEFI launch
+// endorsements signed by a key it certifies: one containing the measurement,
+// and one that doesn't.
+func signedUefiEndorsements(t *testing.T, measurement []byte) (root []byte, signed []byte, missing []byte) {
+	t.Helper()
+	rootKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signingKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	rootTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test UEFI Root"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	root, err = x509.CreateCertificate(rand.Reader, rootTemplate, rootTemplate, rootKey.Public(), rootKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signingTemplate := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "Test UEFI Signer"},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	signingCert, err := x509.CreateCertificate(rand.Reader, signingTemplate, rootTemplate, signingKey.Public(), rootKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	endorse := func(measurements map[uint32][]byte) []byte {
+		golden, err := proto.Marshal(&epb.VMGoldenMeasurement{
+			Timestamp: timestamppb.New(now),
+			Commit:    []byte("test"),
+			Cert:      signingCert,
+			SevSnp:    &epb.VMSevSnp{Measurements: measurements},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		digest := sha256.Sum256(golden)
+		sig, err := rsa.SignPSS(rand.Reader, signingKey, crypto.SHA256, digest[:], &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
+		if err != nil {
+			t.Fatal(err)
+		}
+		endorsement, err := proto.Marshal(&epb.VMLaunchEndorsement{SerializedUefiGolden: golden, Signature: sig})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return endorsement
+	}
+	other := make([]byte, 48)
+	return root, endorse(map[uint32][]byte{1: other, 2: measurement}), endorse(map[uint32][]byte{1: other})
+}
+
+func TestEvaluateSevSnpPolicyFetchesByMeasurement(t *testing.T) {
+	measurement := make([]byte, 48)
+	measurement[47] = 0x01
+	state := &pb.MachineState{
+		TeeAttestation: &pb.MachineState_SevSnpAttestation{
+			SevSnpAttestation: &sevsnp.Attestation{
+				Report: &sevsnp.Report{Measurement: measurement},
+			},
+		},
+	}
+	policy := &pb.Policy{SevSnp: &pb.SevSnpPolicy{Uefi: &pb.RIMPolicy{
+		RootCerts: [][]byte{MicrosoftUEFICA2011Cert},
+	}}}
+	getter := &fakeGetter{}
+	if err := EvaluatePolicyWithOptions(state, policy, &PolicyOptions{Getter: getter}); err != nil {
+		t.Fatalf("EvaluatePolicyWithOptions() failed: %v", err)
+	}
+	if len(getter.requested) != 1 || !strings.HasSuffix(getter.requested[0], fmt.Sprintf("%x.binarypb", measurement)) {
+		t.Errorf("expected a single request for the launch measurement, got %v", getter.requested)
+	}
+}
+
+func TestEvaluateTdxPolicy(t *testing.T) {
+	mrTd := bytes.Repeat([]byte{0x11}, 48)
+	mrSignerSeam := bytes.Repeat([]byte{0x22}, 48)
+	rtmrs := [][]byte{
+		bytes.Repeat([]byte{0x00}, 48),
+		bytes.Repeat([]byte{0x01}, 48),
+		bytes.Repeat([]byte{0x02}, 48),
+		bytes.Repeat([]byte{0x03}, 48),
+	}
+	teeTcbSvn := []byte{0x03, 0x01, 0x02, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}
+	quote := &tdx.QuoteV4{
+		TdQuoteBody: &tdx.TDQuoteBody{
+			TeeTcbSvn:    teeTcbSvn,
+			MrSignerSeam: mrSignerSeam,
+			TdAttributes: make([]byte, 8),
+			MrTd:         mrTd,
+			Rtmrs:        rtmrs,
+		},
+	}
+	debugQuote := &tdx.QuoteV4{
+		TdQuoteBody: &tdx.TDQuoteBody{
+			TdAttributes: []byte{0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+		},
+	}
+	higherSvn := append([]byte{}, teeTcbSvn...)
+	higherSvn[1] = 0x02
+
+	tests := []struct {
+		name       string
+		quote      *tdx.QuoteV4
+		policy     *pb.TdxPolicy
+		wantErrors int
+	}{
+		{"NilPolicy", quote, nil, 0},
+		{"Empty", quote, &pb.TdxPolicy{}, 0},
+		{"NoQuote", nil, &pb.TdxPolicy{}, 1},
+		{"AllMatch", quote, &pb.TdxPolicy{
+			AllowedMrtds:         [][]byte{mrTd},
+			ExpectedRtmrs:        rtmrs,
+			MinimumTeeTcbSvn:     teeTcbSvn,
+			ForbidDebug:          true,
+			AllowedMrSignerSeams: [][]byte{mrSignerSeam},
+		}, 0},
+		{"MrtdNotAllowed", quote, &pb.TdxPolicy{AllowedMrtds: [][]byte{mrSignerSeam}}, 1},
+		{"PartialRtmrs", quote, &pb.TdxPolicy{ExpectedRtmrs: [][]byte{nil, rtmrs[1], rtmrs[3]}}, 1},
+		{"TooManyRtmrs", quote, &pb.TdxPolicy{ExpectedRtmrs: append(append([][]byte{}, rtmrs...), rtmrs[0])}, 1},
+		{"TcbSvnTooLow", quote, &pb.TdxPolicy{MinimumTeeTcbSvn: higherSvn}, 1},
+		{"BadMinimumTcbSvn", quote, &pb.TdxPolicy{MinimumTeeTcbSvn: []byte{0x01}}, 1},
+		{"Debug", debugQuote, &pb.TdxPolicy{ForbidDebug: true}, 1},
+		{"MrSignerSeamNotAllowed", quote, &pb.TdxPolicy{AllowedMrSignerSeams: [][]byte{mrTd}}, 1},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			errs := evaluateTdxPolicy(test.quote, test.policy)
+			if len(errs) != test.wantErrors {
+				t.Errorf("evaluateTdxPolicy() got %d errors, want %d: %v", len(errs), test.wantErrors, errs)
+			}
+		})
+	}
+}
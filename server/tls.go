@@ -0,0 +1,71 @@
+package server
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/go-tpm-tools/internal"
+	pb "github.com/google/go-tpm-tools/proto/attest"
+	"google.golang.org/protobuf/proto"
+)
+
+// VerifyAttestedTLS returns a tls.Config.VerifyPeerCertificate callback
+// verifying the peer's attested TLS certificate, as created by
+// client.Key.AttestedTLSCertificate. The callback checks the certificate's
+// self-signature and validity period, then verifies its embedded attestation
+// with VerifyAttestation and opts, whose Nonce must be the one the peer
+// attested with. If checkState is non-nil, it is then called with the
+// resulting MachineState, and its error fails the handshake.
+//
+// As attested TLS certificates are self-issued, clients must also set
+// tls.Config.InsecureSkipVerify, and servers must set tls.Config.ClientAuth to
+// tls.RequireAnyClientCert, so that the certificate is only verified here.
+func VerifyAttestedTLS(opts VerifyOpts, checkState func(*pb.MachineState) error) func(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error {
+	return func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+		if len(rawCerts) == 0 {
+			return errors.New("peer sent no certificate")
+		}
+		cert, err := x509.ParseCertificate(rawCerts[0])
+		if err != nil {
+			return fmt.Errorf("failed to parse peer certificate: %w", err)
+		}
+		attestation, err := attestationFromCert(cert)
+		if err != nil {
+			return err
+		}
+		verifyOpts := opts
+		verifyOpts.Nonce = internal.AttestedTLSNonce(cert.RawSubjectPublicKeyInfo, opts.Nonce)
+		state, err := VerifyAttestation(attestation, verifyOpts)
+		if err != nil {
+			return fmt.Errorf("failed to verify peer attestation: %w", err)
+		}
+		if checkState != nil {
+			return checkState(state)
+		}
+		return nil
+	}
+}
+
+// attestationFromCert checks an attested TLS certificate and returns its
+// attestation.
+func attestationFromCert(cert *x509.Certificate) (*pb.Attestation, error) {
+	if err := cert.CheckSignature(cert.SignatureAlgorithm, cert.RawTBSCertificate, cert.Signature); err != nil {
+		return nil, fmt.Errorf("invalid peer certificate signature: %w", err)
+	}
+	if now := time.Now(); now.Before(cert.NotBefore) || now.After(cert.NotAfter) {
+		return nil, fmt.Errorf("peer certificate is not valid at %v (valid from %v to %v)", now, cert.NotBefore, cert.NotAfter)
+	}
+	for _, ext := range cert.Extensions {
+		if !ext.Id.Equal(internal.AttestedTLSExtensionOID) {
+			continue
+		}
+		attestation := &pb.Attestation{}
+		if err := proto.Unmarshal(ext.Value, attestation); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal peer attestation: %w", err)
+		}
+		return attestation, nil
+	}
+	return nil, errors.New("peer certificate has no attestation")
+}
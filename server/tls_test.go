@@ -0,0 +1,101 @@
+package server
+
+import (
+	"crypto"
+	"crypto/tls"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/google/go-tpm-tools/client"
+	"github.com/google/go-tpm-tools/internal/test"
+	attestpb "github.com/google/go-tpm-tools/proto/attest"
+	"github.com/google/go-tpm/legacy/tpm2"
+)
+
+// attestedTLSHandshake runs a TLS handshake between a server with cert and a
+// client verifying it with verify.
+func attestedTLSHandshake(cert tls.Certificate, verify func(*tls.Config)) error {
+	serverConn, clientConn := net.Pipe()
+	defer serverConn.Close()
+	defer clientConn.Close()
+
+	serverErr := make(chan error, 1)
+	go func() {
+		conn := tls.Server(serverConn, &tls.Config{Certificates: []tls.Certificate{cert}})
+		err := conn.Handshake()
+		if err != nil {
+			// Unblock the client.
+			serverConn.Close()
+		}
+		serverErr <- err
+	}()
+	config := &tls.Config{InsecureSkipVerify: true}
+	verify(config)
+	if err := tls.Client(clientConn, config).Handshake(); err != nil {
+		return err
+	}
+	return <-serverErr
+}
+
+func TestVerifyAttestedTLS(t *testing.T) {
+	rwc := test.GetTPM(t)
+	defer client.CheckedClose(t, rwc)
+
+	ak, err := client.AttestationKeyRSA(rwc)
+	if err != nil {
+		t.Fatalf("failed to generate AK: %v", err)
+	}
+	defer ak.Close()
+
+	template := client.AKTemplateECC()
+	template.Attributes &= ^tpm2.FlagRestricted
+	key, err := client.NewKey(rwc, tpm2.HandleEndorsement, template)
+	if err != nil {
+		t.Fatalf("failed to generate TLS key: %v", err)
+	}
+	defer key.Close()
+
+	nonce := []byte("super secret nonce")
+	cert, err := key.AttestedTLSCertificate(ak, nil, client.AttestOpts{Nonce: nonce})
+	if err != nil {
+		t.Fatalf("failed to create attested TLS certificate: %v", err)
+	}
+	errState := errors.New("bad machine state")
+
+	tests := []struct {
+		name       string
+		nonce      []byte
+		trustedAK  crypto.PublicKey
+		checkState func(*attestpb.MachineState) error
+		wantErr    bool
+	}{
+		{"Success", nonce, ak.PublicKey(), nil, false},
+		{"CheckState", nonce, ak.PublicKey(), func(*attestpb.MachineState) error { return nil }, false},
+		{"CheckStateFails", nonce, ak.PublicKey(), func(*attestpb.MachineState) error { return errState }, true},
+		{"WrongNonce", []byte("other nonce"), ak.PublicKey(), nil, true},
+		{"UntrustedAK", nonce, key.PublicKey(), nil, true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			opts := VerifyOpts{Nonce: tc.nonce, TrustedAKs: []crypto.PublicKey{tc.trustedAK}}
+			err := attestedTLSHandshake(cert, func(config *tls.Config) {
+				config.VerifyPeerCertificate = VerifyAttestedTLS(opts, tc.checkState)
+			})
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("TLS handshake returned error %v, want error: %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestVerifyAttestedTLSFailsWithoutAttestation(t *testing.T) {
+	cert, _ := test.GetTestCert(t, nil, nil, nil)
+	verify := VerifyAttestedTLS(VerifyOpts{}, nil)
+	if err := verify([][]byte{cert.Raw}, nil); err == nil {
+		t.Error("VerifyAttestedTLS() succeeded for a certificate without an attestation")
+	}
+	if err := verify(nil, nil); err == nil {
+		t.Error("VerifyAttestedTLS() succeeded without a certificate")
+	}
+}
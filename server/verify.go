@@ -7,6 +7,7 @@ import (
 	"encoding/asn1"
 	"errors"
 	"fmt"
+	"slices"
 
 	"github.com/google/go-tpm-tools/internal"
 	pb "github.com/google/go-tpm-tools/proto/attest"
@@ -15,8 +16,11 @@ import (
 	"google.golang.org/protobuf/proto"
 )
 
+// defaultPCRHashAlgs is the order in which PCR banks are used to verify an
+// attestation if VerifyOpts.PreferredHashAlgs is empty. SHA-256 comes first,
+// as it is the bank most event logs and policies have been written against.
 // We conditinally support SHA-1 for PCR hashes, but at the lowest priority.
-var pcrHashAlgs = append(internal.SignatureHashAlgs, tpm2.AlgSHA1)
+var defaultPCRHashAlgs = []tpm2.Algorithm{tpm2.AlgSHA256, tpm2.AlgSHA384, tpm2.AlgSHA512, tpm2.AlgSHA1}
 
 var oidExtensionSubjectAltName = []int{2, 5, 29, 17}
 
@@ -37,6 +41,13 @@ type VerifyOpts struct {
 	// distributions (such as Debian 10). Note that this will NOT allow
 	// SHA-1 signatures to be used, just SHA-1 PCRs.
 	AllowSHA1 bool
+	// The PCR banks to try, in order, when verifying an attestation. The first
+	// bank whose quote verifies and whose event log replays is used to build
+	// the MachineState, so the digests in the MachineState use its algorithm.
+	// Banks missing from this list are not used. If empty, SHA-256 is tried
+	// first, followed by SHA-384, SHA-512, and (if allowed) SHA-1.
+	// Supported banks are SHA-1, SHA-256, SHA-384, and SHA-512.
+	PreferredHashAlgs []tpm2.Algorithm
 	// A collection of trusted root CAs that are used to sign AK certificates.
 	// The TrustedAKs are used first, followed by TrustRootCerts and
 	// IntermediateCerts.
@@ -50,7 +61,7 @@ type VerifyOpts struct {
 	TrustedRootCerts  []*x509.Certificate
 	IntermediateCerts []*x509.Certificate
 	// Which bootloader the instance uses. Pick UNSUPPORTED to skip this
-	// parsing or for unsupported bootloaders (e.g., systemd).
+	// parsing or for unsupported bootloaders.
 	Loader Bootloader
 	// TEEOpts allows customizing the functionality of VerifyTEEAttestation.
 	// Its type can be *VerifySnpOpts if the TEEAttestation is a SevSnpAttestation
@@ -79,6 +90,10 @@ const (
 	UnsupportedLoader Bootloader = iota
 	// GRUB (https://www.gnu.org/software/grub/).
 	GRUB
+	// SystemdBoot refers to systemd-boot (https://systemd.io/BOOT/) and/or a
+	// Unified Kernel Image (https://uapi-group.org/specifications/specs/unified_kernel_image/)
+	// booted with systemd-stub, including a UKI booted directly by the firmware.
+	SystemdBoot
 )
 
 // TODO: Change int64 fields to uint64 when compatible with ASN1 parsing.
@@ -106,6 +121,7 @@ type gceInstanceInfo struct {
 //   - the provided PCR values match the quote data internal digest
 //   - the provided opts.Nonce matches that in the quote data
 //   - the provided eventlog matches the provided PCR values
+//   - the provided IMA measurement list (if any) matches the provided PCR values
 //
 // After this, the eventlog is parsed and the corresponding MachineState is
 // returned. This design prevents unverified MachineStates from being used.
@@ -118,10 +134,22 @@ func VerifyAttestation(attestation *pb.Attestation, opts VerifyOpts) (*pb.Machin
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse and validate AK: %w", err)
 	}
+	_, tpmMachineState, err := verifyQuotesAndParse(attestation, akPubKey, opts)
+	if err != nil {
+		return nil, err
+	}
+	proto.Merge(machineState, tpmMachineState)
+	return machineState, nil
+}
 
+// verifyQuotesAndParse finds the first quote (in order of hash preference)
+// that verifies and whose PCRs the attestation's event log replays against.
+// The IMA measurement list, if any, must then replay against the same PCRs. It
+// returns the quote and the MachineState parsed from the logs.
+func verifyQuotesAndParse(attestation *pb.Attestation, akPubKey crypto.PublicKey, opts VerifyOpts) (*tpmpb.Quote, *pb.MachineState, error) {
 	// Attempt to replay the log against our PCRs in order of hash preference
 	var lastErr error
-	for _, quote := range supportedQuotes(attestation.GetQuotes()) {
+	for _, quote := range supportedQuotes(attestation.GetQuotes(), opts.PreferredHashAlgs) {
 		// Verify the Quote
 		if err := internal.VerifyQuote(quote, akPubKey, opts.Nonce); err != nil {
 			lastErr = fmt.Errorf("failed to verify quote: %w", err)
@@ -145,15 +173,23 @@ func VerifyAttestation(attestation *pb.Attestation, opts VerifyOpts) (*pb.Machin
 			continue
 		}
 
-		proto.Merge(machineState, tpmMachineState)
+		// The IMA measurement list must replay against the same bank as the
+		// event log. Other banks are not tried, as a list that fails to replay
+		// could otherwise be accepted against a bank where the IMA PCR was
+		// never extended.
+		if imaList := attestation.GetImaMeasurementList(); len(imaList) != 0 {
+			if tpmMachineState.Ima, err = parseIMAMeasurementList(imaList, pcrs); err != nil {
+				return nil, nil, fmt.Errorf("failed to validate the IMA measurement list: %w", err)
+			}
+		}
 
-		return machineState, nil
+		return quote, tpmMachineState, nil
 	}
 
 	if lastErr != nil {
-		return nil, lastErr
+		return nil, nil, lastErr
 	}
-	return nil, fmt.Errorf("attestation does not contain a supported quote")
+	return nil, nil, fmt.Errorf("attestation does not contain a supported quote")
 }
 
 // validateAK validates AK cert in the attestation, and returns AK cert (if exists) and public key.
@@ -258,6 +294,11 @@ func validateOpts(opts VerifyOpts) error {
 	if checkPub && checkCert {
 		return fmt.Errorf("multiple trust mechanisms provided, only use one of TrustedAKs or TrustedRootCerts")
 	}
+	for _, alg := range opts.PreferredHashAlgs {
+		if !slices.Contains(defaultPCRHashAlgs, alg) {
+			return fmt.Errorf("unsupported PCR bank %v in PreferredHashAlgs", alg)
+		}
+	}
 	return nil
 }
 
@@ -310,9 +351,14 @@ func VerifyAKCert(akCert *x509.Certificate, trustedRootCerts []*x509.Certificate
 }
 
 // Retrieve the supported quotes in order of hash preference.
-func supportedQuotes(quotes []*tpmpb.Quote) []*tpmpb.Quote {
+// supportedQuotes returns the quotes over the given PCR banks, in the order of
+// the banks. If no banks are given, defaultPCRHashAlgs is used.
+func supportedQuotes(quotes []*tpmpb.Quote, hashAlgs []tpm2.Algorithm) []*tpmpb.Quote {
+	if len(hashAlgs) == 0 {
+		hashAlgs = defaultPCRHashAlgs
+	}
 	out := make([]*tpmpb.Quote, 0, len(quotes))
-	for _, alg := range pcrHashAlgs {
+	for _, alg := range hashAlgs {
 		for _, quote := range quotes {
 			if tpm2.Algorithm(quote.GetPcrs().GetHash()) == alg {
 				out = append(out, quote)
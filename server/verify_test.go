@@ -9,6 +9,7 @@ import (
 	"crypto/x509/pkix"
 	_ "embed"
 	"encoding/asn1"
+	"encoding/binary"
 	"encoding/pem"
 	"fmt"
 	"io"
@@ -251,9 +252,9 @@ func TestVerifySHA1Attestation(t *testing.T) {
 		t.Errorf("expected SHA-256 state, got: %v", h)
 	}
 
-	// Now we mess up the SHA-256 state to force SHA-1 fallback
+	// Now we mess up the other states to force SHA-1 fallback
 	for _, quote := range attestation.GetQuotes() {
-		if tpm2.Algorithm(quote.GetPcrs().GetHash()) == tpm2.AlgSHA256 {
+		if tpm2.Algorithm(quote.GetPcrs().GetHash()) != tpm2.AlgSHA1 {
 			quote.Quote = nil
 		}
 	}
@@ -273,6 +274,164 @@ func TestVerifySHA1Attestation(t *testing.T) {
 	}
 }
 
+func TestVerifyPreferredHashAlgs(t *testing.T) {
+	// The test event log has SHA-1, SHA-256, and SHA-384 digests.
+	test.SkipForRealTPM(t)
+	rwc := test.GetTPM(t)
+	defer client.CheckedClose(t, rwc)
+	test.SkipOnUnsupportedAlg(t, rwc, tpm2.AlgSHA384)
+
+	ak, err := client.AttestationKeyRSA(rwc)
+	if err != nil {
+		t.Fatalf("failed to generate AK: %v", err)
+	}
+	defer ak.Close()
+
+	nonce := []byte("super secret nonce")
+	attestation, err := ak.Attest(client.AttestOpts{Nonce: nonce})
+	if err != nil {
+		t.Fatalf("failed to attest: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		preferred []tpm2.Algorithm
+		want      tpm2.Algorithm
+	}{
+		{"Default", nil, tpm2.AlgSHA256},
+		{"SHA384", []tpm2.Algorithm{tpm2.AlgSHA384, tpm2.AlgSHA256}, tpm2.AlgSHA384},
+		// The log has no SHA-512 digests, so the SHA-512 bank does not replay.
+		{"SHA512Fallback", []tpm2.Algorithm{tpm2.AlgSHA512, tpm2.AlgSHA384}, tpm2.AlgSHA384},
+		{"SHA256", []tpm2.Algorithm{tpm2.AlgSHA256, tpm2.AlgSHA384}, tpm2.AlgSHA256},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			state, err := VerifyAttestation(attestation, VerifyOpts{
+				Nonce:             nonce,
+				TrustedAKs:        []crypto.PublicKey{ak.PublicKey()},
+				Loader:            GRUB,
+				PreferredHashAlgs: tc.preferred,
+			})
+			if err != nil {
+				t.Fatalf("failed to verify: %v", err)
+			}
+			if got := tpm2.Algorithm(state.GetHash()); got != tc.want {
+				t.Errorf("got %v state, want %v", got, tc.want)
+			}
+			if len(state.GetGrub().GetFiles()) == 0 || state.GetLinuxKernel().GetCommandLine() == "" {
+				t.Errorf("%v state is missing GRUB and kernel state", tc.want)
+			}
+			if len(state.GetSecureBoot().GetDb().GetCerts()) == 0 {
+				t.Errorf("%v state is missing Secure Boot state", tc.want)
+			}
+		})
+	}
+
+	if _, err := VerifyAttestation(attestation, VerifyOpts{
+		Nonce:             nonce,
+		TrustedAKs:        []crypto.PublicKey{ak.PublicKey()},
+		PreferredHashAlgs: []tpm2.Algorithm{tpm2.AlgSHA512},
+	}); err == nil {
+		t.Error("verification succeeded using only the SHA-512 bank, expected failure")
+	}
+	if _, err := VerifyAttestation(attestation, VerifyOpts{
+		Nonce:             nonce,
+		TrustedAKs:        []crypto.PublicKey{ak.PublicKey()},
+		PreferredHashAlgs: []tpm2.Algorithm{tpm2.AlgSHA3_256},
+	}); err == nil {
+		t.Error("verification succeeded with an unsupported PCR bank, expected failure")
+	}
+}
+
+func TestVerifySHA512Attestation(t *testing.T) {
+	test.SkipForRealTPM(t)
+	// CreateTpm2EventLog has no SHA-512 digests, so build the log by hand.
+	rwc := test.GetSimulatorWithLog(t, sha512EventLog(t))
+	defer client.CheckedClose(t, rwc)
+	test.SkipOnUnsupportedAlg(t, rwc, tpm2.AlgSHA512)
+
+	ak, err := client.AttestationKeyECC(rwc)
+	if err != nil {
+		t.Fatalf("failed to generate AK: %v", err)
+	}
+	defer ak.Close()
+
+	nonce := []byte("super secret nonce")
+	attestation, err := ak.Attest(client.AttestOpts{Nonce: nonce})
+	if err != nil {
+		t.Fatalf("failed to attest: %v", err)
+	}
+	state, err := VerifyAttestation(attestation, VerifyOpts{
+		Nonce:             nonce,
+		TrustedAKs:        []crypto.PublicKey{ak.PublicKey()},
+		PreferredHashAlgs: []tpm2.Algorithm{tpm2.AlgSHA512},
+	})
+	if err != nil {
+		t.Fatalf("failed to verify: %v", err)
+	}
+	if got := tpm2.Algorithm(state.GetHash()); got != tpm2.AlgSHA512 {
+		t.Errorf("got %v state, want SHA512", got)
+	}
+	if got := state.GetPlatform().GetTechnology(); got != attestpb.GCEConfidentialTechnology_AMD_SEV_SNP {
+		t.Errorf("got confidential technology %v, want AMD_SEV_SNP", got)
+	}
+	for _, event := range state.GetRawEvents() {
+		if event.GetUntrustedType() == NoAction {
+			t.Errorf("got NoAction event in RawEvents: %v", event)
+		}
+	}
+}
+
+// sha512EventLog returns a crypto-agile event log with SHA-1, SHA-256, and
+// SHA-512 digests, which starts with a StartupLocality event and records a
+// SEV-SNP VM.
+func sha512EventLog(t *testing.T) []byte {
+	t.Helper()
+	algs := []tpm2.Algorithm{tpm2.AlgSHA1, tpm2.AlgSHA256, tpm2.AlgSHA512}
+	specID := append([]byte("Spec ID Event03\x00"), 0, 0, 0, 0, 0, 2, 0, 2)
+	specID = binary.LittleEndian.AppendUint32(specID, uint32(len(algs)))
+	for _, alg := range algs {
+		hash, err := alg.Hash()
+		if err != nil {
+			t.Fatal(err)
+		}
+		specID = binary.LittleEndian.AppendUint16(specID, uint16(alg))
+		specID = binary.LittleEndian.AppendUint16(specID, uint16(hash.Size()))
+	}
+	specID = append(specID, 0)
+
+	log := binary.LittleEndian.AppendUint32(nil, 0)
+	log = binary.LittleEndian.AppendUint32(log, NoAction)
+	log = append(log, make([]byte, 20)...)
+	log = binary.LittleEndian.AppendUint32(log, uint32(len(specID)))
+	log = append(log, specID...)
+
+	appendEvent := func(eventType uint32, data []byte) {
+		log = binary.LittleEndian.AppendUint32(log, 0)
+		log = binary.LittleEndian.AppendUint32(log, eventType)
+		log = binary.LittleEndian.AppendUint32(log, uint32(len(algs)))
+		for _, alg := range algs {
+			hash, _ := alg.Hash()
+			digest := make([]byte, hash.Size())
+			if eventType != NoAction {
+				hasher := hash.New()
+				hasher.Write(data)
+				digest = hasher.Sum(nil)
+			}
+			log = binary.LittleEndian.AppendUint16(log, uint16(alg))
+			log = append(log, digest...)
+		}
+		log = binary.LittleEndian.AppendUint32(log, uint32(len(data)))
+		log = append(log, data...)
+	}
+	// The simulator is started from locality 0.
+	appendEvent(NoAction, []byte("StartupLocality\x00\x00"))
+	appendEvent(SCRTMVersion, []byte("G\x00C\x00E\x00 \x00V\x00i\x00r\x00t\x00u\x00a\x00l\x00 \x00F\x00i\x00r\x00m\x00w\x00a\x00r\x00e\x00 \x00v\x001\x00\x00\x00"))
+	nonHostInfo := append([]byte("GCE NonHostInfo\x00"), byte(attestpb.GCEConfidentialTechnology_AMD_SEV_SNP))
+	appendEvent(NonhostInfo, append(nonHostInfo, make([]byte, 15)...))
+	return log
+}
+
 func TestVerifyAttestationWithCerts(t *testing.T) {
 	tests := []struct {
 		name        string